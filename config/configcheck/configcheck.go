@@ -79,26 +79,32 @@ func ValidateConfig(config interface{}) error {
 			tk)
 	}
 
-	return validateConfigDataType(t)
+	return validateConfigDataType(t, make(map[reflect.Type]bool))
 }
 
 // validateConfigDataType performs a descending validation of the given type.
 // If the type is a struct it goes to each of its fields to check for the proper
-// tags.
-func validateConfigDataType(t reflect.Type) error {
+// tags. Structs already in visited are not checked again, this allows recursive
+// configuration types, e.g.: a struct with a slice of itself.
+func validateConfigDataType(t reflect.Type, visited map[reflect.Type]bool) error {
 	var errs []error
 
 	switch t.Kind() {
 	case reflect.Ptr:
-		if err := validateConfigDataType(t.Elem()); err != nil {
+		if err := validateConfigDataType(t.Elem(), visited); err != nil {
 			errs = append(errs, err)
 		}
 	case reflect.Struct:
+		if visited[t] {
+			return nil
+		}
+		visited[t] = true
+
 		// Reflect on the pointed data and check each of its fields.
 		nf := t.NumField()
 		for i := 0; i < nf; i++ {
 			f := t.Field(i)
-			if err := checkStructFieldTags(f); err != nil {
+			if err := checkStructFieldTags(f, visited); err != nil {
 				errs = append(errs, err)
 			}
 		}
@@ -121,7 +127,7 @@ func validateConfigDataType(t reflect.Type) error {
 }
 
 // checkStructFieldTags inspects the tags of a struct field.
-func checkStructFieldTags(f reflect.StructField) error {
+func checkStructFieldTags(f reflect.StructField, visited map[reflect.Type]bool) error {
 
 	tagValue := f.Tag.Get("mapstructure")
 	if tagValue == "" {
@@ -172,11 +178,11 @@ func checkStructFieldTags(f reflect.StructField) error {
 	switch f.Type.Kind() {
 	case reflect.Struct:
 		// It is another struct, continue down-level
-		return validateConfigDataType(f.Type)
+		return validateConfigDataType(f.Type, visited)
 
 	case reflect.Map, reflect.Slice, reflect.Array:
 		// The element of map, array, or slice can be itself a configuration object.
-		return validateConfigDataType(f.Type.Elem())
+		return validateConfigDataType(f.Type.Elem(), visited)
 
 	default:
 		fieldTag := tagParts[0]
@@ -60,6 +60,14 @@ func TestValidateConfig(t *testing.T) {
 	type BadConfigTag struct {
 		BadTagField int `mapstructure:"test-dash"`
 	}
+	type RecursiveConfig struct {
+		Name     string            `mapstructure:"name"`
+		Children []RecursiveConfig `mapstructure:"children"`
+	}
+	type BadRecursiveConfig struct {
+		BadTagField int                   `mapstructure:"test-dash"`
+		Children    []*BadRecursiveConfig `mapstructure:"children"`
+	}
 
 	tests := []struct {
 		name             string
@@ -168,6 +176,15 @@ func TestValidateConfig(t *testing.T) {
 				Slice []string `mapstructure:"test_slice"`
 			}{},
 		},
+		{
+			name:   "valid_recursive_type",
+			config: RecursiveConfig{},
+		},
+		{
+			name:             "invalid_recursive_type",
+			config:           BadRecursiveConfig{},
+			wantErrMsgSubStr: "field \"BadTagField\" has config tag \"test-dash\" which doesn't satisfy",
+		},
 	}
 
 	for _, tt := range tests {
@@ -22,11 +22,14 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer"
+	_ "google.golang.org/grpc/balancer/roundrobin" // register the round_robin balancer
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/encoding/gzip"
 	"google.golang.org/grpc/keepalive"
 
 	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/config/configtls"
 )
 
@@ -34,12 +37,16 @@ import (
 const (
 	CompressionUnsupported = ""
 	CompressionGzip        = "gzip"
+	CompressionSnappy      = "snappy"
+	CompressionZstd        = "zstd"
 )
 
 var (
 	// Map of opentelemetry compression types to grpc registered compression types
 	grpcCompressionKeyMap = map[string]string{
-		CompressionGzip: gzip.Name,
+		CompressionGzip:   gzip.Name,
+		CompressionSnappy: snappyName,
+		CompressionZstd:   zstdName,
 	}
 )
 
@@ -60,7 +67,7 @@ type GRPCClientSettings struct {
 	Endpoint string `mapstructure:"endpoint"`
 
 	// The compression key for supported compression types within
-	// collector. Currently the only supported mode is `gzip`.
+	// collector. Supported modes are `gzip`, `snappy` and `zstd`.
 	Compression string `mapstructure:"compression"`
 
 	// TLSSetting struct exposes TLS client configuration.
@@ -82,8 +89,15 @@ type GRPCClientSettings struct {
 	// (https://github.com/grpc/grpc/blob/master/doc/wait-for-ready.md)
 	WaitForReady bool `mapstructure:"wait_for_ready"`
 
-	// The headers associated with gRPC requests.
-	Headers map[string]string `mapstructure:"headers"`
+	// The headers associated with gRPC requests. The values are often
+	// credentials, they are redacted when the configuration is logged.
+	Headers map[string]configopaque.String `mapstructure:"headers"`
+
+	// Sets the balancer in grpclb_policy to discover the servers. Default is pick_first.
+	// Combined with a `dns:///` endpoint the client connects to all the
+	// addresses the name resolves to, e.g. `round_robin`.
+	// (https://github.com/grpc/grpc-go/blob/master/examples/features/load_balancing/README.md)
+	BalancerName string `mapstructure:"balancer_name"`
 }
 
 type KeepaliveServerConfig struct {
@@ -119,6 +133,8 @@ type GRPCServerSettings struct {
 	TLSSetting *configtls.TLSServerSetting `mapstructure:"tls_settings,omitempty"`
 
 	// MaxRecvMsgSizeMiB sets the maximum size (in MiB) of messages accepted by the server.
+	// The zstd compressed messages are also limited to 64 MiB once decompressed, for all
+	// the servers.
 	MaxRecvMsgSizeMiB uint64 `mapstructure:"max_recv_msg_size_mib"`
 
 	// MaxConcurrentStreams sets the limit on the number of concurrent streams to each ServerTransport.
@@ -176,6 +192,13 @@ func (gcs *GRPCClientSettings) ToDialOptions() ([]grpc.DialOption, error) {
 		opts = append(opts, keepAliveOption)
 	}
 
+	if gcs.BalancerName != "" {
+		if balancer.Get(gcs.BalancerName) == nil {
+			return nil, fmt.Errorf("invalid balancer_name: %s", gcs.BalancerName)
+		}
+		opts = append(opts, grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"loadBalancingPolicy":%q}`, gcs.BalancerName)))
+	}
+
 	return opts, nil
 }
 
@@ -25,8 +25,10 @@ import (
 	"google.golang.org/grpc"
 
 	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/config/configtls"
 	otelcol "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/collector/trace/v1"
+	otlptrace "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/trace/v1"
 	"go.opentelemetry.io/collector/testutil"
 )
 
@@ -43,7 +45,7 @@ func TestDefaultGrpcClientSettings(t *testing.T) {
 
 func TestAllGrpcClientSettings(t *testing.T) {
 	gcs := &GRPCClientSettings{
-		Headers: map[string]string{
+		Headers: map[string]configopaque.String{
 			"test": "test",
 		},
 		Endpoint:    "localhost:1234",
@@ -59,10 +61,11 @@ func TestAllGrpcClientSettings(t *testing.T) {
 		ReadBufferSize:  1024,
 		WriteBufferSize: 1024,
 		WaitForReady:    true,
+		BalancerName:    "round_robin",
 	}
 	opts, err := gcs.ToDialOptions()
 	assert.NoError(t, err)
-	assert.Len(t, opts, 5)
+	assert.Len(t, opts, 6)
 }
 
 func TestDefaultGrpcServerSettings(t *testing.T) {
@@ -142,6 +145,26 @@ func TestGRPCClientSettingsError(t *testing.T) {
 				Keepalive: nil,
 			},
 		},
+		{
+			err: "invalid balancer_name: test",
+			settings: GRPCClientSettings{
+				Endpoint:     "localhost:1234",
+				BalancerName: "test",
+				TLSSetting: configtls.TLSClientSetting{
+					Insecure: true,
+				},
+			},
+		},
+		{
+			err: "unsupported compression type \"lz4\"",
+			settings: GRPCClientSettings{
+				Endpoint:    "localhost:1234",
+				Compression: "lz4",
+				TLSSetting: configtls.TLSClientSetting{
+					Insecure: true,
+				},
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.err, func(t *testing.T) {
@@ -244,6 +267,14 @@ func TestGetGRPCCompressionKey(t *testing.T) {
 		t.Error("Capitalization of CompressionGzip should not matter")
 	}
 
+	if GetGRPCCompressionKey("snappy") != CompressionSnappy {
+		t.Error("snappy is marked as supported but returned unsupported")
+	}
+
+	if GetGRPCCompressionKey("zstd") != CompressionZstd {
+		t.Error("zstd is marked as supported but returned unsupported")
+	}
+
 	if GetGRPCCompressionKey("badType") != CompressionUnsupported {
 		t.Error("badType is not supported but was returned as supported")
 	}
@@ -438,6 +469,86 @@ func TestReceiveOnUnixDomainSocket(t *testing.T) {
 	s.Stop()
 }
 
+func TestCompressionAndBalancerReception(t *testing.T) {
+	tests := []struct {
+		name         string
+		compression  string
+		balancerName string
+		dnsResolver  bool
+	}{
+		{
+			name:        "gzip",
+			compression: CompressionGzip,
+		},
+		{
+			name:        "snappy",
+			compression: CompressionSnappy,
+		},
+		{
+			name:        "zstd",
+			compression: CompressionZstd,
+		},
+		{
+			name:         "roundRobinDNS",
+			compression:  CompressionZstd,
+			balancerName: "round_robin",
+			dnsResolver:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gss := &GRPCServerSettings{
+				NetAddr: confignet.NetAddr{
+					Endpoint:  "localhost:0",
+					Transport: "tcp",
+				},
+			}
+			ln, err := gss.ToListener()
+			assert.NoError(t, err)
+			opts, err := gss.ToServerOption()
+			assert.NoError(t, err)
+			s := grpc.NewServer(opts...)
+			otelcol.RegisterTraceServiceServer(s, &grpcTraceServer{})
+
+			go func() {
+				_ = s.Serve(ln)
+			}()
+
+			gcs := &GRPCClientSettings{
+				Endpoint:     ln.Addr().String(),
+				Compression:  tt.compression,
+				BalancerName: tt.balancerName,
+				TLSSetting: configtls.TLSClientSetting{
+					Insecure: true,
+				},
+			}
+			if tt.dnsResolver {
+				gcs.Endpoint = "dns:///" + gcs.Endpoint
+			}
+			clientOpts, errClient := gcs.ToDialOptions()
+			assert.NoError(t, errClient)
+			grpcClientConn, errDial := grpc.Dial(gcs.Endpoint, clientOpts...)
+			assert.NoError(t, errDial)
+			client := otelcol.NewTraceServiceClient(grpcClientConn)
+			ctx, cancelFunc := context.WithTimeout(context.Background(), 2*time.Second)
+			req := &otelcol.ExportTraceServiceRequest{
+				ResourceSpans: []*otlptrace.ResourceSpans{{
+					InstrumentationLibrarySpans: []*otlptrace.InstrumentationLibrarySpans{{
+						Spans: []*otlptrace.Span{{Name: "compressed"}, {Name: "span"}},
+					}},
+				}},
+			}
+			resp, errResp := client.Export(ctx, req, grpc.WaitForReady(true))
+			assert.NoError(t, errResp)
+			assert.NotNil(t, resp)
+			cancelFunc()
+			assert.NoError(t, grpcClientConn.Close())
+			s.Stop()
+		})
+	}
+}
+
 type grpcTraceServer struct{}
 
 func (gts *grpcTraceServer) Export(context.Context, *otelcol.ExportTraceServiceRequest) (*otelcol.ExportTraceServiceResponse, error) {
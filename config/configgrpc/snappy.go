@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc
+
+import (
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/golang/snappy"
+	"google.golang.org/grpc/encoding"
+)
+
+// snappyName is the name registered for the snappy compressor.
+const snappyName = "snappy"
+
+func init() {
+	encoding.RegisterCompressor(newSnappyCompressor())
+}
+
+type snappyCompressor struct {
+	poolCompressor sync.Pool
+}
+
+type snappyWriter struct {
+	*snappy.Writer
+	pool *sync.Pool
+}
+
+func newSnappyCompressor() *snappyCompressor {
+	c := &snappyCompressor{}
+	c.poolCompressor.New = func() interface{} {
+		return &snappyWriter{Writer: snappy.NewBufferedWriter(ioutil.Discard), pool: &c.poolCompressor}
+	}
+	return c
+}
+
+func (c *snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	sw := c.poolCompressor.Get().(*snappyWriter)
+	sw.Reset(w)
+	return sw, nil
+}
+
+func (c *snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return snappy.NewReader(r), nil
+}
+
+func (c *snappyCompressor) Name() string {
+	return snappyName
+}
+
+// Close flushes the buffered data and returns the writer to the pool.
+func (sw *snappyWriter) Close() error {
+	defer sw.pool.Put(sw)
+	return sw.Writer.Close()
+}
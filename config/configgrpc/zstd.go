@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc/encoding"
+)
+
+const (
+	// zstdName is the name registered for the zstd compressor.
+	zstdName = "zstd"
+	// zstdMaxDecodedSize is the maximum size of a decompressed message, for all
+	// the gRPC servers and clients of the process since the compressor is
+	// registered globally. It also bounds the window size of the frames, so it is
+	// well above the default maximum size of the messages received by gRPC to
+	// accept the windows of the usual compression levels. Each server then
+	// enforces its own maximum message size on the decompressed message.
+	zstdMaxDecodedSize = 64 * 1024 * 1024
+)
+
+func init() {
+	encoding.RegisterCompressor(newZstdCompressor(zstdMaxDecodedSize))
+}
+
+type zstdCompressor struct {
+	poolCompressor sync.Pool
+	// decoder is only used through DecodeAll which is safe for concurrent use.
+	decoder *zstd.Decoder
+}
+
+type zstdWriter struct {
+	*zstd.Encoder
+	pool *sync.Pool
+}
+
+func newZstdCompressor(maxDecodedSize uint64) *zstdCompressor {
+	// NewReader only fails on invalid options.
+	dec, _ := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(maxDecodedSize))
+	return &zstdCompressor{decoder: dec}
+}
+
+func (c *zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	if zw, ok := c.poolCompressor.Get().(*zstdWriter); ok {
+		zw.Reset(w)
+		return zw, nil
+	}
+	// Encoding concurrency is limited to one, the gRPC message is already
+	// the unit of parallelism.
+	enc, err := zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	return &zstdWriter{Encoder: enc, pool: &c.poolCompressor}, nil
+}
+
+// Decompress decodes the whole message, it fails if the decompressed message is
+// larger than the maximum size, so a small message can't expand without bounds.
+func (c *zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	compressed, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := c.decoder.DecodeAll(compressed, nil)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(buf), nil
+}
+
+func (c *zstdCompressor) Name() string {
+	return zstdName
+}
+
+// Close flushes the encoded data and returns the writer to the pool.
+func (zw *zstdWriter) Close() error {
+	defer zw.pool.Put(zw)
+	return zw.Encoder.Close()
+}
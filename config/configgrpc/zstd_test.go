@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/encoding"
+)
+
+func TestZstdDecompressMaxSize(t *testing.T) {
+	c := encoding.GetCompressor(zstdName)
+	require.NotNil(t, c)
+	compress := func(size int) []byte {
+		var buf bytes.Buffer
+		w, err := c.Compress(&buf)
+		require.NoError(t, err)
+		_, err = w.Write(make([]byte, size))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+
+	r, err := c.Decompress(bytes.NewReader(compress(1024)))
+	require.NoError(t, err)
+	decoded, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	assert.Len(t, decoded, 1024)
+
+	// A small message expanding beyond the maximum size is refused.
+	bomb := compress(zstdMaxDecodedSize + 1024*1024)
+	assert.Less(t, len(bomb), 64*1024)
+	_, err = c.Decompress(bytes.NewReader(bomb))
+	assert.Error(t, err)
+
+	// The limit is global, a server accepting larger messages doesn't change it.
+	gss := &GRPCServerSettings{MaxRecvMsgSizeMiB: 1024}
+	_, err = gss.ToServerOption()
+	require.NoError(t, err)
+	_, err = c.Decompress(bytes.NewReader(bomb))
+	assert.Error(t, err)
+}
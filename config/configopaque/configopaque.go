@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package configopaque implements types for configuration values holding
+// sensitive data, like credentials sent as headers, passwords or tokens,
+// that must not leak to logs or to any output of the configuration.
+package configopaque
+
+import (
+	"fmt"
+)
+
+const redacted = "[REDACTED]"
+
+// String is a configuration value that is redacted when printed, logged or
+// marshaled. It is decoded like any other string, including the expansion of
+// environment variables; convert it to a plain string, with string(s), only
+// where the value is actually used.
+type String string
+
+var _ fmt.Stringer = String("")
+var _ fmt.GoStringer = String("")
+
+// String implements fmt.Stringer, it returns "[REDACTED]".
+func (s String) String() string {
+	return redacted
+}
+
+// GoString implements fmt.GoStringer, it returns "[REDACTED]" so that the
+// value does not leak when printed with the %#v verb.
+func (s String) GoString() string {
+	return fmt.Sprintf("%q", redacted)
+}
+
+// MarshalText implements encoding.TextMarshaler, used by the JSON and YAML
+// encoders as well as by the logger, it returns "[REDACTED]".
+func (s String) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, the text is the value.
+func (s *String) UnmarshalText(text []byte) error {
+	*s = String(text)
+	return nil
+}
+
+// MapToStrings converts a map of opaque values to plain strings, to be used
+// where the values are sent to the destination they are meant for.
+func MapToStrings(m map[string]String) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = string(v)
+	}
+	return out
+}
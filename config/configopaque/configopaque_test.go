@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configopaque
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gopkg.in/yaml.v2"
+)
+
+type testConfig struct {
+	Endpoint string            `json:"endpoint" yaml:"endpoint"`
+	Token    String            `json:"token" yaml:"token"`
+	Headers  map[string]String `json:"headers" yaml:"headers"`
+}
+
+func newTestConfig() testConfig {
+	return testConfig{
+		Endpoint: "localhost:55680",
+		Token:    "my-token",
+		Headers:  map[string]String{"api-key": "my-api-key"},
+	}
+}
+
+func TestStringRedactedWhenPrinted(t *testing.T) {
+	cfg := newTestConfig()
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		out := fmt.Sprintf(format, cfg)
+		assert.NotContains(t, out, "my-token", format)
+		assert.NotContains(t, out, "my-api-key", format)
+		assert.Contains(t, out, redacted, format)
+	}
+	assert.Equal(t, "my-token", string(cfg.Token))
+}
+
+func TestStringRedactedWhenMarshaled(t *testing.T) {
+	cfg := newTestConfig()
+
+	out, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"endpoint":"localhost:55680","token":"[REDACTED]","headers":{"api-key":"[REDACTED]"}}`, string(out))
+
+	out, err = yaml.Marshal(cfg)
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "my-token")
+	assert.NotContains(t, string(out), "my-api-key")
+}
+
+func TestStringRedactedWhenLogged(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := zap.New(core)
+	logger.Info("starting", zap.Any("config", newTestConfig()))
+
+	require.Equal(t, 1, logs.Len())
+	enc := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	buf, err := enc.EncodeEntry(logs.All()[0].Entry, logs.All()[0].Context)
+	require.NoError(t, err)
+	assert.NotContains(t, buf.String(), "my-token")
+	assert.NotContains(t, buf.String(), "my-api-key")
+	assert.Contains(t, buf.String(), redacted)
+}
+
+func TestStringUnmarshal(t *testing.T) {
+	var cfg testConfig
+	require.NoError(t, json.Unmarshal([]byte(`{"token":"my-token","headers":{"api-key":"my-api-key"}}`), &cfg))
+	assert.Equal(t, String("my-token"), cfg.Token)
+	assert.Equal(t, String("my-api-key"), cfg.Headers["api-key"])
+
+	cfg = testConfig{}
+	require.NoError(t, yaml.Unmarshal([]byte("token: my-token\nheaders:\n  api-key: my-api-key\n"), &cfg))
+	assert.Equal(t, String("my-token"), cfg.Token)
+	assert.Equal(t, String("my-api-key"), cfg.Headers["api-key"])
+}
+
+func TestMapToStrings(t *testing.T) {
+	assert.Nil(t, MapToStrings(nil))
+	assert.Equal(t,
+		map[string]string{"api-key": "my-api-key"},
+		MapToStrings(map[string]String{"api-key": "my-api-key"}))
+}
@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+
+	"go.opentelemetry.io/collector/config/configopaque"
 )
 
 // TLSSetting exposes the common client and server TLS configurations.
@@ -34,6 +36,11 @@ type TLSSetting struct {
 	CertFile string `mapstructure:"cert_file"`
 	// Path to the TLS key to use for TLS required connections. (optional)
 	KeyFile string `mapstructure:"key_file"`
+	// In memory PEM encoded TLS cert, alternative to CertFile. (optional)
+	CertPem string `mapstructure:"cert_pem"`
+	// In memory PEM encoded TLS key, alternative to KeyFile. It is redacted
+	// when the configuration is logged. (optional)
+	KeyPem configopaque.String `mapstructure:"key_pem"`
 }
 
 // TLSClientSetting contains TLS configurations that are specific to client
@@ -89,13 +96,22 @@ func (c TLSSetting) loadTLSConfig() (*tls.Config, error) {
 		}
 	}
 
-	if (c.CertFile == "" && c.KeyFile != "") || (c.CertFile != "" && c.KeyFile == "") {
+	if c.CertFile != "" && c.CertPem != "" {
+		return nil, fmt.Errorf("for auth via TLS, provide either the certificate file or PEM, not both")
+	}
+	if c.KeyFile != "" && c.KeyPem != "" {
+		return nil, fmt.Errorf("for auth via TLS, provide either the key file or PEM, not both")
+	}
+
+	hasCert := c.CertFile != "" || c.CertPem != ""
+	hasKey := c.KeyFile != "" || c.KeyPem != ""
+	if hasCert != hasKey {
 		return nil, fmt.Errorf("for auth via TLS, either both certificate and key must be supplied, or neither")
 	}
 
 	var certificates []tls.Certificate
-	if c.CertFile != "" && c.KeyFile != "" {
-		tlsCert, err := tls.LoadX509KeyPair(filepath.Clean(c.CertFile), filepath.Clean(c.KeyFile))
+	if hasCert && hasKey {
+		tlsCert, err := c.loadKeyPair()
 		if err != nil {
 			return nil, fmt.Errorf("failed to load TLS cert and key: %w", err)
 		}
@@ -108,6 +124,26 @@ func (c TLSSetting) loadTLSConfig() (*tls.Config, error) {
 	}, nil
 }
 
+func (c TLSSetting) loadKeyPair() (tls.Certificate, error) {
+	certPEM := []byte(c.CertPem)
+	if c.CertFile != "" {
+		var err error
+		if certPEM, err = ioutil.ReadFile(filepath.Clean(c.CertFile)); err != nil {
+			return tls.Certificate{}, err
+		}
+	}
+
+	keyPEM := []byte(c.KeyPem)
+	if c.KeyFile != "" {
+		var err error
+		if keyPEM, err = ioutil.ReadFile(filepath.Clean(c.KeyFile)); err != nil {
+			return tls.Certificate{}, err
+		}
+	}
+
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
+
 func (c TLSSetting) loadCert(caPath string) (*x509.CertPool, error) {
 	caPEM, err := ioutil.ReadFile(filepath.Clean(caPath))
 	if err != nil {
@@ -15,10 +15,14 @@
 package configtls
 
 import (
+	"fmt"
+	"io/ioutil"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/config/configopaque"
 )
 
 func TestOptionsToConfig(t *testing.T) {
@@ -107,6 +111,46 @@ func TestOptionsToConfig(t *testing.T) {
 				CAFile: "testdata/testCA.pem",
 			},
 		},
+		{
+			name: "should load valid TLS settings from PEM",
+			options: TLSSetting{
+				CertPem: readFile(t, "testdata/test-cert.pem"),
+				KeyPem:  configopaque.String(readFile(t, "testdata/test-key.pem")),
+			},
+		},
+		{
+			name: "should fail with invalid TLS key PEM",
+			options: TLSSetting{
+				CertPem: readFile(t, "testdata/test-cert.pem"),
+				KeyPem:  "invalid",
+			},
+			expectError: "failed to load TLS cert and key",
+		},
+		{
+			name: "should fail with missing TLS key PEM",
+			options: TLSSetting{
+				CertPem: readFile(t, "testdata/test-cert.pem"),
+			},
+			expectError: "both certificate and key must be supplied",
+		},
+		{
+			name: "should fail with both key file and PEM",
+			options: TLSSetting{
+				CertFile: "testdata/test-cert.pem",
+				KeyFile:  "testdata/test-key.pem",
+				KeyPem:   configopaque.String(readFile(t, "testdata/test-key.pem")),
+			},
+			expectError: "either the key file or PEM, not both",
+		},
+		{
+			name: "should fail with both cert file and PEM",
+			options: TLSSetting{
+				CertFile: "testdata/test-cert.pem",
+				CertPem:  readFile(t, "testdata/test-cert.pem"),
+				KeyFile:  "testdata/test-key.pem",
+			},
+			expectError: "either the certificate file or PEM, not both",
+		},
 	}
 
 	for _, test := range tests {
@@ -171,3 +215,17 @@ func TestLoadTLSServerConfig(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, tlsCfg)
 }
+
+func TestKeyPemRedacted(t *testing.T) {
+	setting := TLSSetting{
+		CertPem: readFile(t, "testdata/test-cert.pem"),
+		KeyPem:  configopaque.String(readFile(t, "testdata/test-key.pem")),
+	}
+	assert.NotContains(t, fmt.Sprintf("%+v", setting), "PRIVATE KEY")
+}
+
+func readFile(t *testing.T, path string) string {
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	return string(content)
+}
@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumererror
+
+import (
+	"errors"
+	"time"
+)
+
+// throttled is an error indicating that the data was refused because the
+// source is temporarily overloaded, and that it can be retried later.
+type throttled struct {
+	err        error
+	retryAfter time.Duration
+}
+
+// Throttled wraps an error to indicate that the data was refused because the
+// source is overloaded, e.g.: due to memory or rate limits, and that the
+// sender should retry after the given delay. A zero delay means that the
+// source has no suggestion about when to retry.
+func Throttled(err error, retryAfter time.Duration) error {
+	return throttled{err: err, retryAfter: retryAfter}
+}
+
+func (t throttled) Error() string {
+	return "Throttled: " + t.err.Error()
+}
+
+// Unwrap returns the wrapped error for functions Is and As in standard package errors.
+func (t throttled) Unwrap() error {
+	return t.err
+}
+
+// IsThrottled checks if an error was wrapped with the Throttled function,
+// including when it was further wrapped using fmt.Errorf with the %w verb.
+func IsThrottled(err error) bool {
+	_, ok := RetryAfter(err)
+	return ok
+}
+
+// RetryAfter returns the delay suggested by a Throttled error and whether
+// the error was wrapped with the Throttled function.
+func RetryAfter(err error) (time.Duration, bool) {
+	var t throttled
+	if errors.As(err, &t) {
+		return t.retryAfter, true
+	}
+	return 0, false
+}
@@ -0,0 +1,46 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumererror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottled(t *testing.T) {
+	origErr := errors.New("testError")
+	require.False(t, IsThrottled(origErr))
+
+	err := Throttled(origErr, time.Second)
+	require.True(t, IsThrottled(err))
+	assert.True(t, errors.Is(err, origErr))
+	assert.Equal(t, "Throttled: testError", err.Error())
+
+	retryAfter, ok := RetryAfter(fmt.Errorf("wrapped: %w", err))
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, retryAfter)
+}
+
+func TestIsThrottled_NilError(t *testing.T) {
+	var err error
+	require.False(t, IsThrottled(err))
+	_, ok := RetryAfter(err)
+	require.False(t, ok)
+}
@@ -0,0 +1,176 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+// errCircuitBreakerOpen is returned, as a throttled error, while the circuit
+// breaker is open.
+var errCircuitBreakerOpen = errors.New("circuit breaker is open, the backend is failing")
+
+// CircuitBreakerSettings configures the circuit breaker protecting the
+// pipeline from a failing backend.
+type CircuitBreakerSettings struct {
+	// Enabled indicates whether to use the circuit breaker.
+	Enabled bool `mapstructure:"enabled"`
+	// FailureThreshold is the number of consecutive failed requests that opens
+	// the circuit breaker. Permanent errors are not counted as failures, the
+	// backend refused the data but it is available.
+	FailureThreshold int `mapstructure:"failure_threshold"`
+	// OpenTimeout is how long the circuit breaker stays open, failing fast,
+	// before letting a single request probe the backend (half-open).
+	OpenTimeout time.Duration `mapstructure:"open_timeout"`
+}
+
+// CreateDefaultCircuitBreakerSettings returns the default settings for
+// CircuitBreakerSettings.
+func CreateDefaultCircuitBreakerSettings() CircuitBreakerSettings {
+	return CircuitBreakerSettings{
+		Enabled:          false,
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+	}
+}
+
+// CircuitBreakerState is the state of a circuit breaker.
+type CircuitBreakerState int
+
+const (
+	// CircuitBreakerClosed lets all the requests through.
+	CircuitBreakerClosed CircuitBreakerState = iota
+	// CircuitBreakerHalfOpen lets a single request probe the backend.
+	CircuitBreakerHalfOpen
+	// CircuitBreakerOpen fails all the requests fast.
+	CircuitBreakerOpen
+)
+
+// String returns the name of the state.
+func (s CircuitBreakerState) String() string {
+	switch s {
+	case CircuitBreakerClosed:
+		return "closed"
+	case CircuitBreakerHalfOpen:
+		return "half-open"
+	case CircuitBreakerOpen:
+		return "open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerStateReporter is implemented by the exporters created with
+// this package, it reports the state of their circuit breaker, if enabled.
+type CircuitBreakerStateReporter interface {
+	CircuitBreakerState() (state CircuitBreakerState, enabled bool)
+}
+
+type circuitBreaker struct {
+	exporterName     string
+	failureThreshold int
+	openTimeout      time.Duration
+	now              func() time.Time
+
+	mu       sync.Mutex
+	state    CircuitBreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(exporterName string, settings CircuitBreakerSettings) *circuitBreaker {
+	cb := &circuitBreaker{
+		exporterName:     exporterName,
+		failureThreshold: settings.FailureThreshold,
+		openTimeout:      settings.OpenTimeout,
+		now:              time.Now,
+	}
+	if cb.failureThreshold <= 0 {
+		cb.failureThreshold = 1
+	}
+	obsreport.RecordExporterCircuitBreakerState(exporterName, int64(CircuitBreakerClosed))
+	return cb
+}
+
+// State returns the current state of the circuit breaker.
+func (cb *circuitBreaker) State() CircuitBreakerState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+// allow returns an error if the request must not be sent. The error is a
+// throttled error suggesting when to retry, so that the data can be retried
+// later, e.g. by the queued_retry processor, instead of being sent to a
+// backend known to be failing.
+func (cb *circuitBreaker) allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case CircuitBreakerOpen:
+		elapsed := cb.now().Sub(cb.openedAt)
+		if elapsed < cb.openTimeout {
+			return consumererror.Throttled(errCircuitBreakerOpen, cb.openTimeout-elapsed)
+		}
+		cb.setState(CircuitBreakerHalfOpen)
+		cb.probing = true
+		return nil
+	case CircuitBreakerHalfOpen:
+		if cb.probing {
+			return consumererror.Throttled(errCircuitBreakerOpen, cb.openTimeout)
+		}
+		cb.probing = true
+		return nil
+	}
+	return nil
+}
+
+// done records the result of a request let through by allow.
+func (cb *circuitBreaker) done(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == CircuitBreakerHalfOpen {
+		cb.probing = false
+	}
+
+	if err == nil || consumererror.IsPermanent(err) {
+		cb.failures = 0
+		if cb.state != CircuitBreakerClosed {
+			cb.setState(CircuitBreakerClosed)
+		}
+		return
+	}
+
+	cb.failures++
+	if cb.state == CircuitBreakerHalfOpen || cb.failures >= cb.failureThreshold {
+		cb.openedAt = cb.now()
+		cb.setState(CircuitBreakerOpen)
+	}
+}
+
+func (cb *circuitBreaker) setState(state CircuitBreakerState) {
+	if cb.state == state {
+		return
+	}
+	cb.state = state
+	obsreport.RecordExporterCircuitBreakerState(cb.exporterName, int64(state))
+}
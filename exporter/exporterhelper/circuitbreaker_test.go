@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/obsreport/obsreporttest"
+)
+
+func TestCircuitBreakerState_String(t *testing.T) {
+	assert.Equal(t, "closed", CircuitBreakerClosed.String())
+	assert.Equal(t, "half-open", CircuitBreakerHalfOpen.String())
+	assert.Equal(t, "open", CircuitBreakerOpen.String())
+	assert.Equal(t, "unknown", CircuitBreakerState(42).String())
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	require.NoError(t, err)
+	defer doneFn()
+
+	cb := newCircuitBreaker(fakeTraceExporterName, CircuitBreakerSettings{
+		Enabled:          true,
+		FailureThreshold: 2,
+		OpenTimeout:      10 * time.Second,
+	})
+	now := time.Now()
+	cb.now = func() time.Time { return now }
+	failure := errors.New("backend unavailable")
+
+	// A success resets the count of consecutive failures.
+	require.NoError(t, cb.allow())
+	cb.done(failure)
+	require.NoError(t, cb.allow())
+	cb.done(nil)
+	require.NoError(t, cb.allow())
+	cb.done(failure)
+	assert.Equal(t, CircuitBreakerClosed, cb.State())
+
+	// Permanent errors are not failures of the backend.
+	require.NoError(t, cb.allow())
+	cb.done(consumererror.Permanent(failure))
+	require.NoError(t, cb.allow())
+	cb.done(failure)
+	assert.Equal(t, CircuitBreakerClosed, cb.State())
+
+	require.NoError(t, cb.allow())
+	cb.done(failure)
+	assert.Equal(t, CircuitBreakerOpen, cb.State())
+	obsreporttest.CheckExporterCircuitBreakerState(t, fakeTraceExporterName, int64(CircuitBreakerOpen))
+
+	// Open: fail fast suggesting when to retry.
+	now = now.Add(4 * time.Second)
+	err = cb.allow()
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errCircuitBreakerOpen))
+	retryAfter, ok := consumererror.RetryAfter(err)
+	require.True(t, ok)
+	assert.Equal(t, 6*time.Second, retryAfter)
+
+	// Half-open: a single probe is let through, the probe fails.
+	now = now.Add(6 * time.Second)
+	require.NoError(t, cb.allow())
+	assert.Equal(t, CircuitBreakerHalfOpen, cb.State())
+	assert.True(t, consumererror.IsThrottled(cb.allow()))
+	cb.done(failure)
+	assert.Equal(t, CircuitBreakerOpen, cb.State())
+	assert.True(t, consumererror.IsThrottled(cb.allow()))
+
+	// Half-open again, the probe succeeds.
+	now = now.Add(10 * time.Second)
+	require.NoError(t, cb.allow())
+	cb.done(nil)
+	assert.Equal(t, CircuitBreakerClosed, cb.State())
+	obsreporttest.CheckExporterCircuitBreakerState(t, fakeTraceExporterName, int64(CircuitBreakerClosed))
+	require.NoError(t, cb.allow())
+}
+
+func TestTraceExporter_WithCircuitBreaker(t *testing.T) {
+	want := errors.New("my_error")
+	calls := 0
+	pusher := func(context.Context, pdata.Traces) (int, error) {
+		calls++
+		return 0, want
+	}
+	cbs := CreateDefaultCircuitBreakerSettings()
+	cbs.Enabled = true
+	cbs.FailureThreshold = 1
+	te, err := NewTraceExporter(fakeTraceExporterConfig, pusher, WithCircuitBreaker(cbs))
+	require.NoError(t, err)
+
+	reporter, ok := te.(CircuitBreakerStateReporter)
+	require.True(t, ok)
+	state, enabled := reporter.CircuitBreakerState()
+	assert.True(t, enabled)
+	assert.Equal(t, CircuitBreakerClosed, state)
+
+	assert.Equal(t, want, te.ConsumeTraces(context.Background(), pdata.NewTraces()))
+	state, _ = reporter.CircuitBreakerState()
+	assert.Equal(t, CircuitBreakerOpen, state)
+
+	err = te.ConsumeTraces(context.Background(), pdata.NewTraces())
+	assert.True(t, errors.Is(err, errCircuitBreakerOpen))
+	assert.Equal(t, 1, calls)
+}
+
+func TestTraceExporter_WithoutCircuitBreaker(t *testing.T) {
+	te, err := NewTraceExporter(fakeTraceExporterConfig, newTraceDataPusher(0, nil))
+	require.NoError(t, err)
+	_, enabled := te.(CircuitBreakerStateReporter).CircuitBreakerState()
+	assert.False(t, enabled)
+}
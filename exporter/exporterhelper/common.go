@@ -16,6 +16,7 @@ package exporterhelper
 
 import (
 	"context"
+	"time"
 
 	"go.opencensus.io/trace"
 )
@@ -27,9 +28,25 @@ var (
 // Shutdown specifies the function invoked when the exporter is being shutdown.
 type Shutdown func(context.Context) error
 
+// TimeoutSettings for timeout. The timeout applies to individual attempts to send data to the backend.
+type TimeoutSettings struct {
+	// Timeout is the timeout for every attempt to send data to the backend.
+	// Zero means no timeout.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// CreateDefaultTimeoutSettings returns the default settings for TimeoutSettings.
+func CreateDefaultTimeoutSettings() TimeoutSettings {
+	return TimeoutSettings{
+		Timeout: 5 * time.Second,
+	}
+}
+
 // ExporterOptions contains options concerning how an Exporter is configured.
 type ExporterOptions struct {
-	shutdown Shutdown
+	shutdown       Shutdown
+	timeout        TimeoutSettings
+	circuitBreaker CircuitBreakerSettings
 }
 
 // ExporterOption apply changes to ExporterOptions.
@@ -43,6 +60,23 @@ func WithShutdown(shutdown Shutdown) ExporterOption {
 	}
 }
 
+// WithTimeout overrides the default TimeoutSettings for an exporter.
+// Without this option the exporter has no timeout, CreateDefaultTimeoutSettings
+// returns the settings of a default 5s timeout.
+func WithTimeout(timeout TimeoutSettings) ExporterOption {
+	return func(o *ExporterOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithCircuitBreaker sets the CircuitBreakerSettings for an exporter.
+// The circuit breaker is disabled by default.
+func WithCircuitBreaker(circuitBreaker CircuitBreakerSettings) ExporterOption {
+	return func(o *ExporterOptions) {
+		o.circuitBreaker = circuitBreaker
+	}
+}
+
 // Construct the ExporterOptions from multiple ExporterOption.
 func newExporterOptions(options ...ExporterOption) ExporterOptions {
 	var opts ExporterOptions
@@ -51,3 +85,64 @@ func newExporterOptions(options ...ExporterOption) ExporterOptions {
 	}
 	return opts
 }
+
+// baseExporter contains the state shared by all the exporters.
+type baseExporter struct {
+	sender *requestSender
+}
+
+var _ CircuitBreakerStateReporter = (*baseExporter)(nil)
+
+func newBaseExporter(exporterName string, opts ExporterOptions) baseExporter {
+	return baseExporter{sender: newRequestSender(exporterName, opts)}
+}
+
+// CircuitBreakerState implements CircuitBreakerStateReporter.
+func (be *baseExporter) CircuitBreakerState() (CircuitBreakerState, bool) {
+	if be.sender == nil || be.sender.breaker == nil {
+		return CircuitBreakerClosed, false
+	}
+	return be.sender.breaker.State(), true
+}
+
+// requestSender sends the requests to the backend applying the timeout and
+// the circuit breaker.
+type requestSender struct {
+	timeout time.Duration
+	breaker *circuitBreaker
+}
+
+// newRequestSender returns the requestSender for the given options, or nil
+// when neither the timeout nor the circuit breaker are enabled.
+func newRequestSender(exporterName string, opts ExporterOptions) *requestSender {
+	rs := &requestSender{timeout: opts.timeout.Timeout}
+	if opts.circuitBreaker.Enabled {
+		rs.breaker = newCircuitBreaker(exporterName, opts.circuitBreaker)
+	}
+	if rs.timeout <= 0 && rs.breaker == nil {
+		return nil
+	}
+	return rs
+}
+
+// send invokes push, it fails fast without invoking it while the circuit
+// breaker is open.
+func (rs *requestSender) send(ctx context.Context, push func(context.Context) (int, error)) (int, error) {
+	if rs.breaker != nil {
+		if err := rs.breaker.allow(); err != nil {
+			return 0, err
+		}
+	}
+
+	if rs.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, rs.timeout)
+		defer cancel()
+	}
+
+	dropped, err := push(ctx)
+	if rs.breaker != nil {
+		rs.breaker.done(err)
+	}
+	return dropped, err
+}
@@ -14,11 +14,18 @@
 package exporterhelper
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opencensus.io/trace"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/internal/data"
 )
 
 func TestErrorToStatus(t *testing.T) {
@@ -32,3 +39,38 @@ func errToStatus(err error) trace.Status {
 	}
 	return okStatus
 }
+
+func TestTraceExporter_WithTimeout(t *testing.T) {
+	pusher := func(ctx context.Context, td pdata.Traces) (int, error) {
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		assert.True(t, time.Until(deadline) <= time.Second)
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+	te, err := NewTraceExporter(fakeTraceExporterConfig, pusher, WithTimeout(TimeoutSettings{Timeout: 10 * time.Millisecond}))
+	require.NoError(t, err)
+	assert.Equal(t, context.DeadlineExceeded, te.ConsumeTraces(context.Background(), pdata.NewTraces()))
+}
+
+func TestMetricsAndLogsExporters_WithTimeout(t *testing.T) {
+	hasDeadline := func(ctx context.Context) error {
+		if _, ok := ctx.Deadline(); !ok {
+			return errors.New("no deadline")
+		}
+		return nil
+	}
+	opt := WithTimeout(CreateDefaultTimeoutSettings())
+
+	me, err := NewMetricsExporter(fakeMetricsExporterConfig, func(ctx context.Context, _ pdata.Metrics) (int, error) {
+		return 0, hasDeadline(ctx)
+	}, opt)
+	require.NoError(t, err)
+	assert.NoError(t, me.ConsumeMetrics(context.Background(), pdatautil.MetricsFromInternalMetrics(data.NewMetricData())))
+
+	le, err := NewLogsExporter(fakeLogsExporterConfig, func(ctx context.Context, _ data.Logs) (int, error) {
+		return 0, hasDeadline(ctx)
+	}, opt)
+	require.NoError(t, err)
+	assert.NoError(t, le.ConsumeLogs(context.Background(), data.NewLogs()))
+}
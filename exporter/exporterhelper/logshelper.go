@@ -28,6 +28,7 @@ import (
 type PushLogsData func(ctx context.Context, md data.Logs) (droppedTimeSeries int, err error)
 
 type logsExporter struct {
+	baseExporter
 	exporterFullName string
 	pushLogsData     PushLogsData
 	shutdown         Shutdown
@@ -60,7 +61,11 @@ func NewLogsExporter(config configmodels.Exporter, pushLogsData PushLogsData, op
 	}
 
 	opts := newExporterOptions(options...)
+	be := newBaseExporter(config.Name(), opts)
 
+	if be.sender != nil {
+		pushLogsData = pushLogsWithSender(pushLogsData, be.sender)
+	}
 	pushLogsData = pushLogsWithObservability(pushLogsData, config.Name())
 
 	// The default shutdown method always returns nil.
@@ -69,6 +74,7 @@ func NewLogsExporter(config configmodels.Exporter, pushLogsData PushLogsData, op
 	}
 
 	return &logsExporter{
+		baseExporter:     be,
 		exporterFullName: config.Name(),
 		pushLogsData:     pushLogsData,
 		shutdown:         opts.shutdown,
@@ -86,3 +92,11 @@ func pushLogsWithObservability(next PushLogsData, exporterName string) PushLogsD
 		return numLogs, err
 	}
 }
+
+func pushLogsWithSender(next PushLogsData, rs *requestSender) PushLogsData {
+	return func(ctx context.Context, ld data.Logs) (int, error) {
+		return rs.send(ctx, func(ctx context.Context) (int, error) {
+			return next(ctx, ld)
+		})
+	}
+}
@@ -30,6 +30,7 @@ import (
 type PushMetricsDataOld func(ctx context.Context, td consumerdata.MetricsData) (droppedTimeSeries int, err error)
 
 type metricsExporterOld struct {
+	baseExporter
 	exporterFullName string
 	pushMetricsData  PushMetricsDataOld
 	shutdown         Shutdown
@@ -63,7 +64,11 @@ func NewMetricsExporterOld(config configmodels.Exporter, pushMetricsData PushMet
 	}
 
 	opts := newExporterOptions(options...)
+	be := newBaseExporter(config.Name(), opts)
 
+	if be.sender != nil {
+		pushMetricsData = pushMetricsWithSenderOld(pushMetricsData, be.sender)
+	}
 	pushMetricsData = pushMetricsWithObservabilityOld(pushMetricsData, config.Name())
 
 	// The default shutdown method always returns nil.
@@ -72,6 +77,7 @@ func NewMetricsExporterOld(config configmodels.Exporter, pushMetricsData PushMet
 	}
 
 	return &metricsExporterOld{
+		baseExporter:     be,
 		exporterFullName: config.Name(),
 		pushMetricsData:  pushMetricsData,
 		shutdown:         opts.shutdown,
@@ -93,6 +99,14 @@ func pushMetricsWithObservabilityOld(next PushMetricsDataOld, exporterName strin
 	}
 }
 
+func pushMetricsWithSenderOld(next PushMetricsDataOld, rs *requestSender) PushMetricsDataOld {
+	return func(ctx context.Context, md consumerdata.MetricsData) (int, error) {
+		return rs.send(ctx, func(ctx context.Context) (int, error) {
+			return next(ctx, md)
+		})
+	}
+}
+
 // NumTimeSeries returns the number of timeseries in a MetricsData.
 func NumTimeSeries(md consumerdata.MetricsData) int {
 	receivedTimeSeries := 0
@@ -107,6 +121,7 @@ func NumTimeSeries(md consumerdata.MetricsData) int {
 type PushMetricsData func(ctx context.Context, md pdata.Metrics) (droppedTimeSeries int, err error)
 
 type metricsExporter struct {
+	baseExporter
 	exporterFullName string
 	pushMetricsData  PushMetricsData
 	shutdown         Shutdown
@@ -140,7 +155,11 @@ func NewMetricsExporter(config configmodels.Exporter, pushMetricsData PushMetric
 	}
 
 	opts := newExporterOptions(options...)
+	be := newBaseExporter(config.Name(), opts)
 
+	if be.sender != nil {
+		pushMetricsData = pushMetricsWithSender(pushMetricsData, be.sender)
+	}
 	pushMetricsData = pushMetricsWithObservability(pushMetricsData, config.Name())
 
 	// The default shutdown method always returns nil.
@@ -149,6 +168,7 @@ func NewMetricsExporter(config configmodels.Exporter, pushMetricsData PushMetric
 	}
 
 	return &metricsExporter{
+		baseExporter:     be,
 		exporterFullName: config.Name(),
 		pushMetricsData:  pushMetricsData,
 		shutdown:         opts.shutdown,
@@ -169,3 +189,11 @@ func pushMetricsWithObservability(next PushMetricsData, exporterName string) Pus
 		return numReceivedMetrics, err
 	}
 }
+
+func pushMetricsWithSender(next PushMetricsData, rs *requestSender) PushMetricsData {
+	return func(ctx context.Context, md pdata.Metrics) (int, error) {
+		return rs.send(ctx, func(ctx context.Context) (int, error) {
+			return next(ctx, md)
+		})
+	}
+}
@@ -34,6 +34,7 @@ type traceDataPusher func(ctx context.Context, td pdata.Traces) (droppedSpans in
 
 // traceExporterOld implements the exporter with additional helper options.
 type traceExporterOld struct {
+	baseExporter
 	exporterFullName string
 	dataPusher       traceDataPusherOld
 	shutdown         Shutdown
@@ -72,7 +73,11 @@ func NewTraceExporterOld(
 	}
 
 	opts := newExporterOptions(options...)
+	be := newBaseExporter(config.Name(), opts)
 
+	if be.sender != nil {
+		dataPusher = dataPusher.withSender(be.sender)
+	}
 	dataPusher = dataPusher.withObservability(config.Name())
 
 	// The default shutdown function does nothing.
@@ -81,6 +86,7 @@ func NewTraceExporterOld(
 	}
 
 	return &traceExporterOld{
+		baseExporter:     be,
 		exporterFullName: config.Name(),
 		dataPusher:       dataPusher,
 		shutdown:         opts.shutdown,
@@ -104,7 +110,18 @@ func (p traceDataPusherOld) withObservability(exporterName string) traceDataPush
 	}
 }
 
+// withSender wraps the current pusher into a function that sends the data
+// with the timeout and circuit breaker of the sender.
+func (p traceDataPusherOld) withSender(rs *requestSender) traceDataPusherOld {
+	return func(ctx context.Context, td consumerdata.TraceData) (int, error) {
+		return rs.send(ctx, func(ctx context.Context) (int, error) {
+			return p(ctx, td)
+		})
+	}
+}
+
 type traceExporter struct {
+	baseExporter
 	exporterFullName string
 	dataPusher       traceDataPusher
 	shutdown         Shutdown
@@ -145,7 +162,11 @@ func NewTraceExporter(
 	}
 
 	opts := newExporterOptions(options...)
+	be := newBaseExporter(config.Name(), opts)
 
+	if be.sender != nil {
+		dataPusher = dataPusher.withSender(be.sender)
+	}
 	dataPusher = dataPusher.withObservability(config.Name())
 
 	// The default shutdown function does nothing.
@@ -154,6 +175,7 @@ func NewTraceExporter(
 	}
 
 	return &traceExporter{
+		baseExporter:     be,
 		exporterFullName: config.Name(),
 		dataPusher:       dataPusher,
 		shutdown:         opts.shutdown,
@@ -176,3 +198,13 @@ func (p traceDataPusher) withObservability(exporterName string) traceDataPusher
 		return droppedSpans, err
 	}
 }
+
+// withSender wraps the current pusher into a function that sends the data
+// with the timeout and circuit breaker of the sender.
+func (p traceDataPusher) withSender(rs *requestSender) traceDataPusher {
+	return func(ctx context.Context, td pdata.Traces) (int, error) {
+		return rs.send(ctx, func(ctx context.Context) (int, error) {
+			return p(ctx, td)
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"google.golang.org/grpc/metadata"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
@@ -47,7 +48,7 @@ func New(config *Config) (component.TraceExporter, error) {
 	collectorServiceClient := jaegerproto.NewCollectorServiceClient(client)
 	s := &protoGRPCSender{
 		client:       collectorServiceClient,
-		metadata:     metadata.New(config.GRPCClientSettings.Headers),
+		metadata:     metadata.New(configopaque.MapToStrings(config.GRPCClientSettings.Headers)),
 		waitForReady: config.WaitForReady,
 	}
 
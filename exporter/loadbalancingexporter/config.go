@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+)
+
+// Config defines configuration for the load balancing exporter.
+type Config struct {
+	configmodels.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+
+	// Protocol holds the configuration of the exporters created for each
+	// of the resolved backends.
+	Protocol Protocol `mapstructure:"protocol"`
+
+	// Resolver defines how the list of backends is obtained. Exactly one
+	// resolver must be configured.
+	Resolver ResolverSettings `mapstructure:"resolver"`
+}
+
+// Protocol holds the individual protocol-specific settings. Only OTLP is supported at the moment.
+type Protocol struct {
+	// OTLP is the template for the OTLP exporters, its endpoint is replaced
+	// by each of the resolved backends.
+	OTLP otlpexporter.Config `mapstructure:"otlp"`
+}
+
+// ResolverSettings defines the configurations for the backend resolver.
+type ResolverSettings struct {
+	Static *StaticResolver `mapstructure:"static"`
+	DNS    *DNSResolver    `mapstructure:"dns"`
+	File   *FileResolver   `mapstructure:"file"`
+}
+
+// StaticResolver defines the configuration for the resolver providing a fixed list of backends.
+type StaticResolver struct {
+	// Hostnames is the list of backends, in the form host:port.
+	Hostnames []string `mapstructure:"hostnames"`
+}
+
+// DNSResolver defines the configuration for the DNS resolver.
+type DNSResolver struct {
+	// Hostname is resolved periodically, each of its IP addresses is a backend.
+	Hostname string `mapstructure:"hostname"`
+
+	// Port is appended to each of the resolved IP addresses, defaults to the OTLP port.
+	Port string `mapstructure:"port"`
+
+	// Interval is the time between resolutions.
+	Interval time.Duration `mapstructure:"interval"`
+
+	// Timeout is the maximum time a single resolution can take.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// FileResolver defines the configuration for the resolver reading the backends from a file.
+type FileResolver struct {
+	// Path to a file holding one host:port backend per line. Empty lines and
+	// lines starting with '#' are ignored.
+	Path string `mapstructure:"path"`
+
+	// Interval is the time between reads of the file.
+	Interval time.Duration `mapstructure:"interval"`
+}
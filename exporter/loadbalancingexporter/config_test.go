@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := config.ExampleComponents()
+	assert.NoError(t, err)
+
+	factory := &Factory{}
+	factories.Exporters[typeStr] = factory
+	cfg, err := config.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	e0 := cfg.Exporters["loadbalancing"].(*Config)
+	assert.True(t, e0.Protocol.OTLP.TLSSetting.Insecure)
+	assert.Equal(t, &StaticResolver{Hostnames: []string{"endpoint-1:55680", "endpoint-2:55680"}}, e0.Resolver.Static)
+	assert.Nil(t, e0.Resolver.DNS)
+	assert.Nil(t, e0.Resolver.File)
+
+	e1 := cfg.Exporters["loadbalancing/2"].(*Config)
+	assert.Equal(t, "zstd", e1.Protocol.OTLP.Compression)
+	assert.Equal(t, &DNSResolver{
+		Hostname: "service-1.headless",
+		Port:     "55690",
+		Interval: 10 * time.Second,
+		Timeout:  2 * time.Second,
+	}, e1.Resolver.DNS)
+
+	e2 := cfg.Exporters["loadbalancing/3"].(*Config)
+	assert.Equal(t, &FileResolver{Path: "/etc/otel/backends", Interval: 30 * time.Second}, e2.Resolver.File)
+	// The protocol settings default to the OTLP exporter defaults.
+	assert.Equal(t, 512*1024, e2.Protocol.OTLP.WriteBufferSize)
+}
@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+)
+
+// defaultWeight is the number of positions each endpoint takes on the ring.
+// Spreading an endpoint over many positions keeps the load even and bounds
+// the share of traces that move when an endpoint is added or removed.
+const defaultWeight = 100
+
+// ringItem is a single position on the ring, owned by the given endpoint.
+type ringItem struct {
+	position uint32
+	endpoint string
+}
+
+// hashRing maps trace IDs to endpoints using consistent hashing. A hashRing
+// is immutable, membership changes are done by building a new ring.
+type hashRing struct {
+	items []ringItem
+}
+
+// newHashRing builds a ring for the given endpoints. The resulting ring
+// doesn't depend on the order of the endpoints.
+func newHashRing(endpoints []string) *hashRing {
+	items := make([]ringItem, 0, len(endpoints)*defaultWeight)
+	for _, endpoint := range endpoints {
+		for i := 0; i < defaultWeight; i++ {
+			items = append(items, ringItem{
+				position: crc32.ChecksumIEEE([]byte(endpoint + "-" + strconv.Itoa(i))),
+				endpoint: endpoint,
+			})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].position == items[j].position {
+			// Deterministic winner for the unlikely collisions.
+			return items[i].endpoint < items[j].endpoint
+		}
+		return items[i].position < items[j].position
+	})
+	return &hashRing{items: items}
+}
+
+// endpointFor returns the endpoint owning the given trace ID, that is the
+// endpoint of the first position at or after the hash of the trace ID.
+// Returns an empty string if the ring has no endpoints.
+func (h *hashRing) endpointFor(traceID []byte) string {
+	if len(h.items) == 0 {
+		return ""
+	}
+	hash := crc32.ChecksumIEEE(traceID)
+	i := sort.Search(len(h.items), func(i int) bool {
+		return h.items[i].position >= hash
+	})
+	if i == len(h.items) {
+		// Past the last position, wrap around.
+		i = 0
+	}
+	return h.items[i].endpoint
+}
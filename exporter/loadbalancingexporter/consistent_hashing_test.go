@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func traceIDFromInt(i uint64) []byte {
+	traceID := make([]byte, 16)
+	binary.BigEndian.PutUint64(traceID[8:], i)
+	return traceID
+}
+
+func TestEmptyRing(t *testing.T) {
+	ring := newHashRing(nil)
+	assert.Equal(t, "", ring.endpointFor(traceIDFromInt(1)))
+}
+
+func TestRingIsOrderIndependent(t *testing.T) {
+	ring1 := newHashRing([]string{"endpoint-1", "endpoint-2", "endpoint-3"})
+	ring2 := newHashRing([]string{"endpoint-3", "endpoint-1", "endpoint-2"})
+	for i := uint64(0); i < 1000; i++ {
+		assert.Equal(t, ring1.endpointFor(traceIDFromInt(i)), ring2.endpointFor(traceIDFromInt(i)))
+	}
+}
+
+func TestRingDistribution(t *testing.T) {
+	endpoints := []string{"endpoint-1", "endpoint-2", "endpoint-3", "endpoint-4"}
+	ring := newHashRing(endpoints)
+
+	const numTraces = 100000
+	counts := make(map[string]int)
+	for i := uint64(0); i < numTraces; i++ {
+		counts[ring.endpointFor(traceIDFromInt(i))]++
+	}
+
+	assert.Len(t, counts, len(endpoints))
+	for _, endpoint := range endpoints {
+		// Each endpoint is expected to own roughly a quarter of the traces.
+		assert.InDelta(t, numTraces/len(endpoints), counts[endpoint], numTraces/10, endpoint)
+	}
+}
+
+func TestRingMinimalReshuffling(t *testing.T) {
+	before := newHashRing([]string{"endpoint-1", "endpoint-2", "endpoint-3"})
+	after := newHashRing([]string{"endpoint-1", "endpoint-2", "endpoint-3", "endpoint-4"})
+
+	const numTraces = 100000
+	moved := 0
+	for i := uint64(0); i < numTraces; i++ {
+		traceID := traceIDFromInt(i)
+		newEndpoint := after.endpointFor(traceID)
+		if before.endpointFor(traceID) != newEndpoint {
+			// Traces only move to the added endpoint.
+			assert.Equal(t, "endpoint-4", newEndpoint)
+			moved++
+		}
+	}
+	// Ideally a quarter of the traces move to the new endpoint.
+	assert.InDelta(t, numTraces/4, moved, numTraces/10)
+}
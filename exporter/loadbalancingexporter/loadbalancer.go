@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+)
+
+var (
+	errNoResolver                = errors.New("no resolvers specified for the exporter")
+	errMultipleResolversProvided = errors.New("only one resolver should be specified")
+)
+
+// componentFactory creates the exporter sending data to the given endpoint.
+type componentFactory func(ctx context.Context, endpoint string) (component.TraceExporter, error)
+
+// loadBalancer keeps one exporter per resolved backend and the ring used to
+// pick the backend of each trace, both are replaced when the backends change.
+type loadBalancer struct {
+	logger           *zap.Logger
+	host             component.Host
+	res              resolver
+	componentFactory componentFactory
+
+	// updateLock protects the ring and the exporters, it is held for reading
+	// while data is being exported so that exporters of removed backends are
+	// only shut down once they are no longer in use.
+	updateLock sync.RWMutex
+	ring       *hashRing
+	exporters  map[string]component.TraceExporter
+}
+
+func newLoadBalancer(logger *zap.Logger, cfg *Config, factory componentFactory) (*loadBalancer, error) {
+	res, err := newResolver(logger, &cfg.Resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	return &loadBalancer{
+		logger:           logger,
+		res:              res,
+		componentFactory: factory,
+		ring:             newHashRing(nil),
+		exporters:        make(map[string]component.TraceExporter),
+	}, nil
+}
+
+// newResolver creates the single resolver configured in the settings.
+func newResolver(logger *zap.Logger, cfg *ResolverSettings) (resolver, error) {
+	count := 0
+	for _, configured := range []bool{cfg.Static != nil, cfg.DNS != nil, cfg.File != nil} {
+		if configured {
+			count++
+		}
+	}
+	switch {
+	case count == 0:
+		return nil, errNoResolver
+	case count > 1:
+		return nil, errMultipleResolversProvided
+	case cfg.Static != nil:
+		return newStaticResolver(cfg.Static.Hostnames)
+	case cfg.DNS != nil:
+		return newDNSResolver(logger, cfg.DNS)
+	default:
+		return newFileResolver(logger, cfg.File)
+	}
+}
+
+// Start registers for the backend changes and starts the resolver, which
+// results in the initial set of exporters being created.
+func (lb *loadBalancer) Start(ctx context.Context, host component.Host) error {
+	lb.host = host
+	lb.res.onChange(lb.onBackendChanges)
+	return lb.res.start(ctx)
+}
+
+// Shutdown stops the resolver and all the exporters.
+func (lb *loadBalancer) Shutdown(ctx context.Context) error {
+	errs := []error{}
+	if err := lb.res.shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	lb.updateLock.Lock()
+	defer lb.updateLock.Unlock()
+	for endpoint, exp := range lb.exporters {
+		if err := exp.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+		delete(lb.exporters, endpoint)
+	}
+	lb.ring = newHashRing(nil)
+
+	return componenterror.CombineErrors(errs)
+}
+
+// onBackendChanges creates the exporters for the new backends, builds the
+// new ring and then shuts down the exporters of the removed backends.
+func (lb *loadBalancer) onBackendChanges(resolved []string) {
+	ctx := context.Background()
+
+	lb.updateLock.Lock()
+
+	exporters := make(map[string]component.TraceExporter, len(resolved))
+	for _, endpoint := range resolved {
+		if exp, ok := lb.exporters[endpoint]; ok {
+			exporters[endpoint] = exp
+			delete(lb.exporters, endpoint)
+			continue
+		}
+
+		exp, err := lb.componentFactory(ctx, endpoint)
+		if err == nil {
+			err = exp.Start(ctx, lb.host)
+		}
+		if err != nil {
+			// The backend is left out of the ring, it is retried on the next change.
+			lb.logger.Error("failed to create the exporter for the backend", zap.String("endpoint", endpoint), zap.Error(err))
+			continue
+		}
+		exporters[endpoint] = exp
+	}
+
+	endpoints := make([]string, 0, len(exporters))
+	for endpoint := range exporters {
+		endpoints = append(endpoints, endpoint)
+	}
+
+	removed := lb.exporters
+	lb.exporters = exporters
+	lb.ring = newHashRing(endpoints)
+
+	lb.updateLock.Unlock()
+
+	for endpoint, exp := range removed {
+		if err := exp.Shutdown(ctx); err != nil {
+			lb.logger.Warn("failed to shutdown the exporter of a removed backend", zap.String("endpoint", endpoint), zap.Error(err))
+		}
+	}
+
+	lb.logger.Info("backends updated", zap.Strings("endpoints", normalizeEndpoints(endpoints)))
+}
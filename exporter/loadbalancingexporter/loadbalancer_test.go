@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+)
+
+func TestNewLoadBalancerResolvers(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolver ResolverSettings
+		err      error
+	}{
+		{
+			name: "NoResolver",
+			err:  errNoResolver,
+		},
+		{
+			name: "MultipleResolvers",
+			resolver: ResolverSettings{
+				Static: &StaticResolver{Hostnames: []string{"endpoint-1"}},
+				DNS:    &DNSResolver{Hostname: "service-1"},
+			},
+			err: errMultipleResolversProvided,
+		},
+		{
+			name:     "Static",
+			resolver: ResolverSettings{Static: &StaticResolver{Hostnames: []string{"endpoint-1"}}},
+		},
+		{
+			name:     "DNS",
+			resolver: ResolverSettings{DNS: &DNSResolver{Hostname: "service-1"}},
+		},
+		{
+			name:     "File",
+			resolver: ResolverSettings{File: &FileResolver{Path: "/etc/otel/backends"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lb, err := newLoadBalancer(zap.NewNop(), &Config{Resolver: tt.resolver}, nil)
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				assert.Nil(t, lb)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, lb)
+			}
+		})
+	}
+}
+
+func TestLoadBalancerBackendChanges(t *testing.T) {
+	created := make(map[string]*exportertest.SinkTraceExporter)
+	factory := func(_ context.Context, endpoint string) (component.TraceExporter, error) {
+		if endpoint == "broken" {
+			return nil, errors.New("cannot create exporter")
+		}
+		exp := &exportertest.SinkTraceExporter{}
+		created[endpoint] = exp
+		return exp, nil
+	}
+	cfg := &Config{Resolver: ResolverSettings{Static: &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2"}}}}
+	lb, err := newLoadBalancer(zap.NewNop(), cfg, factory)
+	require.NoError(t, err)
+
+	require.NoError(t, lb.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Len(t, lb.exporters, 2)
+	endpoint1 := lb.exporters["endpoint-1"]
+
+	lb.onBackendChanges([]string{"endpoint-1", "endpoint-3", "broken"})
+	assert.Len(t, lb.exporters, 2)
+	// Exporters of the remaining backends are kept.
+	assert.Same(t, endpoint1, lb.exporters["endpoint-1"])
+	assert.Contains(t, lb.exporters, "endpoint-3")
+	assert.NotContains(t, lb.exporters, "broken")
+	for i := uint64(0); i < 100; i++ {
+		assert.NotEqual(t, "endpoint-2", lb.ring.endpointFor(traceIDFromInt(i)))
+		assert.NotEqual(t, "broken", lb.ring.endpointFor(traceIDFromInt(i)))
+	}
+
+	require.NoError(t, lb.Shutdown(context.Background()))
+	assert.Len(t, lb.exporters, 0)
+	assert.Len(t, created, 3)
+}
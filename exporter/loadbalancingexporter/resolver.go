@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// resolver provides the current list of backends and notifies the
+// registered callbacks whenever the list changes.
+type resolver interface {
+	// start resolves the backends for the first time and starts watching for changes.
+	start(ctx context.Context) error
+
+	// shutdown stops watching for changes.
+	shutdown(ctx context.Context) error
+
+	// resolve returns the current list of backends, notifying the callbacks if it changed.
+	resolve(ctx context.Context) ([]string, error)
+
+	// onChange registers a callback invoked with the new list of backends
+	// every time the list changes.
+	onChange(func([]string))
+}
+
+// endpointsTracker keeps the last known list of backends and notifies the
+// callbacks on changes. It is embedded by the resolver implementations.
+type endpointsTracker struct {
+	mu        sync.Mutex
+	endpoints []string
+	callbacks []func([]string)
+}
+
+func (t *endpointsTracker) onChange(f func([]string)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.callbacks = append(t.callbacks, f)
+}
+
+// update records the given backends, calling the callbacks if they are
+// different from the last known ones. Returns the normalized list.
+func (t *endpointsTracker) update(endpoints []string) []string {
+	endpoints = normalizeEndpoints(endpoints)
+
+	t.mu.Lock()
+	if equalEndpoints(t.endpoints, endpoints) {
+		t.mu.Unlock()
+		return endpoints
+	}
+	t.endpoints = endpoints
+	callbacks := make([]func([]string), len(t.callbacks))
+	copy(callbacks, t.callbacks)
+	t.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(endpoints)
+	}
+	return endpoints
+}
+
+// normalizeEndpoints returns a sorted copy of the endpoints without duplicates.
+func normalizeEndpoints(endpoints []string) []string {
+	seen := make(map[string]bool, len(endpoints))
+	normalized := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if endpoint == "" || seen[endpoint] {
+			continue
+		}
+		seen[endpoint] = true
+		normalized = append(normalized, endpoint)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+func equalEndpoints(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
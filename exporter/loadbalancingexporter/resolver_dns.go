@@ -0,0 +1,132 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPort        = "55680"
+	defaultResInterval = 5 * time.Second
+	defaultResTimeout  = time.Second
+)
+
+var errNoHostname = errors.New("no hostname specified for the DNS resolver")
+
+// netResolver is the subset of net.Resolver used, replaceable in tests.
+type netResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// dnsResolver periodically resolves a hostname, each of the IP addresses
+// it resolves to is a backend.
+type dnsResolver struct {
+	endpointsTracker
+	logger *zap.Logger
+
+	hostname    string
+	port        string
+	resolver    netResolver
+	resInterval time.Duration
+	resTimeout  time.Duration
+
+	stopCh     chan struct{}
+	shutdownWg sync.WaitGroup
+}
+
+var _ resolver = (*dnsResolver)(nil)
+
+func newDNSResolver(logger *zap.Logger, cfg *DNSResolver) (*dnsResolver, error) {
+	if cfg.Hostname == "" {
+		return nil, errNoHostname
+	}
+	r := &dnsResolver{
+		logger:      logger,
+		hostname:    cfg.Hostname,
+		port:        cfg.Port,
+		resolver:    net.DefaultResolver,
+		resInterval: cfg.Interval,
+		resTimeout:  cfg.Timeout,
+		stopCh:      make(chan struct{}),
+	}
+	if r.port == "" {
+		r.port = defaultPort
+	}
+	if r.resInterval <= 0 {
+		r.resInterval = defaultResInterval
+	}
+	if r.resTimeout <= 0 {
+		r.resTimeout = defaultResTimeout
+	}
+	return r, nil
+}
+
+func (r *dnsResolver) start(ctx context.Context) error {
+	if _, err := r.resolve(ctx); err != nil {
+		// The name may become resolvable later on, keep trying in the background.
+		r.logger.Warn("failed to resolve the backends, will retry", zap.String("hostname", r.hostname), zap.Error(err))
+	}
+
+	r.shutdownWg.Add(1)
+	go r.periodicallyResolve()
+
+	return nil
+}
+
+func (r *dnsResolver) shutdown(context.Context) error {
+	close(r.stopCh)
+	r.shutdownWg.Wait()
+	return nil
+}
+
+func (r *dnsResolver) periodicallyResolve() {
+	defer r.shutdownWg.Done()
+
+	ticker := time.NewTicker(r.resInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), r.resTimeout)
+			if _, err := r.resolve(ctx); err != nil {
+				r.logger.Warn("failed to resolve the backends", zap.String("hostname", r.hostname), zap.Error(err))
+			}
+			cancel()
+		case <-r.stopCh:
+			return
+		}
+	}
+}
+
+func (r *dnsResolver) resolve(ctx context.Context) ([]string, error) {
+	addrs, err := r.resolver.LookupIPAddr(ctx, r.hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		endpoints = append(endpoints, net.JoinHostPort(addr.IP.String(), r.port))
+	}
+	return r.update(endpoints), nil
+}
@@ -0,0 +1,116 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/testutil"
+)
+
+type mockNetResolver struct {
+	mu    sync.Mutex
+	addrs []net.IPAddr
+	err   error
+}
+
+func (m *mockNetResolver) LookupIPAddr(context.Context, string) ([]net.IPAddr, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.addrs, m.err
+}
+
+func (m *mockNetResolver) set(addrs []net.IPAddr, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.addrs = addrs
+	m.err = err
+}
+
+func TestDNSResolverDefaults(t *testing.T) {
+	res, err := newDNSResolver(zap.NewNop(), &DNSResolver{Hostname: "service-1"})
+	require.NoError(t, err)
+	assert.Equal(t, defaultPort, res.port)
+	assert.Equal(t, defaultResInterval, res.resInterval)
+	assert.Equal(t, defaultResTimeout, res.resTimeout)
+}
+
+func TestDNSResolverNoHostname(t *testing.T) {
+	res, err := newDNSResolver(zap.NewNop(), &DNSResolver{})
+	assert.Equal(t, errNoHostname, err)
+	assert.Nil(t, res)
+}
+
+func TestDNSResolverPeriodicallyResolves(t *testing.T) {
+	res, err := newDNSResolver(zap.NewNop(), &DNSResolver{
+		Hostname: "service-1",
+		Port:     "55690",
+		Interval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	netRes := &mockNetResolver{}
+	netRes.set([]net.IPAddr{{IP: net.IPv4(127, 0, 0, 2)}, {IP: net.IPv4(127, 0, 0, 1)}}, nil)
+	res.resolver = netRes
+
+	var mu sync.Mutex
+	var resolved []string
+	res.onChange(func(endpoints []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		resolved = endpoints
+	})
+	getResolved := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return resolved
+	}
+
+	require.NoError(t, res.start(context.Background()))
+	defer func() {
+		assert.NoError(t, res.shutdown(context.Background()))
+	}()
+	assert.Equal(t, []string{"127.0.0.1:55690", "127.0.0.2:55690"}, getResolved())
+
+	// Failures keep the last known backends.
+	netRes.set(nil, errors.New("temporary failure"))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, []string{"127.0.0.1:55690", "127.0.0.2:55690"}, getResolved())
+
+	netRes.set([]net.IPAddr{{IP: net.IPv4(127, 0, 0, 1)}, {IP: net.ParseIP("::1")}}, nil)
+	testutil.WaitFor(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"127.0.0.1:55690", "[::1]:55690"}, getResolved())
+	}, "backends were not updated")
+}
+
+func TestDNSResolverStartsOnFailure(t *testing.T) {
+	res, err := newDNSResolver(zap.NewNop(), &DNSResolver{Hostname: "service-1"})
+	require.NoError(t, err)
+	netRes := &mockNetResolver{}
+	netRes.set(nil, errors.New("no such host"))
+	res.resolver = netRes
+
+	assert.NoError(t, res.start(context.Background()))
+	assert.NoError(t, res.shutdown(context.Background()))
+}
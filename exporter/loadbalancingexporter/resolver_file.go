@@ -0,0 +1,118 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var errNoPath = errors.New("no path specified for the file resolver")
+
+// fileResolver periodically reads the list of backends from a file, so
+// that it can be maintained by an external process, e.g. a config map.
+type fileResolver struct {
+	endpointsTracker
+	logger *zap.Logger
+
+	path        string
+	resInterval time.Duration
+
+	stopCh     chan struct{}
+	shutdownWg sync.WaitGroup
+}
+
+var _ resolver = (*fileResolver)(nil)
+
+func newFileResolver(logger *zap.Logger, cfg *FileResolver) (*fileResolver, error) {
+	if cfg.Path == "" {
+		return nil, errNoPath
+	}
+	r := &fileResolver{
+		logger:      logger,
+		path:        cfg.Path,
+		resInterval: cfg.Interval,
+		stopCh:      make(chan struct{}),
+	}
+	if r.resInterval <= 0 {
+		r.resInterval = defaultResInterval
+	}
+	return r, nil
+}
+
+func (r *fileResolver) start(ctx context.Context) error {
+	// Contrary to DNS, a missing file is most likely a configuration error.
+	if _, err := r.resolve(ctx); err != nil {
+		return err
+	}
+
+	r.shutdownWg.Add(1)
+	go r.periodicallyResolve()
+
+	return nil
+}
+
+func (r *fileResolver) shutdown(context.Context) error {
+	close(r.stopCh)
+	r.shutdownWg.Wait()
+	return nil
+}
+
+func (r *fileResolver) periodicallyResolve() {
+	defer r.shutdownWg.Done()
+
+	ticker := time.NewTicker(r.resInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := r.resolve(context.Background()); err != nil {
+				r.logger.Warn("failed to read the backends", zap.String("path", r.path), zap.Error(err))
+			}
+		case <-r.stopCh:
+			return
+		}
+	}
+}
+
+func (r *fileResolver) resolve(context.Context) ([]string, error) {
+	content, err := ioutil.ReadFile(r.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		endpoints = append(endpoints, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return r.update(endpoints), nil
+}
@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/testutil"
+)
+
+func TestFileResolver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backends")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "backends")
+	require.NoError(t, ioutil.WriteFile(path, []byte("# tail sampling collectors\nendpoint-2:55680\n\n  endpoint-1:55680  \n"), 0600))
+
+	res, err := newFileResolver(zap.NewNop(), &FileResolver{Path: path, Interval: 10 * time.Millisecond})
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var resolved []string
+	res.onChange(func(endpoints []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		resolved = endpoints
+	})
+	getResolved := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return resolved
+	}
+
+	require.NoError(t, res.start(context.Background()))
+	defer func() {
+		assert.NoError(t, res.shutdown(context.Background()))
+	}()
+	assert.Equal(t, []string{"endpoint-1:55680", "endpoint-2:55680"}, getResolved())
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("endpoint-3:55680\n"), 0600))
+	testutil.WaitFor(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"endpoint-3:55680"}, getResolved())
+	}, "backends were not updated")
+}
+
+func TestFileResolverMissingFile(t *testing.T) {
+	res, err := newFileResolver(zap.NewNop(), &FileResolver{Path: "/doesnt/exist"})
+	require.NoError(t, err)
+	assert.Error(t, res.start(context.Background()))
+}
+
+func TestFileResolverNoPath(t *testing.T) {
+	res, err := newFileResolver(zap.NewNop(), &FileResolver{})
+	assert.Equal(t, errNoPath, err)
+	assert.Nil(t, res)
+}
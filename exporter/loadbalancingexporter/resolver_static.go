@@ -0,0 +1,50 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+)
+
+var errNoEndpoints = errors.New("no endpoints specified for the static resolver")
+
+// staticResolver provides a fixed list of backends.
+type staticResolver struct {
+	endpointsTracker
+	hostnames []string
+}
+
+var _ resolver = (*staticResolver)(nil)
+
+func newStaticResolver(hostnames []string) (*staticResolver, error) {
+	if len(hostnames) == 0 {
+		return nil, errNoEndpoints
+	}
+	return &staticResolver{hostnames: hostnames}, nil
+}
+
+func (r *staticResolver) start(ctx context.Context) error {
+	_, err := r.resolve(ctx)
+	return err
+}
+
+func (r *staticResolver) shutdown(context.Context) error {
+	return nil
+}
+
+func (r *staticResolver) resolve(context.Context) ([]string, error) {
+	return r.update(r.hostnames), nil
+}
@@ -0,0 +1,163 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+)
+
+var errNoBackends = errors.New("no backends available to export the traces")
+
+// traceExporterImp embeds the exporter built by exporterhelper, which
+// provides the observability, and starts the load balancer with it.
+type traceExporterImp struct {
+	component.TraceExporter
+	loadBalancer *loadBalancer
+}
+
+var _ component.TraceExporter = (*traceExporterImp)(nil)
+
+// newTraceExporter creates an exporter routing the spans of each trace to
+// one of the backends, based on the trace ID.
+func newTraceExporter(params component.ExporterCreateParams, cfg *Config) (*traceExporterImp, error) {
+	otlpFactory := &otlpexporter.Factory{}
+	lb, err := newLoadBalancer(params.Logger, cfg, func(ctx context.Context, endpoint string) (component.TraceExporter, error) {
+		oCfg := buildExporterConfig(cfg, endpoint)
+		return otlpFactory.CreateTraceExporter(ctx, params, &oCfg)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	exp := &traceExporterImp{loadBalancer: lb}
+	exp.TraceExporter, err = exporterhelper.NewTraceExporter(
+		cfg,
+		exp.pushTraceData,
+		exporterhelper.WithShutdown(lb.Shutdown))
+	if err != nil {
+		return nil, err
+	}
+	return exp, nil
+}
+
+// buildExporterConfig returns a copy of the OTLP template sending to the given endpoint.
+func buildExporterConfig(cfg *Config, endpoint string) otlpexporter.Config {
+	oCfg := cfg.Protocol.OTLP
+	oCfg.TypeVal = "otlp"
+	oCfg.NameVal = fmt.Sprintf("%s/%s", cfg.Name(), endpoint)
+	oCfg.Endpoint = endpoint
+	return oCfg
+}
+
+// Start starts the load balancer, which resolves the backends.
+func (e *traceExporterImp) Start(ctx context.Context, host component.Host) error {
+	return e.loadBalancer.Start(ctx, host)
+}
+
+func (e *traceExporterImp) pushTraceData(ctx context.Context, td pdata.Traces) (int, error) {
+	lb := e.loadBalancer
+	lb.updateLock.RLock()
+	defer lb.updateLock.RUnlock()
+
+	if len(lb.exporters) == 0 {
+		return td.SpanCount(), errNoBackends
+	}
+
+	droppedSpans := 0
+	errs := []error{}
+	for endpoint, batch := range splitByBackend(lb.ring, td) {
+		if err := lb.exporters[endpoint].ConsumeTraces(ctx, batch); err != nil {
+			droppedSpans += batch.SpanCount()
+			errs = append(errs, err)
+		}
+	}
+	return droppedSpans, componenterror.CombineErrors(errs)
+}
+
+// splitByBackend regroups the spans per backend, each backend receives the
+// spans of its traces under copies of their original resource and
+// instrumentation library.
+func splitByBackend(ring *hashRing, td pdata.Traces) map[string]pdata.Traces {
+	batches := make(map[string]pdata.Traces)
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+
+		destRSs := make(map[string]pdata.ResourceSpans)
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
+			}
+
+			destILSs := make(map[string]pdata.InstrumentationLibrarySpans)
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
+				}
+
+				endpoint := ring.endpointFor(span.TraceID().Bytes())
+				destILS, ok := destILSs[endpoint]
+				if !ok {
+					destRS, ok := destRSs[endpoint]
+					if !ok {
+						batch, ok := batches[endpoint]
+						if !ok {
+							batch = pdata.NewTraces()
+							batches[endpoint] = batch
+						}
+						destRS = appendResourceSpans(batch.ResourceSpans())
+						rs.Resource().CopyTo(destRS.Resource())
+						destRSs[endpoint] = destRS
+					}
+					destILS = appendInstrumentationLibrarySpans(destRS.InstrumentationLibrarySpans())
+					ils.InstrumentationLibrary().CopyTo(destILS.InstrumentationLibrary())
+					destILSs[endpoint] = destILS
+				}
+
+				destSpans := destILS.Spans()
+				destSpans.Resize(destSpans.Len() + 1)
+				span.CopyTo(destSpans.At(destSpans.Len() - 1))
+			}
+		}
+	}
+
+	return batches
+}
+
+func appendResourceSpans(rss pdata.ResourceSpansSlice) pdata.ResourceSpans {
+	rss.Resize(rss.Len() + 1)
+	return rss.At(rss.Len() - 1)
+}
+
+func appendInstrumentationLibrarySpans(ilss pdata.InstrumentationLibrarySpansSlice) pdata.InstrumentationLibrarySpans {
+	ilss.Resize(ilss.Len() + 1)
+	return ilss.At(ilss.Len() - 1)
+}
@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+)
+
+func TestBuildExporterConfig(t *testing.T) {
+	cfg := (&Factory{}).CreateDefaultConfig().(*Config)
+	cfg.Protocol.OTLP.Compression = "zstd"
+
+	oCfg := buildExporterConfig(cfg, "endpoint-1:55680")
+	assert.Equal(t, "endpoint-1:55680", oCfg.Endpoint)
+	assert.Equal(t, "zstd", oCfg.Compression)
+	assert.Equal(t, "loadbalancing/endpoint-1:55680", oCfg.Name())
+	// The template is left untouched.
+	assert.Equal(t, "", cfg.Protocol.OTLP.Endpoint)
+}
+
+func TestSplitByBackend(t *testing.T) {
+	ring := newHashRing([]string{"endpoint-1", "endpoint-2", "endpoint-3"})
+
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(2)
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		rs.Resource().InitEmpty()
+		rs.Resource().Attributes().InsertInt("resource", int64(i))
+		rs.InstrumentationLibrarySpans().Resize(1)
+		ils := rs.InstrumentationLibrarySpans().At(0)
+		ils.InstrumentationLibrary().InitEmpty()
+		ils.InstrumentationLibrary().SetName("library")
+		ils.Spans().Resize(50)
+		for j := 0; j < ils.Spans().Len(); j++ {
+			// Both resources hold spans of the same traces.
+			ils.Spans().At(j).SetTraceID(traceIDFromInt(uint64(j)))
+			ils.Spans().At(j).SetName("span")
+		}
+	}
+
+	batches := splitByBackend(ring, td)
+	assert.Len(t, batches, 3)
+
+	total := 0
+	for endpoint, batch := range batches {
+		total += batch.SpanCount()
+		assert.Equal(t, 2, batch.ResourceSpans().Len())
+		for i := 0; i < batch.ResourceSpans().Len(); i++ {
+			rs := batch.ResourceSpans().At(i)
+			attr, ok := rs.Resource().Attributes().Get("resource")
+			require.True(t, ok)
+			assert.Equal(t, int64(i), attr.IntVal())
+			require.Equal(t, 1, rs.InstrumentationLibrarySpans().Len())
+			ils := rs.InstrumentationLibrarySpans().At(0)
+			assert.Equal(t, "library", ils.InstrumentationLibrary().Name())
+			for j := 0; j < ils.Spans().Len(); j++ {
+				assert.Equal(t, endpoint, ring.endpointFor(ils.Spans().At(j).TraceID().Bytes()))
+				assert.Equal(t, "span", ils.Spans().At(j).Name())
+			}
+		}
+	}
+	assert.Equal(t, td.SpanCount(), total)
+}
+
+func TestTraceExporterConsumeTraces(t *testing.T) {
+	cfg := (&Factory{}).CreateDefaultConfig().(*Config)
+	cfg.Resolver.Static = &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2"}}
+
+	exp, err := newTraceExporter(component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+
+	sinks := make(map[string]*exportertest.SinkTraceExporter)
+	exp.loadBalancer.componentFactory = func(_ context.Context, endpoint string) (component.TraceExporter, error) {
+		sink := &exportertest.SinkTraceExporter{}
+		sinks[endpoint] = sink
+		return sink, nil
+	}
+
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	ils := td.ResourceSpans().At(0).InstrumentationLibrarySpans()
+	ils.Resize(1)
+	ils.At(0).Spans().Resize(100)
+	for i := 0; i < 100; i++ {
+		// Two spans per trace.
+		ils.At(0).Spans().At(i).SetTraceID(traceIDFromInt(uint64(i / 2)))
+	}
+
+	// No backends before the exporter is started.
+	assert.Equal(t, errNoBackends, exp.ConsumeTraces(context.Background(), td))
+
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, exp.ConsumeTraces(context.Background(), td))
+
+	require.Len(t, sinks, 2)
+	total := 0
+	seen := make(map[string]string)
+	for endpoint, sink := range sinks {
+		for _, batch := range sink.AllTraces() {
+			total += batch.SpanCount()
+			spans := batch.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+			for i := 0; i < spans.Len(); i++ {
+				traceID := spans.At(i).TraceID().String()
+				if previous, ok := seen[traceID]; ok {
+					assert.Equal(t, previous, endpoint, "spans of a trace sent to different backends")
+				}
+				seen[traceID] = endpoint
+			}
+		}
+	}
+	assert.Equal(t, 100, total)
+	assert.Len(t, seen, 50)
+
+	sinks["endpoint-1"].SetConsumeTraceError(errors.New("backend failure"))
+	assert.Error(t, exp.ConsumeTraces(context.Background(), td))
+
+	assert.NoError(t, exp.Shutdown(context.Background()))
+}
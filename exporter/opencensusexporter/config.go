@@ -19,6 +19,7 @@ import (
 
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
 
 // Config defines configuration for OpenCensus exporter.
@@ -27,6 +28,11 @@ type Config struct {
 
 	configgrpc.GRPCClientSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
 
+	exporterhelper.TimeoutSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+
+	// CircuitBreaker configures the circuit breaker failing fast while the backend is unavailable.
+	CircuitBreaker exporterhelper.CircuitBreakerSettings `mapstructure:"circuit_breaker"`
+
 	// The number of workers that send the gRPC requests.
 	NumWorkers int `mapstructure:"num_workers"`
 
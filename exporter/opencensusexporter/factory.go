@@ -20,12 +20,15 @@ import (
 	"contrib.go.opencensus.io/exporter/ocagent"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
 
 const (
@@ -50,10 +53,12 @@ func (f *Factory) CreateDefaultConfig() configmodels.Exporter {
 			NameVal: typeStr,
 		},
 		GRPCClientSettings: configgrpc.GRPCClientSettings{
-			Headers: map[string]string{},
+			Headers: map[string]configopaque.String{},
 			// We almost read 0 bytes, so no need to tune ReadBufferSize.
 			WriteBufferSize: 512 * 1024,
 		},
+		TimeoutSettings: exporterhelper.CreateDefaultTimeoutSettings(),
+		CircuitBreaker:  exporterhelper.CreateDefaultCircuitBreakerSettings(),
 	}
 }
 
@@ -107,7 +112,13 @@ func (f *Factory) OCAgentOptions(logger *zap.Logger, ocac *Config) ([]ocagent.Ex
 		opts = append(opts, ocagent.WithInsecure())
 	}
 	if len(ocac.Headers) > 0 {
-		opts = append(opts, ocagent.WithHeaders(ocac.Headers))
+		opts = append(opts, ocagent.WithHeaders(configopaque.MapToStrings(ocac.Headers)))
+	}
+	if ocac.BalancerName != "" {
+		if balancer.Get(ocac.BalancerName) == nil {
+			return nil, fmt.Errorf("OpenCensus exporter invalid balancer_name: %s", ocac.BalancerName)
+		}
+		opts = append(opts, ocagent.WithGRPCDialOption(grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"loadBalancingPolicy":%q}`, ocac.BalancerName))))
 	}
 	if ocac.ReconnectionDelay > 0 {
 		opts = append(opts, ocagent.WithReconnectionPeriod(ocac.ReconnectionDelay))
@@ -26,6 +26,7 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configcheck"
 	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/receiver/opencensusreceiver"
@@ -130,7 +131,7 @@ func TestCreateTraceExporter(t *testing.T) {
 			config: Config{
 				GRPCClientSettings: configgrpc.GRPCClientSettings{
 					Endpoint: rcvCfg.NetAddr.Endpoint,
-					Headers: map[string]string{
+					Headers: map[string]configopaque.String{
 						"hdr1": "val1",
 						"hdr2": "val2",
 					},
@@ -146,6 +147,26 @@ func TestCreateTraceExporter(t *testing.T) {
 				NumWorkers: 3,
 			},
 		},
+		{
+			name: "BalancerName",
+			config: Config{
+				GRPCClientSettings: configgrpc.GRPCClientSettings{
+					Endpoint:     rcvCfg.NetAddr.Endpoint,
+					Compression:  configgrpc.CompressionZstd,
+					BalancerName: "round_robin",
+				},
+			},
+		},
+		{
+			name: "BalancerNameError",
+			config: Config{
+				GRPCClientSettings: configgrpc.GRPCClientSettings{
+					Endpoint:     rcvCfg.NetAddr.Endpoint,
+					BalancerName: "unknown balancer",
+				},
+			},
+			mustFail: true,
+		},
 		{
 			name: "CompressionError",
 			config: Config{
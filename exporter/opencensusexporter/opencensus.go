@@ -32,7 +32,11 @@ import (
 )
 
 type ocAgentExporter struct {
+	logger    *zap.Logger
 	exporters chan *ocagent.Exporter
+	// newExporter creates the exporter replacing a worker stuck on an export
+	// that timed out.
+	newExporter func() (*ocagent.Exporter, error)
 }
 
 type ocExporterErrorCode int
@@ -67,10 +71,13 @@ func NewTraceExporter(logger *zap.Logger, config configmodels.Exporter, opts ...
 	if err != nil {
 		return nil, err
 	}
+	oCfg := config.(*Config)
 	oexp, err := exporterhelper.NewTraceExporterOld(
 		config,
 		oce.PushTraceData,
-		exporterhelper.WithShutdown(oce.Shutdown))
+		exporterhelper.WithShutdown(oce.Shutdown),
+		exporterhelper.WithTimeout(oCfg.TimeoutSettings),
+		exporterhelper.WithCircuitBreaker(oCfg.CircuitBreaker))
 	if err != nil {
 		return nil, err
 	}
@@ -98,7 +105,13 @@ func createOCAgentExporter(logger *zap.Logger, config configmodels.Exporter, opt
 		}
 		exportersChan <- exporter
 	}
-	oce := &ocAgentExporter{exporters: exportersChan}
+	oce := &ocAgentExporter{
+		logger:    logger,
+		exporters: exportersChan,
+		newExporter: func() (*ocagent.Exporter, error) {
+			return ocagent.NewExporter(opts...)
+		},
+	}
 	return oce, nil
 }
 
@@ -108,10 +121,13 @@ func NewMetricsExporter(logger *zap.Logger, config configmodels.Exporter, opts .
 	if err != nil {
 		return nil, err
 	}
+	oCfg := config.(*Config)
 	oexp, err := exporterhelper.NewMetricsExporterOld(
 		config,
 		oce.PushMetricsData,
-		exporterhelper.WithShutdown(oce.Shutdown))
+		exporterhelper.WithShutdown(oce.Shutdown),
+		exporterhelper.WithTimeout(oCfg.TimeoutSettings),
+		exporterhelper.WithCircuitBreaker(oCfg.CircuitBreaker))
 	if err != nil {
 		return nil, err
 	}
@@ -150,7 +166,10 @@ func (oce *ocAgentExporter) Shutdown(context.Context) error {
 
 func (oce *ocAgentExporter) PushTraceData(ctx context.Context, td consumerdata.TraceData) (int, error) {
 	// Get first available exporter.
-	exporter, ok := <-oce.exporters
+	exporter, ok, err := oce.acquireExporter(ctx)
+	if err != nil {
+		return len(td.Spans), fmt.Errorf("failed to push trace data via OpenCensus exporter: %w", err)
+	}
 	if !ok {
 		err := &ocExporterError{
 			code: errAlreadyStopped,
@@ -159,14 +178,15 @@ func (oce *ocAgentExporter) PushTraceData(ctx context.Context, td consumerdata.T
 		return len(td.Spans), fmt.Errorf("failed to push trace data via OpenCensus exporter: %w", err)
 	}
 
-	err := exporter.ExportTraceServiceRequest(
-		&agenttracepb.ExportTraceServiceRequest{
-			Spans:    td.Spans,
-			Resource: td.Resource,
-			Node:     td.Node,
-		},
-	)
-	oce.exporters <- exporter
+	err = oce.send(ctx, exporter, func() error {
+		return exporter.ExportTraceServiceRequest(
+			&agenttracepb.ExportTraceServiceRequest{
+				Spans:    td.Spans,
+				Resource: td.Resource,
+				Node:     td.Node,
+			},
+		)
+	})
 	if err != nil {
 		return len(td.Spans), fmt.Errorf("failed to push trace data via OpenCensus exporter: %w", err)
 	}
@@ -175,7 +195,10 @@ func (oce *ocAgentExporter) PushTraceData(ctx context.Context, td consumerdata.T
 
 func (oce *ocAgentExporter) PushMetricsData(ctx context.Context, md consumerdata.MetricsData) (int, error) {
 	// Get first available exporter.
-	exporter, ok := <-oce.exporters
+	exporter, ok, err := oce.acquireExporter(ctx)
+	if err != nil {
+		return exporterhelper.NumTimeSeries(md), fmt.Errorf("failed to push metrics data via OpenCensus exporter: %w", err)
+	}
 	if !ok {
 		err := &ocExporterError{
 			code: errAlreadyStopped,
@@ -189,10 +212,58 @@ func (oce *ocAgentExporter) PushMetricsData(ctx context.Context, md consumerdata
 		Resource: md.Resource,
 		Node:     md.Node,
 	}
-	err := exporter.ExportMetricsServiceRequest(req)
-	oce.exporters <- exporter
+	err = oce.send(ctx, exporter, func() error {
+		return exporter.ExportMetricsServiceRequest(req)
+	})
 	if err != nil {
 		return exporterhelper.NumTimeSeries(md), fmt.Errorf("failed to push metrics data via OpenCensus exporter: %w", err)
 	}
 	return 0, nil
 }
+
+// acquireExporter waits for the first available exporter, ok is false if the
+// exporter was stopped. It gives up when the context is done, e.g. when the
+// export timeout expires because all the workers are stuck on a dead backend.
+func (oce *ocAgentExporter) acquireExporter(ctx context.Context) (exporter *ocagent.Exporter, ok bool, err error) {
+	select {
+	case exporter, ok = <-oce.exporters:
+		return exporter, ok, nil
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
+}
+
+// send runs the export on the exporter and gives the exporter back to the
+// workers when done. It gives up when the context is done, the exporter stuck
+// on the export is then replaced in the background.
+func (oce *ocAgentExporter) send(ctx context.Context, exporter *ocagent.Exporter, export func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- export()
+	}()
+
+	select {
+	case err := <-done:
+		oce.exporters <- exporter
+		return err
+	case <-ctx.Done():
+		go oce.replaceExporter(exporter, done)
+		return ctx.Err()
+	}
+}
+
+// replaceExporter stops the exporter, which ends its pending export, and gives
+// a new exporter to the workers in its place.
+func (oce *ocAgentExporter) replaceExporter(exporter *ocagent.Exporter, done <-chan error) {
+	_ = exporter.Stop()
+	<-done
+
+	replacement, err := oce.newExporter()
+	if err != nil {
+		// The stopped exporter is given back, its exports fail until the
+		// exporter is shut down.
+		oce.logger.Error("Failed to replace the OpenCensus exporter of a timed out export", zap.Error(err))
+		replacement = exporter
+	}
+	oce.exporters <- replacement
+}
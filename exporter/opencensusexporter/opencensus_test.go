@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opencensusexporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"contrib.go.opencensus.io/exporter/ocagent"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+)
+
+func TestPushTraceData_AllWorkersBusy(t *testing.T) {
+	// No worker is available, as if all of them were stuck on a dead backend.
+	oce := &ocAgentExporter{exporters: make(chan *ocagent.Exporter, 1)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	td := consumerdata.TraceData{Spans: make([]*tracepb.Span, 2)}
+	dropped, err := oce.PushTraceData(ctx, td)
+	assert.Equal(t, 2, dropped)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	_, err = oce.PushMetricsData(ctx, consumerdata.MetricsData{})
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestSend_Timeout(t *testing.T) {
+	stuck, err := ocagent.NewUnstartedExporter()
+	require.NoError(t, err)
+	replacement, err := ocagent.NewUnstartedExporter()
+	require.NoError(t, err)
+	oce := &ocAgentExporter{
+		logger:    zap.NewNop(),
+		exporters: make(chan *ocagent.Exporter, 1),
+		newExporter: func() (*ocagent.Exporter, error) {
+			return replacement, nil
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	release := make(chan struct{})
+	err = oce.send(ctx, stuck, func() error {
+		<-release
+		return nil
+	})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	// The stuck worker is replaced once its export ends.
+	close(release)
+	select {
+	case exporter := <-oce.exporters:
+		assert.Same(t, replacement, exporter)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the stuck exporter was not replaced")
+	}
+
+	// A completed export gives the exporter back.
+	require.NoError(t, oce.send(context.Background(), replacement, func() error { return nil }))
+	assert.Same(t, replacement, <-oce.exporters)
+}
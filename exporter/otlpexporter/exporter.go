@@ -27,6 +27,7 @@ import (
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
+	"go.opentelemetry.io/collector/config/configopaque"
 	otlpmetriccol "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/collector/metrics/v1"
 	otlptracecol "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/collector/trace/v1"
 	otlplogcol "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/logs/v1"
@@ -112,7 +113,7 @@ func newGrpcSender(config *Config) (sender, error) {
 		metricExporter: otlpmetriccol.NewMetricsServiceClient(clientConn),
 		logExporter:    otlplogcol.NewLogServiceClient(clientConn),
 		grpcClientConn: clientConn,
-		metadata:       metadata.New(config.GRPCClientSettings.Headers),
+		metadata:       metadata.New(configopaque.MapToStrings(config.GRPCClientSettings.Headers)),
 		waitForReady:   config.GRPCClientSettings.WaitForReady,
 	}
 	return gs, nil
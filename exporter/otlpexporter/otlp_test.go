@@ -30,6 +30,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	otlptracecol "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/collector/trace/v1"
@@ -123,7 +124,7 @@ func TestSendTraceData(t *testing.T) {
 			TLSSetting: configtls.TLSClientSetting{
 				Insecure: true,
 			},
-			Headers: map[string]string{
+			Headers: map[string]configopaque.String{
 				"header": "header-value",
 			},
 		},
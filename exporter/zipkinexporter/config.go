@@ -17,6 +17,7 @@ package zipkinexporter
 import (
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
 
 // Config defines configuration settings for the Zipkin exporter.
@@ -30,4 +31,7 @@ type Config struct {
 	Format string `mapstructure:"format"`
 
 	DefaultServiceName string `mapstructure:"default_service_name"`
+
+	// CircuitBreaker configures the circuit breaker failing fast while the backend is unavailable.
+	CircuitBreaker exporterhelper.CircuitBreakerSettings `mapstructure:"circuit_breaker"`
 }
@@ -24,6 +24,7 @@ import (
 	"go.opentelemetry.io/collector/config/configerror"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
 
 const (
@@ -58,6 +59,7 @@ func (f *Factory) CreateDefaultConfig() configmodels.Exporter {
 		},
 		Format:             defaultFormat,
 		DefaultServiceName: defaultServiceName,
+		CircuitBreaker:     exporterhelper.CreateDefaultCircuitBreakerSettings(),
 	}
 }
 
@@ -51,7 +51,10 @@ func newTraceExporter(config *Config) (component.TraceExporterOld, error) {
 	if err != nil {
 		return nil, err
 	}
-	zexp, err := exporterhelper.NewTraceExporterOld(config, ze.PushTraceData)
+	zexp, err := exporterhelper.NewTraceExporterOld(
+		config,
+		ze.PushTraceData,
+		exporterhelper.WithCircuitBreaker(config.CircuitBreaker))
 	if err != nil {
 		return nil, err
 	}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/internal/processor/filterhelper"
@@ -26,7 +27,9 @@ import (
 // Settings
 type Settings struct {
 	// Actions specifies the list of attributes to act on.
-	// The set of actions are {INSERT, UPDATE, UPSERT, DELETE, HASH, EXTRACT}.
+	// The set of actions are {INSERT, UPDATE, UPSERT, DELETE, HASH, EXTRACT,
+	// CONVERT, TRUNCATE, REPLACE, COPY_FROM_RESOURCE, MOVE_TO_RESOURCE,
+	// MOVE_FROM_RESOURCE}.
 	// This is a required field.
 	Actions []ActionKeyValue `mapstructure:"actions"`
 }
@@ -49,12 +52,28 @@ type ActionKeyValue struct {
 	// Note: All subexpressions must have a name.
 	// Note: The value type of the source key must be a string. If it isn't,
 	// no extraction will occur.
+	// A regex pattern must also be specified for the action REPLACE, it is
+	// replaced by Replacement in the value of `key`.
 	RegexPattern string `mapstructure:"pattern"`
 
+	// Replacement is the replacement of the matches of the pattern for the
+	// action REPLACE. It can reference the capture groups, e.g. $1 or ${name}.
+	Replacement string `mapstructure:"replacement"`
+
 	// FromAttribute specifies the attribute to use to populate
 	// the value. If the attribute doesn't exist, no action is performed.
+	// For the actions COPY_FROM_RESOURCE and MOVE_FROM_RESOURCE it is the
+	// attribute of the resource, `key` if not set.
 	FromAttribute string `mapstructure:"from_attribute"`
 
+	// ConvertedType is the type the value is converted to for the action
+	// CONVERT, one of {int, double, bool, string}.
+	ConvertedType string `mapstructure:"converted_type"`
+
+	// MaxLength is the maximum length, in bytes, of a string value for the
+	// action TRUNCATE.
+	MaxLength int `mapstructure:"max_length"`
+
 	// Action specifies the type of action to perform.
 	// The set of values are {INSERT, UPDATE, UPSERT, DELETE, HASH}.
 	// Both lower case and upper case are supported.
@@ -76,6 +95,19 @@ type ActionKeyValue struct {
 	// EXTRACT - Extracts values using a regular expression rule from the input
 	//           'key' to target keys specified in the 'rule'. If a target key
 	//           already exists, it will be overridden.
+	// CONVERT - Converts the value of an existing key to ConvertedType. No
+	//           action is performed if the value can't be converted.
+	// TRUNCATE - Truncates the string value of an existing key to MaxLength bytes.
+	// REPLACE - Replaces the matches of the pattern in the string value of an
+	//           existing key with Replacement.
+	// COPY_FROM_RESOURCE - Upserts the key with the value of the FromAttribute
+	//           attribute of the resource. No action is performed if the resource
+	//           doesn't have it.
+	// MOVE_TO_RESOURCE - Moves the key to the attributes of the resource,
+	//           overriding the existing value, when all the records of the
+	//           resource have the key with the same value.
+	// MOVE_FROM_RESOURCE - Moves the FromAttribute attribute of the resource
+	//           to the key of all the records of the resource.
 	// This is a required field.
 	Action Action `mapstructure:"action"`
 }
@@ -109,6 +141,31 @@ const (
 	// 'key' to target keys specified in the 'rule'. If a target key already
 	// exists, it will be overridden.
 	EXTRACT Action = "extract"
+
+	// CONVERT converts the value of an existing key to another type. No action
+	// is performed if the value can't be converted.
+	CONVERT Action = "convert"
+
+	// TRUNCATE truncates the string value of an existing key to a maximum length.
+	TRUNCATE Action = "truncate"
+
+	// REPLACE replaces the matches of a regular expression in the string value of
+	// an existing key.
+	REPLACE Action = "replace"
+
+	// COPY_FROM_RESOURCE upserts the key with the value of an attribute of the
+	// resource. No action is performed if the resource doesn't have it.
+	COPY_FROM_RESOURCE Action = "copy_from_resource"
+
+	// MOVE_TO_RESOURCE moves the key to the attributes of the resource, overriding
+	// the existing value. No action is performed unless all the records of the
+	// resource have the key with the same value.
+	MOVE_TO_RESOURCE Action = "move_to_resource"
+
+	// MOVE_FROM_RESOURCE moves an attribute of the resource to the key of all the
+	// records of the resource, overriding the existing values. The attribute is
+	// left on the resource when some records of the resource are not processed.
+	MOVE_FROM_RESOURCE Action = "move_from_resource"
 )
 
 type attributeAction struct {
@@ -118,6 +175,12 @@ type attributeAction struct {
 	Regex *regexp.Regexp
 	// Attribute names extracted from the regexp's subexpressions.
 	AttrNames []string
+	// Replacement of the matches of the regex.
+	Replacement string
+	// ConvertedType is the type of the converted values.
+	ConvertedType string
+	// MaxLength is the length of the truncated values.
+	MaxLength int
 	// Number of non empty strings in above array
 
 	// TODO https://go.opentelemetry.io/collector/issues/296
@@ -201,6 +264,49 @@ func NewAttrProc(settings *Settings) (*AttrProc, error) {
 			}
 			action.Regex = re
 			action.AttrNames = attrNames
+		case CONVERT:
+			if a.Value != nil || a.FromAttribute != "" || a.RegexPattern != "" {
+				return nil, fmt.Errorf("error creating AttrProc. Action \"%s\" does not use \"value\", \"pattern\" or \"from_attribute\" field. These must not be specified for %d-th action", a.Action, i)
+			}
+			switch a.ConvertedType {
+			case convertedTypeInt, convertedTypeDouble, convertedTypeBool, convertedTypeString:
+			default:
+				return nil, fmt.Errorf("error creating AttrProc due to unsupported \"converted_type\" %q at the %d-th actions", a.ConvertedType, i)
+			}
+			action.ConvertedType = a.ConvertedType
+		case TRUNCATE:
+			if a.Value != nil || a.FromAttribute != "" || a.RegexPattern != "" {
+				return nil, fmt.Errorf("error creating AttrProc. Action \"%s\" does not use \"value\", \"pattern\" or \"from_attribute\" field. These must not be specified for %d-th action", a.Action, i)
+			}
+			if a.MaxLength <= 0 {
+				return nil, fmt.Errorf("error creating AttrProc. Field \"max_length\" must be positive at the %d-th actions", i)
+			}
+			action.MaxLength = a.MaxLength
+		case REPLACE:
+			if a.Value != nil || a.FromAttribute != "" {
+				return nil, fmt.Errorf("error creating AttrProc. Action \"%s\" does not use \"value\" or \"from_attribute\" field. These must not be specified for %d-th action", a.Action, i)
+			}
+			if a.RegexPattern == "" {
+				return nil, fmt.Errorf("error creating AttrProc due to missing required field \"pattern\" for action \"%s\" at the %d-th action", a.Action, i)
+			}
+			re, err := regexp.Compile(a.RegexPattern)
+			if err != nil {
+				return nil, fmt.Errorf("error creating AttrProc. Field \"pattern\" has invalid pattern: \"%s\" to be set at the %d-th actions", a.RegexPattern, i)
+			}
+			action.Regex = re
+			action.Replacement = a.Replacement
+		case COPY_FROM_RESOURCE, MOVE_FROM_RESOURCE:
+			if a.Value != nil || a.RegexPattern != "" {
+				return nil, fmt.Errorf("error creating AttrProc. Action \"%s\" does not use \"value\" or \"pattern\" field. These must not be specified for %d-th action", a.Action, i)
+			}
+			action.FromAttribute = a.FromAttribute
+			if action.FromAttribute == "" {
+				action.FromAttribute = a.Key
+			}
+		case MOVE_TO_RESOURCE:
+			if a.Value != nil || a.FromAttribute != "" || a.RegexPattern != "" {
+				return nil, fmt.Errorf("error creating AttrProc. Action \"%s\" does not use \"value\", \"pattern\" or \"from_attribute\" field. These must not be specified for %d-th action", a.Action, i)
+			}
 		default:
 			return nil, fmt.Errorf("error creating AttrProc due to unsupported action %q at the %d-th actions", a.Action, i)
 		}
@@ -210,41 +316,92 @@ func NewAttrProc(settings *Settings) (*AttrProc, error) {
 	return &AttrProc{actions: attributeActions}, nil
 }
 
+// Process applies the actions to the attributes. The actions on the resource,
+// COPY_FROM_RESOURCE, MOVE_TO_RESOURCE and MOVE_FROM_RESOURCE, are ignored.
 func (ap *AttrProc) Process(attrs pdata.AttributeMap) {
 	for _, action := range ap.actions {
-		// TODO https://go.opentelemetry.io/collector/issues/296
-		// Do benchmark testing between having action be of type string vs integer.
-		// The reason is attributes processor will most likely be commonly used
-		// and could impact performance.
+		processAttributes(action, attrs)
+	}
+}
+
+// ProcessWithResource applies the actions to the attributes of the processed
+// spans or records of the resource, including the actions on the resource.
+// complete is false when some records of the resource are not processed, e.g.
+// because they are filtered out, the attributes are then not moved off the
+// resource nor to it.
+func (ap *AttrProc) ProcessWithResource(resource pdata.Resource, attrs []pdata.AttributeMap, complete bool) {
+	for _, action := range ap.actions {
 		switch action.Action {
-		case DELETE:
-			attrs.Delete(action.Key)
-		case INSERT:
-			av, found := getSourceAttributeValue(action, attrs)
-			if !found {
-				continue
+		case COPY_FROM_RESOURCE:
+			copyFromResource(action, resource, attrs)
+		case MOVE_FROM_RESOURCE:
+			if copyFromResource(action, resource, attrs) && complete && len(attrs) > 0 {
+				resource.Attributes().Delete(action.FromAttribute)
 			}
-			attrs.Insert(action.Key, av)
-		case UPDATE:
-			av, found := getSourceAttributeValue(action, attrs)
-			if !found {
-				continue
+		case MOVE_TO_RESOURCE:
+			if complete {
+				moveToResource(action, resource, attrs)
 			}
-			attrs.Update(action.Key, av)
-		case UPSERT:
-			av, found := getSourceAttributeValue(action, attrs)
-			if !found {
-				continue
+		default:
+			for _, a := range attrs {
+				processAttributes(action, a)
 			}
-			attrs.Upsert(action.Key, av)
-		case HASH:
-			hashAttribute(action, attrs)
-		case EXTRACT:
-			extractAttributes(action, attrs)
 		}
 	}
 }
 
+// HasResourceActions returns true if some of the actions act on the resource.
+func (ap *AttrProc) HasResourceActions() bool {
+	for _, action := range ap.actions {
+		switch action.Action {
+		case COPY_FROM_RESOURCE, MOVE_TO_RESOURCE, MOVE_FROM_RESOURCE:
+			return true
+		}
+	}
+	return false
+}
+
+func processAttributes(action attributeAction, attrs pdata.AttributeMap) {
+	// TODO https://go.opentelemetry.io/collector/issues/296
+	// Do benchmark testing between having action be of type string vs integer.
+	// The reason is attributes processor will most likely be commonly used
+	// and could impact performance.
+	switch action.Action {
+	case DELETE:
+		attrs.Delete(action.Key)
+	case INSERT:
+		av, found := getSourceAttributeValue(action, attrs)
+		if !found {
+			return
+		}
+		attrs.Insert(action.Key, av)
+	case UPDATE:
+		av, found := getSourceAttributeValue(action, attrs)
+		if !found {
+			return
+		}
+		attrs.Update(action.Key, av)
+	case UPSERT:
+		av, found := getSourceAttributeValue(action, attrs)
+		if !found {
+			return
+		}
+		attrs.Upsert(action.Key, av)
+	case HASH:
+		hashAttribute(action, attrs)
+	case EXTRACT:
+		extractAttributes(action, attrs)
+	case CONVERT:
+		if value, exists := attrs.Get(action.Key); exists {
+			convertValue(value, action.ConvertedType)
+		}
+	case TRUNCATE:
+		truncateAttribute(action, attrs)
+	case REPLACE:
+		replaceAttribute(action, attrs)
+	}
+}
+
 func getSourceAttributeValue(action attributeAction, attrs pdata.AttributeMap) (pdata.AttributeValue, bool) {
 	// Set the key with a value from the configuration.
 	if action.AttributeValue != nil {
@@ -281,3 +438,70 @@ func extractAttributes(action attributeAction, attrs pdata.AttributeMap) {
 		attrs.UpsertString(action.AttrNames[i], matches[i])
 	}
 }
+
+func truncateAttribute(action attributeAction, attrs pdata.AttributeMap) {
+	value, found := attrs.Get(action.Key)
+	if !found || value.Type() != pdata.AttributeValueSTRING || len(value.StringVal()) <= action.MaxLength {
+		return
+	}
+
+	// The value is truncated at the start of a rune, to remain valid UTF-8.
+	str := value.StringVal()
+	end := action.MaxLength
+	for end > 0 && !utf8.RuneStart(str[end]) {
+		end--
+	}
+	value.SetStringVal(str[:end])
+}
+
+func replaceAttribute(action attributeAction, attrs pdata.AttributeMap) {
+	value, found := attrs.Get(action.Key)
+
+	// Replacing values only functions on strings.
+	if !found || value.Type() != pdata.AttributeValueSTRING {
+		return
+	}
+	value.SetStringVal(action.Regex.ReplaceAllString(value.StringVal(), action.Replacement))
+}
+
+// copyFromResource upserts the key of the attributes with the FromAttribute
+// attribute of the resource, and returns false if the resource doesn't have it.
+func copyFromResource(action attributeAction, resource pdata.Resource, attrs []pdata.AttributeMap) bool {
+	if resource.IsNil() {
+		return false
+	}
+	av, found := resource.Attributes().Get(action.FromAttribute)
+	if !found {
+		return false
+	}
+	for _, a := range attrs {
+		a.Upsert(action.Key, av)
+	}
+	return true
+}
+
+// moveToResource moves the key of the attributes to the resource, when all the
+// attributes have the key with the same value. Spans or records with different
+// values can't share their resource.
+func moveToResource(action attributeAction, resource pdata.Resource, attrs []pdata.AttributeMap) {
+	if len(attrs) == 0 {
+		return
+	}
+	value, found := attrs[0].Get(action.Key)
+	if !found {
+		return
+	}
+	for _, a := range attrs[1:] {
+		if other, found := a.Get(action.Key); !found || !other.Equal(value) {
+			return
+		}
+	}
+
+	if resource.IsNil() {
+		resource.InitEmpty()
+	}
+	resource.Attributes().Upsert(action.Key, value)
+	for _, a := range attrs {
+		a.Delete(action.Key)
+	}
+}
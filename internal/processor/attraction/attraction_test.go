@@ -640,6 +640,325 @@ func TestAttributes_Ordering(t *testing.T) {
 	}
 }
 
+func TestAttributes_Convert(t *testing.T) {
+	testCases := []testCase{
+		{
+			name: "ConvertStrings",
+			inputAttributes: map[string]pdata.AttributeValue{
+				"int":    pdata.NewAttributeValueString("404"),
+				"double": pdata.NewAttributeValueString(" 1.5 "),
+				"bool":   pdata.NewAttributeValueString("true"),
+				"string": pdata.NewAttributeValueInt(200),
+			},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"int":    pdata.NewAttributeValueInt(404),
+				"double": pdata.NewAttributeValueDouble(1.5),
+				"bool":   pdata.NewAttributeValueBool(true),
+				"string": pdata.NewAttributeValueString("200"),
+			},
+		},
+		{
+			name: "ConvertNumbers",
+			inputAttributes: map[string]pdata.AttributeValue{
+				"int":    pdata.NewAttributeValueDouble(2.7),
+				"double": pdata.NewAttributeValueBool(true),
+				"bool":   pdata.NewAttributeValueInt(0),
+				"string": pdata.NewAttributeValueDouble(0.25),
+			},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"int":    pdata.NewAttributeValueInt(2),
+				"double": pdata.NewAttributeValueDouble(1),
+				"bool":   pdata.NewAttributeValueBool(false),
+				"string": pdata.NewAttributeValueString("0.25"),
+			},
+		},
+		// Ensures the values which can't be parsed are left unchanged.
+		{
+			name: "ConvertInvalid",
+			inputAttributes: map[string]pdata.AttributeValue{
+				"int":    pdata.NewAttributeValueString("not found"),
+				"double": pdata.NewAttributeValueString("1,5"),
+				"bool":   pdata.NewAttributeValueString("yes"),
+			},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"int":    pdata.NewAttributeValueString("not found"),
+				"double": pdata.NewAttributeValueString("1,5"),
+				"bool":   pdata.NewAttributeValueString("yes"),
+			},
+		},
+		{
+			name:               "ConvertNoKeys",
+			inputAttributes:    map[string]pdata.AttributeValue{},
+			expectedAttributes: map[string]pdata.AttributeValue{},
+		},
+	}
+
+	cfg := &Settings{
+		Actions: []ActionKeyValue{
+			{Key: "int", Action: CONVERT, ConvertedType: "int"},
+			{Key: "double", Action: CONVERT, ConvertedType: "double"},
+			{Key: "bool", Action: CONVERT, ConvertedType: "bool"},
+			{Key: "string", Action: CONVERT, ConvertedType: "string"},
+		},
+	}
+
+	ap, err := NewAttrProc(cfg)
+	require.Nil(t, err)
+	require.NotNil(t, ap)
+
+	for _, tt := range testCases {
+		runIndividualTestCase(t, tt, ap)
+	}
+}
+
+func TestAttributes_Truncate(t *testing.T) {
+	testCases := []testCase{
+		{
+			name: "TruncateLongValue",
+			inputAttributes: map[string]pdata.AttributeValue{
+				"db.statement": pdata.NewAttributeValueString("SELECT * FROM users"),
+			},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"db.statement": pdata.NewAttributeValueString("SELECT"),
+			},
+		},
+		{
+			name: "TruncateShortValue",
+			inputAttributes: map[string]pdata.AttributeValue{
+				"db.statement": pdata.NewAttributeValueString("SELECT"),
+			},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"db.statement": pdata.NewAttributeValueString("SELECT"),
+			},
+		},
+		// Ensures the value isn't cut in the middle of a multi-byte character.
+		{
+			name: "TruncateRuneBoundary",
+			inputAttributes: map[string]pdata.AttributeValue{
+				"db.statement": pdata.NewAttributeValueString("héllo wörld"),
+			},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"db.statement": pdata.NewAttributeValueString("héllo"),
+			},
+		},
+		{
+			name: "TruncateNonString",
+			inputAttributes: map[string]pdata.AttributeValue{
+				"db.statement": pdata.NewAttributeValueInt(1234567890),
+			},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"db.statement": pdata.NewAttributeValueInt(1234567890),
+			},
+		},
+	}
+
+	cfg := &Settings{
+		Actions: []ActionKeyValue{
+			{Key: "db.statement", Action: TRUNCATE, MaxLength: 6},
+		},
+	}
+
+	ap, err := NewAttrProc(cfg)
+	require.Nil(t, err)
+	require.NotNil(t, ap)
+
+	for _, tt := range testCases {
+		runIndividualTestCase(t, tt, ap)
+	}
+}
+
+func TestAttributes_Replace(t *testing.T) {
+	testCases := []testCase{
+		{
+			name: "ReplaceMatches",
+			inputAttributes: map[string]pdata.AttributeValue{
+				"http.url": pdata.NewAttributeValueString("/api/v1/users/123/orders/456"),
+			},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"http.url": pdata.NewAttributeValueString("/api/v1/users/{users_id}/orders/{orders_id}"),
+			},
+		},
+		{
+			name: "ReplaceNoMatch",
+			inputAttributes: map[string]pdata.AttributeValue{
+				"http.url": pdata.NewAttributeValueString("/health"),
+			},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"http.url": pdata.NewAttributeValueString("/health"),
+			},
+		},
+		{
+			name: "ReplaceNonString",
+			inputAttributes: map[string]pdata.AttributeValue{
+				"http.url": pdata.NewAttributeValueInt(123),
+			},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"http.url": pdata.NewAttributeValueInt(123),
+			},
+		},
+	}
+
+	cfg := &Settings{
+		Actions: []ActionKeyValue{
+			{Key: "http.url", Action: REPLACE, RegexPattern: `/(?P<resource>[a-z]+)/[0-9]+`, Replacement: "/$1/{${resource}_id}"},
+		},
+	}
+
+	ap, err := NewAttrProc(cfg)
+	require.Nil(t, err)
+	require.NotNil(t, ap)
+
+	for _, tt := range testCases {
+		runIndividualTestCase(t, tt, ap)
+	}
+}
+
+func TestAttributes_CopyFromResource(t *testing.T) {
+	ap, err := NewAttrProc(&Settings{
+		Actions: []ActionKeyValue{
+			{Key: "service.name", Action: COPY_FROM_RESOURCE},
+			{Key: "host", FromAttribute: "host.name", Action: COPY_FROM_RESOURCE},
+			{Key: "region", FromAttribute: "cloud.region", Action: COPY_FROM_RESOURCE},
+		},
+	})
+	require.Nil(t, err)
+	require.NotNil(t, ap)
+	assert.True(t, ap.HasResourceActions())
+
+	resource := pdata.NewResource()
+	resource.InitEmpty()
+	resource.Attributes().InsertString("service.name", "frontend")
+	resource.Attributes().InsertString("host.name", "host-1")
+
+	attrs := pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"host": pdata.NewAttributeValueString("unknown"),
+	})
+	ap.ProcessWithResource(resource, []pdata.AttributeMap{attrs}, true)
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"service.name": pdata.NewAttributeValueString("frontend"),
+		"host":         pdata.NewAttributeValueString("host-1"),
+	}).Sort(), attrs.Sort())
+	assert.Equal(t, 2, resource.Attributes().Len())
+
+	// The actions on the resource are ignored without it.
+	attrs = pdata.NewAttributeMap()
+	ap.Process(attrs)
+	assert.Equal(t, 0, attrs.Len())
+}
+
+func TestAttributes_MoveToResource(t *testing.T) {
+	ap, err := NewAttrProc(&Settings{
+		Actions: []ActionKeyValue{
+			{Key: "k8s.pod.name", Action: MOVE_TO_RESOURCE},
+		},
+	})
+	require.Nil(t, err)
+	require.NotNil(t, ap)
+
+	newAttrs := func(values ...pdata.AttributeValue) []pdata.AttributeMap {
+		var attrs []pdata.AttributeMap
+		for _, v := range values {
+			attrs = append(attrs, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+				"k8s.pod.name": v,
+				"other":        pdata.NewAttributeValueInt(1),
+			}))
+		}
+		return attrs
+	}
+	remaining := pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"other": pdata.NewAttributeValueInt(1),
+	})
+
+	// The attribute is moved when all the records have the same value.
+	resource := pdata.NewResource()
+	attrs := newAttrs(pdata.NewAttributeValueString("pod-1"), pdata.NewAttributeValueString("pod-1"))
+	ap.ProcessWithResource(resource, attrs, true)
+	assert.Equal(t, remaining, attrs[0])
+	assert.Equal(t, remaining, attrs[1])
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"k8s.pod.name": pdata.NewAttributeValueString("pod-1"),
+	}), resource.Attributes())
+
+	// It is left on the records when their values differ, when some records
+	// don't have it, or when some records of the resource are not processed.
+	for _, tt := range []struct {
+		name     string
+		attrs    []pdata.AttributeMap
+		complete bool
+	}{
+		{"different values", newAttrs(pdata.NewAttributeValueString("pod-1"), pdata.NewAttributeValueString("pod-2")), true},
+		{"different types", newAttrs(pdata.NewAttributeValueString("1"), pdata.NewAttributeValueInt(1)), true},
+		{"missing", append(newAttrs(pdata.NewAttributeValueString("pod-1")), pdata.NewAttributeMap()), true},
+		{"incomplete", newAttrs(pdata.NewAttributeValueString("pod-1")), false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := pdata.NewResource()
+			resource.InitEmpty()
+			ap.ProcessWithResource(resource, tt.attrs, tt.complete)
+			assert.Equal(t, 0, resource.Attributes().Len())
+			_, found := tt.attrs[0].Get("k8s.pod.name")
+			assert.True(t, found)
+		})
+	}
+
+	// The attribute is left on the record without the resource.
+	single := pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"k8s.pod.name": pdata.NewAttributeValueString("pod-2"),
+	})
+	ap.Process(single)
+	assert.Equal(t, 1, single.Len())
+}
+
+func TestAttributes_MoveFromResource(t *testing.T) {
+	ap, err := NewAttrProc(&Settings{
+		Actions: []ActionKeyValue{
+			{Key: "pod", FromAttribute: "k8s.pod.name", Action: MOVE_FROM_RESOURCE},
+			{Key: "pod", Action: UPSERT, Value: "other"},
+		},
+	})
+	require.Nil(t, err)
+	require.NotNil(t, ap)
+
+	newResource := func() pdata.Resource {
+		resource := pdata.NewResource()
+		resource.InitEmpty()
+		resource.Attributes().InsertString("k8s.pod.name", "pod-1")
+		return resource
+	}
+
+	// The attribute is moved to all the records, before the next actions.
+	resource := newResource()
+	attrs := []pdata.AttributeMap{pdata.NewAttributeMap(), pdata.NewAttributeMap()}
+	ap.ProcessWithResource(resource, attrs, true)
+	assert.Equal(t, 0, resource.Attributes().Len())
+	for _, a := range attrs {
+		assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+			"pod": pdata.NewAttributeValueString("other"),
+		}), a)
+	}
+
+	ap, err = NewAttrProc(&Settings{
+		Actions: []ActionKeyValue{
+			{Key: "k8s.pod.name", Action: MOVE_FROM_RESOURCE},
+		},
+	})
+	require.Nil(t, err)
+
+	// The attribute is kept on the resource when some records are not processed.
+	resource = newResource()
+	attrs = []pdata.AttributeMap{pdata.NewAttributeMap()}
+	ap.ProcessWithResource(resource, attrs, false)
+	assert.Equal(t, 1, resource.Attributes().Len())
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"k8s.pod.name": pdata.NewAttributeValueString("pod-1"),
+	}), attrs[0])
+
+	// Or when there are no records.
+	resource = newResource()
+	ap.ProcessWithResource(resource, nil, true)
+	assert.Equal(t, 1, resource.Attributes().Len())
+}
+
 func TestInvalidConfig(t *testing.T) {
 	testcase := []struct {
 		name        string
@@ -737,6 +1056,62 @@ func TestInvalidConfig(t *testing.T) {
 			},
 			errorString: "error creating AttrProc. Field \"pattern\" contains at least one unnamed matcher group at the 0-th actions",
 		},
+		{
+			name: "unsupported converted type",
+			actionLists: []ActionKeyValue{
+				{Key: "aa", ConvertedType: "float", Action: CONVERT},
+			},
+			errorString: "error creating AttrProc due to unsupported \"converted_type\" \"float\" at the 0-th actions",
+		},
+		{
+			name: "set value for convert",
+			actionLists: []ActionKeyValue{
+				{Key: "aa", Value: 123, ConvertedType: "int", Action: CONVERT},
+			},
+			errorString: "error creating AttrProc. Action \"convert\" does not use \"value\", \"pattern\" or \"from_attribute\" field. These must not be specified for 0-th action",
+		},
+		{
+			name: "missing max length for truncate",
+			actionLists: []ActionKeyValue{
+				{Key: "aa", Action: TRUNCATE},
+			},
+			errorString: "error creating AttrProc. Field \"max_length\" must be positive at the 0-th actions",
+		},
+		{
+			name: "missing pattern for replace",
+			actionLists: []ActionKeyValue{
+				{Key: "aa", Replacement: "b", Action: REPLACE},
+			},
+			errorString: "error creating AttrProc due to missing required field \"pattern\" for action \"replace\" at the 0-th action",
+		},
+		{
+			name: "invalid regex for replace",
+			actionLists: []ActionKeyValue{
+				{Key: "aa", RegexPattern: "(", Action: REPLACE},
+			},
+			errorString: "error creating AttrProc. Field \"pattern\" has invalid pattern: \"(\" to be set at the 0-th actions",
+		},
+		{
+			name: "set value for copy from resource",
+			actionLists: []ActionKeyValue{
+				{Key: "aa", Value: "b", Action: COPY_FROM_RESOURCE},
+			},
+			errorString: "error creating AttrProc. Action \"copy_from_resource\" does not use \"value\" or \"pattern\" field. These must not be specified for 0-th action",
+		},
+		{
+			name: "set from attribute for move to resource",
+			actionLists: []ActionKeyValue{
+				{Key: "aa", FromAttribute: "b", Action: MOVE_TO_RESOURCE},
+			},
+			errorString: "error creating AttrProc. Action \"move_to_resource\" does not use \"value\", \"pattern\" or \"from_attribute\" field. These must not be specified for 0-th action",
+		},
+		{
+			name: "set pattern for move from resource",
+			actionLists: []ActionKeyValue{
+				{Key: "aa", RegexPattern: "b", Action: MOVE_FROM_RESOURCE},
+			},
+			errorString: "error creating AttrProc. Action \"move_from_resource\" does not use \"value\" or \"pattern\" field. These must not be specified for 0-th action",
+		},
 	}
 
 	for _, tc := range testcase {
@@ -756,6 +1131,12 @@ func TestValidConfiguration(t *testing.T) {
 			{Key: "three", FromAttribute: "two", Action: "upDaTE"},
 			{Key: "five", FromAttribute: "two", Action: "upsert"},
 			{Key: "two", RegexPattern: "^\\/api\\/v1\\/document\\/(?P<documentId>.*)\\/update$", Action: "EXTRact"},
+			{Key: "six", ConvertedType: "int", Action: "Convert"},
+			{Key: "seven", MaxLength: 10, Action: "truncate"},
+			{Key: "eight", RegexPattern: "[0-9]+", Replacement: "*", Action: "replace"},
+			{Key: "nine", Action: "copy_from_resource"},
+			{Key: "ten", Action: "move_to_resource"},
+			{Key: "eleven", FromAttribute: "k8s.pod.name", Action: "Move_From_Resource"},
 		},
 	}
 	ap, err := NewAttrProc(cfg)
@@ -773,6 +1154,12 @@ func TestValidConfiguration(t *testing.T) {
 		{Key: "three", FromAttribute: "two", Action: UPDATE},
 		{Key: "five", FromAttribute: "two", Action: UPSERT},
 		{Key: "two", Regex: compiledRegex, AttrNames: []string{"", "documentId"}, Action: EXTRACT},
+		{Key: "six", ConvertedType: "int", Action: CONVERT},
+		{Key: "seven", MaxLength: 10, Action: TRUNCATE},
+		{Key: "eight", Regex: regexp.MustCompile("[0-9]+"), Replacement: "*", Action: REPLACE},
+		{Key: "nine", FromAttribute: "nine", Action: COPY_FROM_RESOURCE},
+		{Key: "ten", Action: MOVE_TO_RESOURCE},
+		{Key: "eleven", FromAttribute: "k8s.pod.name", Action: MOVE_FROM_RESOURCE},
 	}, ap.actions)
 
 }
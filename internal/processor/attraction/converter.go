@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attraction
+
+import (
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const (
+	convertedTypeInt    = "int"
+	convertedTypeDouble = "double"
+	convertedTypeBool   = "bool"
+	convertedTypeString = "string"
+)
+
+// convertValue converts an AttributeValue to the type, the strings are parsed and
+// the booleans are converted to 0 or 1. The value is unchanged if it can't be
+// converted.
+func convertValue(attr pdata.AttributeValue, convertedType string) {
+	switch convertedType {
+	case convertedTypeInt:
+		switch attr.Type() {
+		case pdata.AttributeValueSTRING:
+			if v, err := strconv.ParseInt(strings.TrimSpace(attr.StringVal()), 10, 64); err == nil {
+				attr.SetIntVal(v)
+			}
+		case pdata.AttributeValueDOUBLE:
+			attr.SetIntVal(int64(attr.DoubleVal()))
+		case pdata.AttributeValueBOOL:
+			attr.SetIntVal(boolToInt(attr.BoolVal()))
+		}
+	case convertedTypeDouble:
+		switch attr.Type() {
+		case pdata.AttributeValueSTRING:
+			if v, err := strconv.ParseFloat(strings.TrimSpace(attr.StringVal()), 64); err == nil {
+				attr.SetDoubleVal(v)
+			}
+		case pdata.AttributeValueINT:
+			attr.SetDoubleVal(float64(attr.IntVal()))
+		case pdata.AttributeValueBOOL:
+			attr.SetDoubleVal(float64(boolToInt(attr.BoolVal())))
+		}
+	case convertedTypeBool:
+		switch attr.Type() {
+		case pdata.AttributeValueSTRING:
+			if v, err := strconv.ParseBool(strings.TrimSpace(attr.StringVal())); err == nil {
+				attr.SetBoolVal(v)
+			}
+		case pdata.AttributeValueINT:
+			attr.SetBoolVal(attr.IntVal() != 0)
+		case pdata.AttributeValueDOUBLE:
+			attr.SetBoolVal(attr.DoubleVal() != 0)
+		}
+	case convertedTypeString:
+		switch attr.Type() {
+		case pdata.AttributeValueINT:
+			attr.SetStringVal(strconv.FormatInt(attr.IntVal(), 10))
+		case pdata.AttributeValueDOUBLE:
+			attr.SetStringVal(strconv.FormatFloat(attr.DoubleVal(), 'f', -1, 64))
+		case pdata.AttributeValueBOOL:
+			attr.SetStringVal(strconv.FormatBool(attr.BoolVal()))
+		}
+	}
+}
+
+func boolToInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
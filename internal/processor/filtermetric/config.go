@@ -20,6 +20,9 @@ import (
 
 // MatchProperties specifies the set of properties in a metric to match against and the
 // type of string pattern matching to use.
+// The metric names, metric types and resource attributes match whole metrics, the
+// labels match their individual data points. Only the properties specified must
+// match, at least one must be specified for a match to occur.
 type MatchProperties struct {
 	// MatchConfig configures the matching patterns used when matching metric properties.
 	filterset.Config `mapstructure:",squash"`
@@ -27,4 +30,28 @@ type MatchProperties struct {
 	// MetricNames specifies the list of string patterns to match metric names against.
 	// A match occurs if the metric name matches at least one string pattern in this list.
 	MetricNames []string `mapstructure:"metric_names"`
+
+	// MetricTypes specifies the list of metric types to match metrics against, one of
+	// "INT64", "DOUBLE", "MONOTONIC_INT64", "MONOTONIC_DOUBLE", "HISTOGRAM" or "SUMMARY".
+	// A match occurs if the metric type is in this list.
+	MetricTypes []string `mapstructure:"metric_types"`
+
+	// ResourceAttributes specifies the list of resource attributes to match metrics
+	// against. A match occurs if the resource of the metric has all of these attributes.
+	ResourceAttributes []KeyValue `mapstructure:"resource_attributes"`
+
+	// Labels specifies the list of labels to match the data points of metrics against.
+	// A data point matches if it has all of these labels.
+	Labels []KeyValue `mapstructure:"labels"`
+}
+
+// KeyValue specifies a key and the string pattern to match its value against.
+type KeyValue struct {
+	// Key specifies the attribute or label key.
+	Key string `mapstructure:"key"`
+
+	// Value specifies the string pattern to match the value against, using the match
+	// type of the properties. If it is not set, any value will match.
+	// The attribute values that are not strings are matched in their string form.
+	Value string `mapstructure:"value"`
 }
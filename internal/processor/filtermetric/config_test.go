@@ -75,6 +75,22 @@ func TestConfig(t *testing.T) {
 		}, {
 			name:   "config/emptyproperties",
 			expCfg: createConfig(nil, filterset.Regexp),
+		}, {
+			name: "config/properties",
+			expCfg: &MatchProperties{
+				Config: filterset.Config{
+					MatchType: filterset.Regexp,
+				},
+				MetricNames: []string{`container\..*`},
+				MetricTypes: []string{"INT64", "DOUBLE"},
+				ResourceAttributes: []KeyValue{
+					{Key: "k8s.cluster.name", Value: "prod-.*"},
+				},
+				Labels: []KeyValue{
+					{Key: "namespace", Value: "kube-system"},
+					{Key: "pod"},
+				},
+			},
 		},
 	}
 
@@ -15,21 +15,91 @@
 package filtermetric
 
 import (
-	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"errors"
+	"fmt"
 
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/internal/processor/filterset"
+	"go.opentelemetry.io/collector/internal/processor/timeseries"
 )
 
+// metricTypes are the metric types by name.
+var metricTypes = map[string]pdata.MetricType{
+	pdata.MetricTypeInt64.String():           pdata.MetricTypeInt64,
+	pdata.MetricTypeDouble.String():          pdata.MetricTypeDouble,
+	pdata.MetricTypeMonotonicInt64.String():  pdata.MetricTypeMonotonicInt64,
+	pdata.MetricTypeMonotonicDouble.String(): pdata.MetricTypeMonotonicDouble,
+	pdata.MetricTypeHistogram.String():       pdata.MetricTypeHistogram,
+	pdata.MetricTypeSummary.String():         pdata.MetricTypeSummary,
+}
+
 // Matcher matches metrics by metric properties against prespecified values for each property.
 type Matcher struct {
-	nameFilters filterset.FilterSet
+	// nameFilters is nil if no metric name is specified, same for the other properties.
+	nameFilters        filterset.FilterSet
+	types              map[pdata.MetricType]bool
+	resourceAttributes []keyValueMatcher
+	labels             []keyValueMatcher
 }
 
-// MatchMetric matches a metric using the metric properties configured on the Matcher.
-// A metric only matches if every metric property configured on the Matcher is a match.
-func (m *Matcher) MatchMetric(metric *metricspb.Metric) bool {
-	name := metric.GetMetricDescriptor().GetName()
-	return m.nameFilters.Matches(name)
+// keyValueMatcher matches the value of a key, any value matches if values is nil.
+type keyValueMatcher struct {
+	key    string
+	values filterset.FilterSet
+}
+
+// MatchMetric matches a metric and its resource using the metric properties configured
+// on the Matcher: the metric name, the metric type and the resource attributes.
+// A metric only matches if every metric property configured on the Matcher is a match,
+// a Matcher with no property configured matches no metric.
+// The labels are matched separately on the data points, see MatchLabels.
+func (m *Matcher) MatchMetric(resource pdata.Resource, metric pdata.Metric) bool {
+	if m.nameFilters == nil && m.types == nil && m.resourceAttributes == nil && m.labels == nil {
+		return false
+	}
+
+	descriptor := metric.MetricDescriptor()
+	if m.nameFilters != nil && (descriptor.IsNil() || !m.nameFilters.Matches(descriptor.Name())) {
+		return false
+	}
+	if m.types != nil && (descriptor.IsNil() || !m.types[descriptor.Type()]) {
+		return false
+	}
+	if m.resourceAttributes != nil {
+		if resource.IsNil() {
+			return false
+		}
+		attrs := resource.Attributes()
+		for _, kv := range m.resourceAttributes {
+			v, ok := attrs.Get(kv.key)
+			if !ok || !kv.matches(timeseries.AttributeValueString(v)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// HasLabels returns true if labels are configured on the Matcher, the data points of
+// the matched metrics must then be matched by MatchLabels.
+func (m *Matcher) HasLabels() bool {
+	return m.labels != nil
+}
+
+// MatchLabels matches the labels of a data point using the labels configured on the
+// Matcher. A data point only matches if it has all of the labels.
+func (m *Matcher) MatchLabels(labels pdata.StringMap) bool {
+	for _, kv := range m.labels {
+		v, ok := labels.Get(kv.key)
+		if !ok || !kv.matches(v.Value()) {
+			return false
+		}
+	}
+	return true
+}
+
+func (kv *keyValueMatcher) matches(value string) bool {
+	return kv.values == nil || kv.values.Matches(value)
 }
 
 // NewMatcher constructs a metric Matcher that can be used to match metrics by metric properties.
@@ -39,13 +109,58 @@ func (m *Matcher) MatchMetric(metric *metricspb.Metric) bool {
 //
 // The metric Matcher supports matching by the following metric properties:
 // - Metric name
+// - Metric type
+// - Resource attributes
+// - Data point labels
 func NewMatcher(config *MatchProperties) (Matcher, error) {
-	nameFS, err := filterset.CreateFilterSet(config.MetricNames, &config.Config)
-	if err != nil {
+	var m Matcher
+	if len(config.MetricNames) > 0 {
+		nameFS, err := filterset.CreateFilterSet(config.MetricNames, &config.Config)
+		if err != nil {
+			return Matcher{}, err
+		}
+		m.nameFilters = nameFS
+	}
+
+	if len(config.MetricTypes) > 0 {
+		m.types = make(map[pdata.MetricType]bool, len(config.MetricTypes))
+		for _, name := range config.MetricTypes {
+			typ, ok := metricTypes[name]
+			if !ok {
+				return Matcher{}, fmt.Errorf("unsupported metric type %q", name)
+			}
+			m.types[typ] = true
+		}
+	}
+
+	var err error
+	if m.resourceAttributes, err = newKeyValueMatchers(config.ResourceAttributes, &config.Config); err != nil {
+		return Matcher{}, err
+	}
+	if m.labels, err = newKeyValueMatchers(config.Labels, &config.Config); err != nil {
 		return Matcher{}, err
 	}
+	return m, nil
+}
 
-	return Matcher{
-		nameFilters: nameFS,
-	}, nil
+func newKeyValueMatchers(kvs []KeyValue, config *filterset.Config) ([]keyValueMatcher, error) {
+	if len(kvs) == 0 {
+		return nil, nil
+	}
+	matchers := make([]keyValueMatcher, 0, len(kvs))
+	for _, kv := range kvs {
+		if kv.Key == "" {
+			return nil, errors.New("error creating metric filter. Can't have empty key in the list of attributes or labels")
+		}
+		matcher := keyValueMatcher{key: kv.Key}
+		if kv.Value != "" {
+			values, err := filterset.CreateFilterSet([]string{kv.Value}, config)
+			if err != nil {
+				return nil, err
+			}
+			matcher.values = values
+		}
+		matchers = append(matchers, matcher)
+	}
+	return matchers, nil
 }
@@ -17,9 +17,10 @@ package filtermetric
 import (
 	"testing"
 
-	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/internal/processor/filterset"
 )
 
@@ -42,19 +43,31 @@ var (
 	}
 )
 
-func createMetric(name string) *metricspb.Metric {
-	return &metricspb.Metric{
-		MetricDescriptor: &metricspb.MetricDescriptor{
-			Name: name,
-		},
-	}
+func createMetric(name string) pdata.Metric {
+	return createMetricWithType(name, pdata.MetricTypeInt64)
+}
+
+func createMetricWithType(name string, typ pdata.MetricType) pdata.Metric {
+	metric := pdata.NewMetric()
+	metric.InitEmpty()
+	metric.MetricDescriptor().InitEmpty()
+	metric.MetricDescriptor().SetName(name)
+	metric.MetricDescriptor().SetType(typ)
+	return metric
+}
+
+func createResource(attrs map[string]pdata.AttributeValue) pdata.Resource {
+	resource := pdata.NewResource()
+	resource.InitEmpty()
+	resource.Attributes().InitFromMap(attrs)
+	return resource
 }
 
 func TestMatcherMatches(t *testing.T) {
 	tests := []struct {
 		name        string
 		cfg         *MatchProperties
-		metric      *metricspb.Metric
+		metric      pdata.Metric
 		shouldMatch bool
 	}{
 		{
@@ -91,7 +104,104 @@ func TestMatcherMatches(t *testing.T) {
 			assert.NotNil(t, matcher)
 			assert.NoError(t, err)
 
-			assert.Equal(t, test.shouldMatch, matcher.MatchMetric(test.metric))
+			assert.Equal(t, test.shouldMatch, matcher.MatchMetric(pdata.NewResource(), test.metric))
 		})
 	}
 }
+
+func TestMatcherMatchesTypes(t *testing.T) {
+	matcher, err := NewMatcher(&MatchProperties{
+		Config:      filterset.Config{MatchType: filterset.Regexp},
+		MetricNames: []string{`container\..*`},
+		MetricTypes: []string{"INT64", "DOUBLE"},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, matcher.MatchMetric(pdata.NewResource(), createMetricWithType("container.memory", pdata.MetricTypeDouble)))
+	assert.False(t, matcher.MatchMetric(pdata.NewResource(), createMetricWithType("container.cpu", pdata.MetricTypeMonotonicDouble)))
+	assert.False(t, matcher.MatchMetric(pdata.NewResource(), createMetricWithType("host.memory", pdata.MetricTypeDouble)))
+
+	_, err = NewMatcher(&MatchProperties{
+		Config:      filterset.Config{MatchType: filterset.Strict},
+		MetricTypes: []string{"GAUGE"},
+	})
+	assert.Error(t, err)
+}
+
+func TestMatcherMatchesResourceAttributes(t *testing.T) {
+	matcher, err := NewMatcher(&MatchProperties{
+		Config: filterset.Config{MatchType: filterset.Regexp},
+		ResourceAttributes: []KeyValue{
+			{Key: "k8s.namespace.name", Value: "kube-.*"},
+			{Key: "k8s.pod.uid"},
+			{Key: "replicas", Value: "[0-9]"},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		resource    pdata.Resource
+		shouldMatch bool
+	}{
+		{
+			name: "allAttributes",
+			resource: createResource(map[string]pdata.AttributeValue{
+				"k8s.namespace.name": pdata.NewAttributeValueString("kube-system"),
+				"k8s.pod.uid":        pdata.NewAttributeValueString("abc"),
+				"replicas":           pdata.NewAttributeValueInt(3),
+			}),
+			shouldMatch: true,
+		}, {
+			name: "valueMismatch",
+			resource: createResource(map[string]pdata.AttributeValue{
+				"k8s.namespace.name": pdata.NewAttributeValueString("default"),
+				"k8s.pod.uid":        pdata.NewAttributeValueString("abc"),
+				"replicas":           pdata.NewAttributeValueInt(3),
+			}),
+			shouldMatch: false,
+		}, {
+			name: "missingAttribute",
+			resource: createResource(map[string]pdata.AttributeValue{
+				"k8s.namespace.name": pdata.NewAttributeValueString("kube-system"),
+				"replicas":           pdata.NewAttributeValueInt(3),
+			}),
+			shouldMatch: false,
+		}, {
+			name:        "nilResource",
+			resource:    pdata.NewResource(),
+			shouldMatch: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.shouldMatch, matcher.MatchMetric(test.resource, createMetric("metric")))
+		})
+	}
+}
+
+func TestMatcherMatchesLabels(t *testing.T) {
+	matcher, err := NewMatcher(&MatchProperties{
+		Config: filterset.Config{MatchType: filterset.Strict},
+		Labels: []KeyValue{
+			{Key: "namespace", Value: "kube-system"},
+			{Key: "pod"},
+		},
+	})
+	require.NoError(t, err)
+
+	// The metric level properties aren't configured, all the metrics match.
+	assert.True(t, matcher.MatchMetric(pdata.NewResource(), createMetric("metric")))
+	assert.True(t, matcher.HasLabels())
+
+	assert.True(t, matcher.MatchLabels(pdata.NewStringMap().InitFromMap(map[string]string{"namespace": "kube-system", "pod": "a"})))
+	assert.False(t, matcher.MatchLabels(pdata.NewStringMap().InitFromMap(map[string]string{"namespace": "default", "pod": "a"})))
+	assert.False(t, matcher.MatchLabels(pdata.NewStringMap().InitFromMap(map[string]string{"namespace": "kube-system"})))
+
+	_, err = NewMatcher(&MatchProperties{
+		Config: filterset.Config{MatchType: filterset.Strict},
+		Labels: []KeyValue{{Value: "kube-system"}},
+	})
+	assert.Error(t, err)
+}
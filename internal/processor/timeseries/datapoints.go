@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeseries
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// FilterDataPoints keeps the data points of the metric whose labels match the keep
+// function, and returns the number of data points kept.
+func FilterDataPoints(metric pdata.Metric, keep func(labels pdata.StringMap) bool) int {
+	int64Dps := metric.Int64DataPoints()
+	keptInt64 := pdata.NewInt64DataPointSlice()
+	for i := 0; i < int64Dps.Len(); i++ {
+		if dp := int64Dps.At(i); !dp.IsNil() && keep(dp.LabelsMap()) {
+			keptInt64.Append(&dp)
+		}
+	}
+	int64Dps.Resize(0)
+	keptInt64.MoveAndAppendTo(int64Dps)
+
+	doubleDps := metric.DoubleDataPoints()
+	keptDouble := pdata.NewDoubleDataPointSlice()
+	for i := 0; i < doubleDps.Len(); i++ {
+		if dp := doubleDps.At(i); !dp.IsNil() && keep(dp.LabelsMap()) {
+			keptDouble.Append(&dp)
+		}
+	}
+	doubleDps.Resize(0)
+	keptDouble.MoveAndAppendTo(doubleDps)
+
+	histogramDps := metric.HistogramDataPoints()
+	keptHistogram := pdata.NewHistogramDataPointSlice()
+	for i := 0; i < histogramDps.Len(); i++ {
+		if dp := histogramDps.At(i); !dp.IsNil() && keep(dp.LabelsMap()) {
+			keptHistogram.Append(&dp)
+		}
+	}
+	histogramDps.Resize(0)
+	keptHistogram.MoveAndAppendTo(histogramDps)
+
+	summaryDps := metric.SummaryDataPoints()
+	keptSummary := pdata.NewSummaryDataPointSlice()
+	for i := 0; i < summaryDps.Len(); i++ {
+		if dp := summaryDps.At(i); !dp.IsNil() && keep(dp.LabelsMap()) {
+			keptSummary.Append(&dp)
+		}
+	}
+	summaryDps.Resize(0)
+	keptSummary.MoveAndAppendTo(summaryDps)
+
+	return int64Dps.Len() + doubleDps.Len() + histogramDps.Len() + summaryDps.Len()
+}
@@ -0,0 +1,260 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeseries
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// KeySeparator separates the components of the timeseries keys, it can't be part
+// of the names nor of the values. The processors use it for their own keys too.
+const KeySeparator = "\u0000"
+
+// Key returns the identifier of the timeseries of a data point: the attributes of
+// its resource, the name, type and unit of its metric and its labels.
+func Key(resource pdata.Resource, descriptor pdata.MetricDescriptor, labels pdata.StringMap) string {
+	var b strings.Builder
+	b.WriteString(ResourceKey(resource))
+	b.WriteString(KeySeparator)
+	b.WriteString(DescriptorKey(descriptor))
+	b.WriteString(KeySeparator)
+	b.WriteString(LabelsKey(labels))
+	return b.String()
+}
+
+// DescriptorKey returns the identifier of a metric among the metrics of an
+// instrumentation library: its name, type and unit.
+func DescriptorKey(descriptor pdata.MetricDescriptor) string {
+	return descriptor.Name() + KeySeparator + descriptor.Type().String() + KeySeparator + descriptor.Unit()
+}
+
+// LibraryKey returns the identifier of an instrumentation library.
+func LibraryKey(il pdata.InstrumentationLibrary) string {
+	if il.IsNil() {
+		return ""
+	}
+	return il.Name() + KeySeparator + il.Version()
+}
+
+// ResourceKey returns the identifier of a resource, made of its attributes.
+func ResourceKey(resource pdata.Resource) string {
+	if resource.IsNil() {
+		return ""
+	}
+	return AttributesKey(resource.Attributes())
+}
+
+// AttributesKey returns the identifier of an attribute set. The identifier
+// includes the type of the values and the content of the map values, attribute
+// sets only differing by them have different identifiers.
+func AttributesKey(attributes pdata.AttributeMap) string {
+	var b strings.Builder
+	writeAttributes(&b, attributes)
+	return b.String()
+}
+
+// LabelsKey returns the identifier of a label set, its labels sorted by key.
+func LabelsKey(labels pdata.StringMap) string {
+	keys := make([]string, 0, labels.Len())
+	labels.ForEach(func(k string, _ pdata.StringValue) {
+		keys = append(keys, k)
+	})
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for i, k := range keys {
+		v, _ := labels.Get(k)
+		if i > 0 {
+			b.WriteString(KeySeparator)
+		}
+		b.WriteString(k)
+		b.WriteString(KeySeparator)
+		b.WriteString(v.Value())
+	}
+	return b.String()
+}
+
+// AttributeValueString returns the value as a string, e.g. to use it as a label
+// value. Map and null values are empty strings.
+func AttributeValueString(v pdata.AttributeValue) string {
+	switch v.Type() {
+	case pdata.AttributeValueSTRING:
+		return v.StringVal()
+	case pdata.AttributeValueINT:
+		return strconv.FormatInt(v.IntVal(), 10)
+	case pdata.AttributeValueDOUBLE:
+		return strconv.FormatFloat(v.DoubleVal(), 'f', -1, 64)
+	case pdata.AttributeValueBOOL:
+		return strconv.FormatBool(v.BoolVal())
+	default:
+		return ""
+	}
+}
+
+// writeAttributes writes the number of attributes followed by the attributes,
+// sorted by key, so that nested maps can't be confused with the enclosing one.
+func writeAttributes(b *strings.Builder, attributes pdata.AttributeMap) {
+	keys := make([]string, 0, attributes.Len())
+	attributes.ForEach(func(k string, _ pdata.AttributeValue) {
+		keys = append(keys, k)
+	})
+	sort.Strings(keys)
+
+	b.WriteString(strconv.Itoa(len(keys)))
+	for _, k := range keys {
+		v, _ := attributes.Get(k)
+		b.WriteString(KeySeparator)
+		b.WriteString(k)
+		b.WriteString(KeySeparator)
+		writeAttributeValue(b, v)
+	}
+}
+
+// writeAttributeValue writes the value prefixed by its type.
+func writeAttributeValue(b *strings.Builder, v pdata.AttributeValue) {
+	switch v.Type() {
+	case pdata.AttributeValueSTRING:
+		b.WriteString("s")
+		b.WriteString(v.StringVal())
+	case pdata.AttributeValueINT:
+		b.WriteString("i")
+		b.WriteString(strconv.FormatInt(v.IntVal(), 10))
+	case pdata.AttributeValueDOUBLE:
+		b.WriteString("d")
+		b.WriteString(strconv.FormatFloat(v.DoubleVal(), 'g', -1, 64))
+	case pdata.AttributeValueBOOL:
+		b.WriteString("b")
+		b.WriteString(strconv.FormatBool(v.BoolVal()))
+	case pdata.AttributeValueMAP:
+		b.WriteString("m")
+		writeAttributes(b, v.MapVal())
+	default:
+		b.WriteString("n")
+	}
+}
+
+// Point is the tracked point of a timeseries. Only the value fields matching the
+// type of the metric are set.
+type Point struct {
+	StartTime pdata.TimestampUnixNano
+	Timestamp pdata.TimestampUnixNano
+
+	// Int64 is the value of the int64 points.
+	Int64 int64
+	// Double is the value of the double points.
+	Double float64
+
+	// Count, Sum, Buckets and Bounds are the values of the histogram points.
+	Count   uint64
+	Sum     float64
+	Buckets []uint64
+	Bounds  []float64
+}
+
+// NewHistogramPoint returns the Point tracking the values of a histogram point.
+func NewHistogramPoint(dp pdata.HistogramDataPoint) *Point {
+	p := &Point{
+		StartTime: dp.StartTime(),
+		Timestamp: dp.Timestamp(),
+		Count:     dp.Count(),
+		Sum:       dp.Sum(),
+		Buckets:   make([]uint64, dp.Buckets().Len()),
+		Bounds:    append([]float64(nil), dp.ExplicitBounds()...),
+	}
+	for i := range p.Buckets {
+		if b := dp.Buckets().At(i); !b.IsNil() {
+			p.Buckets[i] = b.Count()
+		}
+	}
+	return p
+}
+
+// SameBuckets returns true if the other point has the same bucket bounds.
+func (p *Point) SameBuckets(other *Point) bool {
+	if len(p.Buckets) != len(other.Buckets) || len(p.Bounds) != len(other.Bounds) {
+		return false
+	}
+	for i := range p.Bounds {
+		if p.Bounds[i] != other.Bounds[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type entry struct {
+	point    *Point
+	lastSeen time.Time
+}
+
+// Tracker keeps the last point of the timeseries, until they are not updated for
+// longer than the maximum staleness. It is not safe for concurrent use.
+type Tracker struct {
+	maxStale  time.Duration
+	series    map[string]*entry
+	lastSweep time.Time
+}
+
+// NewTracker returns a Tracker forgetting the timeseries not updated for longer
+// than maxStale.
+func NewTracker(maxStale time.Duration) *Tracker {
+	return &Tracker{
+		maxStale: maxStale,
+		series:   make(map[string]*entry),
+	}
+}
+
+// Get returns the last point of the timeseries, if tracked.
+func (t *Tracker) Get(key string) (*Point, bool) {
+	e, ok := t.series[key]
+	if !ok {
+		return nil, false
+	}
+	return e.point, true
+}
+
+// Put sets the last point of the timeseries.
+func (t *Tracker) Put(key string, p *Point, now time.Time) {
+	t.series[key] = &entry{point: p, lastSeen: now}
+}
+
+// Len returns the number of tracked timeseries.
+func (t *Tracker) Len() int {
+	return len(t.series)
+}
+
+// Sweep forgets the timeseries not updated for longer than the maximum staleness,
+// and returns their number. The timeseries are only walked once per maximum
+// staleness period, the calls in between do nothing.
+func (t *Tracker) Sweep(now time.Time) int {
+	if now.Sub(t.lastSweep) < t.maxStale {
+		return 0
+	}
+	t.lastSweep = now
+
+	removed := 0
+	for key, e := range t.series {
+		if now.Sub(e.lastSeen) >= t.maxStale {
+			delete(t.series, key)
+			removed++
+		}
+	}
+	return removed
+}
@@ -0,0 +1,195 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeseries
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestKey(t *testing.T) {
+	newResource := func(attrs map[string]pdata.AttributeValue) pdata.Resource {
+		r := pdata.NewResource()
+		r.InitEmpty()
+		r.Attributes().InitFromMap(attrs)
+		return r
+	}
+	newDescriptor := func(name string, typ pdata.MetricType) pdata.MetricDescriptor {
+		d := pdata.NewMetricDescriptor()
+		d.InitEmpty()
+		d.SetName(name)
+		d.SetType(typ)
+		return d
+	}
+
+	resource := newResource(map[string]pdata.AttributeValue{
+		"host": pdata.NewAttributeValueString("a"),
+		"pid":  pdata.NewAttributeValueInt(1),
+	})
+	descriptor := newDescriptor("requests", pdata.MetricTypeMonotonicInt64)
+	labels := pdata.NewStringMap().InitFromMap(map[string]string{"method": "GET", "code": "200"})
+	key := Key(resource, descriptor, labels)
+
+	// The order of the attributes and labels doesn't matter.
+	sameLabels := pdata.NewStringMap()
+	sameLabels.Insert("code", "200")
+	sameLabels.Insert("method", "GET")
+	assert.Equal(t, key, Key(resource, descriptor, sameLabels))
+
+	assert.NotEqual(t, key, Key(pdata.NewResource(), descriptor, labels))
+	assert.NotEqual(t, key, Key(newResource(map[string]pdata.AttributeValue{
+		"host": pdata.NewAttributeValueString("a"),
+		"pid":  pdata.NewAttributeValueInt(2),
+	}), descriptor, labels))
+	assert.NotEqual(t, key, Key(resource, newDescriptor("requests", pdata.MetricTypeMonotonicDouble), labels))
+	assert.NotEqual(t, key, Key(resource, newDescriptor("errors", pdata.MetricTypeMonotonicInt64), labels))
+	assert.NotEqual(t, key, Key(resource, descriptor, pdata.NewStringMap().InitFromMap(map[string]string{"method": "GET"})))
+}
+
+func TestAttributesKey(t *testing.T) {
+	newMap := func(attrs map[string]pdata.AttributeValue) pdata.AttributeMap {
+		return pdata.NewAttributeMap().InitFromMap(attrs)
+	}
+	newMapValue := func(attrs map[string]pdata.AttributeValue) pdata.AttributeValue {
+		v := pdata.NewAttributeValueMap()
+		v.SetMapVal(newMap(attrs))
+		return v
+	}
+
+	key := AttributesKey(newMap(map[string]pdata.AttributeValue{
+		"id":  pdata.NewAttributeValueString("1"),
+		"tag": newMapValue(map[string]pdata.AttributeValue{"env": pdata.NewAttributeValueString("prod")}),
+	}))
+	assert.Equal(t, key, AttributesKey(newMap(map[string]pdata.AttributeValue{
+		"tag": newMapValue(map[string]pdata.AttributeValue{"env": pdata.NewAttributeValueString("prod")}),
+		"id":  pdata.NewAttributeValueString("1"),
+	})))
+
+	// The type of the values matters.
+	assert.NotEqual(t, key, AttributesKey(newMap(map[string]pdata.AttributeValue{
+		"id":  pdata.NewAttributeValueInt(1),
+		"tag": newMapValue(map[string]pdata.AttributeValue{"env": pdata.NewAttributeValueString("prod")}),
+	})))
+	// So does the content of the maps.
+	assert.NotEqual(t, key, AttributesKey(newMap(map[string]pdata.AttributeValue{
+		"id":  pdata.NewAttributeValueString("1"),
+		"tag": newMapValue(map[string]pdata.AttributeValue{"env": pdata.NewAttributeValueString("dev")}),
+	})))
+	assert.NotEqual(t, key, AttributesKey(newMap(map[string]pdata.AttributeValue{
+		"id":  pdata.NewAttributeValueString("1"),
+		"tag": newMapValue(nil),
+	})))
+	// A nested map can't pass for attributes of the enclosing one.
+	assert.NotEqual(t,
+		AttributesKey(newMap(map[string]pdata.AttributeValue{
+			"a": newMapValue(map[string]pdata.AttributeValue{"b": pdata.NewAttributeValueString("c")}),
+		})),
+		AttributesKey(newMap(map[string]pdata.AttributeValue{
+			"a": newMapValue(nil),
+			"b": pdata.NewAttributeValueString("c"),
+		})))
+}
+
+func TestLabelsKey(t *testing.T) {
+	newLabels := func(labels map[string]string) pdata.StringMap {
+		return pdata.NewStringMap().InitFromMap(labels)
+	}
+
+	key := LabelsKey(newLabels(map[string]string{"a": "1", "b": "2"}))
+	assert.Equal(t, key, LabelsKey(newLabels(map[string]string{"b": "2", "a": "1"})))
+	assert.NotEqual(t, key, LabelsKey(newLabels(map[string]string{"a": "1", "b": "3"})))
+
+	// The keys and values containing "=" can't be confused.
+	assert.NotEqual(t,
+		LabelsKey(newLabels(map[string]string{"a=b": "c"})),
+		LabelsKey(newLabels(map[string]string{"a": "b=c"})))
+}
+
+func TestAttributeValueString(t *testing.T) {
+	assert.Equal(t, "a", AttributeValueString(pdata.NewAttributeValueString("a")))
+	assert.Equal(t, "-1", AttributeValueString(pdata.NewAttributeValueInt(-1)))
+	assert.Equal(t, "0.5", AttributeValueString(pdata.NewAttributeValueDouble(0.5)))
+	assert.Equal(t, "true", AttributeValueString(pdata.NewAttributeValueBool(true)))
+	assert.Equal(t, "", AttributeValueString(pdata.NewAttributeValueMap()))
+}
+
+func TestFilterDataPoints(t *testing.T) {
+	metric := pdata.NewMetric()
+	metric.InitEmpty()
+	metric.Int64DataPoints().Resize(2)
+	metric.Int64DataPoints().At(0).LabelsMap().InitFromMap(map[string]string{"keep": "true"})
+	metric.Int64DataPoints().At(1).LabelsMap().InitFromMap(map[string]string{"keep": "false"})
+	metric.DoubleDataPoints().Resize(1)
+	metric.DoubleDataPoints().At(0).LabelsMap().InitFromMap(map[string]string{"keep": "false"})
+
+	keep := func(labels pdata.StringMap) bool {
+		v, _ := labels.Get("keep")
+		return v.Value() == "true"
+	}
+	assert.Equal(t, 1, FilterDataPoints(metric, keep))
+	assert.Equal(t, 1, metric.Int64DataPoints().Len())
+	assert.Equal(t, 0, metric.DoubleDataPoints().Len())
+}
+
+func TestTracker(t *testing.T) {
+	now := time.Unix(1000, 0)
+	tracker := NewTracker(time.Minute)
+
+	_, ok := tracker.Get("a")
+	assert.False(t, ok)
+
+	tracker.Put("a", &Point{Int64: 1}, now)
+	tracker.Put("b", &Point{Int64: 2}, now.Add(30*time.Second))
+	p, ok := tracker.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), p.Int64)
+	assert.Equal(t, 2, tracker.Len())
+
+	// Only the timeseries not updated for a minute are removed.
+	assert.Equal(t, 1, tracker.Sweep(now.Add(time.Minute)))
+	_, ok = tracker.Get("a")
+	assert.False(t, ok)
+
+	// The sweeps happen at most once per minute.
+	assert.Equal(t, 0, tracker.Sweep(now.Add(100*time.Second)))
+	assert.Equal(t, 1, tracker.Len())
+	assert.Equal(t, 1, tracker.Sweep(now.Add(2*time.Minute)))
+	assert.Equal(t, 0, tracker.Len())
+}
+
+func TestHistogramPoint(t *testing.T) {
+	dp := pdata.NewHistogramDataPoint()
+	dp.InitEmpty()
+	dp.SetCount(3)
+	dp.SetSum(10)
+	dp.SetExplicitBounds([]float64{1, 5})
+	dp.Buckets().Resize(3)
+	for i := 0; i < 3; i++ {
+		dp.Buckets().At(i).SetCount(1)
+	}
+
+	p := NewHistogramPoint(dp)
+	assert.Equal(t, uint64(3), p.Count)
+	assert.Equal(t, 10.0, p.Sum)
+	assert.Equal(t, []uint64{1, 1, 1}, p.Buckets)
+	assert.True(t, p.SameBuckets(NewHistogramPoint(dp)))
+
+	dp.SetExplicitBounds([]float64{1, 10})
+	assert.False(t, p.SameBuckets(NewHistogramPoint(dp)))
+}
@@ -36,6 +36,10 @@ var (
 	useNew    = true
 
 	okStatus = trace.Status{Code: trace.StatusCodeOK}
+
+	// lastValue is shared so that repeated calls to AllViews return equal
+	// views, view.LastValue returns a new aggregation on every call.
+	lastValue = view.LastValue()
 )
 
 // setParentLink tries to retrieve a span from parentCtx and if one exists
@@ -132,6 +136,8 @@ func AllViews() (views []*view.View) {
 	}
 	tagKeys = []tag.Key{tagKeyExporter}
 	views = append(views, genViews(measures, tagKeys, view.Sum())...)
+	views = append(views, genViews(
+		[]*stats.Int64Measure{mExporterCircuitBreakerState}, tagKeys, lastValue)...)
 
 	// Processor views.
 	measures = []*stats.Int64Measure{
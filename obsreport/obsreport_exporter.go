@@ -42,6 +42,9 @@ const (
 	SentLogRecordsKey = "sent_log_records"
 	// Key used to track logs that failed to be sent by exporters.
 	FailedToSendLogRecordsKey = "send_failed_log_records"
+
+	// Key used to track the state of the circuit breaker of exporters.
+	CircuitBreakerStateKey = "circuit_breaker_state"
 )
 
 var (
@@ -81,6 +84,10 @@ var (
 		exporterPrefix+FailedToSendLogRecordsKey,
 		"Number of log records in failed attempts to send to destination.",
 		stats.UnitDimensionless)
+	mExporterCircuitBreakerState = stats.Int64(
+		exporterPrefix+CircuitBreakerStateKey,
+		"State of the circuit breaker of the exporter: 0 closed, 1 half-open, 2 open.",
+		stats.UnitDimensionless)
 )
 
 // StartTraceDataExportOp is called at the start of an Export operation.
@@ -200,6 +207,19 @@ func ExporterContext(
 	return ctx
 }
 
+// RecordExporterCircuitBreakerState records the current state of the circuit
+// breaker of the exporter: 0 closed, 1 half-open, 2 open.
+func RecordExporterCircuitBreakerState(
+	exporter string,
+	state int64,
+) {
+	if !useNew {
+		return
+	}
+	ctx, _ := tag.New(context.Background(), tag.Upsert(tagKeyExporter, exporter, tag.WithTTL(tag.TTLNoPropagation)))
+	stats.Record(ctx, mExporterCircuitBreakerState.M(state))
+}
+
 // traceExportDataOp creates the span used to trace the operation. Returning
 // the updated context and the created span.
 func traceExportDataOp(
@@ -77,6 +77,12 @@ func CheckExporterLogsViews(t *testing.T, exporter string, acceptedLogRecords, d
 	CheckValueForView(t, exporterTags, droppedLogRecords, "exporter/send_failed_log_records")
 }
 
+// CheckExporterCircuitBreakerState checks that the last recorded state of the circuit breaker of the exporter matches
+// the given value. When this function is called it is required to also call SetupRecordedMetricsTest as first thing.
+func CheckExporterCircuitBreakerState(t *testing.T, exporter string, state int64) {
+	CheckValueForView(t, tagsForExporterView(exporter), state, "exporter/circuit_breaker_state")
+}
+
 // CheckProcessorTracesViews checks that for the current exported values for trace exporter views match given values.
 // When this function is called it is required to also call SetupRecordedMetricsTest as first thing.
 func CheckProcessorTracesViews(t *testing.T, processor string, acceptedSpans, refusedSpans, droppedSpans int64) {
@@ -133,8 +139,14 @@ func CheckValueForView(t *testing.T, wantTags []tag.Tag, value int64, vName stri
 		// Make sure the tags slice is sorted by tag keys.
 		sortTags(row.Tags)
 		if reflect.DeepEqual(wantTags, row.Tags) {
-			sum := row.Data.(*view.SumData)
-			require.Equal(t, float64(value), sum.Value)
+			switch data := row.Data.(type) {
+			case *view.SumData:
+				require.Equal(t, float64(value), data.Value)
+			case *view.LastValueData:
+				require.Equal(t, float64(value), data.Value)
+			default:
+				require.Failf(t, "unexpected aggregation", "view %s has data %T", vName, row.Data)
+			}
 			return
 		}
 	}
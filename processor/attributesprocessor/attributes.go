@@ -57,6 +57,9 @@ func (a *attributesProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces
 			continue
 		}
 		serviceName := processor.ServiceNameForResource(rs.Resource())
+		// The actions on the resource need the attributes of all its spans.
+		var attrs []pdata.AttributeMap
+		complete := true
 		ilss := rss.At(i).InstrumentationLibrarySpans()
 		for j := 0; j < ilss.Len(); j++ {
 			ils := ilss.At(j)
@@ -72,12 +75,14 @@ func (a *attributesProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces
 				}
 
 				if a.skipSpan(span, serviceName) {
+					complete = false
 					continue
 				}
 
-				a.attrProc.Process(span.Attributes())
+				attrs = append(attrs, span.Attributes())
 			}
 		}
+		a.attrProc.ProcessWithResource(rs.Resource(), attrs, complete)
 	}
 	return a.nextConsumer.ConsumeTraces(ctx, td)
 }
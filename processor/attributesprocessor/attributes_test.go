@@ -415,6 +415,106 @@ func TestAttributes_Hash(t *testing.T) {
 	}
 }
 
+func TestAttributes_ResourceActions(t *testing.T) {
+	factory := Factory{}
+	cfg := factory.CreateDefaultConfig()
+	oCfg := cfg.(*Config)
+	oCfg.Actions = []attraction.ActionKeyValue{
+		{Key: "service", FromAttribute: conventions.AttributeServiceName, Action: attraction.COPY_FROM_RESOURCE},
+		{Key: conventions.AttributeK8sPod, Action: attraction.MOVE_TO_RESOURCE},
+	}
+
+	tp, err := factory.CreateTraceProcessor(context.Background(), component.ProcessorCreateParams{}, exportertest.NewNopTraceExporter(), cfg)
+	require.Nil(t, err)
+	require.NotNil(t, tp)
+
+	td := generateTraceData("frontend", "span", map[string]pdata.AttributeValue{
+		conventions.AttributeK8sPod: pdata.NewAttributeValueString("pod-1"),
+	})
+	assert.NoError(t, tp.ConsumeTraces(context.Background(), td))
+	sortAttributes(td)
+
+	rs := td.ResourceSpans().At(0)
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		conventions.AttributeServiceName: pdata.NewAttributeValueString("frontend"),
+		conventions.AttributeK8sPod:      pdata.NewAttributeValueString("pod-1"),
+	}).Sort(), rs.Resource().Attributes())
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"service": pdata.NewAttributeValueString("frontend"),
+	}), rs.InstrumentationLibrarySpans().At(0).Spans().At(0).Attributes())
+}
+
+func TestAttributes_ResourceActionsSeveralSpans(t *testing.T) {
+	factory := Factory{}
+	cfg := factory.CreateDefaultConfig()
+	oCfg := cfg.(*Config)
+	oCfg.Actions = []attraction.ActionKeyValue{
+		{Key: conventions.AttributeK8sPod, Action: attraction.MOVE_TO_RESOURCE},
+		{Key: conventions.AttributeHostName, Action: attraction.MOVE_FROM_RESOURCE},
+	}
+	oCfg.Exclude = &filterspan.MatchProperties{
+		Config:    *createConfig(filterset.Strict),
+		SpanNames: []string{"excluded"},
+	}
+
+	tp, err := factory.CreateTraceProcessor(context.Background(), component.ProcessorCreateParams{}, exportertest.NewNopTraceExporter(), cfg)
+	require.Nil(t, err)
+	require.NotNil(t, tp)
+
+	generate := func(pods ...string) pdata.Traces {
+		td := generateTraceData("frontend", "span", nil)
+		rs := td.ResourceSpans().At(0)
+		rs.Resource().Attributes().UpsertString(conventions.AttributeHostName, "host-1")
+		spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+		spans.Resize(len(pods))
+		for i, pod := range pods {
+			spans.At(i).SetName("span")
+			if pod == "excluded" {
+				spans.At(i).SetName("excluded")
+			}
+			spans.At(i).Attributes().UpsertString(conventions.AttributeK8sPod, pod)
+		}
+		return td
+	}
+
+	// The spans of the resource have the same pod, it is moved to the resource,
+	// and the host is moved to the spans.
+	td := generate("pod-1", "pod-1")
+	assert.NoError(t, tp.ConsumeTraces(context.Background(), td))
+	sortAttributes(td)
+	rs := td.ResourceSpans().At(0)
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		conventions.AttributeServiceName: pdata.NewAttributeValueString("frontend"),
+		conventions.AttributeK8sPod:      pdata.NewAttributeValueString("pod-1"),
+	}).Sort(), rs.Resource().Attributes())
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	for i := 0; i < spans.Len(); i++ {
+		assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+			conventions.AttributeHostName: pdata.NewAttributeValueString("host-1"),
+		}), spans.At(i).Attributes())
+	}
+
+	// The spans have different pods, the pods stay on the spans.
+	td = generate("pod-1", "pod-2")
+	assert.NoError(t, tp.ConsumeTraces(context.Background(), td))
+	rs = td.ResourceSpans().At(0)
+	_, found := rs.Resource().Attributes().Get(conventions.AttributeK8sPod)
+	assert.False(t, found)
+	pod, _ := rs.InstrumentationLibrarySpans().At(0).Spans().At(1).Attributes().Get(conventions.AttributeK8sPod)
+	assert.Equal(t, "pod-2", pod.StringVal())
+
+	// A span is excluded, the attributes stay where they are for it.
+	td = generate("pod-1", "excluded")
+	assert.NoError(t, tp.ConsumeTraces(context.Background(), td))
+	rs = td.ResourceSpans().At(0)
+	_, found = rs.Resource().Attributes().Get(conventions.AttributeK8sPod)
+	assert.False(t, found)
+	_, found = rs.Resource().Attributes().Get(conventions.AttributeHostName)
+	assert.True(t, found)
+	_, found = rs.InstrumentationLibrarySpans().At(0).Spans().At(1).Attributes().Get(conventions.AttributeHostName)
+	assert.False(t, found)
+}
+
 func BenchmarkAttributes_FilterSpansByName(b *testing.B) {
 	testCases := []testCase{
 		{
@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cardinalitylimiter
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Action is what happens to the data points of new series beyond the limit.
+type Action string
+
+const (
+	// Drop drops the data points of the new series.
+	Drop Action = "drop"
+	// Overflow replaces the values of the labels with the most distinct values by
+	// "overflow" in the data points of the new series, until they fit in the limit.
+	Overflow Action = "overflow"
+)
+
+// Config defines the configuration for the cardinality limiter processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Limit is the maximum number of unique label sets of each metric name seen
+	// over the window.
+	Limit int `mapstructure:"limit"`
+
+	// Window is the duration of the sliding window, a label set not seen for longer
+	// no longer counts towards the limit. Defaults to 10 minutes.
+	Window time.Duration `mapstructure:"window"`
+
+	// Action is either "drop" or "overflow", defaults to "drop".
+	Action Action `mapstructure:"action"`
+}
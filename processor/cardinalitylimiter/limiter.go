@@ -0,0 +1,190 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cardinalitylimiter
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/timeseries"
+)
+
+// overflowValue replaces the value of the offending label with the overflow action.
+const overflowValue = "overflow"
+
+// label is a key and value pair of the label set of a series.
+type label struct {
+	key   string
+	value string
+}
+
+// series is a label set of a metric.
+type series struct {
+	labels   []label
+	lastSeen time.Time
+}
+
+// metricSeries are the series of a metric seen over the window.
+type metricSeries struct {
+	// series are the series counting towards the limit, by key.
+	series map[string]*series
+	// overflow are the series with overflow labels, by key.
+	overflow map[string]*series
+	// labelValues is the number of series of each value of each label.
+	labelValues map[string]map[string]int
+	// lastLogged is when the limiting of the metric was last logged.
+	lastLogged time.Time
+}
+
+func newMetricSeries() *metricSeries {
+	return &metricSeries{
+		series:      make(map[string]*series),
+		overflow:    make(map[string]*series),
+		labelValues: make(map[string]map[string]int),
+	}
+}
+
+// touch updates the series of the key if it is known, and returns false otherwise.
+func (ms *metricSeries) touch(key string, now time.Time) bool {
+	if s, ok := ms.series[key]; ok {
+		s.lastSeen = now
+		return true
+	}
+	if s, ok := ms.overflow[key]; ok {
+		s.lastSeen = now
+		return true
+	}
+	return false
+}
+
+// len returns the number of series counting towards the limit, overflow series
+// included.
+func (ms *metricSeries) len() int {
+	return len(ms.series) + len(ms.overflow)
+}
+
+func (ms *metricSeries) add(key string, labels []label, now time.Time) {
+	ms.series[key] = &series{labels: labels, lastSeen: now}
+	for _, l := range labels {
+		values, ok := ms.labelValues[l.key]
+		if !ok {
+			values = make(map[string]int)
+			ms.labelValues[l.key] = values
+		}
+		values[l.value]++
+	}
+}
+
+func (ms *metricSeries) remove(key string) {
+	s := ms.series[key]
+	delete(ms.series, key)
+	for _, l := range s.labels {
+		values := ms.labelValues[l.key]
+		if values[l.value]--; values[l.value] == 0 {
+			delete(values, l.value)
+		}
+		if len(values) == 0 {
+			delete(ms.labelValues, l.key)
+		}
+	}
+}
+
+// offendingLabel returns the label with the most distinct values among the labels
+// not already overflow, or false if there are none.
+func (ms *metricSeries) offendingLabel(labels []label) (string, bool) {
+	offending, most := "", -1
+	for _, l := range labels {
+		if l.value == overflowValue {
+			continue
+		}
+		// The labels are sorted, the first key wins ties.
+		if n := len(ms.labelValues[l.key]); n > most {
+			offending, most = l.key, n
+		}
+	}
+	return offending, most >= 0
+}
+
+// collapse replaces the values of the offending labels by overflow, one label at
+// a time, until the labels are those of a known series or a new overflow series
+// fits in the limit. Overflow series with labels left fit while there are less
+// than partialLimit series, the series whose labels are all overflow while there
+// are less than limit series. It returns false if the labels don't fit even once
+// all collapsed.
+func (ms *metricSeries) collapse(labels []label, now time.Time, partialLimit, limit int) bool {
+	for {
+		offending, ok := ms.offendingLabel(labels)
+		if !ok {
+			return false
+		}
+		for i := range labels {
+			if labels[i].key == offending {
+				labels[i].value = overflowValue
+			}
+		}
+
+		key := seriesKey(labels)
+		if ms.touch(key, now) {
+			return true
+		}
+		_, more := ms.offendingLabel(labels)
+		if ms.len() < partialLimit || (!more && ms.len() < limit) {
+			ms.overflow[key] = &series{labels: labels, lastSeen: now}
+			return true
+		}
+	}
+}
+
+// expire removes the series not seen since the cutoff, and returns true if the
+// metric has no series left.
+func (ms *metricSeries) expire(cutoff time.Time) bool {
+	for key, s := range ms.series {
+		if s.lastSeen.Before(cutoff) {
+			ms.remove(key)
+		}
+	}
+	for key, s := range ms.overflow {
+		if s.lastSeen.Before(cutoff) {
+			delete(ms.overflow, key)
+		}
+	}
+	return len(ms.series) == 0 && len(ms.overflow) == 0
+}
+
+// sortedLabels returns the labels sorted by key.
+func sortedLabels(lm pdata.StringMap) []label {
+	labels := make([]label, 0, lm.Len())
+	lm.ForEach(func(k string, v pdata.StringValue) {
+		labels = append(labels, label{key: k, value: v.Value()})
+	})
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].key < labels[j].key
+	})
+	return labels
+}
+
+// seriesKey identifies the series of the sorted labels.
+func seriesKey(labels []label) string {
+	var b strings.Builder
+	for _, l := range labels {
+		b.WriteString(l.key)
+		b.WriteString(timeseries.KeySeparator)
+		b.WriteString(l.value)
+		b.WriteString(timeseries.KeySeparator)
+	}
+	return b.String()
+}
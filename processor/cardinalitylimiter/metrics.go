@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cardinalitylimiter
+
+import (
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/processor"
+)
+
+var (
+	tagMetricNameKey, _ = tag.NewKey("metric_name")
+	tagLabelKey, _      = tag.NewKey("label")
+
+	statLimitedPoints = stats.Int64("limited_points", "Number of data points of new series beyond the cardinality limit, dropped or with an overflow label", stats.UnitDimensionless)
+)
+
+// MetricViews returns the metrics views related to cardinality limiting.
+func MetricViews() []*view.View {
+	limitedPointsView := &view.View{
+		Name:        statLimitedPoints.Name(),
+		Measure:     statLimitedPoints,
+		Description: statLimitedPoints.Description(),
+		TagKeys:     []tag.Key{processor.TagProcessorNameKey, tagMetricNameKey, tagLabelKey},
+		Aggregation: view.Sum(),
+	}
+
+	legacyViews := []*view.View{
+		limitedPointsView,
+	}
+
+	return obsreport.ProcessorMetricViews(typeStr, legacyViews)
+}
@@ -0,0 +1,261 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cardinalitylimiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/internal/processor/timeseries"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/processor"
+)
+
+// sweepsPerWindow is how many times per window the expired series are removed.
+const sweepsPerWindow = 10
+
+type cardinalityLimiter struct {
+	logger *zap.Logger
+	next   consumer.MetricsConsumer
+	name   string
+	limit  int
+	window time.Duration
+	action Action
+
+	mu        sync.Mutex
+	metrics   map[string]*metricSeries
+	lastSweep time.Time
+}
+
+var _ component.MetricsProcessor = (*cardinalityLimiter)(nil)
+
+// limitKey identifies the limited data points of a metric, label is the offending
+// label with the overflow action.
+type limitKey struct {
+	metric string
+	label  string
+}
+
+func newCardinalityLimiter(logger *zap.Logger, next consumer.MetricsConsumer, cfg *Config) (*cardinalityLimiter, error) {
+	if next == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	if cfg.Limit <= 0 {
+		return nil, errors.New("\"limit\" must be positive")
+	}
+	if cfg.Window <= 0 {
+		return nil, errors.New("\"window\" must be positive")
+	}
+
+	action := cfg.Action
+	switch action {
+	case "":
+		action = Drop
+	case Drop, Overflow:
+	default:
+		return nil, fmt.Errorf("unsupported \"action\" %q", cfg.Action)
+	}
+
+	return &cardinalityLimiter{
+		logger:  logger,
+		next:    next,
+		name:    cfg.Name(),
+		limit:   cfg.Limit,
+		window:  cfg.Window,
+		action:  action,
+		metrics: make(map[string]*metricSeries),
+	}, nil
+}
+
+// GetCapabilities returns the Capabilities assocciated with the cardinality limiter processor.
+func (*cardinalityLimiter) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: true}
+}
+
+// Start is invoked during service startup.
+func (*cardinalityLimiter) Start(_ context.Context, _ component.Host) error {
+	return nil
+}
+
+// Shutdown is invoked during service shutdown.
+func (*cardinalityLimiter) Shutdown(_ context.Context) error {
+	return nil
+}
+
+// ConsumeMetrics limits the series of the metrics and forwards them to the next
+// consumer.
+func (cl *cardinalityLimiter) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	imd := pdatautil.MetricsToInternalMetrics(md)
+	now := time.Now()
+	limited := make(map[limitKey]int)
+	dropped := 0
+
+	cl.mu.Lock()
+	cl.sweep(now)
+	rms := imd.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				continue
+			}
+			metrics := ilm.Metrics()
+			keep := pdata.NewMetricSlice()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				if metric.IsNil() || metric.MetricDescriptor().IsNil() {
+					continue
+				}
+				n, empty := cl.limitMetric(metric, now, limited)
+				dropped += n
+				if !empty {
+					keep.Append(&metric)
+				}
+			}
+			metrics.Resize(0)
+			keep.MoveAndAppendTo(metrics)
+		}
+	}
+	cl.logLimited(limited, now)
+	cl.mu.Unlock()
+
+	cl.recordLimited(ctx, limited, dropped)
+	return cl.next.ConsumeMetrics(ctx, pdatautil.MetricsFromInternalMetrics(imd))
+}
+
+// limitMetric applies the limit to the data points of the new series of the metric,
+// and counts them in limited. It returns the number of dropped data points, and
+// true if all the data points of the metric were dropped.
+func (cl *cardinalityLimiter) limitMetric(metric pdata.Metric, now time.Time, limited map[limitKey]int) (int, bool) {
+	name := metric.MetricDescriptor().Name()
+	ms, ok := cl.metrics[name]
+	if !ok {
+		ms = newMetricSeries()
+		cl.metrics[name] = ms
+	}
+
+	// With the overflow action a slot is kept for the series whose labels are all
+	// overflow, so that the points of the new series can always be collapsed in it.
+	reserved := 0
+	if cl.action == Overflow {
+		reserved = 1
+	}
+
+	dropped := 0
+	keep := func(lm pdata.StringMap) bool {
+		labels := sortedLabels(lm)
+		key := seriesKey(labels)
+		if ms.touch(key, now) {
+			return true
+		}
+		if ms.len() < cl.limit-reserved {
+			ms.add(key, labels, now)
+			return true
+		}
+
+		if cl.action == Overflow {
+			if offending, ok := ms.offendingLabel(labels); ok {
+				limited[limitKey{metric: name, label: offending}]++
+				if ms.collapse(labels, now, cl.limit-reserved, cl.limit) {
+					for _, l := range labels {
+						lm.Update(l.key, l.value)
+					}
+					return true
+				}
+				dropped++
+				return false
+			}
+		}
+
+		limited[limitKey{metric: name}]++
+		dropped++
+		return false
+	}
+
+	remaining := timeseries.FilterDataPoints(metric, keep)
+	return dropped, dropped > 0 && remaining == 0
+}
+
+// sweep removes the series not seen over the window, a few times per window.
+func (cl *cardinalityLimiter) sweep(now time.Time) {
+	if now.Sub(cl.lastSweep) < cl.window/sweepsPerWindow {
+		return
+	}
+	cl.lastSweep = now
+
+	cutoff := now.Add(-cl.window)
+	for name, ms := range cl.metrics {
+		if ms.expire(cutoff) {
+			delete(cl.metrics, name)
+		}
+	}
+}
+
+// logLimited logs the limited metrics, at most once per window for each metric.
+func (cl *cardinalityLimiter) logLimited(limited map[limitKey]int, now time.Time) {
+	for key, count := range limited {
+		ms := cl.metrics[key.metric]
+		if now.Sub(ms.lastLogged) < cl.window {
+			continue
+		}
+		ms.lastLogged = now
+
+		fields := []zap.Field{
+			zap.String("processor", cl.name),
+			zap.String("metric", key.metric),
+			zap.Int("limit", cl.limit),
+			zap.String("action", string(cl.action)),
+			zap.Int("limited_points", count),
+		}
+		if key.label != "" {
+			fields = append(fields, zap.String("label", key.label))
+		}
+		cl.logger.Warn("Metric cardinality limit reached, limiting new series", fields...)
+	}
+}
+
+// recordLimited records the limited and dropped data points.
+func (cl *cardinalityLimiter) recordLimited(ctx context.Context, limited map[limitKey]int, dropped int) {
+	if dropped > 0 {
+		obsreport.ProcessorMetricsDataDropped(obsreport.ProcessorContext(ctx, cl.name), dropped)
+	}
+	for key, count := range limited {
+		statsTags := []tag.Mutator{
+			tag.Insert(processor.TagProcessorNameKey, cl.name),
+			tag.Insert(tagMetricNameKey, key.metric),
+		}
+		if key.label != "" {
+			statsTags = append(statsTags, tag.Insert(tagLabelKey, key.label))
+		}
+		_ = stats.RecordWithTags(context.Background(), statsTags, statLimitedPoints.M(int64(count)))
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cardinalitylimiter
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/data"
+)
+
+func TestNewProcessorInvalidConfig(t *testing.T) {
+	valid := Config{Limit: 1, Window: time.Minute}
+
+	_, err := newCardinalityLimiter(zap.NewNop(), nil, &valid)
+	assert.Error(t, err)
+
+	cfg := valid
+	cfg.Limit = 0
+	_, err = newCardinalityLimiter(zap.NewNop(), &exportertest.SinkMetricsExporter{}, &cfg)
+	assert.Error(t, err)
+
+	cfg = valid
+	cfg.Window = 0
+	_, err = newCardinalityLimiter(zap.NewNop(), &exportertest.SinkMetricsExporter{}, &cfg)
+	assert.Error(t, err)
+
+	cfg = valid
+	cfg.Action = "sample"
+	_, err = newCardinalityLimiter(zap.NewNop(), &exportertest.SinkMetricsExporter{}, &cfg)
+	assert.Error(t, err)
+
+	cl, err := newCardinalityLimiter(zap.NewNop(), &exportertest.SinkMetricsExporter{}, &valid)
+	require.NoError(t, err)
+	assert.Equal(t, Drop, cl.action)
+}
+
+func TestLimitDrop(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	sink := &exportertest.SinkMetricsExporter{}
+	cl, err := newCardinalityLimiter(zap.New(core), sink, &Config{Limit: 2, Window: time.Minute, Action: Drop})
+	require.NoError(t, err)
+
+	md := newMetricData(
+		newMetric("requests",
+			map[string]string{"request_id": "1"},
+			map[string]string{"request_id": "2"},
+			map[string]string{"request_id": "3"},
+			map[string]string{"request_id": "1"},
+		),
+		// The limit applies to each metric.
+		newMetric("errors",
+			map[string]string{"request_id": "4"},
+			map[string]string{"request_id": "5"},
+		),
+		// A metric whose points are all dropped is removed.
+		newMetric("requests", map[string]string{"request_id": "6"}),
+	)
+	require.NoError(t, cl.ConsumeMetrics(context.Background(), pdatautil.MetricsFromInternalMetrics(md)))
+
+	metrics := receivedMetrics(t, sink)
+	require.Equal(t, 2, metrics.Len())
+	assert.Equal(t, []map[string]string{{"request_id": "1"}, {"request_id": "2"}, {"request_id": "1"}}, pointLabels(metrics.At(0)))
+	assert.Equal(t, []map[string]string{{"request_id": "4"}, {"request_id": "5"}}, pointLabels(metrics.At(1)))
+
+	// The limited metric is logged once per window.
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "requests", logs.All()[0].ContextMap()["metric"])
+	assert.Equal(t, int64(2), logs.All()[0].ContextMap()["limited_points"])
+	require.NoError(t, cl.ConsumeMetrics(context.Background(), pdatautil.MetricsFromInternalMetrics(
+		newMetricData(newMetric("requests", map[string]string{"request_id": "7"})))))
+	assert.Equal(t, 1, logs.Len())
+}
+
+func TestLimitOverflow(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	cl, err := newCardinalityLimiter(zap.NewNop(), sink, &Config{Limit: 4, Window: time.Minute, Action: Overflow})
+	require.NoError(t, err)
+
+	md := newMetricData(
+		newMetric("requests",
+			map[string]string{"method": "GET", "request_id": "1"},
+			map[string]string{"method": "GET", "request_id": "2"},
+			map[string]string{"method": "PUT", "request_id": "3"},
+			// A slot is kept for the series whose labels are all overflow.
+			map[string]string{"method": "GET", "request_id": "4"},
+			map[string]string{"method": "PUT", "request_id": "5"},
+		),
+	)
+	require.NoError(t, cl.ConsumeMetrics(context.Background(), pdatautil.MetricsFromInternalMetrics(md)))
+
+	metrics := receivedMetrics(t, sink)
+	require.Equal(t, 1, metrics.Len())
+	assert.Equal(t, []map[string]string{
+		{"method": "GET", "request_id": "1"},
+		{"method": "GET", "request_id": "2"},
+		{"method": "PUT", "request_id": "3"},
+		{"method": "overflow", "request_id": "overflow"},
+		{"method": "overflow", "request_id": "overflow"},
+	}, pointLabels(metrics.At(0)))
+
+	ms := cl.metrics["requests"]
+	assert.Len(t, ms.series, 3)
+	assert.Len(t, ms.overflow, 1)
+
+}
+
+func TestCollapse(t *testing.T) {
+	now := time.Now()
+	ms := newMetricSeries()
+	for _, labels := range [][]label{
+		{{"method", "GET"}, {"request_id", "1"}},
+		{{"method", "GET"}, {"request_id", "2"}},
+	} {
+		ms.add(seriesKey(labels), labels, now)
+	}
+
+	// Only the offending label is replaced when the series fits.
+	labels := []label{{"method", "PUT"}, {"request_id", "3"}}
+	require.True(t, ms.collapse(labels, now, 3, 4))
+	assert.Equal(t, []label{{"method", "PUT"}, {"request_id", "overflow"}}, labels)
+
+	// The next offending label is replaced when it doesn't.
+	labels = []label{{"method", "POST"}, {"request_id", "4"}}
+	require.True(t, ms.collapse(labels, now, 3, 4))
+	assert.Equal(t, []label{{"method", "overflow"}, {"request_id", "overflow"}}, labels)
+	assert.Equal(t, 4, ms.len())
+
+	// Known series are reused.
+	labels = []label{{"method", "PUT"}, {"request_id", "5"}}
+	require.True(t, ms.collapse(labels, now, 3, 4))
+	assert.Equal(t, []label{{"method", "PUT"}, {"request_id", "overflow"}}, labels)
+
+	// Labels that don't fit even once all replaced are refused.
+	assert.False(t, ms.collapse([]label{{"path", "/"}}, now, 3, 4))
+	assert.Equal(t, 4, ms.len())
+}
+
+func TestLimitOverflowSeveralLabels(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	cl, err := newCardinalityLimiter(zap.NewNop(), sink, &Config{Limit: 3, Window: time.Minute, Action: Overflow})
+	require.NoError(t, err)
+
+	// Both request_id and user_id explode, the number of series stays bounded.
+	var labels []map[string]string
+	for i := 0; i < 100; i++ {
+		labels = append(labels, map[string]string{"request_id": strconv.Itoa(i), "user_id": strconv.Itoa(i)})
+	}
+	md := newMetricData(newMetric("requests", labels...))
+	require.NoError(t, cl.ConsumeMetrics(context.Background(), pdatautil.MetricsFromInternalMetrics(md)))
+
+	metrics := receivedMetrics(t, sink)
+	require.Equal(t, 1, metrics.Len())
+	received := pointLabels(metrics.At(0))
+	require.Len(t, received, 100)
+	seen := make(map[string]bool)
+	for _, l := range received {
+		seen[l["request_id"]+","+l["user_id"]] = true
+	}
+	assert.Equal(t, map[string]bool{"0,0": true, "1,1": true, "overflow,overflow": true}, seen)
+
+	ms := cl.metrics["requests"]
+	assert.Len(t, ms.series, 2)
+	assert.Len(t, ms.overflow, 1)
+}
+
+func TestLimitWindow(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	cl, err := newCardinalityLimiter(zap.NewNop(), sink, &Config{Limit: 1, Window: time.Minute, Action: Drop})
+	require.NoError(t, err)
+
+	now := time.Unix(1000, 0)
+	limited := make(map[limitKey]int)
+	dropped, _ := cl.limitMetric(newMetric("requests", map[string]string{"request_id": "1"}), now, limited)
+	assert.Equal(t, 0, dropped)
+	dropped, empty := cl.limitMetric(newMetric("requests", map[string]string{"request_id": "2"}), now.Add(30*time.Second), limited)
+	assert.Equal(t, 1, dropped)
+	assert.True(t, empty)
+	assert.Equal(t, map[limitKey]int{{metric: "requests"}: 1}, limited)
+
+	// The first series no longer counts once not seen for the window, the sweeps
+	// happen at most every tenth of the window.
+	cl.sweep(now.Add(time.Minute))
+	assert.Len(t, cl.metrics, 1)
+	cl.sweep(now.Add(time.Minute + 10*time.Second))
+	assert.Len(t, cl.metrics, 0)
+	dropped, _ = cl.limitMetric(newMetric("requests", map[string]string{"request_id": "2"}), now.Add(2*time.Minute), limited)
+	assert.Equal(t, 0, dropped)
+}
+
+func newMetricData(metrics ...pdata.Metric) data.MetricData {
+	md := data.NewMetricData()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	ilm := rm.InstrumentationLibraryMetrics().At(0)
+	for i := range metrics {
+		ilm.Metrics().Append(&metrics[i])
+	}
+	return md
+}
+
+func newMetric(name string, labels ...map[string]string) pdata.Metric {
+	metric := pdata.NewMetric()
+	metric.InitEmpty()
+	metric.MetricDescriptor().InitEmpty()
+	metric.MetricDescriptor().SetName(name)
+	metric.MetricDescriptor().SetType(pdata.MetricTypeMonotonicInt64)
+	dps := metric.Int64DataPoints()
+	dps.Resize(len(labels))
+	for i, lbls := range labels {
+		dps.At(i).LabelsMap().InitFromMap(lbls)
+		dps.At(i).SetValue(1)
+	}
+	return metric
+}
+
+func receivedMetrics(t *testing.T, sink *exportertest.SinkMetricsExporter) pdata.MetricSlice {
+	allMetrics := sink.AllMetrics()
+	require.Len(t, allMetrics, 1)
+	imd := pdatautil.MetricsToInternalMetrics(allMetrics[0])
+	return imd.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+}
+
+func pointLabels(metric pdata.Metric) []map[string]string {
+	var labels []map[string]string
+	dps := metric.Int64DataPoints()
+	for i := 0; i < dps.Len(); i++ {
+		m := make(map[string]string)
+		dps.At(i).LabelsMap().ForEach(func(k string, v pdata.StringValue) {
+			m[k] = v.Value()
+		})
+		labels = append(labels, m)
+	}
+	return labels
+}
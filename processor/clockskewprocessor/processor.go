@@ -0,0 +1,187 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clockskewprocessor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// adjustmentAttribute is the key of the span attribute that records the shift,
+// in nanoseconds, applied to the timestamps of the span.
+const adjustmentAttribute = "clock_skew.adjustment_ns"
+
+type clockSkewProcessor struct {
+	nextConsumer consumer.TraceConsumer
+}
+
+var _ component.TraceProcessor = (*clockSkewProcessor)(nil)
+
+func newClockSkewProcessor(nextConsumer consumer.TraceConsumer) (*clockSkewProcessor, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	return &clockSkewProcessor{nextConsumer: nextConsumer}, nil
+}
+
+// ConsumeTraces corrects the clock skew of the spans of the batch and forwards it
+// to the next consumer.
+func (csp *clockSkewProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	newSpanTree(td).adjust()
+	return csp.nextConsumer.ConsumeTraces(ctx, td)
+}
+
+func (csp *clockSkewProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: true}
+}
+
+// Start is invoked during service startup.
+func (csp *clockSkewProcessor) Start(_ context.Context, _ component.Host) error {
+	return nil
+}
+
+// Shutdown is invoked during service shutdown.
+func (csp *clockSkewProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+// spanTree links the spans of a batch, of any trace, to their children.
+type spanTree struct {
+	// spans are the spans of the batch, keyed by trace and span ID.
+	spans map[string]pdata.Span
+	// children are the children of the spans, keyed by trace and parent span ID.
+	children map[string][]pdata.Span
+}
+
+func newSpanTree(td pdata.Traces) *spanTree {
+	tree := &spanTree{
+		spans:    make(map[string]pdata.Span),
+		children: make(map[string][]pdata.Span),
+	}
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
+			}
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
+				}
+				traceID := string(span.TraceID().Bytes())
+				tree.spans[traceID+string(span.SpanID().Bytes())] = span
+				if parentSpanID := span.ParentSpanID().Bytes(); len(parentSpanID) > 0 {
+					key := traceID + string(parentSpanID)
+					tree.children[key] = append(tree.children[key], span)
+				}
+			}
+		}
+	}
+	return tree
+}
+
+// adjust shifts the server spans that aren't within the window of their client
+// parent span, along with their descendants, walking the tree from the spans whose
+// parent isn't in the batch.
+func (tree *spanTree) adjust() {
+	visited := make(map[string]bool, len(tree.spans))
+	for key, span := range tree.spans {
+		if parentSpanID := span.ParentSpanID().Bytes(); len(parentSpanID) > 0 {
+			if _, ok := tree.spans[string(span.TraceID().Bytes())+string(parentSpanID)]; ok {
+				continue
+			}
+		}
+		tree.adjustSubtree(key, span, 0, visited)
+	}
+}
+
+// adjustSubtree shifts the span by the given delta, in nanoseconds, and its children
+// by the same delta plus their own clock skew.
+func (tree *spanTree) adjustSubtree(key string, span pdata.Span, delta int64, visited map[string]bool) {
+	if visited[key] {
+		// Protect against cycles of invalid parent span IDs.
+		return
+	}
+	visited[key] = true
+
+	if delta != 0 {
+		shiftSpan(span, delta)
+		span.Attributes().UpsertInt(adjustmentAttribute, delta)
+	}
+
+	for _, child := range tree.children[key] {
+		childDelta := delta
+		if span.Kind() == pdata.SpanKindCLIENT && child.Kind() == pdata.SpanKindSERVER {
+			childDelta += clockSkew(span, child, delta)
+		}
+		tree.adjustSubtree(string(child.TraceID().Bytes())+string(child.SpanID().Bytes()), child, childDelta, visited)
+	}
+}
+
+// clockSkew returns the shift, in nanoseconds, that places the server span, once
+// shifted by the given delta, within the window of the client span. If the server
+// span is longer than the client one their starts are aligned, otherwise the
+// server span is centered, splitting the network latency evenly between the
+// request and the response.
+func clockSkew(client, server pdata.Span, delta int64) int64 {
+	clientStart, clientEnd := int64(client.StartTime()), int64(client.EndTime())
+	serverStart, serverEnd := int64(server.StartTime())+delta, int64(server.EndTime())+delta
+	if serverStart >= clientStart && serverEnd <= clientEnd {
+		return 0
+	}
+
+	clientDuration := clientEnd - clientStart
+	serverDuration := serverEnd - serverStart
+	if serverDuration > clientDuration {
+		return clientStart - serverStart
+	}
+	latency := (clientDuration - serverDuration) / 2
+	return clientStart + latency - serverStart
+}
+
+// shiftSpan shifts the timestamps of the span and its events by the given delta,
+// in nanoseconds.
+func shiftSpan(span pdata.Span, delta int64) {
+	span.SetStartTime(shiftTimestamp(span.StartTime(), delta))
+	span.SetEndTime(shiftTimestamp(span.EndTime(), delta))
+	events := span.Events()
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		if event.IsNil() {
+			continue
+		}
+		event.SetTimestamp(shiftTimestamp(event.Timestamp(), delta))
+	}
+}
+
+func shiftTimestamp(ts pdata.TimestampUnixNano, delta int64) pdata.TimestampUnixNano {
+	if ts == 0 {
+		// Keep unset timestamps unset.
+		return ts
+	}
+	return pdata.TimestampUnixNano(int64(ts) + delta)
+}
@@ -0,0 +1,164 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clockskewprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+)
+
+var traceID = []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+
+func TestNewProcessor(t *testing.T) {
+	_, err := newClockSkewProcessor(nil)
+	assert.Error(t, err)
+}
+
+func TestClockSkew(t *testing.T) {
+	tests := []struct {
+		name   string
+		client [2]int64
+		server [2]int64
+		want   int64
+	}{
+		{
+			name:   "within_window",
+			client: [2]int64{100, 200},
+			server: [2]int64{110, 190},
+			want:   0,
+		},
+		{
+			name:   "before_window",
+			client: [2]int64{100, 200},
+			server: [2]int64{50, 130},
+			// Centered in the client window.
+			want: 60,
+		},
+		{
+			name:   "after_window",
+			client: [2]int64{100, 200},
+			server: [2]int64{1000, 1080},
+			want:   -890,
+		},
+		{
+			name:   "longer_than_client",
+			client: [2]int64{100, 200},
+			server: [2]int64{50, 500},
+			want:   50,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestSpan([]byte{1}, nil, pdata.SpanKindCLIENT, tt.client[0], tt.client[1])
+			server := newTestSpan([]byte{2}, []byte{1}, pdata.SpanKindSERVER, tt.server[0], tt.server[1])
+			assert.Equal(t, tt.want, clockSkew(client, server, 0))
+		})
+	}
+}
+
+func TestProcessorConsumeTraces(t *testing.T) {
+	sink := &exportertest.SinkTraceExporter{}
+	csp, err := newClockSkewProcessor(sink)
+	require.NoError(t, err)
+
+	base := time.Unix(1000, 0).UnixNano()
+	ms := int64(time.Millisecond)
+
+	// The client host is 1s ahead of the server one.
+	clientTd := newTraces(
+		newTestSpan([]byte{1}, nil, pdata.SpanKindSERVER, base, base+100*ms),
+		newTestSpan([]byte{2}, []byte{1}, pdata.SpanKindCLIENT, base+10*ms, base+90*ms),
+	)
+	serverTd := newTraces(
+		newTestSpan([]byte{3}, []byte{2}, pdata.SpanKindSERVER, base-1000*ms, base-940*ms),
+		newTestSpan([]byte{4}, []byte{3}, pdata.SpanKindINTERNAL, base-990*ms, base-950*ms),
+		// A span of another trace is left as is.
+		newTestSpan([]byte{3}, []byte{2}, pdata.SpanKindSERVER, base-1000*ms, base-940*ms),
+	)
+	serverTd.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(2).SetTraceID(pdata.NewTraceID([]byte{2}))
+	event := serverTd.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(1).Events()
+	event.Resize(1)
+	event.At(0).SetTimestamp(pdata.TimestampUnixNano(base - 960*ms))
+
+	td := pdata.NewTraces()
+	clientTd.ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	serverTd.ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	require.NoError(t, csp.ConsumeTraces(context.Background(), td))
+	require.Len(t, sink.AllTraces(), 1)
+
+	rss := sink.AllTraces()[0].ResourceSpans()
+	clientSpans := rss.At(0).InstrumentationLibrarySpans().At(0).Spans()
+	for i := 0; i < clientSpans.Len(); i++ {
+		_, ok := clientSpans.At(i).Attributes().Get(adjustmentAttribute)
+		assert.False(t, ok)
+	}
+
+	// The server span is centered in the 80ms window of the client span, and its
+	// child is shifted by as much.
+	delta := 1020 * ms
+	serverSpans := rss.At(1).InstrumentationLibrarySpans().At(0).Spans()
+	server := serverSpans.At(0)
+	assert.Equal(t, pdata.TimestampUnixNano(base+20*ms), server.StartTime())
+	assert.Equal(t, pdata.TimestampUnixNano(base+80*ms), server.EndTime())
+	assertAdjustment(t, server, delta)
+
+	internal := serverSpans.At(1)
+	assert.Equal(t, pdata.TimestampUnixNano(base+30*ms), internal.StartTime())
+	assert.Equal(t, pdata.TimestampUnixNano(base+70*ms), internal.EndTime())
+	assert.Equal(t, pdata.TimestampUnixNano(base+60*ms), internal.Events().At(0).Timestamp())
+	assertAdjustment(t, internal, delta)
+
+	other := serverSpans.At(2)
+	assert.Equal(t, pdata.TimestampUnixNano(base-1000*ms), other.StartTime())
+	_, ok := other.Attributes().Get(adjustmentAttribute)
+	assert.False(t, ok)
+}
+
+func assertAdjustment(t *testing.T, span pdata.Span, want int64) {
+	attr, ok := span.Attributes().Get(adjustmentAttribute)
+	require.True(t, ok)
+	assert.Equal(t, want, attr.IntVal())
+}
+
+func newTraces(spans ...pdata.Span) pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	for i := range spans {
+		ils.Spans().Append(&spans[i])
+	}
+	return td
+}
+
+func newTestSpan(spanID, parentSpanID []byte, kind pdata.SpanKind, start, end int64) pdata.Span {
+	span := pdata.NewSpan()
+	span.InitEmpty()
+	span.SetTraceID(pdata.NewTraceID(traceID))
+	span.SetSpanID(pdata.NewSpanID(spanID))
+	span.SetParentSpanID(pdata.NewSpanID(parentSpanID))
+	span.SetKind(kind)
+	span.SetStartTime(pdata.TimestampUnixNano(start))
+	span.SetEndTime(pdata.TimestampUnixNano(end))
+	return span
+}
@@ -0,0 +1,267 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cumulativetodeltaprocessor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/internal/processor/timeseries"
+)
+
+type cumulativeToDeltaProcessor struct {
+	logger  *zap.Logger
+	next    consumer.MetricsConsumer
+	metrics map[string]bool
+
+	// mu protects the tracker, the points of a timeseries must be converted in
+	// order.
+	mu      sync.Mutex
+	tracker *timeseries.Tracker
+}
+
+var _ component.MetricsProcessor = (*cumulativeToDeltaProcessor)(nil)
+
+func newCumulativeToDeltaProcessor(logger *zap.Logger, next consumer.MetricsConsumer, cfg *Config) (*cumulativeToDeltaProcessor, error) {
+	if next == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	if cfg.MaxStale <= 0 {
+		return nil, errors.New("\"max_stale\" must be positive")
+	}
+
+	metrics := make(map[string]bool, len(cfg.Metrics))
+	for _, name := range cfg.Metrics {
+		metrics[name] = true
+	}
+
+	return &cumulativeToDeltaProcessor{
+		logger:  logger,
+		next:    next,
+		metrics: metrics,
+		tracker: timeseries.NewTracker(cfg.MaxStale),
+	}, nil
+}
+
+// GetCapabilities returns the Capabilities assocciated with the cumulative to delta processor.
+func (*cumulativeToDeltaProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: true}
+}
+
+// Start is invoked during service startup.
+func (*cumulativeToDeltaProcessor) Start(_ context.Context, _ component.Host) error {
+	return nil
+}
+
+// Shutdown is invoked during service shutdown.
+func (*cumulativeToDeltaProcessor) Shutdown(_ context.Context) error {
+	return nil
+}
+
+// ConsumeMetrics converts the points of the monotonic sums and histograms to
+// deltas and forwards the metrics to the next consumer.
+func (p *cumulativeToDeltaProcessor) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	imd := pdatautil.MetricsToInternalMetrics(md)
+	now := time.Now()
+
+	p.mu.Lock()
+	rms := imd.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				continue
+			}
+			metrics := ilm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				p.convertMetric(rm.Resource(), metrics.At(k), now)
+			}
+		}
+	}
+	if removed := p.tracker.Sweep(now); removed > 0 {
+		p.logger.Debug("Removed stale timeseries", zap.Int("count", removed))
+	}
+	p.mu.Unlock()
+
+	return p.next.ConsumeMetrics(ctx, pdatautil.MetricsFromInternalMetrics(imd))
+}
+
+// convertMetric converts the points of the metric, and removes the ones without
+// delta.
+func (p *cumulativeToDeltaProcessor) convertMetric(resource pdata.Resource, metric pdata.Metric, now time.Time) {
+	if metric.IsNil() || metric.MetricDescriptor().IsNil() {
+		return
+	}
+	descriptor := metric.MetricDescriptor()
+	if len(p.metrics) > 0 && !p.metrics[descriptor.Name()] {
+		return
+	}
+
+	switch descriptor.Type() {
+	case pdata.MetricTypeMonotonicInt64:
+		dps := metric.Int64DataPoints()
+		kept := pdata.NewInt64DataPointSlice()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			if !dp.IsNil() && p.convertInt64(timeseries.Key(resource, descriptor, dp.LabelsMap()), dp, now) {
+				kept.Append(&dp)
+			}
+		}
+		dps.Resize(0)
+		kept.MoveAndAppendTo(dps)
+
+	case pdata.MetricTypeMonotonicDouble:
+		dps := metric.DoubleDataPoints()
+		kept := pdata.NewDoubleDataPointSlice()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			if !dp.IsNil() && p.convertDouble(timeseries.Key(resource, descriptor, dp.LabelsMap()), dp, now) {
+				kept.Append(&dp)
+			}
+		}
+		dps.Resize(0)
+		kept.MoveAndAppendTo(dps)
+
+	case pdata.MetricTypeHistogram:
+		dps := metric.HistogramDataPoints()
+		kept := pdata.NewHistogramDataPointSlice()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			if !dp.IsNil() && p.convertHistogram(timeseries.Key(resource, descriptor, dp.LabelsMap()), dp, now) {
+				kept.Append(&dp)
+			}
+		}
+		dps.Resize(0)
+		kept.MoveAndAppendTo(dps)
+	}
+}
+
+// convertInt64 sets the value of the point to its increase since the previous
+// point of the timeseries. It returns false if the point must be dropped: the
+// first point of a timeseries, whose increase is unknown, and the points older
+// than the previous one.
+func (p *cumulativeToDeltaProcessor) convertInt64(key string, dp pdata.Int64DataPoint, now time.Time) bool {
+	cur := &timeseries.Point{StartTime: dp.StartTime(), Timestamp: dp.Timestamp(), Int64: dp.Value()}
+	prev, ok := p.tracker.Get(key)
+	if ok && cur.Timestamp <= prev.Timestamp {
+		return false
+	}
+	p.tracker.Put(key, cur, now)
+	if !ok {
+		return false
+	}
+
+	if isReset(prev, cur) || cur.Int64 < prev.Int64 {
+		setResetStartTime(prev, dp)
+		return true
+	}
+	dp.SetStartTime(prev.Timestamp)
+	dp.SetValue(cur.Int64 - prev.Int64)
+	return true
+}
+
+// convertDouble is the equivalent of convertInt64 for double points.
+func (p *cumulativeToDeltaProcessor) convertDouble(key string, dp pdata.DoubleDataPoint, now time.Time) bool {
+	cur := &timeseries.Point{StartTime: dp.StartTime(), Timestamp: dp.Timestamp(), Double: dp.Value()}
+	prev, ok := p.tracker.Get(key)
+	if ok && cur.Timestamp <= prev.Timestamp {
+		return false
+	}
+	p.tracker.Put(key, cur, now)
+	if !ok {
+		return false
+	}
+
+	if isReset(prev, cur) || cur.Double < prev.Double {
+		setResetStartTime(prev, dp)
+		return true
+	}
+	dp.SetStartTime(prev.Timestamp)
+	dp.SetValue(cur.Double - prev.Double)
+	return true
+}
+
+// convertHistogram is the equivalent of convertInt64 for histogram points, the
+// count, the sum and the bucket counts are converted.
+func (p *cumulativeToDeltaProcessor) convertHistogram(key string, dp pdata.HistogramDataPoint, now time.Time) bool {
+	cur := timeseries.NewHistogramPoint(dp)
+	prev, ok := p.tracker.Get(key)
+	if ok && cur.Timestamp <= prev.Timestamp {
+		return false
+	}
+	p.tracker.Put(key, cur, now)
+	if !ok {
+		return false
+	}
+
+	if isReset(prev, cur) || !cur.SameBuckets(prev) || cur.Count < prev.Count || bucketDecreased(prev, cur) {
+		setResetStartTime(prev, dp)
+		return true
+	}
+	dp.SetStartTime(prev.Timestamp)
+	dp.SetCount(cur.Count - prev.Count)
+	dp.SetSum(cur.Sum - prev.Sum)
+	buckets := dp.Buckets()
+	for i := 0; i < buckets.Len(); i++ {
+		if b := buckets.At(i); !b.IsNil() {
+			b.SetCount(cur.Buckets[i] - prev.Buckets[i])
+		}
+	}
+	return true
+}
+
+// isReset returns true if the start time of the timeseries changed: the counter
+// was restarted, its value is the increase since its new start time.
+func isReset(prev, cur *timeseries.Point) bool {
+	return cur.StartTime != prev.StartTime
+}
+
+func bucketDecreased(prev, cur *timeseries.Point) bool {
+	for i := range cur.Buckets {
+		if cur.Buckets[i] < prev.Buckets[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// startTimeSetter is implemented by all the data points.
+type startTimeSetter interface {
+	StartTime() pdata.TimestampUnixNano
+	SetStartTime(pdata.TimestampUnixNano)
+}
+
+// setResetStartTime keeps the value of a point following a counter reset, which
+// is the increase since the reset. The reset happened after the previous point,
+// which bounds the start time of the point.
+func setResetStartTime(prev *timeseries.Point, dp startTimeSetter) {
+	if dp.StartTime() < prev.Timestamp {
+		dp.SetStartTime(prev.Timestamp)
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deltatocumulativeprocessor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/internal/processor/timeseries"
+)
+
+type deltaToCumulativeProcessor struct {
+	logger  *zap.Logger
+	next    consumer.MetricsConsumer
+	metrics map[string]bool
+
+	// mu protects the tracker, the points of a timeseries must be accumulated in
+	// order.
+	mu      sync.Mutex
+	tracker *timeseries.Tracker
+}
+
+var _ component.MetricsProcessor = (*deltaToCumulativeProcessor)(nil)
+
+func newDeltaToCumulativeProcessor(logger *zap.Logger, next consumer.MetricsConsumer, cfg *Config) (*deltaToCumulativeProcessor, error) {
+	if next == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	if cfg.MaxStale <= 0 {
+		return nil, errors.New("\"max_stale\" must be positive")
+	}
+
+	metrics := make(map[string]bool, len(cfg.Metrics))
+	for _, name := range cfg.Metrics {
+		metrics[name] = true
+	}
+
+	return &deltaToCumulativeProcessor{
+		logger:  logger,
+		next:    next,
+		metrics: metrics,
+		tracker: timeseries.NewTracker(cfg.MaxStale),
+	}, nil
+}
+
+// GetCapabilities returns the Capabilities assocciated with the delta to cumulative processor.
+func (*deltaToCumulativeProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: true}
+}
+
+// Start is invoked during service startup.
+func (*deltaToCumulativeProcessor) Start(_ context.Context, _ component.Host) error {
+	return nil
+}
+
+// Shutdown is invoked during service shutdown.
+func (*deltaToCumulativeProcessor) Shutdown(_ context.Context) error {
+	return nil
+}
+
+// ConsumeMetrics accumulates the points of the monotonic sums and histograms into
+// cumulative values and forwards the metrics to the next consumer.
+func (p *deltaToCumulativeProcessor) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	imd := pdatautil.MetricsToInternalMetrics(md)
+	now := time.Now()
+
+	p.mu.Lock()
+	rms := imd.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				continue
+			}
+			metrics := ilm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				p.convertMetric(rm.Resource(), metrics.At(k), now)
+			}
+		}
+	}
+	if removed := p.tracker.Sweep(now); removed > 0 {
+		p.logger.Debug("Removed stale timeseries", zap.Int("count", removed))
+	}
+	p.mu.Unlock()
+
+	return p.next.ConsumeMetrics(ctx, pdatautil.MetricsFromInternalMetrics(imd))
+}
+
+// convertMetric converts the points of the metric, and removes the ones that
+// can't be accumulated.
+func (p *deltaToCumulativeProcessor) convertMetric(resource pdata.Resource, metric pdata.Metric, now time.Time) {
+	if metric.IsNil() || metric.MetricDescriptor().IsNil() {
+		return
+	}
+	descriptor := metric.MetricDescriptor()
+	if len(p.metrics) > 0 && !p.metrics[descriptor.Name()] {
+		return
+	}
+
+	switch descriptor.Type() {
+	case pdata.MetricTypeMonotonicInt64:
+		dps := metric.Int64DataPoints()
+		kept := pdata.NewInt64DataPointSlice()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			if !dp.IsNil() && p.convertInt64(timeseries.Key(resource, descriptor, dp.LabelsMap()), dp, now) {
+				kept.Append(&dp)
+			}
+		}
+		dps.Resize(0)
+		kept.MoveAndAppendTo(dps)
+
+	case pdata.MetricTypeMonotonicDouble:
+		dps := metric.DoubleDataPoints()
+		kept := pdata.NewDoubleDataPointSlice()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			if !dp.IsNil() && p.convertDouble(timeseries.Key(resource, descriptor, dp.LabelsMap()), dp, now) {
+				kept.Append(&dp)
+			}
+		}
+		dps.Resize(0)
+		kept.MoveAndAppendTo(dps)
+
+	case pdata.MetricTypeHistogram:
+		dps := metric.HistogramDataPoints()
+		kept := pdata.NewHistogramDataPointSlice()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			if !dp.IsNil() && p.convertHistogram(timeseries.Key(resource, descriptor, dp.LabelsMap()), dp, now) {
+				kept.Append(&dp)
+			}
+		}
+		dps.Resize(0)
+		kept.MoveAndAppendTo(dps)
+	}
+}
+
+// convertInt64 sets the value of the point to the sum of the deltas of the
+// timeseries, and its start time to the start time of the first delta. It returns
+// false if the point must be dropped: the points older than the previous one.
+func (p *deltaToCumulativeProcessor) convertInt64(key string, dp pdata.Int64DataPoint, now time.Time) bool {
+	prev, ok := p.tracker.Get(key)
+	if ok && dp.Timestamp() <= prev.Timestamp {
+		return false
+	}
+
+	acc := &timeseries.Point{StartTime: dp.StartTime(), Timestamp: dp.Timestamp(), Int64: dp.Value()}
+	if ok && !isRestart(prev, dp.StartTime()) {
+		acc.StartTime = prev.StartTime
+		acc.Int64 += prev.Int64
+	}
+	p.tracker.Put(key, acc, now)
+
+	dp.SetStartTime(acc.StartTime)
+	dp.SetValue(acc.Int64)
+	return true
+}
+
+// convertDouble is the equivalent of convertInt64 for double points.
+func (p *deltaToCumulativeProcessor) convertDouble(key string, dp pdata.DoubleDataPoint, now time.Time) bool {
+	prev, ok := p.tracker.Get(key)
+	if ok && dp.Timestamp() <= prev.Timestamp {
+		return false
+	}
+
+	acc := &timeseries.Point{StartTime: dp.StartTime(), Timestamp: dp.Timestamp(), Double: dp.Value()}
+	if ok && !isRestart(prev, dp.StartTime()) {
+		acc.StartTime = prev.StartTime
+		acc.Double += prev.Double
+	}
+	p.tracker.Put(key, acc, now)
+
+	dp.SetStartTime(acc.StartTime)
+	dp.SetValue(acc.Double)
+	return true
+}
+
+// convertHistogram is the equivalent of convertInt64 for histogram points, the
+// count, the sum and the bucket counts are accumulated. The accumulation restarts
+// when the bucket bounds change.
+func (p *deltaToCumulativeProcessor) convertHistogram(key string, dp pdata.HistogramDataPoint, now time.Time) bool {
+	prev, ok := p.tracker.Get(key)
+	if ok && dp.Timestamp() <= prev.Timestamp {
+		return false
+	}
+
+	acc := timeseries.NewHistogramPoint(dp)
+	if ok && !isRestart(prev, dp.StartTime()) && acc.SameBuckets(prev) {
+		acc.StartTime = prev.StartTime
+		acc.Count += prev.Count
+		acc.Sum += prev.Sum
+		for i := range acc.Buckets {
+			acc.Buckets[i] += prev.Buckets[i]
+		}
+	}
+	p.tracker.Put(key, acc, now)
+
+	dp.SetStartTime(acc.StartTime)
+	dp.SetCount(acc.Count)
+	dp.SetSum(acc.Sum)
+	buckets := dp.Buckets()
+	for i := 0; i < buckets.Len(); i++ {
+		if b := buckets.At(i); !b.IsNil() {
+			b.SetCount(acc.Buckets[i])
+		}
+	}
+	return true
+}
+
+// isRestart returns true if a delta starting at start overlaps the deltas already
+// accumulated: the source of the deltas restarted, and the accumulation restarts
+// with the delta. Gaps between deltas, when some were lost, don't restart it.
+func isRestart(acc *timeseries.Point, start pdata.TimestampUnixNano) bool {
+	return start != 0 && start < acc.Timestamp
+}
@@ -0,0 +1,215 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deltatocumulativeprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/data"
+)
+
+type testPoint struct {
+	start pdata.TimestampUnixNano
+	ts    pdata.TimestampUnixNano
+	value int64
+}
+
+func TestNewProcessorInvalidConfig(t *testing.T) {
+	_, err := newDeltaToCumulativeProcessor(zap.NewNop(), nil, &Config{MaxStale: time.Minute})
+	assert.Error(t, err)
+
+	_, err = newDeltaToCumulativeProcessor(zap.NewNop(), &exportertest.SinkMetricsExporter{}, &Config{})
+	assert.Error(t, err)
+}
+
+func TestConvertSums(t *testing.T) {
+	tests := []struct {
+		name string
+		in   testPoint
+		want *testPoint
+	}{
+		{name: "first", in: testPoint{start: 10, ts: 20, value: 5}, want: &testPoint{start: 10, ts: 20, value: 5}},
+		{name: "next", in: testPoint{start: 20, ts: 30, value: 3}, want: &testPoint{start: 10, ts: 30, value: 8}},
+		{name: "not_newer", in: testPoint{start: 25, ts: 30, value: 1}},
+		// Some deltas were lost, the accumulation goes on.
+		{name: "gap", in: testPoint{start: 40, ts: 50, value: 2}, want: &testPoint{start: 10, ts: 50, value: 10}},
+		// The source of the deltas restarted.
+		{name: "overlap", in: testPoint{start: 45, ts: 60, value: 4}, want: &testPoint{start: 45, ts: 60, value: 4}},
+		{name: "next_after_restart", in: testPoint{start: 60, ts: 70, value: 1}, want: &testPoint{start: 45, ts: 70, value: 5}},
+	}
+
+	for _, typ := range []pdata.MetricType{pdata.MetricTypeMonotonicInt64, pdata.MetricTypeMonotonicDouble} {
+		t.Run(typ.String(), func(t *testing.T) {
+			sink := &exportertest.SinkMetricsExporter{}
+			p, err := newDeltaToCumulativeProcessor(zap.NewNop(), sink, &Config{MaxStale: time.Minute})
+			require.NoError(t, err)
+
+			for _, tt := range tests {
+				require.NoError(t, p.ConsumeMetrics(context.Background(), newMetrics("requests", typ, tt.in)), tt.name)
+				got := points(t, sink)
+				if tt.want == nil {
+					assert.Empty(t, got, tt.name)
+					continue
+				}
+				assert.Equal(t, []testPoint{*tt.want}, got, tt.name)
+			}
+		})
+	}
+}
+
+func TestConvertIgnoredMetrics(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	p, err := newDeltaToCumulativeProcessor(zap.NewNop(), sink, &Config{Metrics: []string{"requests"}, MaxStale: time.Minute})
+	require.NoError(t, err)
+
+	// The gauges and the metrics not listed are left as is.
+	for _, md := range []pdata.Metrics{
+		newMetrics("requests", pdata.MetricTypeInt64, testPoint{start: 10, ts: 20, value: 5}),
+		newMetrics("errors", pdata.MetricTypeMonotonicInt64, testPoint{start: 10, ts: 20, value: 5}),
+	} {
+		require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+		assert.Equal(t, []testPoint{{start: 10, ts: 20, value: 5}}, points(t, sink))
+	}
+	assert.Equal(t, 0, p.tracker.Len())
+}
+
+func TestConvertHistograms(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	p, err := newDeltaToCumulativeProcessor(zap.NewNop(), sink, &Config{MaxStale: time.Minute})
+	require.NoError(t, err)
+
+	require.NoError(t, p.ConsumeMetrics(context.Background(), newHistogramMetrics(10, 20, []float64{1, 5}, []uint64{1, 2, 3}, 20)))
+	require.NoError(t, p.ConsumeMetrics(context.Background(), newHistogramMetrics(20, 30, []float64{1, 5}, []uint64{1, 2, 0}, 5)))
+	// The bounds changed, the accumulation restarts.
+	require.NoError(t, p.ConsumeMetrics(context.Background(), newHistogramMetrics(30, 40, []float64{2, 5}, []uint64{3, 4, 3}, 30)))
+
+	allMetrics := sink.AllMetrics()
+	require.Len(t, allMetrics, 3)
+
+	dp := histogramPoints(allMetrics[1]).At(0)
+	assert.Equal(t, pdata.TimestampUnixNano(10), dp.StartTime())
+	assert.Equal(t, uint64(9), dp.Count())
+	assert.Equal(t, 25.0, dp.Sum())
+	assert.Equal(t, []uint64{2, 4, 3}, bucketCounts(dp))
+
+	dp = histogramPoints(allMetrics[2]).At(0)
+	assert.Equal(t, pdata.TimestampUnixNano(30), dp.StartTime())
+	assert.Equal(t, uint64(10), dp.Count())
+	assert.Equal(t, 30.0, dp.Sum())
+	assert.Equal(t, []uint64{3, 4, 3}, bucketCounts(dp))
+}
+
+func newMetricData(metric pdata.Metric) data.MetricData {
+	md := data.NewMetricData()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.Resource().InitEmpty()
+	rm.Resource().Attributes().InsertString("host", "a")
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	rm.InstrumentationLibraryMetrics().At(0).Metrics().Append(&metric)
+	return md
+}
+
+func newMetric(name string, typ pdata.MetricType) pdata.Metric {
+	metric := pdata.NewMetric()
+	metric.InitEmpty()
+	metric.MetricDescriptor().InitEmpty()
+	metric.MetricDescriptor().SetName(name)
+	metric.MetricDescriptor().SetType(typ)
+	return metric
+}
+
+func newMetrics(name string, typ pdata.MetricType, points ...testPoint) pdata.Metrics {
+	metric := newMetric(name, typ)
+	switch typ {
+	case pdata.MetricTypeInt64, pdata.MetricTypeMonotonicInt64:
+		dps := metric.Int64DataPoints()
+		dps.Resize(len(points))
+		for i, p := range points {
+			dps.At(i).SetStartTime(p.start)
+			dps.At(i).SetTimestamp(p.ts)
+			dps.At(i).SetValue(p.value)
+		}
+	case pdata.MetricTypeDouble, pdata.MetricTypeMonotonicDouble:
+		dps := metric.DoubleDataPoints()
+		dps.Resize(len(points))
+		for i, p := range points {
+			dps.At(i).SetStartTime(p.start)
+			dps.At(i).SetTimestamp(p.ts)
+			dps.At(i).SetValue(float64(p.value))
+		}
+	}
+	return pdatautil.MetricsFromInternalMetrics(newMetricData(metric))
+}
+
+func newHistogramMetrics(start, ts pdata.TimestampUnixNano, bounds []float64, counts []uint64, sum float64) pdata.Metrics {
+	metric := newMetric("latency", pdata.MetricTypeHistogram)
+	metric.HistogramDataPoints().Resize(1)
+	dp := metric.HistogramDataPoints().At(0)
+	dp.SetStartTime(start)
+	dp.SetTimestamp(ts)
+	dp.SetExplicitBounds(bounds)
+	dp.SetSum(sum)
+	dp.Buckets().Resize(len(counts))
+	var count uint64
+	for i, c := range counts {
+		dp.Buckets().At(i).SetCount(c)
+		count += c
+	}
+	dp.SetCount(count)
+	return pdatautil.MetricsFromInternalMetrics(newMetricData(metric))
+}
+
+// points returns the int64 and double points of the last metrics received by the
+// sink.
+func points(t *testing.T, sink *exportertest.SinkMetricsExporter) []testPoint {
+	allMetrics := sink.AllMetrics()
+	require.NotEmpty(t, allMetrics)
+	metric := pdatautil.MetricsToInternalMetrics(allMetrics[len(allMetrics)-1]).ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+
+	var pts []testPoint
+	int64Dps := metric.Int64DataPoints()
+	for i := 0; i < int64Dps.Len(); i++ {
+		dp := int64Dps.At(i)
+		pts = append(pts, testPoint{start: dp.StartTime(), ts: dp.Timestamp(), value: dp.Value()})
+	}
+	doubleDps := metric.DoubleDataPoints()
+	for i := 0; i < doubleDps.Len(); i++ {
+		dp := doubleDps.At(i)
+		pts = append(pts, testPoint{start: dp.StartTime(), ts: dp.Timestamp(), value: int64(dp.Value())})
+	}
+	return pts
+}
+
+func histogramPoints(md pdata.Metrics) pdata.HistogramDataPointSlice {
+	return pdatautil.MetricsToInternalMetrics(md).ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).HistogramDataPoints()
+}
+
+func bucketCounts(dp pdata.HistogramDataPoint) []uint64 {
+	counts := make([]uint64, dp.Buckets().Len())
+	for i := range counts {
+		counts[i] = dp.Buckets().At(i).Count()
+	}
+	return counts
+}
@@ -218,6 +218,29 @@ func TestLoadingConfigRegexp(t *testing.T) {
 					},
 				},
 			},
+		}, {
+			filterName: "filter/datapoints",
+			expCfg: &Config{
+				ProcessorSettings: configmodels.ProcessorSettings{
+					NameVal: "filter/datapoints",
+					TypeVal: typeStr,
+				},
+				Metrics: MetricFilters{
+					Exclude: &filtermetric.MatchProperties{
+						Config: filterset.Config{
+							MatchType: filterset.Regexp,
+						},
+						MetricNames: []string{`container\..*`},
+						MetricTypes: []string{"INT64", "DOUBLE"},
+						ResourceAttributes: []filtermetric.KeyValue{
+							{Key: "k8s.cluster.name", Value: "prod-.*"},
+						},
+						Labels: []filtermetric.KeyValue{
+							{Key: "namespace", Value: "kube-system"},
+						},
+					},
+				},
+			},
 		},
 	}
 
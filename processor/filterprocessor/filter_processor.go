@@ -17,13 +17,12 @@ package filterprocessor
 import (
 	"context"
 
-	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
-
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/consumer/pdatautil"
 	"go.opentelemetry.io/collector/internal/processor/filtermetric"
+	"go.opentelemetry.io/collector/internal/processor/timeseries"
 )
 
 type filterMetricProcessor struct {
@@ -70,7 +69,7 @@ func createMatcher(mp *filtermetric.MatchProperties) (*filtermetric.Matcher, err
 
 // GetCapabilities returns the Capabilities assocciated with the resource processor.
 func (fmp *filterMetricProcessor) GetCapabilities() component.ProcessorCapabilities {
-	return component.ProcessorCapabilities{MutatesConsumedData: false}
+	return component.ProcessorCapabilities{MutatesConsumedData: true}
 }
 
 // Start is invoked during service startup.
@@ -88,35 +87,59 @@ func (fmp *filterMetricProcessor) ConsumeMetrics(ctx context.Context, md pdata.M
 	return fmp.next.ConsumeMetrics(ctx, fmp.filterMetrics(md))
 }
 
-// filterMetrics filters the given spans based off the filterMetricProcessor's filters.
+// filterMetrics filters the given metrics based off the filterMetricProcessor's filters.
 func (fmp *filterMetricProcessor) filterMetrics(md pdata.Metrics) pdata.Metrics {
-	mds := pdatautil.MetricsToMetricsData(md)
-	for i := range mds {
-		if len(mds[i].Metrics) == 0 {
+	imd := pdatautil.MetricsToInternalMetrics(md)
+	rms := imd.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
 			continue
 		}
-		keep := make([]*metricspb.Metric, 0, len(mds[i].Metrics))
-		for _, m := range mds[i].Metrics {
-			if fmp.shouldKeepMetric(m) {
-				keep = append(keep, m)
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				continue
+			}
+			metrics := ilm.Metrics()
+			keep := pdata.NewMetricSlice()
+			for k := 0; k < metrics.Len(); k++ {
+				if metric := metrics.At(k); !metric.IsNil() && fmp.shouldKeepMetric(rm.Resource(), metric) {
+					keep.Append(&metric)
+				}
 			}
+			metrics.Resize(0)
+			keep.MoveAndAppendTo(metrics)
 		}
-		mds[i].Metrics = keep
 	}
-	return pdatautil.MetricsFromMetricsData(mds)
+	return pdatautil.MetricsFromInternalMetrics(imd)
 }
 
 // shouldKeepMetric determines whether a metric should be kept based off the filterMetricProcessor's filters.
-func (fmp *filterMetricProcessor) shouldKeepMetric(metric *metricspb.Metric) bool {
+// When the filters match labels the data points are filtered individually, the metric is only dropped when
+// none of them is kept.
+func (fmp *filterMetricProcessor) shouldKeepMetric(resource pdata.Resource, metric pdata.Metric) bool {
 	if fmp.include != nil {
-		if !fmp.include.MatchMetric(metric) {
+		if !fmp.include.MatchMetric(resource, metric) {
+			return false
+		}
+		if fmp.include.HasLabels() && timeseries.FilterDataPoints(metric, fmp.include.MatchLabels) == 0 {
 			return false
 		}
 	}
 
 	if fmp.exclude != nil {
-		if fmp.exclude.MatchMetric(metric) {
-			return false
+		if fmp.exclude.MatchMetric(resource, metric) {
+			if !fmp.exclude.HasLabels() {
+				return false
+			}
+			exclude := func(labels pdata.StringMap) bool {
+				return !fmp.exclude.MatchLabels(labels)
+			}
+			if timeseries.FilterDataPoints(metric, exclude) == 0 {
+				return false
+			}
 		}
 	}
 
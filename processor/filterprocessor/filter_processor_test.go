@@ -24,8 +24,10 @@ import (
 
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/consumer/pdatautil"
 	etest "go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/data"
 	"go.opentelemetry.io/collector/internal/processor/filtermetric"
 	"go.opentelemetry.io/collector/internal/processor/filterset"
 )
@@ -143,8 +145,8 @@ var (
 				},
 			},
 			inMN: [][]*metricspb.Metric{nil, metricsWithName(inMetricNames), {}},
+			// The batches without metrics are dropped by the conversion to the internal metrics.
 			outMN: [][]string{
-				{},
 				{
 					"full_name_match",
 					"prefix/test/match",
@@ -158,7 +160,6 @@ var (
 					"full/name/match",
 					"full_name_match",
 				},
-				{},
 			},
 		},
 		{
@@ -204,7 +205,7 @@ func TestFilterMetricProcessor(t *testing.T) {
 			assert.Nil(t, err)
 
 			caps := fmp.GetCapabilities()
-			assert.Equal(t, false, caps.MutatesConsumedData)
+			assert.Equal(t, true, caps.MutatesConsumedData)
 			ctx := context.Background()
 			assert.NoError(t, fmp.Start(ctx, nil))
 
@@ -234,6 +235,115 @@ func TestFilterMetricProcessor(t *testing.T) {
 	}
 }
 
+func TestFilterMetricProcessorDataPoints(t *testing.T) {
+	kubeSystem := &filtermetric.MatchProperties{
+		Config: filterset.Config{
+			MatchType: filterset.Regexp,
+		},
+		MetricNames: []string{`container\..*`},
+		ResourceAttributes: []filtermetric.KeyValue{
+			{Key: "k8s.cluster.name", Value: "prod-.*"},
+		},
+		Labels: []filtermetric.KeyValue{
+			{Key: "namespace", Value: "kube-system"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		inc     *filtermetric.MatchProperties
+		exc     *filtermetric.MatchProperties
+		cluster string
+		// out are the namespaces of the data points kept, by metric name.
+		out map[string][]string
+	}{
+		{
+			name:    "excludeDataPoints",
+			exc:     kubeSystem,
+			cluster: "prod-1",
+			out: map[string][]string{
+				"container.cpu": {"default"},
+				"host.cpu":      {"kube-system", "default"},
+			},
+		}, {
+			name:    "includeDataPoints",
+			inc:     kubeSystem,
+			cluster: "prod-1",
+			out: map[string][]string{
+				"container.cpu":    {"kube-system"},
+				"container.memory": {"kube-system"},
+			},
+		}, {
+			name:    "resourceMismatch",
+			exc:     kubeSystem,
+			cluster: "staging",
+			out: map[string][]string{
+				"container.cpu":    {"kube-system", "default"},
+				"container.memory": {"kube-system"},
+				"host.cpu":         {"kube-system", "default"},
+			},
+		}, {
+			name: "excludeMetricType",
+			exc: &filtermetric.MatchProperties{
+				Config: filterset.Config{
+					MatchType: filterset.Strict,
+				},
+				MetricTypes: []string{"INT64"},
+			},
+			cluster: "prod-1",
+			out: map[string][]string{
+				"container.cpu": {"kube-system", "default"},
+				"host.cpu":      {"kube-system", "default"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next := &etest.SinkMetricsExporter{}
+			cfg := &Config{
+				Metrics: MetricFilters{
+					Include: test.inc,
+					Exclude: test.exc,
+				},
+			}
+			fmp, err := newFilterMetricProcessor(next, cfg)
+			require.NoError(t, err)
+
+			md := data.NewMetricData()
+			md.ResourceMetrics().Resize(1)
+			rm := md.ResourceMetrics().At(0)
+			rm.Resource().InitEmpty()
+			rm.Resource().Attributes().InsertString("k8s.cluster.name", test.cluster)
+			rm.InstrumentationLibraryMetrics().Resize(1)
+			metrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+			metrics.Append(metricWithNamespaces("container.cpu", pdata.MetricTypeDouble, "kube-system", "default"))
+			metrics.Append(metricWithNamespaces("container.memory", pdata.MetricTypeInt64, "kube-system"))
+			metrics.Append(metricWithNamespaces("host.cpu", pdata.MetricTypeDouble, "kube-system", "default"))
+			require.NoError(t, fmp.ConsumeMetrics(context.Background(), pdatautil.MetricsFromInternalMetrics(md)))
+
+			got := next.AllMetrics()
+			require.Len(t, got, 1)
+			gotMetrics := pdatautil.MetricsToInternalMetrics(got[0]).ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+			out := make(map[string][]string)
+			for i := 0; i < gotMetrics.Len(); i++ {
+				metric := gotMetrics.At(i)
+				var namespaces []string
+				for j := 0; j < metric.DoubleDataPoints().Len(); j++ {
+					v, _ := metric.DoubleDataPoints().At(j).LabelsMap().Get("namespace")
+					namespaces = append(namespaces, v.Value())
+				}
+				for j := 0; j < metric.Int64DataPoints().Len(); j++ {
+					v, _ := metric.Int64DataPoints().At(j).LabelsMap().Get("namespace")
+					namespaces = append(namespaces, v.Value())
+				}
+				out[metric.MetricDescriptor().Name()] = namespaces
+			}
+			assert.Equal(t, test.out, out)
+		})
+	}
+}
+
 func BenchmarkFilter_MetricNames(b *testing.B) {
 	// runs 1000 metrics through a filterprocessor with both include and exclude filters.
 	stressTest := metricNameTest{
@@ -316,3 +426,26 @@ func metricsWithName(names []string) []*metricspb.Metric {
 	}
 	return ret
 }
+
+func metricWithNamespaces(name string, typ pdata.MetricType, namespaces ...string) *pdata.Metric {
+	metric := pdata.NewMetric()
+	metric.InitEmpty()
+	metric.MetricDescriptor().InitEmpty()
+	metric.MetricDescriptor().SetName(name)
+	metric.MetricDescriptor().SetType(typ)
+	for _, namespace := range namespaces {
+		labels := map[string]string{"namespace": namespace}
+		if typ == pdata.MetricTypeInt64 {
+			dp := pdata.NewInt64DataPoint()
+			dp.InitEmpty()
+			dp.LabelsMap().InitFromMap(labels)
+			metric.Int64DataPoints().Append(&dp)
+		} else {
+			dp := pdata.NewDoubleDataPoint()
+			dp.InitEmpty()
+			dp.LabelsMap().InitFromMap(labels)
+			metric.DoubleDataPoints().Append(&dp)
+		}
+	}
+	return &metric
+}
@@ -0,0 +1,337 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbyattrsprocessor
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/internal/data"
+	"go.opentelemetry.io/collector/internal/processor/timeseries"
+)
+
+type groupByAttrsProcessor struct {
+	keys []string
+
+	traceConsumer   consumer.TraceConsumer
+	metricsConsumer consumer.MetricsConsumer
+	logConsumer     consumer.LogConsumer
+}
+
+var _ component.TraceProcessor = (*groupByAttrsProcessor)(nil)
+var _ component.MetricsProcessor = (*groupByAttrsProcessor)(nil)
+var _ component.LogProcessor = (*groupByAttrsProcessor)(nil)
+
+func newGroupByAttrsProcessor(
+	traceConsumer consumer.TraceConsumer,
+	metricsConsumer consumer.MetricsConsumer,
+	logConsumer consumer.LogConsumer,
+	cfg *Config,
+) (*groupByAttrsProcessor, error) {
+	if len(cfg.Keys) == 0 {
+		return nil, errors.New("\"keys\" must be set")
+	}
+	for _, key := range cfg.Keys {
+		if key == "" {
+			return nil, errors.New("\"keys\" can't have an empty key")
+		}
+	}
+
+	return &groupByAttrsProcessor{
+		keys:            cfg.Keys,
+		traceConsumer:   traceConsumer,
+		metricsConsumer: metricsConsumer,
+		logConsumer:     logConsumer,
+	}, nil
+}
+
+// GetCapabilities returns the Capabilities assocciated with the group by attributes processor.
+func (*groupByAttrsProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: true}
+}
+
+// Start is invoked during service startup.
+func (*groupByAttrsProcessor) Start(_ context.Context, _ component.Host) error {
+	return nil
+}
+
+// Shutdown is invoked during service shutdown.
+func (*groupByAttrsProcessor) Shutdown(_ context.Context) error {
+	return nil
+}
+
+// ConsumeTraces regroups the spans by the values of the keys and forwards them to
+// the next consumer.
+func (p *groupByAttrsProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	return p.traceConsumer.ConsumeTraces(ctx, p.groupTraces(td))
+}
+
+// ConsumeMetrics regroups the data points by the values of the keys and forwards
+// them to the next consumer.
+func (p *groupByAttrsProcessor) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	grouped := p.groupMetrics(pdatautil.MetricsToInternalMetrics(md))
+	return p.metricsConsumer.ConsumeMetrics(ctx, pdatautil.MetricsFromInternalMetrics(grouped))
+}
+
+// ConsumeLogs regroups the log records by the values of the keys and forwards them
+// to the next consumer.
+func (p *groupByAttrsProcessor) ConsumeLogs(ctx context.Context, ld data.Logs) error {
+	return p.logConsumer.ConsumeLogs(ctx, p.groupLogs(ld))
+}
+
+// extractAttributes removes the attributes of the keys and returns them.
+func (p *groupByAttrsProcessor) extractAttributes(attrs pdata.AttributeMap) pdata.AttributeMap {
+	found := pdata.NewAttributeMap()
+	for _, key := range p.keys {
+		if v, ok := attrs.Get(key); ok {
+			found.Upsert(key, v)
+			attrs.Delete(key)
+		}
+	}
+	return found
+}
+
+// extractLabels removes the labels of the keys and returns them as attributes.
+func (p *groupByAttrsProcessor) extractLabels(labels pdata.StringMap) pdata.AttributeMap {
+	found := pdata.NewAttributeMap()
+	for _, key := range p.keys {
+		if v, ok := labels.Get(key); ok {
+			found.UpsertString(key, v.Value())
+			labels.Delete(key)
+		}
+	}
+	return found
+}
+
+// grouper identifies the groups of the records of a batch by the attributes of
+// their resource, with the grouping attributes promoted to it.
+type grouper struct {
+	// groups are the keys of the groups by input resource and grouping attributes.
+	groups map[string]string
+	// created are the keys of the groups already created.
+	created map[string]bool
+}
+
+func newGrouper() *grouper {
+	return &grouper{
+		groups:  make(map[string]string),
+		created: make(map[string]bool),
+	}
+}
+
+// group returns the key of the group of a record of the input resource, of the
+// index, with the grouping attributes found. The resource of the group is returned
+// if it has to be created.
+func (g *grouper) group(index int, resource pdata.Resource, found pdata.AttributeMap) (string, pdata.Resource, bool) {
+	inputKey := strconv.Itoa(index) + timeseries.KeySeparator + timeseries.AttributesKey(found)
+	if key, ok := g.groups[inputKey]; ok {
+		return key, pdata.Resource{}, false
+	}
+
+	// The grouping attributes override those of the resource.
+	grouped := pdata.NewResource()
+	grouped.InitEmpty()
+	if !resource.IsNil() {
+		resource.CopyTo(grouped)
+	}
+	found.ForEach(func(k string, v pdata.AttributeValue) {
+		grouped.Attributes().Upsert(k, v)
+	})
+
+	key := timeseries.ResourceKey(grouped)
+	g.groups[inputKey] = key
+	if g.created[key] {
+		return key, pdata.Resource{}, false
+	}
+	g.created[key] = true
+	return key, grouped, true
+}
+
+// groupTraces returns the traces with the spans regrouped by resource.
+func (p *groupByAttrsProcessor) groupTraces(td pdata.Traces) pdata.Traces {
+	out := pdata.NewTraces()
+	g := newGrouper()
+	resources := make(map[string]pdata.ResourceSpans)
+	libraries := make(map[string]pdata.InstrumentationLibrarySpans)
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
+			}
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
+				}
+
+				key, resource, created := g.group(i, rs.Resource(), p.extractAttributes(span.Attributes()))
+				if created {
+					ors := pdata.NewResourceSpans()
+					ors.InitEmpty()
+					resource.CopyTo(ors.Resource())
+					out.ResourceSpans().Append(&ors)
+					resources[key] = ors
+				}
+				libraryKey := key + timeseries.KeySeparator + timeseries.LibraryKey(ils.InstrumentationLibrary())
+				oils, ok := libraries[libraryKey]
+				if !ok {
+					oils = pdata.NewInstrumentationLibrarySpans()
+					oils.InitEmpty()
+					ils.InstrumentationLibrary().CopyTo(oils.InstrumentationLibrary())
+					resources[key].InstrumentationLibrarySpans().Append(&oils)
+					libraries[libraryKey] = oils
+				}
+				oils.Spans().Append(&span)
+			}
+		}
+	}
+	return out
+}
+
+// groupMetrics returns the metrics with the data points regrouped by resource.
+func (p *groupByAttrsProcessor) groupMetrics(md data.MetricData) data.MetricData {
+	out := data.NewMetricData()
+	g := newGrouper()
+	resources := make(map[string]pdata.ResourceMetrics)
+	libraries := make(map[string]pdata.InstrumentationLibraryMetrics)
+	metrics := make(map[string]pdata.Metric)
+
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				continue
+			}
+			for k := 0; k < ilm.Metrics().Len(); k++ {
+				metric := ilm.Metrics().At(k)
+				if metric.IsNil() || metric.MetricDescriptor().IsNil() {
+					continue
+				}
+				descriptor := metric.MetricDescriptor()
+
+				// groupMetric returns the metric, in the group of the data point, the
+				// data point is moved to.
+				groupMetric := func(labels pdata.StringMap) pdata.Metric {
+					key, resource, created := g.group(i, rm.Resource(), p.extractLabels(labels))
+					if created {
+						orm := pdata.NewResourceMetrics()
+						orm.InitEmpty()
+						resource.CopyTo(orm.Resource())
+						out.ResourceMetrics().Append(&orm)
+						resources[key] = orm
+					}
+					libraryKey := key + timeseries.KeySeparator + timeseries.LibraryKey(ilm.InstrumentationLibrary())
+					oilm, ok := libraries[libraryKey]
+					if !ok {
+						oilm = pdata.NewInstrumentationLibraryMetrics()
+						oilm.InitEmpty()
+						ilm.InstrumentationLibrary().CopyTo(oilm.InstrumentationLibrary())
+						resources[key].InstrumentationLibraryMetrics().Append(&oilm)
+						libraries[libraryKey] = oilm
+					}
+					metricKey := libraryKey + timeseries.KeySeparator + timeseries.DescriptorKey(descriptor)
+					om, ok := metrics[metricKey]
+					if !ok {
+						om = pdata.NewMetric()
+						om.InitEmpty()
+						descriptor.CopyTo(om.MetricDescriptor())
+						oilm.Metrics().Append(&om)
+						metrics[metricKey] = om
+					}
+					return om
+				}
+
+				int64Dps := metric.Int64DataPoints()
+				for l := 0; l < int64Dps.Len(); l++ {
+					if dp := int64Dps.At(l); !dp.IsNil() {
+						groupMetric(dp.LabelsMap()).Int64DataPoints().Append(&dp)
+					}
+				}
+				doubleDps := metric.DoubleDataPoints()
+				for l := 0; l < doubleDps.Len(); l++ {
+					if dp := doubleDps.At(l); !dp.IsNil() {
+						groupMetric(dp.LabelsMap()).DoubleDataPoints().Append(&dp)
+					}
+				}
+				histogramDps := metric.HistogramDataPoints()
+				for l := 0; l < histogramDps.Len(); l++ {
+					if dp := histogramDps.At(l); !dp.IsNil() {
+						groupMetric(dp.LabelsMap()).HistogramDataPoints().Append(&dp)
+					}
+				}
+				summaryDps := metric.SummaryDataPoints()
+				for l := 0; l < summaryDps.Len(); l++ {
+					if dp := summaryDps.At(l); !dp.IsNil() {
+						groupMetric(dp.LabelsMap()).SummaryDataPoints().Append(&dp)
+					}
+				}
+			}
+		}
+	}
+	return out
+}
+
+// groupLogs returns the logs with the log records regrouped by resource.
+func (p *groupByAttrsProcessor) groupLogs(ld data.Logs) data.Logs {
+	out := data.NewLogs()
+	g := newGrouper()
+	resources := make(map[string]pdata.ResourceLogs)
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		logs := rl.Logs()
+		for j := 0; j < logs.Len(); j++ {
+			lr := logs.At(j)
+			if lr.IsNil() {
+				continue
+			}
+
+			key, resource, created := g.group(i, rl.Resource(), p.extractAttributes(lr.Attributes()))
+			if created {
+				orl := pdata.NewResourceLogs()
+				orl.InitEmpty()
+				resource.CopyTo(orl.Resource())
+				out.ResourceLogs().Append(&orl)
+				resources[key] = orl
+			}
+			resources[key].Logs().Append(&lr)
+		}
+	}
+	return out
+}
@@ -0,0 +1,261 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbyattrsprocessor
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/data"
+)
+
+func TestNewProcessorInvalidConfig(t *testing.T) {
+	_, err := newGroupByAttrsProcessor(&exportertest.SinkTraceExporter{}, nil, nil, &Config{})
+	assert.Error(t, err)
+	_, err = newGroupByAttrsProcessor(&exportertest.SinkTraceExporter{}, nil, nil, &Config{Keys: []string{""}})
+	assert.Error(t, err)
+}
+
+func TestGroupTraces(t *testing.T) {
+	sink := &exportertest.SinkTraceExporter{}
+	p, err := newGroupByAttrsProcessor(sink, nil, nil, &Config{Keys: []string{"host.name", "tenant"}})
+	require.NoError(t, err)
+
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(2)
+	for i, service := range []string{"api", "api"} {
+		rs := td.ResourceSpans().At(i)
+		rs.Resource().InitEmpty()
+		rs.Resource().Attributes().InsertString("service.name", service)
+		rs.InstrumentationLibrarySpans().Resize(1)
+		rs.InstrumentationLibrarySpans().At(0).InstrumentationLibrary().InitEmpty()
+		rs.InstrumentationLibrarySpans().At(0).InstrumentationLibrary().SetName("lib")
+	}
+	// The resource attributes are overridden by the grouping attributes.
+	td.ResourceSpans().At(1).Resource().Attributes().InsertString("host.name", "default")
+
+	spans0 := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	spans0.Append(newSpan("a", map[string]string{"host.name": "h1", "http.method": "GET"}))
+	spans0.Append(newSpan("b", map[string]string{"host.name": "h2"}))
+	spans0.Append(newSpan("c", map[string]string{"host.name": "h1", "tenant": "t1"}))
+	spans0.Append(newSpan("d", nil))
+	spans1 := td.ResourceSpans().At(1).InstrumentationLibrarySpans().At(0).Spans()
+	spans1.Append(newSpan("e", map[string]string{"host.name": "h1"}))
+	spans1.Append(newSpan("f", nil))
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), td))
+	require.Len(t, sink.AllTraces(), 1)
+	got := sink.AllTraces()[0]
+
+	groups := make(map[string][]string)
+	rss := got.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		require.Equal(t, 1, rs.InstrumentationLibrarySpans().Len())
+		ils := rs.InstrumentationLibrarySpans().At(0)
+		assert.Equal(t, "lib", ils.InstrumentationLibrary().Name())
+		var names []string
+		for j := 0; j < ils.Spans().Len(); j++ {
+			span := ils.Spans().At(j)
+			_, ok := span.Attributes().Get("host.name")
+			assert.False(t, ok, "the grouping attributes are removed from the spans")
+			names = append(names, span.Name())
+		}
+		groups[resourceString(rs.Resource())] = names
+	}
+	assert.Equal(t, map[string][]string{
+		"host.name=h1,service.name=api":           {"a", "e"},
+		"host.name=h2,service.name=api":           {"b"},
+		"host.name=h1,service.name=api,tenant=t1": {"c"},
+		"service.name=api":                        {"d"},
+		"host.name=default,service.name=api":      {"f"},
+	}, groups)
+
+	// The other attributes are kept.
+	method, ok := rss.At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Attributes().Get("http.method")
+	require.True(t, ok)
+	assert.Equal(t, "GET", method.StringVal())
+}
+
+func TestGroupTracesDistinctResources(t *testing.T) {
+	sink := &exportertest.SinkTraceExporter{}
+	p, err := newGroupByAttrsProcessor(sink, nil, nil, &Config{Keys: []string{"host.name"}})
+	require.NoError(t, err)
+
+	// The resources only differ by the content of a map value or by the type of a
+	// value, they are not merged.
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(4)
+	for i := 0; i < 4; i++ {
+		rs := td.ResourceSpans().At(i)
+		rs.Resource().InitEmpty()
+		rs.InstrumentationLibrarySpans().Resize(1)
+		rs.InstrumentationLibrarySpans().At(0).Spans().Append(newSpan("span", map[string]string{"host.name": "h1"}))
+	}
+	for i, env := range []string{"prod", "dev"} {
+		tags := pdata.NewAttributeValueMap()
+		tags.SetMapVal(pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+			"env": pdata.NewAttributeValueString(env),
+		}))
+		td.ResourceSpans().At(i).Resource().Attributes().Insert("tags", tags)
+	}
+	td.ResourceSpans().At(2).Resource().Attributes().InsertString("pid", "1")
+	td.ResourceSpans().At(3).Resource().Attributes().InsertInt("pid", 1)
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), td))
+	require.Len(t, sink.AllTraces(), 1)
+	rss := sink.AllTraces()[0].ResourceSpans()
+	require.Equal(t, 4, rss.Len())
+	for i := 0; i < rss.Len(); i++ {
+		assert.Equal(t, 1, rss.At(i).InstrumentationLibrarySpans().At(0).Spans().Len())
+	}
+	env, _ := rss.At(1).Resource().Attributes().Get("tags")
+	value, _ := env.MapVal().Get("env")
+	assert.Equal(t, "dev", value.StringVal())
+	pid, _ := rss.At(3).Resource().Attributes().Get("pid")
+	assert.True(t, pid.Type() == pdata.AttributeValueINT)
+}
+
+func TestGroupMetrics(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	p, err := newGroupByAttrsProcessor(nil, sink, nil, &Config{Keys: []string{"host.name"}})
+	require.NoError(t, err)
+
+	md := data.NewMetricData()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	metrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+
+	requests := newMetric("requests", pdata.MetricTypeMonotonicInt64)
+	for _, host := range []string{"h1", "h2"} {
+		dp := pdata.NewInt64DataPoint()
+		dp.InitEmpty()
+		dp.LabelsMap().InitFromMap(map[string]string{"host.name": host, "code": "200"})
+		dp.SetValue(1)
+		requests.Int64DataPoints().Append(&dp)
+	}
+	metrics.Append(&requests)
+	latency := newMetric("latency", pdata.MetricTypeHistogram)
+	dp := pdata.NewHistogramDataPoint()
+	dp.InitEmpty()
+	dp.LabelsMap().InitFromMap(map[string]string{"host.name": "h1"})
+	dp.SetCount(3)
+	latency.HistogramDataPoints().Append(&dp)
+	metrics.Append(&latency)
+
+	require.NoError(t, p.ConsumeMetrics(context.Background(), pdatautil.MetricsFromInternalMetrics(md)))
+	require.Len(t, sink.AllMetrics(), 1)
+	got := pdatautil.MetricsToInternalMetrics(sink.AllMetrics()[0])
+
+	groups := make(map[string][]string)
+	rms := got.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		ilms := rms.At(i).InstrumentationLibraryMetrics()
+		require.Equal(t, 1, ilms.Len())
+		var names []string
+		for j := 0; j < ilms.At(0).Metrics().Len(); j++ {
+			metric := ilms.At(0).Metrics().At(j)
+			names = append(names, metric.MetricDescriptor().Name())
+			for k := 0; k < metric.Int64DataPoints().Len(); k++ {
+				labels := metric.Int64DataPoints().At(k).LabelsMap()
+				assert.Equal(t, 1, labels.Len())
+				_, ok := labels.Get("code")
+				assert.True(t, ok)
+			}
+		}
+		groups[resourceString(rms.At(i).Resource())] = names
+	}
+	assert.Equal(t, map[string][]string{
+		"host.name=h1": {"requests", "latency"},
+		"host.name=h2": {"requests"},
+	}, groups)
+}
+
+func TestGroupLogs(t *testing.T) {
+	sink := &exportertest.SinkLogExporter{}
+	p, err := newGroupByAttrsProcessor(nil, nil, sink, &Config{Keys: []string{"tenant"}})
+	require.NoError(t, err)
+
+	ld := data.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().InitEmpty()
+	rl.Resource().Attributes().InsertString("service.name", "api")
+	for _, tenant := range []string{"t1", "t2", "t1"} {
+		lr := pdata.NewLogRecord()
+		lr.InitEmpty()
+		lr.Attributes().InsertString("tenant", tenant)
+		lr.SetBody(tenant)
+		rl.Logs().Append(&lr)
+	}
+
+	require.NoError(t, p.ConsumeLogs(context.Background(), ld))
+	require.Len(t, sink.AllLogs(), 1)
+	got := sink.AllLogs()[0]
+
+	groups := make(map[string][]string)
+	rls := got.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		var bodies []string
+		for j := 0; j < rls.At(i).Logs().Len(); j++ {
+			lr := rls.At(i).Logs().At(j)
+			assert.Equal(t, 0, lr.Attributes().Len())
+			bodies = append(bodies, lr.Body())
+		}
+		groups[resourceString(rls.At(i).Resource())] = bodies
+	}
+	assert.Equal(t, map[string][]string{
+		"service.name=api,tenant=t1": {"t1", "t1"},
+		"service.name=api,tenant=t2": {"t2"},
+	}, groups)
+}
+
+func newSpan(name string, attrs map[string]string) *pdata.Span {
+	span := pdata.NewSpan()
+	span.InitEmpty()
+	span.SetName(name)
+	for k, v := range attrs {
+		span.Attributes().InsertString(k, v)
+	}
+	return &span
+}
+
+func newMetric(name string, typ pdata.MetricType) pdata.Metric {
+	metric := pdata.NewMetric()
+	metric.InitEmpty()
+	metric.MetricDescriptor().InitEmpty()
+	metric.MetricDescriptor().SetName(name)
+	metric.MetricDescriptor().SetType(typ)
+	return metric
+}
+
+// resourceString returns the sorted attributes of the resource.
+func resourceString(resource pdata.Resource) string {
+	var attrs []string
+	resource.Attributes().ForEach(func(k string, v pdata.AttributeValue) {
+		attrs = append(attrs, k+"="+v.StringVal())
+	})
+	sort.Strings(attrs)
+	return strings.Join(attrs, ",")
+}
@@ -0,0 +1,170 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package histogramprocessor
+
+import (
+	"math"
+	"sort"
+)
+
+// validBounds returns true if the bounds are strictly increasing.
+func validBounds(bounds []float64) bool {
+	for i := 1; i < len(bounds); i++ {
+		if !(bounds[i] > bounds[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// bucketIndex returns the index of the bucket, upper bound included, containing
+// the value.
+func bucketIndex(bounds []float64, v float64) int {
+	return sort.SearchFloat64s(bounds, v)
+}
+
+// rebin distributes the bucket counts of a histogram onto the buckets of the new
+// bounds. The counts are spread linearly over the range of their bucket, the
+// counts of the unbounded first and last buckets are placed at their bound.
+func rebin(bounds []float64, counts []uint64, newBounds []float64) []uint64 {
+	out := make([]float64, len(newBounds)+1)
+	for i, c := range counts {
+		if c == 0 {
+			continue
+		}
+		switch i {
+		case 0:
+			out[bucketIndex(newBounds, bounds[0])] += float64(c)
+		case len(bounds):
+			// The values are above the last bound, in the first bucket whose upper
+			// bound is greater.
+			last := bounds[len(bounds)-1]
+			out[sort.Search(len(newBounds), func(j int) bool { return newBounds[j] > last })] += float64(c)
+		default:
+			lo, hi := bounds[i-1], bounds[i]
+			for j := range out {
+				tlo, thi := math.Inf(-1), math.Inf(1)
+				if j > 0 {
+					tlo = newBounds[j-1]
+				}
+				if j < len(newBounds) {
+					thi = newBounds[j]
+				}
+				if overlap := math.Min(hi, thi) - math.Max(lo, tlo); overlap > 0 {
+					out[j] += float64(c) * overlap / (hi - lo)
+				}
+			}
+		}
+	}
+	return roundCounts(out)
+}
+
+// roundCounts rounds the fractional bucket counts, rounding their cumulative sums
+// so the total count is kept.
+func roundCounts(counts []float64) []uint64 {
+	out := make([]uint64, len(counts))
+	var cum float64
+	var prev uint64
+	for i, c := range counts {
+		cum += c
+		rounded := uint64(math.Round(cum))
+		out[i] = rounded - prev
+		prev = rounded
+	}
+	return out
+}
+
+// percentile returns the approximate value at the percentile, between 0 and 100,
+// of a histogram, interpolated linearly in its bucket. The lower bound of the first
+// bucket is 0 if its upper bound is positive, the values in the last bucket are
+// reported as its lower bound. It returns false if the histogram is empty or has
+// no bounds.
+func percentile(bounds []float64, counts []uint64, p float64) (float64, bool) {
+	var total uint64
+	for _, c := range counts {
+		total += c
+	}
+	if total == 0 || len(bounds) == 0 {
+		return 0, false
+	}
+
+	rank := p / 100 * float64(total)
+	var cum float64
+	for i, c := range counts {
+		if c == 0 || cum+float64(c) < rank {
+			cum += float64(c)
+			continue
+		}
+		if i == len(bounds) {
+			return bounds[len(bounds)-1], true
+		}
+		hi := bounds[i]
+		var lo float64
+		switch {
+		case i > 0:
+			lo = bounds[i-1]
+		case hi > 0:
+			lo = 0
+		default:
+			return hi, true
+		}
+		return lo + (hi-lo)*(rank-cum)/float64(c), true
+	}
+	return bounds[len(bounds)-1], true
+}
+
+// quantile is a value of a summary and the fraction, between 0 and 1, of the
+// values lower or equal to it.
+type quantile struct {
+	value    float64
+	fraction float64
+}
+
+// summaryCounts returns the bucket counts, for the bounds, of the distribution of
+// a summary. The distribution function is interpolated linearly between the
+// quantiles, the values below the first quantile are placed at it and the values
+// above the last quantile are placed above it.
+func summaryCounts(count uint64, quantiles []quantile, bounds []float64) []uint64 {
+	sort.Slice(quantiles, func(i, j int) bool {
+		return quantiles[i].value < quantiles[j].value
+	})
+	// The distribution function can't decrease.
+	for i := 1; i < len(quantiles); i++ {
+		quantiles[i].fraction = math.Max(quantiles[i].fraction, quantiles[i-1].fraction)
+	}
+
+	cdf := func(b float64) float64 {
+		last := len(quantiles) - 1
+		switch {
+		case b < quantiles[0].value:
+			return 0
+		case b >= quantiles[last].value:
+			return quantiles[last].fraction
+		}
+		k := sort.Search(len(quantiles), func(i int) bool { return quantiles[i].value > b }) - 1
+		lo, hi := quantiles[k], quantiles[k+1]
+		return lo.fraction + (hi.fraction-lo.fraction)*(b-lo.value)/(hi.value-lo.value)
+	}
+
+	out := make([]float64, len(bounds)+1)
+	var prev float64
+	for i, b := range bounds {
+		cum := cdf(b) * float64(count)
+		out[i] = cum - prev
+		prev = cum
+	}
+	out[len(bounds)] = float64(count) - prev
+	return roundCounts(out)
+}
@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package histogramprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebin(t *testing.T) {
+	// The counts of the unbounded buckets are placed at their bound, the other
+	// ones are spread over their bucket.
+	got := rebin([]float64{0, 10, 20}, []uint64{1, 10, 4, 2}, []float64{5, 15, 30})
+	assert.Equal(t, []uint64{6, 7, 4, 0}, got)
+
+	// The bucket counts are rounded without changing the total count.
+	got = rebin([]float64{0, 10}, []uint64{0, 3, 0}, []float64{2.5, 5, 7.5})
+	assert.Equal(t, []uint64{1, 1, 0, 1}, got)
+}
+
+func TestRoundCounts(t *testing.T) {
+	assert.Equal(t, []uint64{0, 1, 0, 1}, roundCounts([]float64{0.4, 0.4, 0.4, 0.8}))
+}
+
+func TestPercentile(t *testing.T) {
+	bounds := []float64{10, 20, 40}
+	counts := []uint64{2, 4, 4, 0}
+
+	tests := []struct {
+		percentile float64
+		want       float64
+	}{
+		{percentile: 0, want: 0},
+		{percentile: 10, want: 5},
+		{percentile: 50, want: 17.5},
+		{percentile: 100, want: 40},
+	}
+	for _, tt := range tests {
+		got, ok := percentile(bounds, counts, tt.percentile)
+		assert.True(t, ok)
+		assert.InDelta(t, tt.want, got, 1e-9, "percentile %v", tt.percentile)
+	}
+
+	// The values above the last bound are reported as the last bound.
+	got, ok := percentile(bounds, []uint64{0, 0, 0, 5}, 99)
+	assert.True(t, ok)
+	assert.Equal(t, 40.0, got)
+
+	// The first bucket has no lower bound if its upper bound is negative.
+	got, ok = percentile([]float64{-5, 5}, []uint64{2, 2, 0}, 25)
+	assert.True(t, ok)
+	assert.Equal(t, -5.0, got)
+
+	_, ok = percentile(bounds, []uint64{0, 0, 0, 0}, 50)
+	assert.False(t, ok)
+	_, ok = percentile(nil, []uint64{3}, 50)
+	assert.False(t, ok)
+}
+
+func TestSummaryCounts(t *testing.T) {
+	quantiles := []quantile{
+		{value: 50, fraction: 0.99},
+		{value: 10, fraction: 0.5},
+		{value: 20, fraction: 0.9},
+	}
+	got := summaryCounts(100, quantiles, []float64{5, 10, 15, 50, 100})
+	assert.Equal(t, []uint64{0, 50, 20, 29, 0, 1}, got)
+}
@@ -0,0 +1,40 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package histogramprocessor
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines the configuration for the histogram processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Metrics are the names of the metrics to process. All the histograms, and
+	// summaries if converted, are processed if empty.
+	Metrics []string `mapstructure:"metrics"`
+
+	// Buckets are the increasing bucket bounds the histograms are re-binned onto.
+	// The histograms keep their buckets if empty.
+	Buckets []float64 `mapstructure:"buckets"`
+
+	// ConvertSummaries converts the summaries into histograms with the Buckets,
+	// which must be set.
+	ConvertSummaries bool `mapstructure:"convert_summaries"`
+
+	// Percentiles are the percentiles, between 0 and 100, of the gauges derived from
+	// the histograms. No gauge is derived if empty.
+	Percentiles []float64 `mapstructure:"percentiles"`
+}
@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package histogramprocessor
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := config.ExampleComponents()
+	require.NoError(t, err)
+
+	factories.Processors[typeStr] = &Factory{}
+
+	cfg, err := config.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, cfg.Processors["histogram"], &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: "histogram",
+			NameVal: "histogram",
+		},
+	})
+
+	assert.Equal(t, cfg.Processors["histogram/custom"], &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: "histogram",
+			NameVal: "histogram/custom",
+		},
+		Metrics:          []string{"http.server.duration", "rpc.server.duration"},
+		Buckets:          []float64{0.005, 0.01, 0.05, 0.1, 0.5, 1, 5},
+		ConvertSummaries: true,
+		Percentiles:      []float64{50, 99.9},
+	})
+}
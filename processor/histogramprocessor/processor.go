@@ -0,0 +1,300 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package histogramprocessor
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+)
+
+const (
+	// percentileMetricSuffix is appended to the name of a histogram to name its
+	// percentile gauge.
+	percentileMetricSuffix = ".percentile"
+	// percentileLabel is the label of the percentile of a percentile gauge point.
+	percentileLabel = "percentile"
+)
+
+type histogramProcessor struct {
+	logger           *zap.Logger
+	next             consumer.MetricsConsumer
+	metrics          map[string]bool
+	buckets          []float64
+	convertSummaries bool
+	percentiles      []float64
+}
+
+var _ component.MetricsProcessor = (*histogramProcessor)(nil)
+
+func newHistogramProcessor(logger *zap.Logger, next consumer.MetricsConsumer, cfg *Config) (*histogramProcessor, error) {
+	if next == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	if len(cfg.Buckets) == 0 && len(cfg.Percentiles) == 0 {
+		return nil, errors.New("\"buckets\" or \"percentiles\" must be set")
+	}
+	if !validBounds(cfg.Buckets) {
+		return nil, errors.New("\"buckets\" must be increasing")
+	}
+	if cfg.ConvertSummaries && len(cfg.Buckets) == 0 {
+		return nil, errors.New("\"buckets\" must be set to convert summaries")
+	}
+	for _, p := range cfg.Percentiles {
+		if p < 0 || p > 100 {
+			return nil, errors.New("\"percentiles\" must be between 0 and 100")
+		}
+	}
+
+	metrics := make(map[string]bool, len(cfg.Metrics))
+	for _, name := range cfg.Metrics {
+		metrics[name] = true
+	}
+
+	return &histogramProcessor{
+		logger:           logger,
+		next:             next,
+		metrics:          metrics,
+		buckets:          cfg.Buckets,
+		convertSummaries: cfg.ConvertSummaries,
+		percentiles:      cfg.Percentiles,
+	}, nil
+}
+
+// GetCapabilities returns the Capabilities assocciated with the histogram processor.
+func (*histogramProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: true}
+}
+
+// Start is invoked during service startup.
+func (*histogramProcessor) Start(_ context.Context, _ component.Host) error {
+	return nil
+}
+
+// Shutdown is invoked during service shutdown.
+func (*histogramProcessor) Shutdown(_ context.Context) error {
+	return nil
+}
+
+// ConsumeMetrics derives the percentile gauges of the histograms, re-bins the
+// histograms, converts the summaries and forwards the metrics to the next consumer.
+func (p *histogramProcessor) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	imd := pdatautil.MetricsToInternalMetrics(md)
+
+	rms := imd.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				continue
+			}
+			p.processMetrics(ilm.Metrics())
+		}
+	}
+
+	return p.next.ConsumeMetrics(ctx, pdatautil.MetricsFromInternalMetrics(imd))
+}
+
+// processMetrics processes the metrics of an instrumentation library, the
+// percentile gauges are appended to them.
+func (p *histogramProcessor) processMetrics(metrics pdata.MetricSlice) {
+	// The percentile gauges appended to the slice are not processed.
+	n := metrics.Len()
+	for k := 0; k < n; k++ {
+		metric := metrics.At(k)
+		if metric.IsNil() || metric.MetricDescriptor().IsNil() {
+			continue
+		}
+		descriptor := metric.MetricDescriptor()
+		if len(p.metrics) > 0 && !p.metrics[descriptor.Name()] {
+			continue
+		}
+
+		switch descriptor.Type() {
+		case pdata.MetricTypeHistogram:
+			if len(p.percentiles) > 0 {
+				if gauge, ok := p.percentileMetric(metric); ok {
+					metrics.Append(&gauge)
+				}
+			}
+			if len(p.buckets) > 0 {
+				p.rebinMetric(metric)
+			}
+		case pdata.MetricTypeSummary:
+			if p.convertSummaries {
+				p.convertSummary(metric)
+			}
+		}
+	}
+}
+
+// percentileMetric returns the gauge of the percentiles of the points of a
+// histogram. It returns false if no percentile can be derived.
+func (p *histogramProcessor) percentileMetric(metric pdata.Metric) (pdata.Metric, bool) {
+	gauge := pdata.NewMetric()
+	gauge.InitEmpty()
+	descriptor := gauge.MetricDescriptor()
+	descriptor.InitEmpty()
+	descriptor.SetName(metric.MetricDescriptor().Name() + percentileMetricSuffix)
+	descriptor.SetDescription(metric.MetricDescriptor().Description())
+	descriptor.SetUnit(metric.MetricDescriptor().Unit())
+	descriptor.SetType(pdata.MetricTypeDouble)
+
+	gdps := gauge.DoubleDataPoints()
+	hdps := metric.HistogramDataPoints()
+	for i := 0; i < hdps.Len(); i++ {
+		hdp := hdps.At(i)
+		if hdp.IsNil() {
+			continue
+		}
+		bounds, counts := histogramBuckets(hdp)
+		if !validBuckets(bounds, counts) {
+			continue
+		}
+		for _, pct := range p.percentiles {
+			value, ok := percentile(bounds, counts, pct)
+			if !ok {
+				continue
+			}
+			gdp := pdata.NewDoubleDataPoint()
+			gdp.InitEmpty()
+			hdp.LabelsMap().CopyTo(gdp.LabelsMap())
+			gdp.LabelsMap().Upsert(percentileLabel, strconv.FormatFloat(pct, 'f', -1, 64))
+			gdp.SetTimestamp(hdp.Timestamp())
+			gdp.SetValue(value)
+			gdps.Append(&gdp)
+		}
+	}
+	return gauge, gdps.Len() > 0
+}
+
+// rebinMetric re-bins the points of a histogram onto the configured buckets.
+func (p *histogramProcessor) rebinMetric(metric pdata.Metric) {
+	dps := metric.HistogramDataPoints()
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		if dp.IsNil() {
+			continue
+		}
+		bounds, counts := histogramBuckets(dp)
+		// A histogram without bounds has no distribution to re-bin.
+		if len(bounds) == 0 || !validBuckets(bounds, counts) {
+			p.logger.Debug("Histogram point with invalid buckets not re-binned",
+				zap.String("metric", metric.MetricDescriptor().Name()))
+			continue
+		}
+
+		// The exemplars still reference the replaced buckets.
+		var exemplars []pdata.HistogramBucketExemplar
+		for j := 0; j < dp.Buckets().Len(); j++ {
+			bucket := dp.Buckets().At(j)
+			if bucket.IsNil() {
+				continue
+			}
+			if exemplar := bucket.Exemplar(); !exemplar.IsNil() {
+				exemplars = append(exemplars, exemplar)
+			}
+		}
+		p.setBuckets(dp, rebin(bounds, counts, p.buckets))
+		// The exemplars are kept in the bucket of their value, the first one wins.
+		for _, exemplar := range exemplars {
+			if dest := dp.Buckets().At(bucketIndex(p.buckets, exemplar.Value())).Exemplar(); dest.IsNil() {
+				exemplar.CopyTo(dest)
+			}
+		}
+	}
+}
+
+// convertSummary converts a summary into a histogram with the configured buckets.
+// The summary is kept if one of its points has no percentile.
+func (p *histogramProcessor) convertSummary(metric pdata.Metric) {
+	sdps := metric.SummaryDataPoints()
+	for i := 0; i < sdps.Len(); i++ {
+		if sdp := sdps.At(i); !sdp.IsNil() && sdp.ValueAtPercentiles().Len() == 0 {
+			p.logger.Debug("Summary without percentiles not converted",
+				zap.String("metric", metric.MetricDescriptor().Name()))
+			return
+		}
+	}
+
+	hdps := metric.HistogramDataPoints()
+	for i := 0; i < sdps.Len(); i++ {
+		sdp := sdps.At(i)
+		if sdp.IsNil() {
+			continue
+		}
+		quantiles := make([]quantile, 0, sdp.ValueAtPercentiles().Len())
+		for j := 0; j < sdp.ValueAtPercentiles().Len(); j++ {
+			vp := sdp.ValueAtPercentiles().At(j)
+			if !vp.IsNil() {
+				quantiles = append(quantiles, quantile{value: vp.Value(), fraction: vp.Percentile() / 100})
+			}
+		}
+
+		hdp := pdata.NewHistogramDataPoint()
+		hdp.InitEmpty()
+		sdp.LabelsMap().CopyTo(hdp.LabelsMap())
+		hdp.SetStartTime(sdp.StartTime())
+		hdp.SetTimestamp(sdp.Timestamp())
+		hdp.SetCount(sdp.Count())
+		hdp.SetSum(sdp.Sum())
+		p.setBuckets(hdp, summaryCounts(sdp.Count(), quantiles, p.buckets))
+		hdps.Append(&hdp)
+	}
+	sdps.Resize(0)
+	metric.MetricDescriptor().SetType(pdata.MetricTypeHistogram)
+}
+
+// setBuckets replaces the buckets of a histogram point with the configured
+// buckets and the counts.
+func (p *histogramProcessor) setBuckets(dp pdata.HistogramDataPoint, counts []uint64) {
+	bounds := make([]float64, len(p.buckets))
+	copy(bounds, p.buckets)
+	dp.SetExplicitBounds(bounds)
+	dp.Buckets().Resize(0)
+	dp.Buckets().Resize(len(counts))
+	for i, c := range counts {
+		dp.Buckets().At(i).SetCount(c)
+	}
+}
+
+// histogramBuckets returns the bounds and the bucket counts of a histogram point.
+func histogramBuckets(dp pdata.HistogramDataPoint) ([]float64, []uint64) {
+	counts := make([]uint64, dp.Buckets().Len())
+	for i := range counts {
+		if bucket := dp.Buckets().At(i); !bucket.IsNil() {
+			counts[i] = bucket.Count()
+		}
+	}
+	return dp.ExplicitBounds(), counts
+}
+
+// validBuckets returns true if the bounds are increasing and match the counts.
+func validBuckets(bounds []float64, counts []uint64) bool {
+	return len(counts) == len(bounds)+1 && validBounds(bounds)
+}
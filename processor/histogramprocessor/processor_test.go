@@ -0,0 +1,204 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package histogramprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/data"
+)
+
+func TestNewProcessorInvalidConfig(t *testing.T) {
+	_, err := newHistogramProcessor(zap.NewNop(), nil, &Config{Buckets: []float64{1}})
+	assert.Error(t, err)
+
+	invalid := []*Config{
+		{},
+		{Buckets: []float64{10, 1}},
+		{Percentiles: []float64{50}, ConvertSummaries: true},
+		{Percentiles: []float64{101}},
+	}
+	for _, cfg := range invalid {
+		_, err = newHistogramProcessor(zap.NewNop(), &exportertest.SinkMetricsExporter{}, cfg)
+		assert.Error(t, err)
+	}
+}
+
+func TestProcessMetrics(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	p, err := newHistogramProcessor(zap.NewNop(), sink, &Config{
+		Metrics:          []string{"latency", "rpc"},
+		Buckets:          []float64{5, 15, 30},
+		ConvertSummaries: true,
+		Percentiles:      []float64{50},
+	})
+	require.NoError(t, err)
+
+	latency := newHistogramMetric("latency", []float64{0, 10, 20}, []uint64{1, 10, 4, 2})
+	exemplar := latency.HistogramDataPoints().At(0).Buckets().At(1).Exemplar()
+	exemplar.InitEmpty()
+	exemplar.SetValue(7)
+	other := newHistogramMetric("other", []float64{0, 10, 20}, []uint64{1, 10, 4, 2})
+	rpc := newSummaryMetric("rpc", 100, map[float64]float64{50: 10, 90: 20, 99: 50})
+	require.NoError(t, p.ConsumeMetrics(context.Background(), newMetrics(latency, other, rpc)))
+
+	allMetrics := sink.AllMetrics()
+	require.Len(t, allMetrics, 1)
+	metrics := pdatautil.MetricsToInternalMetrics(allMetrics[0]).ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 4, metrics.Len())
+
+	// The histogram is re-binned, its exemplar is moved to the bucket of its value.
+	latencyDp := metrics.At(0).HistogramDataPoints().At(0)
+	assert.Equal(t, []float64{5, 15, 30}, latencyDp.ExplicitBounds())
+	assert.Equal(t, []uint64{6, 7, 4, 0}, bucketCounts(latencyDp))
+	assert.Equal(t, uint64(17), latencyDp.Count())
+	assert.Equal(t, 7.0, latencyDp.Buckets().At(1).Exemplar().Value())
+	assert.True(t, latencyDp.Buckets().At(0).Exemplar().IsNil())
+
+	// The metrics not selected are kept as is.
+	otherDp := metrics.At(1).HistogramDataPoints().At(0)
+	assert.Equal(t, []float64{0, 10, 20}, otherDp.ExplicitBounds())
+	assert.Equal(t, []uint64{1, 10, 4, 2}, bucketCounts(otherDp))
+
+	// The summary is converted to a histogram.
+	assert.Equal(t, pdata.MetricTypeHistogram, metrics.At(2).MetricDescriptor().Type())
+	assert.Equal(t, 0, metrics.At(2).SummaryDataPoints().Len())
+	require.Equal(t, 1, metrics.At(2).HistogramDataPoints().Len())
+	rpcDp := metrics.At(2).HistogramDataPoints().At(0)
+	assert.Equal(t, uint64(100), rpcDp.Count())
+	assert.Equal(t, 1000.0, rpcDp.Sum())
+	assert.Equal(t, []float64{5, 15, 30}, rpcDp.ExplicitBounds())
+	assert.Equal(t, []uint64{0, 70, 23, 7}, bucketCounts(rpcDp))
+
+	// The percentile gauge is derived from the histogram as received.
+	gauge := metrics.At(3)
+	assert.Equal(t, "latency.percentile", gauge.MetricDescriptor().Name())
+	assert.Equal(t, "ms", gauge.MetricDescriptor().Unit())
+	assert.Equal(t, pdata.MetricTypeDouble, gauge.MetricDescriptor().Type())
+	require.Equal(t, 1, gauge.DoubleDataPoints().Len())
+	gaugeDp := gauge.DoubleDataPoints().At(0)
+	assert.InDelta(t, 7.5, gaugeDp.Value(), 1e-9)
+	assert.Equal(t, pdata.TimestampUnixNano(20), gaugeDp.Timestamp())
+	value, ok := gaugeDp.LabelsMap().Get(percentileLabel)
+	require.True(t, ok)
+	assert.Equal(t, "50", value.Value())
+}
+
+func TestConvertSummaryWithoutPercentiles(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	p, err := newHistogramProcessor(zap.NewNop(), sink, &Config{
+		Buckets:          []float64{5, 15, 30},
+		ConvertSummaries: true,
+	})
+	require.NoError(t, err)
+
+	rpc := newSummaryMetric("rpc", 100, nil)
+	require.NoError(t, p.ConsumeMetrics(context.Background(), newMetrics(rpc)))
+
+	metric := pdatautil.MetricsToInternalMetrics(sink.AllMetrics()[0]).ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, pdata.MetricTypeSummary, metric.MetricDescriptor().Type())
+	assert.Equal(t, 1, metric.SummaryDataPoints().Len())
+}
+
+func TestProcessNilBucket(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	p, err := newHistogramProcessor(zap.NewNop(), sink, &Config{
+		Buckets: []float64{5, 15, 30},
+	})
+	require.NoError(t, err)
+
+	latency := newHistogramMetric("latency", []float64{0, 10, 20}, []uint64{1, 10, 4})
+	nilBucket := pdata.NewHistogramBucket()
+	latency.HistogramDataPoints().At(0).Buckets().Append(&nilBucket)
+	require.NoError(t, p.ConsumeMetrics(context.Background(), newMetrics(latency)))
+
+	// The nil bucket counts as empty.
+	metric := pdatautil.MetricsToInternalMetrics(sink.AllMetrics()[0]).ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	dp := metric.HistogramDataPoints().At(0)
+	assert.Equal(t, []float64{5, 15, 30}, dp.ExplicitBounds())
+	assert.Equal(t, []uint64{6, 7, 2, 0}, bucketCounts(dp))
+}
+
+func newMetrics(metrics ...pdata.Metric) pdata.Metrics {
+	md := data.NewMetricData()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	for i := range metrics {
+		rm.InstrumentationLibraryMetrics().At(0).Metrics().Append(&metrics[i])
+	}
+	return pdatautil.MetricsFromInternalMetrics(md)
+}
+
+func newMetric(name string, typ pdata.MetricType) pdata.Metric {
+	metric := pdata.NewMetric()
+	metric.InitEmpty()
+	metric.MetricDescriptor().InitEmpty()
+	metric.MetricDescriptor().SetName(name)
+	metric.MetricDescriptor().SetUnit("ms")
+	metric.MetricDescriptor().SetType(typ)
+	return metric
+}
+
+func newHistogramMetric(name string, bounds []float64, counts []uint64) pdata.Metric {
+	metric := newMetric(name, pdata.MetricTypeHistogram)
+	metric.HistogramDataPoints().Resize(1)
+	dp := metric.HistogramDataPoints().At(0)
+	dp.SetStartTime(10)
+	dp.SetTimestamp(20)
+	dp.SetExplicitBounds(bounds)
+	dp.Buckets().Resize(len(counts))
+	var count uint64
+	for i, c := range counts {
+		dp.Buckets().At(i).SetCount(c)
+		count += c
+	}
+	dp.SetCount(count)
+	return metric
+}
+
+func newSummaryMetric(name string, count uint64, percentiles map[float64]float64) pdata.Metric {
+	metric := newMetric(name, pdata.MetricTypeSummary)
+	metric.SummaryDataPoints().Resize(1)
+	dp := metric.SummaryDataPoints().At(0)
+	dp.SetStartTime(10)
+	dp.SetTimestamp(20)
+	dp.SetCount(count)
+	dp.SetSum(10 * float64(count))
+	for p, v := range percentiles {
+		vp := pdata.NewSummaryValueAtPercentile()
+		vp.InitEmpty()
+		vp.SetPercentile(p)
+		vp.SetValue(v)
+		dp.ValueAtPercentiles().Append(&vp)
+	}
+	return metric
+}
+
+func bucketCounts(dp pdata.HistogramDataPoint) []uint64 {
+	counts := make([]uint64, dp.Buckets().Len())
+	for i := range counts {
+		counts[i] = dp.Buckets().At(i).Count()
+	}
+	return counts
+}
@@ -0,0 +1,274 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intervalprocessor
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/data"
+	"go.opentelemetry.io/collector/internal/processor/timeseries"
+)
+
+// accumulator aggregates the points of each timeseries into the metrics emitted at
+// the end of the interval. The maps reference the resources, libraries, metrics
+// and points of the emitted metrics by key, to add the next points to them.
+type accumulator struct {
+	md        data.MetricData
+	resources map[string]pdata.ResourceMetrics
+	libraries map[string]pdata.InstrumentationLibraryMetrics
+	metrics   map[string]pdata.Metric
+
+	int64Points     map[string]pdata.Int64DataPoint
+	doublePoints    map[string]pdata.DoubleDataPoint
+	histogramPoints map[string]pdata.HistogramDataPoint
+	summaryPoints   map[string]pdata.SummaryDataPoint
+}
+
+func newAccumulator() *accumulator {
+	return &accumulator{
+		md:              data.NewMetricData(),
+		resources:       make(map[string]pdata.ResourceMetrics),
+		libraries:       make(map[string]pdata.InstrumentationLibraryMetrics),
+		metrics:         make(map[string]pdata.Metric),
+		int64Points:     make(map[string]pdata.Int64DataPoint),
+		doublePoints:    make(map[string]pdata.DoubleDataPoint),
+		histogramPoints: make(map[string]pdata.HistogramDataPoint),
+		summaryPoints:   make(map[string]pdata.SummaryDataPoint),
+	}
+}
+
+// pointCount returns the number of accumulated points, one per timeseries.
+func (a *accumulator) pointCount() int {
+	return len(a.int64Points) + len(a.doublePoints) + len(a.histogramPoints) + len(a.summaryPoints)
+}
+
+// add aggregates the points of the metrics into the accumulated ones.
+func (a *accumulator) add(md data.MetricData) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		resourceKey := timeseries.ResourceKey(rm.Resource())
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				continue
+			}
+			libraryKey := resourceKey + timeseries.KeySeparator + timeseries.LibraryKey(ilm.InstrumentationLibrary())
+			metrics := ilm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				if metric.IsNil() || metric.MetricDescriptor().IsNil() {
+					continue
+				}
+				a.addMetric(rm, ilm, resourceKey, libraryKey, metric)
+			}
+		}
+	}
+}
+
+func (a *accumulator) addMetric(rm pdata.ResourceMetrics, ilm pdata.InstrumentationLibraryMetrics, resourceKey, libraryKey string, metric pdata.Metric) {
+	descriptor := metric.MetricDescriptor()
+	metricKey := libraryKey + timeseries.KeySeparator + timeseries.DescriptorKey(descriptor)
+
+	// The accumulated metric is created lazily, along with its resource and library.
+	var accMetric pdata.Metric
+	created := false
+	getMetric := func() pdata.Metric {
+		if created {
+			return accMetric
+		}
+		created = true
+		var ok bool
+		if accMetric, ok = a.metrics[metricKey]; !ok {
+			accMetric = pdata.NewMetric()
+			accMetric.InitEmpty()
+			descriptor.CopyTo(accMetric.MetricDescriptor())
+			accIlm := a.library(rm, ilm, resourceKey, libraryKey)
+			accIlm.Metrics().Append(&accMetric)
+			a.metrics[metricKey] = accMetric
+		}
+		return accMetric
+	}
+
+	typ := descriptor.Type()
+	int64Dps := metric.Int64DataPoints()
+	for i := 0; i < int64Dps.Len(); i++ {
+		dp := int64Dps.At(i)
+		if dp.IsNil() {
+			continue
+		}
+		key := metricKey + timeseries.KeySeparator + timeseries.LabelsKey(dp.LabelsMap())
+		if acc, ok := a.int64Points[key]; ok {
+			mergeInt64(acc, dp, typ)
+			continue
+		}
+		acc := pdata.NewInt64DataPoint()
+		acc.InitEmpty()
+		dp.CopyTo(acc)
+		getMetric().Int64DataPoints().Append(&acc)
+		a.int64Points[key] = acc
+	}
+
+	doubleDps := metric.DoubleDataPoints()
+	for i := 0; i < doubleDps.Len(); i++ {
+		dp := doubleDps.At(i)
+		if dp.IsNil() {
+			continue
+		}
+		key := metricKey + timeseries.KeySeparator + timeseries.LabelsKey(dp.LabelsMap())
+		if acc, ok := a.doublePoints[key]; ok {
+			mergeDouble(acc, dp, typ)
+			continue
+		}
+		acc := pdata.NewDoubleDataPoint()
+		acc.InitEmpty()
+		dp.CopyTo(acc)
+		getMetric().DoubleDataPoints().Append(&acc)
+		a.doublePoints[key] = acc
+	}
+
+	histogramDps := metric.HistogramDataPoints()
+	for i := 0; i < histogramDps.Len(); i++ {
+		dp := histogramDps.At(i)
+		if dp.IsNil() {
+			continue
+		}
+		key := metricKey + timeseries.KeySeparator + timeseries.LabelsKey(dp.LabelsMap())
+		if acc, ok := a.histogramPoints[key]; ok {
+			mergeHistogram(acc, dp)
+			continue
+		}
+		acc := pdata.NewHistogramDataPoint()
+		acc.InitEmpty()
+		dp.CopyTo(acc)
+		getMetric().HistogramDataPoints().Append(&acc)
+		a.histogramPoints[key] = acc
+	}
+
+	summaryDps := metric.SummaryDataPoints()
+	for i := 0; i < summaryDps.Len(); i++ {
+		dp := summaryDps.At(i)
+		if dp.IsNil() {
+			continue
+		}
+		key := metricKey + timeseries.KeySeparator + timeseries.LabelsKey(dp.LabelsMap())
+		if acc, ok := a.summaryPoints[key]; ok {
+			if dp.Timestamp() >= acc.Timestamp() {
+				dp.CopyTo(acc)
+			}
+			continue
+		}
+		acc := pdata.NewSummaryDataPoint()
+		acc.InitEmpty()
+		dp.CopyTo(acc)
+		getMetric().SummaryDataPoints().Append(&acc)
+		a.summaryPoints[key] = acc
+	}
+}
+
+// library returns the accumulated instrumentation library metrics of the key,
+// created along with its resource if needed.
+func (a *accumulator) library(rm pdata.ResourceMetrics, ilm pdata.InstrumentationLibraryMetrics, resourceKey, libraryKey string) pdata.InstrumentationLibraryMetrics {
+	if accIlm, ok := a.libraries[libraryKey]; ok {
+		return accIlm
+	}
+
+	accRm, ok := a.resources[resourceKey]
+	if !ok {
+		accRm = pdata.NewResourceMetrics()
+		accRm.InitEmpty()
+		rm.Resource().CopyTo(accRm.Resource())
+		a.md.ResourceMetrics().Append(&accRm)
+		a.resources[resourceKey] = accRm
+	}
+
+	accIlm := pdata.NewInstrumentationLibraryMetrics()
+	accIlm.InitEmpty()
+	ilm.InstrumentationLibrary().CopyTo(accIlm.InstrumentationLibrary())
+	accRm.InstrumentationLibraryMetrics().Append(&accIlm)
+	a.libraries[libraryKey] = accIlm
+	return accIlm
+}
+
+// isDelta returns true if a point starting at start follows the accumulated point
+// ending at timestamp: the points are deltas to sum. The points of cumulative
+// values share their start time.
+func isDelta(timestamp, start pdata.TimestampUnixNano) bool {
+	return start != 0 && start >= timestamp
+}
+
+// mergeInt64 merges the point into the accumulated one: the last value of gauges,
+// the sum of deltas and the latest cumulative value.
+func mergeInt64(acc, dp pdata.Int64DataPoint, typ pdata.MetricType) {
+	if typ == pdata.MetricTypeMonotonicInt64 && isDelta(acc.Timestamp(), dp.StartTime()) {
+		acc.SetValue(acc.Value() + dp.Value())
+		acc.SetTimestamp(dp.Timestamp())
+		return
+	}
+	if dp.Timestamp() >= acc.Timestamp() {
+		dp.CopyTo(acc)
+	}
+}
+
+// mergeDouble is the equivalent of mergeInt64 for double points.
+func mergeDouble(acc, dp pdata.DoubleDataPoint, typ pdata.MetricType) {
+	if typ == pdata.MetricTypeMonotonicDouble && isDelta(acc.Timestamp(), dp.StartTime()) {
+		acc.SetValue(acc.Value() + dp.Value())
+		acc.SetTimestamp(dp.Timestamp())
+		return
+	}
+	if dp.Timestamp() >= acc.Timestamp() {
+		dp.CopyTo(acc)
+	}
+}
+
+// mergeHistogram merges the buckets of delta histograms with the same bounds, and
+// keeps the latest point otherwise.
+func mergeHistogram(acc, dp pdata.HistogramDataPoint) {
+	if isDelta(acc.Timestamp(), dp.StartTime()) && sameBounds(acc, dp) {
+		acc.SetCount(acc.Count() + dp.Count())
+		acc.SetSum(acc.Sum() + dp.Sum())
+		acc.SetTimestamp(dp.Timestamp())
+		accBuckets := acc.Buckets()
+		for i := 0; i < accBuckets.Len(); i++ {
+			if b := dp.Buckets().At(i); !b.IsNil() && !accBuckets.At(i).IsNil() {
+				accBuckets.At(i).SetCount(accBuckets.At(i).Count() + b.Count())
+			}
+		}
+		return
+	}
+	if dp.Timestamp() >= acc.Timestamp() {
+		dp.CopyTo(acc)
+	}
+}
+
+func sameBounds(a, b pdata.HistogramDataPoint) bool {
+	if a.Buckets().Len() != b.Buckets().Len() {
+		return false
+	}
+	aBounds, bBounds := a.ExplicitBounds(), b.ExplicitBounds()
+	if len(aBounds) != len(bBounds) {
+		return false
+	}
+	for i := range aBounds {
+		if aBounds[i] != bBounds[i] {
+			return false
+		}
+	}
+	return true
+}
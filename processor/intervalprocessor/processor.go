@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intervalprocessor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+)
+
+type intervalProcessor struct {
+	logger   *zap.Logger
+	next     consumer.MetricsConsumer
+	interval time.Duration
+
+	mu  sync.Mutex
+	acc *accumulator
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+var _ component.MetricsProcessor = (*intervalProcessor)(nil)
+
+func newIntervalProcessor(logger *zap.Logger, next consumer.MetricsConsumer, cfg *Config) (*intervalProcessor, error) {
+	if next == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	if cfg.Interval <= 0 {
+		return nil, errors.New("\"interval\" must be positive")
+	}
+
+	return &intervalProcessor{
+		logger:   logger,
+		next:     next,
+		interval: cfg.Interval,
+		acc:      newAccumulator(),
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// GetCapabilities returns the Capabilities assocciated with the interval processor.
+func (*intervalProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: false}
+}
+
+// Start starts emitting the aggregated metrics at each interval.
+func (ip *intervalProcessor) Start(_ context.Context, _ component.Host) error {
+	ip.wg.Add(1)
+	go ip.run()
+	return nil
+}
+
+// Shutdown stops the processor and emits the metrics aggregated so far.
+func (ip *intervalProcessor) Shutdown(ctx context.Context) error {
+	close(ip.done)
+	ip.wg.Wait()
+	return ip.flush(ctx)
+}
+
+// ConsumeMetrics aggregates the points of the metrics, which are emitted at the end
+// of the interval.
+func (ip *intervalProcessor) ConsumeMetrics(_ context.Context, md pdata.Metrics) error {
+	imd := pdatautil.MetricsToInternalMetrics(md)
+	ip.mu.Lock()
+	ip.acc.add(imd)
+	ip.mu.Unlock()
+	return nil
+}
+
+func (ip *intervalProcessor) run() {
+	defer ip.wg.Done()
+	ticker := time.NewTicker(ip.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := ip.flush(context.Background()); err != nil {
+				ip.logger.Warn("Failed to send the aggregated metrics", zap.Error(err))
+			}
+		case <-ip.done:
+			return
+		}
+	}
+}
+
+// flush sends the aggregated metrics to the next consumer and starts a new
+// interval.
+func (ip *intervalProcessor) flush(ctx context.Context) error {
+	ip.mu.Lock()
+	acc := ip.acc
+	ip.acc = newAccumulator()
+	ip.mu.Unlock()
+
+	if acc.pointCount() == 0 {
+		return nil
+	}
+	return ip.next.ConsumeMetrics(ctx, pdatautil.MetricsFromInternalMetrics(acc.md))
+}
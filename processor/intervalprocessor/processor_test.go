@@ -0,0 +1,187 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intervalprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/data"
+)
+
+type testPoint struct {
+	start pdata.TimestampUnixNano
+	ts    pdata.TimestampUnixNano
+	value int64
+}
+
+func TestNewProcessorInvalidConfig(t *testing.T) {
+	_, err := newIntervalProcessor(zap.NewNop(), nil, &Config{Interval: time.Minute})
+	assert.Error(t, err)
+
+	_, err = newIntervalProcessor(zap.NewNop(), &exportertest.SinkMetricsExporter{}, &Config{})
+	assert.Error(t, err)
+}
+
+func TestAggregate(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	ip, err := newIntervalProcessor(zap.NewNop(), sink, &Config{Interval: time.Minute})
+	require.NoError(t, err)
+
+	batches := [][]pdata.Metric{
+		{
+			newMetric("cpu", pdata.MetricTypeInt64, testPoint{start: 0, ts: 10, value: 1}),
+			newMetric("requests", pdata.MetricTypeMonotonicInt64, testPoint{start: 5, ts: 10, value: 3}),
+			newMetric("bytes", pdata.MetricTypeMonotonicDouble, testPoint{start: 5, ts: 10, value: 10}),
+			newHistogramMetric(5, 10, 1, 2),
+		},
+		{
+			newMetric("cpu", pdata.MetricTypeInt64, testPoint{start: 0, ts: 20, value: 2}),
+			newMetric("requests", pdata.MetricTypeMonotonicInt64, testPoint{start: 10, ts: 20, value: 4}),
+			newMetric("bytes", pdata.MetricTypeMonotonicDouble, testPoint{start: 5, ts: 20, value: 15}),
+			newHistogramMetric(10, 20, 3, 0),
+			// Older gauge points are ignored.
+			newMetric("cpu", pdata.MetricTypeInt64, testPoint{start: 0, ts: 15, value: 5}),
+		},
+	}
+	for _, metrics := range batches {
+		require.NoError(t, ip.ConsumeMetrics(context.Background(), pdatautil.MetricsFromInternalMetrics(newMetricData("a", metrics...))))
+	}
+	// The timeseries of another resource are aggregated separately.
+	require.NoError(t, ip.ConsumeMetrics(context.Background(), pdatautil.MetricsFromInternalMetrics(
+		newMetricData("b", newMetric("cpu", pdata.MetricTypeInt64, testPoint{start: 0, ts: 20, value: 7})))))
+	assert.Len(t, sink.AllMetrics(), 0)
+
+	require.NoError(t, ip.flush(context.Background()))
+	require.Len(t, sink.AllMetrics(), 1)
+	rms := pdatautil.MetricsToInternalMetrics(sink.AllMetrics()[0]).ResourceMetrics()
+	require.Equal(t, 2, rms.Len())
+
+	metrics := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 4, metrics.Len())
+	assert.Equal(t, []testPoint{{start: 0, ts: 20, value: 2}}, points(metrics.At(0)))
+	assert.Equal(t, []testPoint{{start: 5, ts: 20, value: 7}}, points(metrics.At(1)))
+	assert.Equal(t, []testPoint{{start: 5, ts: 20, value: 15}}, points(metrics.At(2)))
+
+	hdp := metrics.At(3).HistogramDataPoints().At(0)
+	assert.Equal(t, pdata.TimestampUnixNano(5), hdp.StartTime())
+	assert.Equal(t, pdata.TimestampUnixNano(20), hdp.Timestamp())
+	assert.Equal(t, uint64(6), hdp.Count())
+	assert.Equal(t, []uint64{4, 2}, []uint64{hdp.Buckets().At(0).Count(), hdp.Buckets().At(1).Count()})
+
+	metrics = rms.At(1).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 1, metrics.Len())
+	assert.Equal(t, []testPoint{{start: 0, ts: 20, value: 7}}, points(metrics.At(0)))
+
+	// The next interval starts empty, nothing is sent without points.
+	require.NoError(t, ip.flush(context.Background()))
+	assert.Len(t, sink.AllMetrics(), 1)
+}
+
+func TestShutdownFlushes(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	ip, err := newIntervalProcessor(zap.NewNop(), sink, &Config{Interval: time.Hour})
+	require.NoError(t, err)
+	require.NoError(t, ip.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.NoError(t, ip.ConsumeMetrics(context.Background(), pdatautil.MetricsFromInternalMetrics(
+		newMetricData("a", newMetric("cpu", pdata.MetricTypeInt64, testPoint{ts: 10, value: 1})))))
+	assert.Len(t, sink.AllMetrics(), 0)
+
+	require.NoError(t, ip.Shutdown(context.Background()))
+	assert.Len(t, sink.AllMetrics(), 1)
+}
+
+func newMetricData(host string, metrics ...pdata.Metric) data.MetricData {
+	md := data.NewMetricData()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.Resource().InitEmpty()
+	rm.Resource().Attributes().InsertString("host", host)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	ilm := rm.InstrumentationLibraryMetrics().At(0)
+	for i := range metrics {
+		ilm.Metrics().Append(&metrics[i])
+	}
+	return md
+}
+
+func newMetric(name string, typ pdata.MetricType, p testPoint) pdata.Metric {
+	metric := pdata.NewMetric()
+	metric.InitEmpty()
+	metric.MetricDescriptor().InitEmpty()
+	metric.MetricDescriptor().SetName(name)
+	metric.MetricDescriptor().SetType(typ)
+	switch typ {
+	case pdata.MetricTypeInt64, pdata.MetricTypeMonotonicInt64:
+		metric.Int64DataPoints().Resize(1)
+		dp := metric.Int64DataPoints().At(0)
+		dp.SetStartTime(p.start)
+		dp.SetTimestamp(p.ts)
+		dp.SetValue(p.value)
+	case pdata.MetricTypeDouble, pdata.MetricTypeMonotonicDouble:
+		metric.DoubleDataPoints().Resize(1)
+		dp := metric.DoubleDataPoints().At(0)
+		dp.SetStartTime(p.start)
+		dp.SetTimestamp(p.ts)
+		dp.SetValue(float64(p.value))
+	}
+	return metric
+}
+
+func newHistogramMetric(start, ts pdata.TimestampUnixNano, counts ...uint64) pdata.Metric {
+	metric := pdata.NewMetric()
+	metric.InitEmpty()
+	metric.MetricDescriptor().InitEmpty()
+	metric.MetricDescriptor().SetName("latency")
+	metric.MetricDescriptor().SetType(pdata.MetricTypeHistogram)
+	metric.HistogramDataPoints().Resize(1)
+	dp := metric.HistogramDataPoints().At(0)
+	dp.SetStartTime(start)
+	dp.SetTimestamp(ts)
+	dp.SetExplicitBounds([]float64{1})
+	dp.Buckets().Resize(len(counts))
+	var count uint64
+	for i, c := range counts {
+		dp.Buckets().At(i).SetCount(c)
+		count += c
+	}
+	dp.SetCount(count)
+	return metric
+}
+
+func points(metric pdata.Metric) []testPoint {
+	var pts []testPoint
+	int64Dps := metric.Int64DataPoints()
+	for i := 0; i < int64Dps.Len(); i++ {
+		dp := int64Dps.At(i)
+		pts = append(pts, testPoint{start: dp.StartTime(), ts: dp.Timestamp(), value: dp.Value()})
+	}
+	doubleDps := metric.DoubleDataPoints()
+	for i := 0; i < doubleDps.Len(); i++ {
+		dp := doubleDps.At(i)
+		pts = append(pts, testPoint{start: dp.StartTime(), ts: dp.Timestamp(), value: int64(dp.Value())})
+	}
+	return pts
+}
@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logmetricsprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/internal/processor/filterspan"
+)
+
+// MetricType is the type of a metric generated from the log records.
+type MetricType string
+
+const (
+	// Counter counts the matching log records.
+	Counter MetricType = "counter"
+	// Gauge reports the last value extracted from the matching log records.
+	Gauge MetricType = "gauge"
+	// Histogram reports the distribution of the values extracted from the matching
+	// log records.
+	Histogram MetricType = "histogram"
+)
+
+// Config defines the configuration for the log metrics processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// MetricsExporter is the name of the exporter, configured in a metrics pipeline,
+	// that receives the generated metrics.
+	MetricsExporter string `mapstructure:"metrics_exporter"`
+
+	// Metrics are the metrics generated from the log records.
+	Metrics []MetricConfig `mapstructure:"metrics"`
+
+	// SeriesExpiry is the duration after which a series without any selected
+	// record is forgotten, its values start again from zero afterwards.
+	SeriesExpiry time.Duration `mapstructure:"series_expiry"`
+}
+
+// MetricConfig defines a metric generated from the log records.
+type MetricConfig struct {
+	// Name is the name of the metric.
+	Name string `mapstructure:"name"`
+	// Description is the description of the metric.
+	Description string `mapstructure:"description"`
+	// Unit is the unit of the metric.
+	Unit string `mapstructure:"unit"`
+
+	// Type is the type of the metric, "counter" if empty.
+	Type MetricType `mapstructure:"type"`
+
+	// Match selects the log records of the metric, all the records are selected
+	// if empty.
+	Match MatchProperties `mapstructure:"match"`
+
+	// GroupBy are the attributes, of the log record or else of its resource, whose
+	// values label the points of the metric.
+	GroupBy []string `mapstructure:"group_by"`
+
+	// ValueAttribute is the numeric attribute holding the value of a gauge or a
+	// histogram. Either it or ValueRegexp must be set for these types.
+	ValueAttribute string `mapstructure:"value_attribute"`
+	// ValueRegexp is the regular expression whose first capture group, in the
+	// body of the log record, holds the value of a gauge or a histogram.
+	ValueRegexp string `mapstructure:"value_regexp"`
+
+	// Buckets are the strictly increasing bucket bounds of a histogram.
+	Buckets []float64 `mapstructure:"buckets"`
+}
+
+// MatchProperties specifies the properties a log record must have to be
+// selected. All the properties set must match.
+type MatchProperties struct {
+	// MinSeverity is the lowest severity, e.g. "WARN" or "ERROR2", of the
+	// selected records, compared to their severity number.
+	MinSeverity string `mapstructure:"min_severity"`
+
+	// SeverityTexts are the severity texts of the selected records, one of them
+	// must match.
+	SeverityTexts []string `mapstructure:"severity_texts"`
+
+	// BodyRegexp is the regular expression the body of the selected records must
+	// match.
+	BodyRegexp string `mapstructure:"body_regexp"`
+
+	// Attributes are the attributes the selected records must have, with the same
+	// value if it is set.
+	Attributes []filterspan.Attribute `mapstructure:"attributes"`
+}
@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logmetricsprocessor
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/internal/processor/filterspan"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := config.ExampleComponents()
+	require.NoError(t, err)
+
+	factories.Processors[typeStr] = &Factory{}
+
+	cfg, err := config.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, cfg.Processors["log_metrics"], &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: "log_metrics",
+			NameVal: "log_metrics",
+		},
+		MetricsExporter: "exampleexporter",
+		SeriesExpiry:    defaultSeriesExpiry,
+	})
+
+	assert.Equal(t, cfg.Processors["log_metrics/custom"], &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: "log_metrics",
+			NameVal: "log_metrics/custom",
+		},
+		MetricsExporter: "exampleexporter",
+		SeriesExpiry:    time.Hour,
+		Metrics: []MetricConfig{
+			{
+				Name:        "log.errors",
+				Description: "Count of error log records",
+				Match:       MatchProperties{MinSeverity: "ERROR"},
+				GroupBy:     []string{"service.name", "logger"},
+			},
+			{
+				Name: "log.timeouts",
+				Match: MatchProperties{
+					SeverityTexts: []string{"WARN", "WARNING"},
+					BodyRegexp:    "timed out",
+					Attributes:    []filterspan.Attribute{{Key: "component", Value: "db"}},
+				},
+			},
+			{
+				Name:        "http.request.duration",
+				Unit:        "ms",
+				Type:        Histogram,
+				Match:       MatchProperties{Attributes: []filterspan.Attribute{{Key: "http.method"}}},
+				ValueRegexp: `took (\d+(?:\.\d+)?)ms`,
+				Buckets:     []float64{10, 100, 1000},
+				GroupBy:     []string{"http.method"},
+			},
+			{
+				Name:           "queue.size",
+				Type:           Gauge,
+				ValueAttribute: "queue.size",
+			},
+		},
+	})
+}
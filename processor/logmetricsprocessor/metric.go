@@ -0,0 +1,372 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logmetricsprocessor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	logsproto "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/logs/v1"
+	"go.opentelemetry.io/collector/internal/processor/filterhelper"
+	"go.opentelemetry.io/collector/internal/processor/timeseries"
+)
+
+type attributeMatcher struct {
+	key   string
+	value *pdata.AttributeValue
+}
+
+// series is the state of a timeseries of a metric.
+type series struct {
+	labels map[string]string
+	// start is the start time of the cumulative values of the series.
+	start time.Time
+	// lastSeen is the time of the last record of the series, used to expire it.
+	lastSeen time.Time
+	// updated is true if a record was selected since the series was last sent.
+	updated bool
+
+	count        int64
+	value        float64
+	sum          float64
+	bucketCounts []uint64
+}
+
+// logMetric generates a metric from the log records.
+type logMetric struct {
+	name        string
+	description string
+	unit        string
+	metricType  MetricType
+	groupBy     []string
+
+	minSeverity   logsproto.SeverityNumber
+	severityTexts map[string]bool
+	bodyRegexp    *regexp.Regexp
+	attributes    []attributeMatcher
+
+	valueAttribute string
+	valueRegexp    *regexp.Regexp
+	buckets        []float64
+
+	series map[string]*series
+}
+
+func newLogMetric(cfg MetricConfig) (*logMetric, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("\"name\" must be set")
+	}
+	m := &logMetric{
+		name:           cfg.Name,
+		description:    cfg.Description,
+		unit:           cfg.Unit,
+		metricType:     cfg.Type,
+		groupBy:        cfg.GroupBy,
+		valueAttribute: cfg.ValueAttribute,
+		buckets:        cfg.Buckets,
+		series:         make(map[string]*series),
+	}
+	if m.metricType == "" {
+		m.metricType = Counter
+	}
+
+	switch m.metricType {
+	case Counter:
+	case Gauge, Histogram:
+		if (cfg.ValueAttribute == "") == (cfg.ValueRegexp == "") {
+			return nil, fmt.Errorf("metric %q: either \"value_attribute\" or \"value_regexp\" must be set", cfg.Name)
+		}
+		if cfg.ValueRegexp != "" {
+			re, err := regexp.Compile(cfg.ValueRegexp)
+			if err != nil {
+				return nil, fmt.Errorf("metric %q: invalid \"value_regexp\": %v", cfg.Name, err)
+			}
+			if re.NumSubexp() == 0 {
+				return nil, fmt.Errorf("metric %q: \"value_regexp\" must have a capture group", cfg.Name)
+			}
+			m.valueRegexp = re
+		}
+	default:
+		return nil, fmt.Errorf("metric %q: unsupported \"type\" %q", cfg.Name, cfg.Type)
+	}
+	if m.metricType == Histogram {
+		if len(cfg.Buckets) == 0 || !increasing(cfg.Buckets) {
+			return nil, fmt.Errorf("metric %q: \"buckets\" must be set in strictly increasing order", cfg.Name)
+		}
+	}
+
+	if err := m.initMatch(cfg.Match); err != nil {
+		return nil, fmt.Errorf("metric %q: %v", cfg.Name, err)
+	}
+	return m, nil
+}
+
+// initMatch compiles the properties selecting the log records.
+func (m *logMetric) initMatch(mp MatchProperties) error {
+	if mp.MinSeverity != "" {
+		severity, ok := logsproto.SeverityNumber_value[strings.ToUpper(mp.MinSeverity)]
+		if !ok {
+			return fmt.Errorf("unknown \"min_severity\" %q", mp.MinSeverity)
+		}
+		m.minSeverity = logsproto.SeverityNumber(severity)
+	}
+
+	if len(mp.SeverityTexts) > 0 {
+		m.severityTexts = make(map[string]bool, len(mp.SeverityTexts))
+		for _, text := range mp.SeverityTexts {
+			m.severityTexts[text] = true
+		}
+	}
+
+	if mp.BodyRegexp != "" {
+		re, err := regexp.Compile(mp.BodyRegexp)
+		if err != nil {
+			return fmt.Errorf("invalid \"body_regexp\": %v", err)
+		}
+		m.bodyRegexp = re
+	}
+
+	for _, attribute := range mp.Attributes {
+		if attribute.Key == "" {
+			return errors.New("can't have empty key in the list of attributes")
+		}
+		am := attributeMatcher{key: attribute.Key}
+		if attribute.Value != nil {
+			val, err := filterhelper.NewAttributeValueRaw(attribute.Value)
+			if err != nil {
+				return err
+			}
+			am.value = &val
+		}
+		m.attributes = append(m.attributes, am)
+	}
+	return nil
+}
+
+// matches returns true if the log record is selected for the metric.
+func (m *logMetric) matches(lr pdata.LogRecord) bool {
+	if m.minSeverity != logsproto.SeverityNumber_UNDEFINED_SEVERITY_NUMBER && lr.SeverityNumber() < m.minSeverity {
+		return false
+	}
+	if m.severityTexts != nil && !m.severityTexts[lr.SeverityText()] {
+		return false
+	}
+	if m.bodyRegexp != nil && !m.bodyRegexp.MatchString(lr.Body()) {
+		return false
+	}
+	for _, am := range m.attributes {
+		attr, ok := lr.Attributes().Get(am.key)
+		if !ok || (am.value != nil && !attr.Equal(*am.value)) {
+			return false
+		}
+	}
+	return true
+}
+
+// extractValue returns the value of a gauge or a histogram held by the log record.
+func (m *logMetric) extractValue(lr pdata.LogRecord) (float64, bool) {
+	if m.valueRegexp != nil {
+		match := m.valueRegexp.FindStringSubmatch(lr.Body())
+		if match == nil {
+			return 0, false
+		}
+		value, err := strconv.ParseFloat(match[1], 64)
+		return value, err == nil
+	}
+
+	attr, ok := lr.Attributes().Get(m.valueAttribute)
+	if !ok {
+		return 0, false
+	}
+	switch attr.Type() {
+	case pdata.AttributeValueINT:
+		return float64(attr.IntVal()), true
+	case pdata.AttributeValueDOUBLE:
+		return attr.DoubleVal(), true
+	case pdata.AttributeValueSTRING:
+		value, err := strconv.ParseFloat(attr.StringVal(), 64)
+		return value, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// record updates the metric with the log record, it returns true if the record
+// was selected.
+func (m *logMetric) record(resource pdata.Resource, lr pdata.LogRecord, now time.Time) bool {
+	if !m.matches(lr) {
+		return false
+	}
+	var value float64
+	if m.metricType != Counter {
+		var ok bool
+		if value, ok = m.extractValue(lr); !ok {
+			return false
+		}
+	}
+
+	s := m.getSeries(resource, lr, now)
+	s.lastSeen = now
+	s.updated = true
+	s.count++
+	switch m.metricType {
+	case Gauge:
+		s.value = value
+	case Histogram:
+		s.sum += value
+		// The bounds are the inclusive upper bounds of the buckets.
+		s.bucketCounts[sort.SearchFloat64s(m.buckets, value)]++
+	}
+	return true
+}
+
+// getSeries returns the timeseries of the log record, labeled with the values of
+// the group by attributes.
+func (m *logMetric) getSeries(resource pdata.Resource, lr pdata.LogRecord, now time.Time) *series {
+	labels := make(map[string]string, len(m.groupBy))
+	var key strings.Builder
+	for _, name := range m.groupBy {
+		// The records without the attribute have no label, unlike those with an
+		// empty value.
+		if value, ok := groupByValue(resource, lr, name); ok {
+			labels[name] = value
+			key.WriteByte(1)
+			key.WriteString(value)
+		}
+		key.WriteByte(0)
+	}
+
+	s, ok := m.series[key.String()]
+	if !ok {
+		s = &series{labels: labels, start: now}
+		if m.metricType == Histogram {
+			s.bucketCounts = make([]uint64, len(m.buckets)+1)
+		}
+		m.series[key.String()] = s
+	}
+	return s
+}
+
+// expire removes the series without any record since the given time, they start
+// again from zero if new records are selected.
+func (m *logMetric) expire(before time.Time) {
+	for key, s := range m.series {
+		if s.lastSeen.Before(before) {
+			delete(m.series, key)
+		}
+	}
+}
+
+// appendTo appends the metric, with the current value of the timeseries updated
+// since the last call, to the metrics. Nothing is appended if no series was
+// updated.
+func (m *logMetric) appendTo(metrics pdata.MetricSlice, now time.Time) {
+	var updated []*series
+	for _, s := range m.series {
+		if s.updated {
+			updated = append(updated, s)
+			s.updated = false
+		}
+	}
+	if len(updated) == 0 {
+		return
+	}
+
+	metric := pdata.NewMetric()
+	metric.InitEmpty()
+	descriptor := metric.MetricDescriptor()
+	descriptor.InitEmpty()
+	descriptor.SetName(m.name)
+	descriptor.SetDescription(m.description)
+	descriptor.SetUnit(m.unit)
+
+	timestamp := toTimestamp(now)
+	switch m.metricType {
+	case Counter:
+		descriptor.SetType(pdata.MetricTypeMonotonicInt64)
+		dps := metric.Int64DataPoints()
+		dps.Resize(len(updated))
+		for i, s := range updated {
+			dp := dps.At(i)
+			dp.LabelsMap().InitFromMap(s.labels)
+			dp.SetStartTime(toTimestamp(s.start))
+			dp.SetTimestamp(timestamp)
+			dp.SetValue(s.count)
+		}
+	case Gauge:
+		descriptor.SetType(pdata.MetricTypeDouble)
+		dps := metric.DoubleDataPoints()
+		dps.Resize(len(updated))
+		for i, s := range updated {
+			dp := dps.At(i)
+			dp.LabelsMap().InitFromMap(s.labels)
+			dp.SetTimestamp(timestamp)
+			dp.SetValue(s.value)
+		}
+	case Histogram:
+		descriptor.SetType(pdata.MetricTypeHistogram)
+		dps := metric.HistogramDataPoints()
+		dps.Resize(len(updated))
+		for i, s := range updated {
+			dp := dps.At(i)
+			dp.LabelsMap().InitFromMap(s.labels)
+			dp.SetStartTime(toTimestamp(s.start))
+			dp.SetTimestamp(timestamp)
+			dp.SetCount(uint64(s.count))
+			dp.SetSum(s.sum)
+			dp.SetExplicitBounds(m.buckets)
+			dp.Buckets().Resize(len(s.bucketCounts))
+			for j, count := range s.bucketCounts {
+				dp.Buckets().At(j).SetCount(count)
+			}
+		}
+	}
+	metrics.Append(&metric)
+}
+
+// increasing returns true if the bounds are strictly increasing.
+func increasing(bounds []float64) bool {
+	for i := 1; i < len(bounds); i++ {
+		if !(bounds[i] > bounds[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+func toTimestamp(t time.Time) pdata.TimestampUnixNano {
+	return pdata.TimestampUnixNano(uint64(t.UnixNano()))
+}
+
+// groupByValue returns the value of the attribute of the log record or else of
+// its resource.
+func groupByValue(resource pdata.Resource, lr pdata.LogRecord, name string) (string, bool) {
+	if attr, ok := lr.Attributes().Get(name); ok {
+		return timeseries.AttributeValueString(attr), true
+	}
+	if resource.IsNil() {
+		return "", false
+	}
+	if attr, ok := resource.Attributes().Get(name); ok {
+		return timeseries.AttributeValueString(attr), true
+	}
+	return "", false
+}
@@ -0,0 +1,185 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logmetricsprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/internal/data"
+)
+
+// sweepsPerExpiry is how many times per series expiry the idle series are removed.
+const sweepsPerExpiry = 10
+
+type logMetricsProcessor struct {
+	logger *zap.Logger
+	config Config
+
+	nextConsumer    consumer.LogConsumer
+	metricsExporter consumer.MetricsConsumer
+
+	// lock protects the state of the metrics.
+	lock      sync.Mutex
+	metrics   []*logMetric
+	lastSweep time.Time
+}
+
+var _ component.LogProcessor = (*logMetricsProcessor)(nil)
+
+func newLogMetricsProcessor(logger *zap.Logger, nextConsumer consumer.LogConsumer, cfg *Config) (*logMetricsProcessor, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	if len(cfg.Metrics) == 0 {
+		return nil, errors.New("\"metrics\" must be set")
+	}
+	if cfg.SeriesExpiry <= 0 {
+		return nil, errors.New("\"series_expiry\" must be positive")
+	}
+
+	names := make(map[string]bool, len(cfg.Metrics))
+	metrics := make([]*logMetric, 0, len(cfg.Metrics))
+	for _, mc := range cfg.Metrics {
+		if names[mc.Name] {
+			return nil, fmt.Errorf("metric %q is defined more than once", mc.Name)
+		}
+		names[mc.Name] = true
+		m, err := newLogMetric(mc)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, m)
+	}
+
+	return &logMetricsProcessor{
+		logger:       logger,
+		config:       *cfg,
+		nextConsumer: nextConsumer,
+		metrics:      metrics,
+	}, nil
+}
+
+// Start looks for the metrics exporter that receives the generated metrics.
+func (p *logMetricsProcessor) Start(ctx context.Context, host component.Host) error {
+	exporters := host.GetExporters()[configmodels.MetricsDataType]
+	for cfg, exp := range exporters {
+		if cfg.Name() != p.config.MetricsExporter {
+			continue
+		}
+		metricsExporter, ok := exp.(consumer.MetricsConsumer)
+		if !ok {
+			return fmt.Errorf("the exporter %q isn't a metrics exporter", p.config.MetricsExporter)
+		}
+		p.metricsExporter = metricsExporter
+		return nil
+	}
+	return fmt.Errorf("failed to find metrics exporter %q, it must be configured in a metrics pipeline", p.config.MetricsExporter)
+}
+
+// Shutdown is invoked during service shutdown.
+func (p *logMetricsProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+// GetCapabilities returns the Capabilities assocciated with the log metrics processor.
+func (p *logMetricsProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: false}
+}
+
+// ConsumeLogs updates the metrics with the log records, sends the series updated
+// by the records to the metrics exporter and forwards the logs to the next
+// consumer.
+func (p *logMetricsProcessor) ConsumeLogs(ctx context.Context, ld data.Logs) error {
+	if md, ok := p.recordLogs(ld, time.Now()); ok {
+		if err := p.metricsExporter.ConsumeMetrics(ctx, md); err != nil {
+			p.logger.Warn("Failed to export log metrics", zap.Error(err))
+		}
+	}
+	return p.nextConsumer.ConsumeLogs(ctx, ld)
+}
+
+// recordLogs updates the metrics with the log records and returns the series
+// they updated, it returns false if no record was selected.
+func (p *logMetricsProcessor) recordLogs(ld data.Logs, now time.Time) (pdata.Metrics, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.sweep(now)
+	updated := false
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		logs := rl.Logs()
+		for j := 0; j < logs.Len(); j++ {
+			lr := logs.At(j)
+			if lr.IsNil() {
+				continue
+			}
+			for _, m := range p.metrics {
+				if m.record(rl.Resource(), lr, now) {
+					updated = true
+				}
+			}
+		}
+	}
+	if !updated {
+		return pdata.Metrics{}, false
+	}
+	return p.buildMetrics(now), true
+}
+
+// buildMetrics returns the series updated since the last call, cumulative since
+// the start of each series.
+func (p *logMetricsProcessor) buildMetrics(now time.Time) pdata.Metrics {
+	md := data.NewMetricData()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	ilm := rm.InstrumentationLibraryMetrics().At(0)
+	ilm.InstrumentationLibrary().InitEmpty()
+	ilm.InstrumentationLibrary().SetName(typeStr)
+	for _, m := range p.metrics {
+		m.appendTo(ilm.Metrics(), now)
+	}
+
+	return pdatautil.MetricsFromInternalMetrics(md)
+}
+
+// sweep removes the series without any record over the series expiry, a few
+// times per expiry.
+func (p *logMetricsProcessor) sweep(now time.Time) {
+	if now.Sub(p.lastSweep) < p.config.SeriesExpiry/sweepsPerExpiry {
+		return
+	}
+	p.lastSweep = now
+	for _, m := range p.metrics {
+		m.expire(now.Add(-p.config.SeriesExpiry))
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logmetricsprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/data"
+	logsproto "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/logs/v1"
+	"go.opentelemetry.io/collector/internal/processor/filterspan"
+)
+
+type mockHost struct {
+	componenttest.NopHost
+	exporters map[configmodels.DataType]map[configmodels.Exporter]component.Exporter
+}
+
+func (h *mockHost) GetExporters() map[configmodels.DataType]map[configmodels.Exporter]component.Exporter {
+	return h.exporters
+}
+
+func newMockHost(dataType configmodels.DataType, name string, exp component.Exporter) *mockHost {
+	return &mockHost{
+		exporters: map[configmodels.DataType]map[configmodels.Exporter]component.Exporter{
+			dataType: {
+				&configmodels.ExporterSettings{TypeVal: "mock", NameVal: name}: exp,
+			},
+		},
+	}
+}
+
+func testConfig() *Config {
+	return &Config{
+		MetricsExporter: "mock",
+		SeriesExpiry:    time.Minute,
+		Metrics: []MetricConfig{
+			{
+				Name:    "log.errors",
+				Match:   MatchProperties{MinSeverity: "error"},
+				GroupBy: []string{"service.name", "logger"},
+			},
+			{
+				Name: "log.timeouts",
+				Match: MatchProperties{
+					SeverityTexts: []string{"WARN"},
+					BodyRegexp:    "timed out",
+					Attributes:    []filterspan.Attribute{{Key: "component", Value: "db"}},
+				},
+			},
+			{
+				Name:        "http.request.duration",
+				Unit:        "ms",
+				Type:        Histogram,
+				ValueRegexp: `took (\d+(?:\.\d+)?)ms`,
+				Buckets:     []float64{10, 100, 1000},
+				GroupBy:     []string{"http.method"},
+			},
+			{
+				Name:           "queue.size",
+				Type:           Gauge,
+				ValueAttribute: "queue.size",
+			},
+			{
+				Name:  "log.fatal",
+				Match: MatchProperties{MinSeverity: "FATAL"},
+			},
+		},
+	}
+}
+
+func TestProcessorStart(t *testing.T) {
+	p, err := newLogMetricsProcessor(zap.NewNop(), &exportertest.SinkLogExporter{}, testConfig())
+	require.NoError(t, err)
+
+	assert.NoError(t, p.Start(context.Background(), newMockHost(configmodels.MetricsDataType, "mock", &exportertest.SinkMetricsExporter{})))
+	assert.Error(t, p.Start(context.Background(), newMockHost(configmodels.MetricsDataType, "other", &exportertest.SinkMetricsExporter{})))
+	assert.Error(t, p.Start(context.Background(), newMockHost(configmodels.MetricsDataType, "mock", &exportertest.SinkTraceExporter{})))
+}
+
+func TestNewProcessorInvalidConfig(t *testing.T) {
+	_, err := newLogMetricsProcessor(zap.NewNop(), nil, testConfig())
+	assert.Error(t, err)
+
+	invalid := [][]MetricConfig{
+		nil,
+		{{}},
+		{{Name: "a"}, {Name: "a"}},
+		{{Name: "a", Type: "summary"}},
+		{{Name: "a", Match: MatchProperties{MinSeverity: "LOUD"}}},
+		{{Name: "a", Match: MatchProperties{BodyRegexp: "("}}},
+		{{Name: "a", Match: MatchProperties{Attributes: []filterspan.Attribute{{Value: "v"}}}}},
+		{{Name: "a", Type: Gauge}},
+		{{Name: "a", Type: Gauge, ValueAttribute: "v", ValueRegexp: `(\d+)`}},
+		{{Name: "a", Type: Gauge, ValueRegexp: `\d+`}},
+		{{Name: "a", Type: Histogram, ValueAttribute: "v"}},
+		{{Name: "a", Type: Histogram, ValueAttribute: "v", Buckets: []float64{10, 1}}},
+		{{Name: "a", Type: Histogram, ValueAttribute: "v", Buckets: []float64{1, 1}}},
+	}
+	for _, metrics := range invalid {
+		cfg := testConfig()
+		cfg.Metrics = metrics
+		_, err = newLogMetricsProcessor(zap.NewNop(), &exportertest.SinkLogExporter{}, cfg)
+		assert.Error(t, err, "%+v", metrics)
+	}
+
+	cfg := testConfig()
+	cfg.SeriesExpiry = 0
+	_, err = newLogMetricsProcessor(zap.NewNop(), &exportertest.SinkLogExporter{}, cfg)
+	assert.Error(t, err)
+}
+
+func TestProcessorConsumeLogs(t *testing.T) {
+	logsSink := &exportertest.SinkLogExporter{}
+	metricsSink := &exportertest.SinkMetricsExporter{}
+	p, err := newLogMetricsProcessor(zap.NewNop(), logsSink, testConfig())
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), newMockHost(configmodels.MetricsDataType, "mock", metricsSink)))
+
+	dbError := newLogRecord(logsproto.SeverityNumber_ERROR, "", "db query timed out")
+	dbError.Attributes().InsertString("component", "db")
+	dbError.Attributes().InsertString("logger", "db")
+	getRequest := newLogRecord(logsproto.SeverityNumber_ERROR2, "", "request took 25ms")
+	getRequest.Attributes().InsertString("http.method", "GET")
+	getRequest.Attributes().InsertString("logger", "http")
+	postRequest := newLogRecord(logsproto.SeverityNumber_INFO, "", "request took 250.5ms")
+	postRequest.Attributes().InsertString("http.method", "POST")
+	postRequest.Attributes().InsertInt("queue.size", 3)
+	dbWarning := newLogRecord(logsproto.SeverityNumber_WARN, "WARN", "call timed out")
+	dbWarning.Attributes().InsertString("component", "db")
+	dbWarning.Attributes().InsertString("queue.size", "7")
+	require.NoError(t, p.ConsumeLogs(context.Background(), newLogs("api", dbError, getRequest, postRequest, dbWarning)))
+
+	allMetrics := metricsSink.AllMetrics()
+	require.Len(t, allMetrics, 1)
+	metrics := pdatautil.MetricsToInternalMetrics(allMetrics[0]).ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	// The metrics without any selected record are not reported.
+	require.Equal(t, 4, metrics.Len())
+
+	errorLogs := metrics.At(0)
+	assert.Equal(t, "log.errors", errorLogs.MetricDescriptor().Name())
+	assert.Equal(t, pdata.MetricTypeMonotonicInt64, errorLogs.MetricDescriptor().Type())
+	errorCounts := make(map[string]int64)
+	for i := 0; i < errorLogs.Int64DataPoints().Len(); i++ {
+		dp := errorLogs.Int64DataPoints().At(i)
+		labels := labelsToMap(dp.LabelsMap())
+		assert.Equal(t, "api", labels["service.name"])
+		errorCounts[labels["logger"]] = dp.Value()
+	}
+	assert.Equal(t, map[string]int64{"db": 1, "http": 1}, errorCounts)
+
+	timeouts := metrics.At(1)
+	assert.Equal(t, "log.timeouts", timeouts.MetricDescriptor().Name())
+	require.Equal(t, 1, timeouts.Int64DataPoints().Len())
+	assert.Equal(t, int64(1), timeouts.Int64DataPoints().At(0).Value())
+	assert.Equal(t, 0, timeouts.Int64DataPoints().At(0).LabelsMap().Len())
+
+	duration := metrics.At(2)
+	assert.Equal(t, "http.request.duration", duration.MetricDescriptor().Name())
+	assert.Equal(t, "ms", duration.MetricDescriptor().Unit())
+	assert.Equal(t, pdata.MetricTypeHistogram, duration.MetricDescriptor().Type())
+	durationCounts := make(map[string][]uint64)
+	for i := 0; i < duration.HistogramDataPoints().Len(); i++ {
+		dp := duration.HistogramDataPoints().At(i)
+		assert.Equal(t, []float64{10, 100, 1000}, dp.ExplicitBounds())
+		assert.Equal(t, uint64(1), dp.Count())
+		durationCounts[labelsToMap(dp.LabelsMap())["http.method"]] = bucketCounts(dp)
+	}
+	assert.Equal(t, map[string][]uint64{"GET": {0, 1, 0, 0}, "POST": {0, 0, 1, 0}}, durationCounts)
+
+	queueSize := metrics.At(3)
+	assert.Equal(t, "queue.size", queueSize.MetricDescriptor().Name())
+	assert.Equal(t, pdata.MetricTypeDouble, queueSize.MetricDescriptor().Type())
+	require.Equal(t, 1, queueSize.DoubleDataPoints().Len())
+	assert.Equal(t, 7.0, queueSize.DoubleDataPoints().At(0).Value())
+
+	// No metric is sent if no record is selected, the logs are always forwarded.
+	debug := newLogRecord(logsproto.SeverityNumber_DEBUG, "", "request took a while")
+	require.NoError(t, p.ConsumeLogs(context.Background(), newLogs("api", debug)))
+	assert.Len(t, metricsSink.AllMetrics(), 1)
+	assert.Len(t, logsSink.AllLogs(), 2)
+
+	// The metrics are cumulative, only the updated series are sent.
+	require.NoError(t, p.ConsumeLogs(context.Background(), newLogs("api", dbError)))
+	require.Len(t, metricsSink.AllMetrics(), 2)
+	metrics = pdatautil.MetricsToInternalMetrics(metricsSink.AllMetrics()[1]).ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 1, metrics.Len())
+	errorLogs = metrics.At(0)
+	assert.Equal(t, "log.errors", errorLogs.MetricDescriptor().Name())
+	require.Equal(t, 1, errorLogs.Int64DataPoints().Len())
+	assert.Equal(t, "db", labelsToMap(errorLogs.Int64DataPoints().At(0).LabelsMap())["logger"])
+	assert.Equal(t, int64(2), errorLogs.Int64DataPoints().At(0).Value())
+}
+
+func TestProcessorExpireSeries(t *testing.T) {
+	p, err := newLogMetricsProcessor(zap.NewNop(), &exportertest.SinkLogExporter{}, testConfig())
+	require.NoError(t, err)
+
+	now := time.Unix(1000, 0)
+	httpError := newLogRecord(logsproto.SeverityNumber_ERROR, "", "request failed")
+	httpError.Attributes().InsertString("logger", "http")
+	dbError := newLogRecord(logsproto.SeverityNumber_ERROR, "", "query failed")
+	dbError.Attributes().InsertString("logger", "db")
+	_, ok := p.recordLogs(newLogs("api", httpError, dbError), now)
+	require.True(t, ok)
+	_, ok = p.recordLogs(newLogs("api", dbError), now.Add(50*time.Second))
+	require.True(t, ok)
+
+	// The http series is idle for the whole expiry, only the db series is kept.
+	md, ok := p.recordLogs(newLogs("api", httpError), now.Add(70*time.Second))
+	require.True(t, ok)
+	assert.Len(t, p.metrics[0].series, 2)
+	errorLogs := pdatautil.MetricsToInternalMetrics(md).ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	require.Equal(t, 1, errorLogs.Int64DataPoints().Len())
+	dp := errorLogs.Int64DataPoints().At(0)
+	// The expired series starts again from zero.
+	assert.Equal(t, int64(1), dp.Value())
+	assert.Equal(t, pdata.TimestampUnixNano(uint64(now.Add(70*time.Second).UnixNano())), dp.StartTime())
+
+	_, ok = p.recordLogs(newLogs("api", newLogRecord(logsproto.SeverityNumber_DEBUG, "", "debug")), now.Add(3*time.Minute))
+	assert.False(t, ok)
+	assert.Len(t, p.metrics[0].series, 0)
+}
+
+func newLogs(serviceName string, records ...pdata.LogRecord) data.Logs {
+	ld := data.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().InitEmpty()
+	rl.Resource().Attributes().InsertString("service.name", serviceName)
+	for i := range records {
+		rl.Logs().Append(&records[i])
+	}
+	return ld
+}
+
+func newLogRecord(severity logsproto.SeverityNumber, severityText, body string) pdata.LogRecord {
+	lr := pdata.NewLogRecord()
+	lr.InitEmpty()
+	lr.SetSeverityNumber(severity)
+	lr.SetSeverityText(severityText)
+	lr.SetBody(body)
+	return lr
+}
+
+func labelsToMap(labels pdata.StringMap) map[string]string {
+	m := make(map[string]string)
+	labels.ForEach(func(k string, v pdata.StringValue) {
+		m[k] = v.Value()
+	})
+	return m
+}
+
+func bucketCounts(dp pdata.HistogramDataPoint) []uint64 {
+	counts := make([]uint64, dp.Buckets().Len())
+	for i := range counts {
+		counts[i] = dp.Buckets().At(i).Count()
+	}
+	return counts
+}
@@ -26,6 +26,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/consumer/pdatautil"
 	"go.opentelemetry.io/collector/internal/data"
@@ -35,7 +36,8 @@ import (
 
 var (
 	// errForcedDrop will be returned to callers of ConsumeTraceData to indicate
-	// that data is being dropped due to high memory usage.
+	// that data is being dropped due to high memory usage. It is wrapped as a
+	// throttled error so that receivers can signal senders to retry later.
 	errForcedDrop = errors.New("data dropped due to high memory usage")
 
 	// Construction errors
@@ -141,7 +143,7 @@ func (ml *memoryLimiter) ConsumeTraces(
 		// 	callstack.
 		obsreport.ProcessorTraceDataRefused(ctx, numSpans)
 
-		return errForcedDrop
+		return ml.refusedError()
 	}
 
 	// Even if the next consumer returns error record the data as accepted by
@@ -162,7 +164,7 @@ func (ml *memoryLimiter) ConsumeMetrics(ctx context.Context, md pdata.Metrics) e
 		// 	callstack.
 		obsreport.ProcessorMetricsDataRefused(ctx, numDataPoints)
 
-		return errForcedDrop
+		return ml.refusedError()
 	}
 
 	// Even if the next consumer returns error record the data as accepted by
@@ -183,7 +185,7 @@ func (ml *memoryLimiter) ConsumeLogs(ctx context.Context, ld data.Logs) error {
 		// 	callstack.
 		obsreport.ProcessorLogRecordsRefused(ctx, numRecords)
 
-		return errForcedDrop
+		return ml.refusedError()
 	}
 
 	// Even if the next consumer returns error record the data as accepted by
@@ -196,6 +198,12 @@ func (ml *memoryLimiter) GetCapabilities() component.ProcessorCapabilities {
 	return component.ProcessorCapabilities{MutatesConsumedData: false}
 }
 
+// refusedError returns the error for refused data, it suggests senders to
+// retry once the memory usage is checked again.
+func (ml *memoryLimiter) refusedError() error {
+	return consumererror.Throttled(errForcedDrop, ml.memCheckWait)
+}
+
 func (ml *memoryLimiter) Start(_ context.Context, _ component.Host) error {
 	return nil
 }
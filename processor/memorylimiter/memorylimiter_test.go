@@ -16,6 +16,7 @@ package memorylimiter
 
 import (
 	"context"
+	"errors"
 	"runtime"
 	"testing"
 	"time"
@@ -123,7 +124,7 @@ func TestMetricsMemoryPressureResponse(t *testing.T) {
 	// Above memAllocLimit.
 	currentMemAlloc = 1800
 	ml.memCheck()
-	assert.Equal(t, errForcedDrop, ml.ConsumeMetrics(ctx, pdatautil.MetricsFromInternalMetrics(md)))
+	assert.True(t, errors.Is(ml.ConsumeMetrics(ctx, pdatautil.MetricsFromInternalMetrics(md)), errForcedDrop))
 
 	// Check ballast effect
 	ml.ballastSize = 1000
@@ -136,7 +137,7 @@ func TestMetricsMemoryPressureResponse(t *testing.T) {
 	// Above memAllocLimit even accountiing for ballast.
 	currentMemAlloc = 1800 + ml.ballastSize
 	ml.memCheck()
-	assert.Equal(t, errForcedDrop, ml.ConsumeMetrics(ctx, pdatautil.MetricsFromInternalMetrics(md)))
+	assert.True(t, errors.Is(ml.ConsumeMetrics(ctx, pdatautil.MetricsFromInternalMetrics(md)), errForcedDrop))
 
 	// Restore ballast to default.
 	ml.ballastSize = 0
@@ -152,7 +153,7 @@ func TestMetricsMemoryPressureResponse(t *testing.T) {
 	// Above memSpikeLimit.
 	currentMemAlloc = 550
 	ml.memCheck()
-	assert.Equal(t, errForcedDrop, ml.ConsumeMetrics(ctx, pdatautil.MetricsFromInternalMetrics(md)))
+	assert.True(t, errors.Is(ml.ConsumeMetrics(ctx, pdatautil.MetricsFromInternalMetrics(md)), errForcedDrop))
 
 }
 
@@ -180,7 +181,7 @@ func TestTraceMemoryPressureResponse(t *testing.T) {
 	// Above memAllocLimit.
 	currentMemAlloc = 1800
 	ml.memCheck()
-	assert.Equal(t, errForcedDrop, ml.ConsumeTraces(ctx, td))
+	assert.True(t, errors.Is(ml.ConsumeTraces(ctx, td), errForcedDrop))
 
 	// Check ballast effect
 	ml.ballastSize = 1000
@@ -193,7 +194,7 @@ func TestTraceMemoryPressureResponse(t *testing.T) {
 	// Above memAllocLimit even accountiing for ballast.
 	currentMemAlloc = 1800 + ml.ballastSize
 	ml.memCheck()
-	assert.Equal(t, errForcedDrop, ml.ConsumeTraces(ctx, td))
+	assert.True(t, errors.Is(ml.ConsumeTraces(ctx, td), errForcedDrop))
 
 	// Restore ballast to default.
 	ml.ballastSize = 0
@@ -209,7 +210,7 @@ func TestTraceMemoryPressureResponse(t *testing.T) {
 	// Above memSpikeLimit.
 	currentMemAlloc = 550
 	ml.memCheck()
-	assert.Equal(t, errForcedDrop, ml.ConsumeTraces(ctx, td))
+	assert.True(t, errors.Is(ml.ConsumeTraces(ctx, td), errForcedDrop))
 
 }
 
@@ -237,7 +238,7 @@ func TestLogMemoryPressureResponse(t *testing.T) {
 	// Above memAllocLimit.
 	currentMemAlloc = 1800
 	ml.memCheck()
-	assert.Equal(t, errForcedDrop, ml.ConsumeLogs(ctx, ld))
+	assert.True(t, errors.Is(ml.ConsumeLogs(ctx, ld), errForcedDrop))
 
 	// Check ballast effect
 	ml.ballastSize = 1000
@@ -250,7 +251,7 @@ func TestLogMemoryPressureResponse(t *testing.T) {
 	// Above memAllocLimit even accountiing for ballast.
 	currentMemAlloc = 1800 + ml.ballastSize
 	ml.memCheck()
-	assert.Equal(t, errForcedDrop, ml.ConsumeLogs(ctx, ld))
+	assert.True(t, errors.Is(ml.ConsumeLogs(ctx, ld), errForcedDrop))
 
 	// Restore ballast to default.
 	ml.ballastSize = 0
@@ -266,5 +267,5 @@ func TestLogMemoryPressureResponse(t *testing.T) {
 	// Above memSpikeLimit.
 	currentMemAlloc = 550
 	ml.memCheck()
-	assert.Equal(t, errForcedDrop, ml.ConsumeLogs(ctx, ld))
+	assert.True(t, errors.Is(ml.ConsumeLogs(ctx, ld), errForcedDrop))
 }
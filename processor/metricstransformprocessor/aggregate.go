@@ -0,0 +1,199 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"strconv"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/timeseries"
+)
+
+// aggregateDataPoints merges the data points of the metric that have the same labels
+// and timestamp. The values of the merged Int64 and Double data points are aggregated
+// with the aggregation type, histograms are summed if they have the same bounds.
+// The merged data points keep the earliest start time.
+func aggregateDataPoints(metric pdata.Metric, aggType AggregationType) {
+	aggregateInt64DataPoints(metric.Int64DataPoints(), aggType)
+	aggregateDoubleDataPoints(metric.DoubleDataPoints(), aggType)
+	aggregateHistogramDataPoints(metric.HistogramDataPoints())
+}
+
+func aggregateInt64DataPoints(dps pdata.Int64DataPointSlice, aggType AggregationType) {
+	var keys []string
+	groups := make(map[string][]pdata.Int64DataPoint)
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		if dp.IsNil() {
+			continue
+		}
+		key := dataPointKey(dp.LabelsMap(), dp.Timestamp())
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], dp)
+	}
+
+	merged := pdata.NewInt64DataPointSlice()
+	for _, key := range keys {
+		group := groups[key]
+		first := group[0]
+		values := make([]int64, len(group))
+		for i, dp := range group {
+			values[i] = dp.Value()
+			if dp.StartTime() < first.StartTime() {
+				first.SetStartTime(dp.StartTime())
+			}
+		}
+		first.SetValue(aggregateInt64(values, aggType))
+		merged.Append(&first)
+	}
+	dps.Resize(0)
+	merged.MoveAndAppendTo(dps)
+}
+
+func aggregateDoubleDataPoints(dps pdata.DoubleDataPointSlice, aggType AggregationType) {
+	var keys []string
+	groups := make(map[string][]pdata.DoubleDataPoint)
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		if dp.IsNil() {
+			continue
+		}
+		key := dataPointKey(dp.LabelsMap(), dp.Timestamp())
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], dp)
+	}
+
+	merged := pdata.NewDoubleDataPointSlice()
+	for _, key := range keys {
+		group := groups[key]
+		first := group[0]
+		values := make([]float64, len(group))
+		for i, dp := range group {
+			values[i] = dp.Value()
+			if dp.StartTime() < first.StartTime() {
+				first.SetStartTime(dp.StartTime())
+			}
+		}
+		first.SetValue(aggregate(values, aggType))
+		merged.Append(&first)
+	}
+	dps.Resize(0)
+	merged.MoveAndAppendTo(dps)
+}
+
+func aggregateHistogramDataPoints(dps pdata.HistogramDataPointSlice) {
+	var keys []string
+	groups := make(map[string][]pdata.HistogramDataPoint)
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		if dp.IsNil() {
+			continue
+		}
+		key := dataPointKey(dp.LabelsMap(), dp.Timestamp()) + timeseries.KeySeparator + boundsKey(dp.ExplicitBounds())
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], dp)
+	}
+
+	merged := pdata.NewHistogramDataPointSlice()
+	for _, key := range keys {
+		group := groups[key]
+		first := group[0]
+		for _, dp := range group[1:] {
+			if dp.StartTime() < first.StartTime() {
+				first.SetStartTime(dp.StartTime())
+			}
+			first.SetCount(first.Count() + dp.Count())
+			first.SetSum(first.Sum() + dp.Sum())
+			buckets := dp.Buckets()
+			if first.Buckets().Len() < buckets.Len() {
+				first.Buckets().Resize(buckets.Len())
+			}
+			for i := 0; i < buckets.Len(); i++ {
+				bucket := first.Buckets().At(i)
+				if bucket.IsNil() {
+					bucket.InitEmpty()
+				}
+				if !buckets.At(i).IsNil() {
+					bucket.SetCount(bucket.Count() + buckets.At(i).Count())
+				}
+			}
+		}
+		merged.Append(&first)
+	}
+	dps.Resize(0)
+	merged.MoveAndAppendTo(dps)
+}
+
+func boundsKey(bounds []float64) string {
+	key := make([]byte, 0, len(bounds)*8)
+	for _, bound := range bounds {
+		key = strconv.AppendFloat(key, bound, 'g', -1, 64)
+		key = append(key, ',')
+	}
+	return string(key)
+}
+
+// aggregateInt64 returns the aggregation of the values, which must not be empty.
+// The mean is rounded towards zero.
+func aggregateInt64(values []int64, aggType AggregationType) int64 {
+	result := values[0]
+	for _, v := range values[1:] {
+		switch aggType {
+		case Sum, Mean:
+			result += v
+		case Max:
+			if v > result {
+				result = v
+			}
+		case Min:
+			if v < result {
+				result = v
+			}
+		}
+	}
+	if aggType == Mean {
+		result /= int64(len(values))
+	}
+	return result
+}
+
+// aggregate returns the aggregation of the values, which must not be empty.
+func aggregate(values []float64, aggType AggregationType) float64 {
+	result := values[0]
+	for _, v := range values[1:] {
+		switch aggType {
+		case Sum, Mean:
+			result += v
+		case Max:
+			if v > result {
+				result = v
+			}
+		case Min:
+			if v < result {
+				result = v
+			}
+		}
+	}
+	if aggType == Mean {
+		result /= float64(len(values))
+	}
+	return result
+}
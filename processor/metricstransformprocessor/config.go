@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines the configuration for the metrics transform processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Transforms are the transformations applied, in the order they are specified,
+	// to the metrics.
+	Transforms []Transform `mapstructure:"transforms"`
+}
+
+// MatchType is how the metric names are matched.
+type MatchType string
+
+const (
+	// Strict matches the metric names equal to the MetricName.
+	Strict MatchType = "strict"
+	// Regexp matches the metric names matching the MetricName regexp.
+	Regexp MatchType = "regexp"
+)
+
+// TransformAction is the action of a Transform.
+type TransformAction string
+
+const (
+	// Update applies the transformation to the matched metrics.
+	Update TransformAction = "update"
+	// Insert applies the transformation to copies of the matched metrics, which are
+	// added alongside the original ones.
+	Insert TransformAction = "insert"
+	// Combine merges the data points of the matched metrics into a new metric. The
+	// named subexpressions of the MetricName regexp become labels of the data points.
+	Combine TransformAction = "combine"
+)
+
+// Transform defines the transformation of the metrics matching a name.
+type Transform struct {
+	// MetricName is the name, or regexp if MatchType is regexp, of the metrics to
+	// transform.
+	MetricName string `mapstructure:"metric_name"`
+
+	// MatchType is either "strict" or "regexp", defaults to "strict".
+	MatchType MatchType `mapstructure:"match_type"`
+
+	// Action is either "update", "insert" or "combine".
+	Action TransformAction `mapstructure:"action"`
+
+	// NewName is the new name of the metrics. With the "regexp" match type it can
+	// reference the submatches of the MetricName regexp, e.g. "$1" or "${name}".
+	// Required with the "insert" and "combine" actions.
+	NewName string `mapstructure:"new_name"`
+
+	// Operations are the operations applied, in the order they are specified, to
+	// the labels and values of the metrics.
+	Operations []Operation `mapstructure:"operations"`
+}
+
+// OperationAction is the action of an Operation.
+type OperationAction string
+
+const (
+	// AddLabel adds the NewLabel label with the NewValue value to the data points
+	// that don't have it.
+	AddLabel OperationAction = "add_label"
+	// UpdateLabel renames the Label label to NewLabel, if set, and its values
+	// according to ValueActions.
+	UpdateLabel OperationAction = "update_label"
+	// DeleteLabelValue deletes the data points whose Label label has the LabelValue value.
+	DeleteLabelValue OperationAction = "delete_label_value"
+	// AggregateLabels keeps only the labels of LabelSet, aggregating the data points
+	// that then have the same labels with AggregationType.
+	AggregateLabels OperationAction = "aggregate_labels"
+	// AggregateLabelValues replaces the AggregatedValues values of the Label label by
+	// NewValue, aggregating the data points that then have the same labels with
+	// AggregationType.
+	AggregateLabelValues OperationAction = "aggregate_label_values"
+	// ScaleValue multiplies the values of the data points by Scale.
+	ScaleValue OperationAction = "scale_value"
+)
+
+// AggregationType is how the values of data points are aggregated.
+type AggregationType string
+
+const (
+	// Sum sums the values.
+	Sum AggregationType = "sum"
+	// Mean averages the values.
+	Mean AggregationType = "mean"
+	// Max keeps the maximum value.
+	Max AggregationType = "max"
+	// Min keeps the minimum value.
+	Min AggregationType = "min"
+)
+
+// Operation defines an operation on the labels or values of the data points of a
+// metric.
+type Operation struct {
+	// Action is the action of the operation.
+	Action OperationAction `mapstructure:"action"`
+
+	// Label is the label the "update_label", "delete_label_value" and
+	// "aggregate_label_values" actions apply to.
+	Label string `mapstructure:"label"`
+
+	// NewLabel is the label added by the "add_label" action, or the new name of the
+	// label with the "update_label" action.
+	NewLabel string `mapstructure:"new_label"`
+
+	// NewValue is the value of the label added by the "add_label" action, or the value
+	// replacing the aggregated values with the "aggregate_label_values" action.
+	NewValue string `mapstructure:"new_value"`
+
+	// ValueActions are the renames of label values of the "update_label" action.
+	ValueActions []ValueAction `mapstructure:"value_actions"`
+
+	// LabelValue is the label value of the data points deleted by the
+	// "delete_label_value" action.
+	LabelValue string `mapstructure:"label_value"`
+
+	// LabelSet are the labels kept by the "aggregate_labels" action.
+	LabelSet []string `mapstructure:"label_set"`
+
+	// AggregatedValues are the label values aggregated by the
+	// "aggregate_label_values" action.
+	AggregatedValues []string `mapstructure:"aggregated_values"`
+
+	// AggregationType is either "sum", "mean", "max" or "min". Histograms are always
+	// summed.
+	AggregationType AggregationType `mapstructure:"aggregation_type"`
+
+	// Scale is the factor the values are multiplied by with the "scale_value" action.
+	Scale float64 `mapstructure:"scale"`
+}
+
+// ValueAction renames a label value.
+type ValueAction struct {
+	// Value is the current label value.
+	Value string `mapstructure:"value"`
+	// NewValue is the new label value.
+	NewValue string `mapstructure:"new_value"`
+}
@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := config.ExampleComponents()
+	require.NoError(t, err)
+
+	factories.Processors[typeStr] = &Factory{}
+
+	cfg, err := config.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, cfg.Processors["metrics_transform"], &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: "metrics_transform",
+			NameVal: "metrics_transform",
+		},
+	})
+
+	assert.Equal(t, cfg.Processors["metrics_transform/custom"], &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: "metrics_transform",
+			NameVal: "metrics_transform/custom",
+		},
+		Transforms: []Transform{
+			{
+				MetricName: "system.cpu.usage",
+				Action:     Update,
+				NewName:    "host.cpu.usage",
+				Operations: []Operation{
+					{
+						Action:       UpdateLabel,
+						Label:        "state",
+						NewLabel:     "cpu_state",
+						ValueActions: []ValueAction{{Value: "idle", NewValue: "free"}},
+					},
+					{
+						Action:          AggregateLabels,
+						LabelSet:        []string{"cpu_state"},
+						AggregationType: Sum,
+					},
+				},
+			},
+			{
+				MetricName: `^disk\.(.*)_bytes$`,
+				MatchType:  Regexp,
+				Action:     Insert,
+				NewName:    "disk.${1}_megabytes",
+				Operations: []Operation{
+					{Action: ScaleValue, Scale: 0.000001},
+					{Action: AddLabel, NewLabel: "unit", NewValue: "MB"},
+				},
+			},
+			{
+				MetricName: `^http\.(?P<direction>in|out)\.count$`,
+				MatchType:  Regexp,
+				Action:     Combine,
+				NewName:    "http.count",
+				Operations: []Operation{
+					{Action: DeleteLabelValue, Label: "path", LabelValue: "/health"},
+					{
+						Action:           AggregateLabelValues,
+						Label:            "method",
+						AggregatedValues: []string{"PUT", "PATCH"},
+						NewValue:         "UPDATE",
+						AggregationType:  Max,
+					},
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,277 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+)
+
+type metricsTransformProcessor struct {
+	logger     *zap.Logger
+	next       consumer.MetricsConsumer
+	transforms []transform
+}
+
+var _ component.MetricsProcessor = (*metricsTransformProcessor)(nil)
+
+// transform is the compiled equivalent of a Transform.
+type transform struct {
+	Transform
+	// re is the compiled MetricName, nil with the "strict" match type.
+	re *regexp.Regexp
+}
+
+func newMetricsTransformProcessor(logger *zap.Logger, next consumer.MetricsConsumer, cfg *Config) (*metricsTransformProcessor, error) {
+	if next == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+
+	transforms := make([]transform, 0, len(cfg.Transforms))
+	for _, t := range cfg.Transforms {
+		ct, err := compileTransform(t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transform of %q: %v", t.MetricName, err)
+		}
+		transforms = append(transforms, ct)
+	}
+
+	return &metricsTransformProcessor{
+		logger:     logger,
+		next:       next,
+		transforms: transforms,
+	}, nil
+}
+
+func compileTransform(t Transform) (transform, error) {
+	ct := transform{Transform: t}
+	if t.MetricName == "" {
+		return ct, errors.New("\"metric_name\" must be set")
+	}
+
+	switch t.MatchType {
+	case "", Strict:
+		ct.MatchType = Strict
+	case Regexp:
+		re, err := regexp.Compile(t.MetricName)
+		if err != nil {
+			return ct, fmt.Errorf("invalid regexp pattern %s", t.MetricName)
+		}
+		ct.re = re
+	default:
+		return ct, fmt.Errorf("unsupported \"match_type\" %q", t.MatchType)
+	}
+
+	switch t.Action {
+	case Update:
+	case Insert:
+		if t.NewName == "" {
+			return ct, errors.New("\"new_name\" must be set with the \"insert\" action")
+		}
+	case Combine:
+		if t.NewName == "" {
+			return ct, errors.New("\"new_name\" must be set with the \"combine\" action")
+		}
+		if ct.re == nil || !hasNamedSubexp(ct.re) {
+			return ct, errors.New("the \"combine\" action requires a \"regexp\" \"metric_name\" with named subexpressions")
+		}
+	default:
+		return ct, fmt.Errorf("unsupported \"action\" %q", t.Action)
+	}
+
+	for _, op := range t.Operations {
+		if err := validateOperation(op); err != nil {
+			return ct, err
+		}
+	}
+	return ct, nil
+}
+
+func hasNamedSubexp(re *regexp.Regexp) bool {
+	for _, name := range re.SubexpNames() {
+		if name != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns true if the metric name matches the transform.
+func (t *transform) match(name string) bool {
+	if t.re != nil {
+		return t.re.MatchString(name)
+	}
+	return name == t.MetricName
+}
+
+// newName returns the name of the metric once transformed.
+func (t *transform) newName(name string) string {
+	if t.NewName == "" {
+		return name
+	}
+	if t.re == nil {
+		return t.NewName
+	}
+	return string(t.re.ExpandString(nil, t.NewName, name, t.re.FindStringSubmatchIndex(name)))
+}
+
+// GetCapabilities returns the Capabilities assocciated with the metrics transform processor.
+func (mtp *metricsTransformProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: true}
+}
+
+// Start is invoked during service startup.
+func (*metricsTransformProcessor) Start(_ context.Context, _ component.Host) error {
+	return nil
+}
+
+// Shutdown is invoked during service shutdown.
+func (*metricsTransformProcessor) Shutdown(_ context.Context) error {
+	return nil
+}
+
+// ConsumeMetrics applies the transforms to the metrics of each instrumentation
+// library and forwards them to the next consumer.
+func (mtp *metricsTransformProcessor) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	imd := pdatautil.MetricsToInternalMetrics(md)
+	rms := imd.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				continue
+			}
+			for k := range mtp.transforms {
+				mtp.transformMetrics(ilm.Metrics(), &mtp.transforms[k])
+			}
+		}
+	}
+	return mtp.next.ConsumeMetrics(ctx, pdatautil.MetricsFromInternalMetrics(imd))
+}
+
+func (mtp *metricsTransformProcessor) transformMetrics(metrics pdata.MetricSlice, t *transform) {
+	switch t.Action {
+	case Update:
+		for i := 0; i < metrics.Len(); i++ {
+			metric := metrics.At(i)
+			if name, ok := metricName(metric); ok && t.match(name) {
+				mtp.applyTransform(metric, t.newName(name), t.Operations)
+			}
+		}
+
+	case Insert:
+		// The copies are appended, only the original metrics are matched.
+		n := metrics.Len()
+		for i := 0; i < n; i++ {
+			metric := metrics.At(i)
+			if name, ok := metricName(metric); ok && t.match(name) {
+				clone := pdata.NewMetric()
+				metric.CopyTo(clone)
+				mtp.applyTransform(clone, t.newName(name), t.Operations)
+				metrics.Append(&clone)
+			}
+		}
+
+	case Combine:
+		var matched []pdata.Metric
+		kept := pdata.NewMetricSlice()
+		for i := 0; i < metrics.Len(); i++ {
+			metric := metrics.At(i)
+			if name, ok := metricName(metric); ok && t.match(name) {
+				matched = append(matched, metric)
+				continue
+			}
+			kept.Append(&metric)
+		}
+		if len(matched) == 0 {
+			return
+		}
+		combined, err := t.combine(matched)
+		if err != nil {
+			mtp.logger.Warn("Failed to combine metrics", zap.String("new_name", t.NewName), zap.Error(err))
+			return
+		}
+		mtp.applyTransform(combined, t.NewName, t.Operations)
+		kept.Append(&combined)
+		metrics.Resize(0)
+		kept.MoveAndAppendTo(metrics)
+	}
+}
+
+// combine moves the data points of the metrics into a new metric, labeled with the
+// named submatches of the metric names. The metrics must have the same type.
+func (t *transform) combine(metrics []pdata.Metric) (pdata.Metric, error) {
+	combined := pdata.NewMetric()
+	metricType := metrics[0].MetricDescriptor().Type()
+	for _, metric := range metrics[1:] {
+		if metric.MetricDescriptor().Type() != metricType {
+			return combined, fmt.Errorf("metrics %q and %q have different types",
+				metrics[0].MetricDescriptor().Name(), metric.MetricDescriptor().Name())
+		}
+	}
+
+	combined.InitEmpty()
+	metrics[0].MetricDescriptor().CopyTo(combined.MetricDescriptor())
+	for _, metric := range metrics {
+		name := metric.MetricDescriptor().Name()
+		submatches := t.re.FindStringSubmatch(name)
+		labels := make(map[string]string)
+		for i, label := range t.re.SubexpNames() {
+			if i > 0 && label != "" {
+				labels[label] = submatches[i]
+			}
+		}
+		forEachLabelsMap(metric, func(lm pdata.StringMap) {
+			for k, v := range labels {
+				lm.Upsert(k, v)
+			}
+		})
+		metric.Int64DataPoints().MoveAndAppendTo(combined.Int64DataPoints())
+		metric.DoubleDataPoints().MoveAndAppendTo(combined.DoubleDataPoints())
+		metric.HistogramDataPoints().MoveAndAppendTo(combined.HistogramDataPoints())
+		metric.SummaryDataPoints().MoveAndAppendTo(combined.SummaryDataPoints())
+	}
+	return combined, nil
+}
+
+func (mtp *metricsTransformProcessor) applyTransform(metric pdata.Metric, newName string, operations []Operation) {
+	metric.MetricDescriptor().SetName(newName)
+	for _, op := range operations {
+		mtp.applyOperation(metric, op)
+	}
+}
+
+// metricName returns the name of the metric, or false if it has no descriptor.
+func metricName(metric pdata.Metric) (string, bool) {
+	if metric.IsNil() || metric.MetricDescriptor().IsNil() {
+		return "", false
+	}
+	return metric.MetricDescriptor().Name(), true
+}
@@ -0,0 +1,386 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/data"
+)
+
+func TestNewProcessorInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform Transform
+	}{
+		{
+			name:      "missing_metric_name",
+			transform: Transform{Action: Update},
+		},
+		{
+			name:      "invalid_match_type",
+			transform: Transform{MetricName: "m", MatchType: "glob", Action: Update},
+		},
+		{
+			name:      "invalid_regexp",
+			transform: Transform{MetricName: "\\", MatchType: Regexp, Action: Update},
+		},
+		{
+			name:      "invalid_action",
+			transform: Transform{MetricName: "m", Action: "delete"},
+		},
+		{
+			name:      "insert_without_new_name",
+			transform: Transform{MetricName: "m", Action: Insert},
+		},
+		{
+			name:      "combine_without_named_subexpression",
+			transform: Transform{MetricName: "m(.*)", MatchType: Regexp, Action: Combine, NewName: "n"},
+		},
+		{
+			name: "invalid_operation",
+			transform: Transform{MetricName: "m", Action: Update, Operations: []Operation{
+				{Action: "drop"},
+			}},
+		},
+		{
+			name: "add_label_without_new_label",
+			transform: Transform{MetricName: "m", Action: Update, Operations: []Operation{
+				{Action: AddLabel, NewValue: "v"},
+			}},
+		},
+		{
+			name: "invalid_aggregation_type",
+			transform: Transform{MetricName: "m", Action: Update, Operations: []Operation{
+				{Action: AggregateLabels, AggregationType: "median"},
+			}},
+		},
+		{
+			name: "scale_without_scale",
+			transform: Transform{MetricName: "m", Action: Update, Operations: []Operation{
+				{Action: ScaleValue},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newMetricsTransformProcessor(zap.NewNop(), exportertest.NewNopMetricsExporter(), &Config{
+				Transforms: []Transform{tt.transform},
+			})
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := newMetricsTransformProcessor(zap.NewNop(), nil, &Config{})
+	assert.Error(t, err)
+}
+
+func TestUpdateAndAggregate(t *testing.T) {
+	md := newMetricData(
+		newInt64Metric("system.cpu.usage",
+			int64Point{labels: map[string]string{"cpu": "0", "state": "idle"}, start: 2, value: 10},
+			int64Point{labels: map[string]string{"cpu": "1", "state": "idle"}, start: 1, value: 20},
+			int64Point{labels: map[string]string{"cpu": "0", "state": "user"}, start: 2, value: 5},
+		),
+		newInt64Metric("other", int64Point{labels: map[string]string{"cpu": "0"}, value: 1}),
+	)
+	metrics := runTransforms(t, md, Transform{
+		MetricName: "system.cpu.usage",
+		Action:     Update,
+		NewName:    "host.cpu.usage",
+		Operations: []Operation{
+			{
+				Action:       UpdateLabel,
+				Label:        "state",
+				NewLabel:     "cpu_state",
+				ValueActions: []ValueAction{{Value: "idle", NewValue: "free"}},
+			},
+			{Action: AggregateLabels, LabelSet: []string{"cpu_state"}, AggregationType: Sum},
+		},
+	})
+
+	require.Equal(t, 2, metrics.Len())
+	metric := metrics.At(0)
+	assert.Equal(t, "host.cpu.usage", metric.MetricDescriptor().Name())
+	require.Equal(t, 2, metric.Int64DataPoints().Len())
+	free := metric.Int64DataPoints().At(0)
+	assert.Equal(t, map[string]string{"cpu_state": "free"}, labelsToMap(free.LabelsMap()))
+	assert.Equal(t, int64(30), free.Value())
+	assert.Equal(t, pdata.TimestampUnixNano(1), free.StartTime())
+	user := metric.Int64DataPoints().At(1)
+	assert.Equal(t, map[string]string{"cpu_state": "user"}, labelsToMap(user.LabelsMap()))
+	assert.Equal(t, int64(5), user.Value())
+
+	// Other metrics are left as is.
+	assert.Equal(t, "other", metrics.At(1).MetricDescriptor().Name())
+	assert.Equal(t, map[string]string{"cpu": "0"}, labelsToMap(metrics.At(1).Int64DataPoints().At(0).LabelsMap()))
+}
+
+func TestAggregationTypes(t *testing.T) {
+	tests := []struct {
+		aggType    AggregationType
+		wantInt64  int64
+		wantDouble float64
+	}{
+		{aggType: Sum, wantInt64: 12, wantDouble: 12},
+		{aggType: Mean, wantInt64: 4, wantDouble: 4},
+		{aggType: Max, wantInt64: 7, wantDouble: 7},
+		{aggType: Min, wantInt64: 1, wantDouble: 1},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.aggType), func(t *testing.T) {
+			int64Metric := newInt64Metric("int64",
+				int64Point{labels: map[string]string{"host": "a"}, value: 1},
+				int64Point{labels: map[string]string{"host": "b"}, value: 7},
+				int64Point{labels: map[string]string{"host": "c"}, value: 4},
+			)
+			doubleMetric := newDoubleMetric("double",
+				doublePoint{labels: map[string]string{"host": "a"}, value: 1},
+				doublePoint{labels: map[string]string{"host": "b"}, value: 7},
+				doublePoint{labels: map[string]string{"host": "c"}, value: 4},
+			)
+			metrics := runTransforms(t, newMetricData(int64Metric, doubleMetric), Transform{
+				MetricName: ".*",
+				MatchType:  Regexp,
+				Action:     Update,
+				Operations: []Operation{{Action: AggregateLabels, AggregationType: tt.aggType}},
+			})
+
+			require.Equal(t, 1, metrics.At(0).Int64DataPoints().Len())
+			assert.Equal(t, tt.wantInt64, metrics.At(0).Int64DataPoints().At(0).Value())
+			require.Equal(t, 1, metrics.At(1).DoubleDataPoints().Len())
+			assert.Equal(t, tt.wantDouble, metrics.At(1).DoubleDataPoints().At(0).Value())
+		})
+	}
+}
+
+func TestAggregateHistograms(t *testing.T) {
+	metric := pdata.NewMetric()
+	metric.InitEmpty()
+	metric.MetricDescriptor().InitEmpty()
+	metric.MetricDescriptor().SetName("latency")
+	metric.MetricDescriptor().SetType(pdata.MetricTypeHistogram)
+	dps := metric.HistogramDataPoints()
+	dps.Resize(3)
+	for i, host := range []string{"a", "b", "c"} {
+		dp := dps.At(i)
+		dp.LabelsMap().InitFromMap(map[string]string{"host": host, "method": "GET"})
+		dp.SetCount(3)
+		dp.SetSum(10)
+		dp.SetExplicitBounds([]float64{1, 10})
+		dp.Buckets().Resize(3)
+		for j := 0; j < 3; j++ {
+			dp.Buckets().At(j).SetCount(1)
+		}
+	}
+	// Histograms with other bounds aren't merged.
+	dps.At(2).SetExplicitBounds([]float64{5})
+	dps.At(2).Buckets().Resize(2)
+	dps.At(2).Buckets().At(1).SetCount(2)
+
+	metrics := runTransforms(t, newMetricData(metric), Transform{
+		MetricName: "latency",
+		Action:     Update,
+		Operations: []Operation{
+			{Action: AggregateLabels, LabelSet: []string{"method"}, AggregationType: Max},
+			{Action: ScaleValue, Scale: 1000},
+		},
+	})
+
+	dps = metrics.At(0).HistogramDataPoints()
+	require.Equal(t, 2, dps.Len())
+	merged := dps.At(0)
+	assert.Equal(t, map[string]string{"method": "GET"}, labelsToMap(merged.LabelsMap()))
+	assert.Equal(t, uint64(6), merged.Count())
+	assert.Equal(t, float64(20000), merged.Sum())
+	assert.Equal(t, []float64{1000, 10000}, merged.ExplicitBounds())
+	assert.Equal(t, []uint64{2, 2, 2}, bucketCounts(merged))
+	assert.Equal(t, []float64{5000}, dps.At(1).ExplicitBounds())
+}
+
+func TestInsertAndScale(t *testing.T) {
+	md := newMetricData(
+		newInt64Metric("disk.read_bytes", int64Point{value: 2500000}),
+		newDoubleMetric("disk.io_time", doublePoint{value: 2}),
+	)
+	metrics := runTransforms(t, md, Transform{
+		MetricName: `^disk\.(.*)_bytes$`,
+		MatchType:  Regexp,
+		Action:     Insert,
+		NewName:    "disk.${1}_megabytes",
+		Operations: []Operation{
+			{Action: ScaleValue, Scale: 0.000001},
+			{Action: AddLabel, NewLabel: "unit", NewValue: "MB"},
+		},
+	})
+
+	require.Equal(t, 3, metrics.Len())
+	assert.Equal(t, "disk.read_bytes", metrics.At(0).MetricDescriptor().Name())
+	assert.Equal(t, int64(2500000), metrics.At(0).Int64DataPoints().At(0).Value())
+	assert.Equal(t, "disk.io_time", metrics.At(1).MetricDescriptor().Name())
+	inserted := metrics.At(2)
+	assert.Equal(t, "disk.read_megabytes", inserted.MetricDescriptor().Name())
+	assert.Equal(t, int64(3), inserted.Int64DataPoints().At(0).Value())
+	assert.Equal(t, map[string]string{"unit": "MB"}, labelsToMap(inserted.Int64DataPoints().At(0).LabelsMap()))
+}
+
+func TestCombine(t *testing.T) {
+	md := newMetricData(
+		newInt64Metric("http.in.count",
+			int64Point{labels: map[string]string{"method": "PUT", "path": "/users"}, value: 1},
+			int64Point{labels: map[string]string{"method": "PATCH", "path": "/users"}, value: 3},
+			int64Point{labels: map[string]string{"method": "GET", "path": "/health"}, value: 10},
+		),
+		newInt64Metric("other", int64Point{value: 1}),
+		newInt64Metric("http.out.count",
+			int64Point{labels: map[string]string{"method": "GET", "path": "/users"}, value: 2},
+		),
+	)
+	metrics := runTransforms(t, md, Transform{
+		MetricName: `^http\.(?P<direction>in|out)\.count$`,
+		MatchType:  Regexp,
+		Action:     Combine,
+		NewName:    "http.count",
+		Operations: []Operation{
+			{Action: DeleteLabelValue, Label: "path", LabelValue: "/health"},
+			{
+				Action:           AggregateLabelValues,
+				Label:            "method",
+				AggregatedValues: []string{"PUT", "PATCH"},
+				NewValue:         "UPDATE",
+				AggregationType:  Max,
+			},
+		},
+	})
+
+	require.Equal(t, 2, metrics.Len())
+	assert.Equal(t, "other", metrics.At(0).MetricDescriptor().Name())
+	combined := metrics.At(1)
+	assert.Equal(t, "http.count", combined.MetricDescriptor().Name())
+	dps := combined.Int64DataPoints()
+	require.Equal(t, 2, dps.Len())
+	assert.Equal(t, map[string]string{"direction": "in", "method": "UPDATE", "path": "/users"}, labelsToMap(dps.At(0).LabelsMap()))
+	assert.Equal(t, int64(3), dps.At(0).Value())
+	assert.Equal(t, map[string]string{"direction": "out", "method": "GET", "path": "/users"}, labelsToMap(dps.At(1).LabelsMap()))
+	assert.Equal(t, int64(2), dps.At(1).Value())
+}
+
+func TestCombineDifferentTypes(t *testing.T) {
+	md := newMetricData(
+		newInt64Metric("http.in.count", int64Point{value: 1}),
+		newDoubleMetric("http.out.count", doublePoint{value: 2}),
+	)
+	metrics := runTransforms(t, md, Transform{
+		MetricName: `^http\.(?P<direction>in|out)\.count$`,
+		MatchType:  Regexp,
+		Action:     Combine,
+		NewName:    "http.count",
+	})
+
+	// The metrics are left as is.
+	require.Equal(t, 2, metrics.Len())
+	assert.Equal(t, "http.in.count", metrics.At(0).MetricDescriptor().Name())
+	assert.Equal(t, "http.out.count", metrics.At(1).MetricDescriptor().Name())
+}
+
+func runTransforms(t *testing.T, md data.MetricData, transforms ...Transform) pdata.MetricSlice {
+	sink := &exportertest.SinkMetricsExporter{}
+	mtp, err := newMetricsTransformProcessor(zap.NewNop(), sink, &Config{Transforms: transforms})
+	require.NoError(t, err)
+	require.NoError(t, mtp.ConsumeMetrics(context.Background(), pdatautil.MetricsFromInternalMetrics(md)))
+	require.Len(t, sink.AllMetrics(), 1)
+	imd := pdatautil.MetricsToInternalMetrics(sink.AllMetrics()[0])
+	return imd.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+}
+
+type int64Point struct {
+	labels map[string]string
+	start  pdata.TimestampUnixNano
+	value  int64
+}
+
+type doublePoint struct {
+	labels map[string]string
+	value  float64
+}
+
+func newMetricData(metrics ...pdata.Metric) data.MetricData {
+	md := data.NewMetricData()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	ilm := rm.InstrumentationLibraryMetrics().At(0)
+	for i := range metrics {
+		ilm.Metrics().Append(&metrics[i])
+	}
+	return md
+}
+
+func newInt64Metric(name string, points ...int64Point) pdata.Metric {
+	metric := pdata.NewMetric()
+	metric.InitEmpty()
+	metric.MetricDescriptor().InitEmpty()
+	metric.MetricDescriptor().SetName(name)
+	metric.MetricDescriptor().SetType(pdata.MetricTypeInt64)
+	dps := metric.Int64DataPoints()
+	dps.Resize(len(points))
+	for i, p := range points {
+		dps.At(i).LabelsMap().InitFromMap(p.labels)
+		dps.At(i).SetStartTime(p.start)
+		dps.At(i).SetTimestamp(100)
+		dps.At(i).SetValue(p.value)
+	}
+	return metric
+}
+
+func newDoubleMetric(name string, points ...doublePoint) pdata.Metric {
+	metric := pdata.NewMetric()
+	metric.InitEmpty()
+	metric.MetricDescriptor().InitEmpty()
+	metric.MetricDescriptor().SetName(name)
+	metric.MetricDescriptor().SetType(pdata.MetricTypeDouble)
+	dps := metric.DoubleDataPoints()
+	dps.Resize(len(points))
+	for i, p := range points {
+		dps.At(i).LabelsMap().InitFromMap(p.labels)
+		dps.At(i).SetTimestamp(100)
+		dps.At(i).SetValue(p.value)
+	}
+	return metric
+}
+
+func labelsToMap(labels pdata.StringMap) map[string]string {
+	m := make(map[string]string)
+	labels.ForEach(func(k string, v pdata.StringValue) {
+		m[k] = v.Value()
+	})
+	return m
+}
+
+func bucketCounts(dp pdata.HistogramDataPoint) []uint64 {
+	counts := make([]uint64, dp.Buckets().Len())
+	for i := range counts {
+		counts[i] = dp.Buckets().At(i).Count()
+	}
+	return counts
+}
@@ -0,0 +1,241 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/timeseries"
+)
+
+func validateOperation(op Operation) error {
+	switch op.Action {
+	case AddLabel:
+		if op.NewLabel == "" {
+			return errors.New("\"new_label\" must be set with the \"add_label\" operation")
+		}
+	case UpdateLabel:
+		if op.Label == "" {
+			return errors.New("\"label\" must be set with the \"update_label\" operation")
+		}
+	case DeleteLabelValue:
+		if op.Label == "" {
+			return errors.New("\"label\" must be set with the \"delete_label_value\" operation")
+		}
+	case AggregateLabels:
+		return validateAggregationType(op.AggregationType)
+	case AggregateLabelValues:
+		if op.Label == "" || len(op.AggregatedValues) == 0 {
+			return errors.New("\"label\" and \"aggregated_values\" must be set with the \"aggregate_label_values\" operation")
+		}
+		return validateAggregationType(op.AggregationType)
+	case ScaleValue:
+		if op.Scale == 0 {
+			return errors.New("\"scale\" must be set with the \"scale_value\" operation")
+		}
+	default:
+		return fmt.Errorf("unsupported operation \"action\" %q", op.Action)
+	}
+	return nil
+}
+
+func validateAggregationType(aggType AggregationType) error {
+	switch aggType {
+	case Sum, Mean, Max, Min:
+		return nil
+	}
+	return fmt.Errorf("unsupported \"aggregation_type\" %q", aggType)
+}
+
+func (mtp *metricsTransformProcessor) applyOperation(metric pdata.Metric, op Operation) {
+	switch op.Action {
+	case AddLabel:
+		forEachLabelsMap(metric, func(lm pdata.StringMap) {
+			lm.Insert(op.NewLabel, op.NewValue)
+		})
+
+	case UpdateLabel:
+		forEachLabelsMap(metric, func(lm pdata.StringMap) {
+			updateLabel(lm, op)
+		})
+
+	case DeleteLabelValue:
+		timeseries.FilterDataPoints(metric, func(lm pdata.StringMap) bool {
+			v, ok := lm.Get(op.Label)
+			return !ok || v.Value() != op.LabelValue
+		})
+
+	case AggregateLabels:
+		if !mtp.canAggregate(metric) {
+			return
+		}
+		labelSet := make(map[string]bool, len(op.LabelSet))
+		for _, label := range op.LabelSet {
+			labelSet[label] = true
+		}
+		forEachLabelsMap(metric, func(lm pdata.StringMap) {
+			var deleted []string
+			lm.ForEach(func(k string, _ pdata.StringValue) {
+				if !labelSet[k] {
+					deleted = append(deleted, k)
+				}
+			})
+			for _, k := range deleted {
+				lm.Delete(k)
+			}
+		})
+		aggregateDataPoints(metric, op.AggregationType)
+
+	case AggregateLabelValues:
+		if !mtp.canAggregate(metric) {
+			return
+		}
+		aggregatedValues := make(map[string]bool, len(op.AggregatedValues))
+		for _, value := range op.AggregatedValues {
+			aggregatedValues[value] = true
+		}
+		forEachLabelsMap(metric, func(lm pdata.StringMap) {
+			if v, ok := lm.Get(op.Label); ok && aggregatedValues[v.Value()] {
+				v.SetValue(op.NewValue)
+			}
+		})
+		aggregateDataPoints(metric, op.AggregationType)
+
+	case ScaleValue:
+		scaleValues(metric, op.Scale)
+	}
+}
+
+// canAggregate returns false, and logs why, if the data points of the metric
+// can't be aggregated.
+func (mtp *metricsTransformProcessor) canAggregate(metric pdata.Metric) bool {
+	if metric.MetricDescriptor().Type() == pdata.MetricTypeSummary {
+		mtp.logger.Debug("Summary metrics can't be aggregated", zap.String("metric_name", metric.MetricDescriptor().Name()))
+		return false
+	}
+	return true
+}
+
+// updateLabel renames the label and its values.
+func updateLabel(lm pdata.StringMap, op Operation) {
+	v, ok := lm.Get(op.Label)
+	if !ok {
+		return
+	}
+	value := v.Value()
+	for _, va := range op.ValueActions {
+		if va.Value == value {
+			value = va.NewValue
+			break
+		}
+	}
+	if op.NewLabel != "" && op.NewLabel != op.Label {
+		lm.Delete(op.Label)
+		lm.Upsert(op.NewLabel, value)
+		return
+	}
+	v.SetValue(value)
+}
+
+// forEachLabelsMap calls f with the labels of each data point of the metric.
+func forEachLabelsMap(metric pdata.Metric, f func(lm pdata.StringMap)) {
+	int64Dps := metric.Int64DataPoints()
+	for i := 0; i < int64Dps.Len(); i++ {
+		if dp := int64Dps.At(i); !dp.IsNil() {
+			f(dp.LabelsMap())
+		}
+	}
+	doubleDps := metric.DoubleDataPoints()
+	for i := 0; i < doubleDps.Len(); i++ {
+		if dp := doubleDps.At(i); !dp.IsNil() {
+			f(dp.LabelsMap())
+		}
+	}
+	histogramDps := metric.HistogramDataPoints()
+	for i := 0; i < histogramDps.Len(); i++ {
+		if dp := histogramDps.At(i); !dp.IsNil() {
+			f(dp.LabelsMap())
+		}
+	}
+	summaryDps := metric.SummaryDataPoints()
+	for i := 0; i < summaryDps.Len(); i++ {
+		if dp := summaryDps.At(i); !dp.IsNil() {
+			f(dp.LabelsMap())
+		}
+	}
+}
+
+// scaleValues multiplies the values of the data points of the metric by the scale.
+// The histogram bucket bounds and the summary percentile values are scaled too.
+func scaleValues(metric pdata.Metric, scale float64) {
+	int64Dps := metric.Int64DataPoints()
+	for i := 0; i < int64Dps.Len(); i++ {
+		if dp := int64Dps.At(i); !dp.IsNil() {
+			dp.SetValue(int64(math.Round(float64(dp.Value()) * scale)))
+		}
+	}
+	doubleDps := metric.DoubleDataPoints()
+	for i := 0; i < doubleDps.Len(); i++ {
+		if dp := doubleDps.At(i); !dp.IsNil() {
+			dp.SetValue(dp.Value() * scale)
+		}
+	}
+	histogramDps := metric.HistogramDataPoints()
+	for i := 0; i < histogramDps.Len(); i++ {
+		dp := histogramDps.At(i)
+		if dp.IsNil() {
+			continue
+		}
+		dp.SetSum(dp.Sum() * scale)
+		bounds := make([]float64, len(dp.ExplicitBounds()))
+		for j, bound := range dp.ExplicitBounds() {
+			bounds[j] = bound * scale
+		}
+		dp.SetExplicitBounds(bounds)
+	}
+	summaryDps := metric.SummaryDataPoints()
+	for i := 0; i < summaryDps.Len(); i++ {
+		dp := summaryDps.At(i)
+		if dp.IsNil() {
+			continue
+		}
+		dp.SetSum(dp.Sum() * scale)
+		percentiles := dp.ValueAtPercentiles()
+		for j := 0; j < percentiles.Len(); j++ {
+			if p := percentiles.At(j); !p.IsNil() {
+				p.SetValue(p.Value() * scale)
+			}
+		}
+	}
+}
+
+// dataPointKey returns the key identifying the data points that are aggregated
+// together: the ones with the same labels and timestamp.
+func dataPointKey(lm pdata.StringMap, ts pdata.TimestampUnixNano) string {
+	labels := make([]string, 0, lm.Len())
+	lm.ForEach(func(k string, v pdata.StringValue) {
+		labels = append(labels, k+timeseries.KeySeparator+v.Value())
+	})
+	sort.Strings(labels)
+	return strconv.FormatUint(uint64(ts), 10) + timeseries.KeySeparator + strings.Join(labels, timeseries.KeySeparator)
+}
@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcedetectionprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines the configuration for the resource detection processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Detectors are the detectors run at startup, among "env", "system",
+	// "container" and "ec2". An attribute detected by several detectors gets the
+	// value of the first one.
+	Detectors []string `mapstructure:"detectors"`
+
+	// Override replaces the existing attributes of the resources with the
+	// detected ones, the existing attributes are kept otherwise.
+	Override bool `mapstructure:"override"`
+
+	// Timeout is the maximum duration of each detector.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// EC2 configures the "ec2" detector.
+	EC2 EC2Config `mapstructure:"ec2"`
+}
+
+// EC2Config defines the configuration of the detector of the EC2 instance.
+type EC2Config struct {
+	// Endpoint is the address of the instance metadata service.
+	Endpoint string `mapstructure:"endpoint"`
+}
@@ -0,0 +1,218 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcedetectionprocessor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+const (
+	envDetectorName       = "env"
+	systemDetectorName    = "system"
+	containerDetectorName = "container"
+	ec2DetectorName       = "ec2"
+
+	// envResourceAttributes is the environment variable holding the resource
+	// attributes, as comma separated key=value pairs.
+	envResourceAttributes = "OTEL_RESOURCE_ATTRIBUTES"
+
+	ec2IdentityDocumentPath = "/latest/dynamic/instance-identity/document"
+)
+
+// detector detects attributes of the resource of the collector.
+type detector interface {
+	// Detect returns the detected attributes, the attributes not found are not
+	// returned.
+	Detect(ctx context.Context) (map[string]string, error)
+}
+
+// newDetector creates the detector with the name.
+func newDetector(name string, cfg *Config) (detector, error) {
+	switch name {
+	case envDetectorName:
+		return &envDetector{getenv: os.Getenv}, nil
+	case systemDetectorName:
+		return &systemDetector{hostname: os.Hostname, machineIDPath: "/etc/machine-id"}, nil
+	case containerDetectorName:
+		return &containerDetector{cgroupPath: "/proc/self/cgroup"}, nil
+	case ec2DetectorName:
+		// The client timeout also bounds the reading of the response body.
+		return &ec2Detector{
+			endpoint: strings.TrimSuffix(cfg.EC2.Endpoint, "/"),
+			client:   &http.Client{Timeout: cfg.Timeout},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown detector %q", name)
+	}
+}
+
+// envDetector detects the attributes set in the OTEL_RESOURCE_ATTRIBUTES
+// environment variable.
+type envDetector struct {
+	getenv func(string) string
+}
+
+func (d *envDetector) Detect(context.Context) (map[string]string, error) {
+	attrs := make(map[string]string)
+	value := strings.TrimSpace(d.getenv(envResourceAttributes))
+	if value == "" {
+		return attrs, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, fmt.Errorf("invalid %s pair %q, must be key=value", envResourceAttributes, pair)
+		}
+		// The values may be percent encoded to hold commas.
+		v, err := url.PathUnescape(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q: %v", envResourceAttributes, kv[1], err)
+		}
+		attrs[strings.TrimSpace(kv[0])] = v
+	}
+	return attrs, nil
+}
+
+// systemDetector detects the host name, the OS type and the machine ID of the
+// host.
+type systemDetector struct {
+	hostname      func() (string, error)
+	machineIDPath string
+}
+
+func (d *systemDetector) Detect(context.Context) (map[string]string, error) {
+	hostname, err := d.hostname()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the host name: %v", err)
+	}
+	attrs := map[string]string{
+		conventions.AttributeHostName: hostname,
+		conventions.AttributeOSType:   runtime.GOOS,
+	}
+
+	// The machine ID is only available on some systems.
+	machineID, err := ioutil.ReadFile(d.machineIDPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read the machine ID: %v", err)
+	}
+	if id := strings.TrimSpace(string(machineID)); id != "" {
+		attrs[conventions.AttributeHostID] = id
+	}
+	return attrs, nil
+}
+
+// containerIDRegexp matches the container ID at the end of a cgroup path, e.g.
+// "/docker/<id>" or "/system.slice/docker-<id>.scope".
+var containerIDRegexp = regexp.MustCompile(`([0-9a-f]{64})(?:\.scope)?$`)
+
+// containerDetector detects the ID of the container of the collector from its
+// cgroups.
+type containerDetector struct {
+	cgroupPath string
+}
+
+func (d *containerDetector) Detect(context.Context) (map[string]string, error) {
+	attrs := make(map[string]string)
+	f, err := os.Open(d.cgroupPath)
+	if os.IsNotExist(err) {
+		return attrs, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the cgroups: %v", err)
+	}
+	defer f.Close()
+
+	// Each line is "hierarchy-ID:controllers:path".
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		if match := containerIDRegexp.FindStringSubmatch(fields[2]); match != nil {
+			attrs[conventions.AttributeContainerID] = match[1]
+			return attrs, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the cgroups: %v", err)
+	}
+	return attrs, nil
+}
+
+// ec2IdentityDocument is the subset of the EC2 instance identity document holding
+// the detected attributes.
+type ec2IdentityDocument struct {
+	AccountID        string `json:"accountId"`
+	Region           string `json:"region"`
+	AvailabilityZone string `json:"availabilityZone"`
+	InstanceID       string `json:"instanceId"`
+	InstanceType     string `json:"instanceType"`
+	ImageID          string `json:"imageId"`
+}
+
+// ec2Detector detects the attributes of the EC2 instance from the identity
+// document of the instance metadata service.
+type ec2Detector struct {
+	endpoint string
+	client   *http.Client
+}
+
+func (d *ec2Detector) Detect(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.endpoint+ec2IdentityDocumentPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the EC2 identity document: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get the EC2 identity document: %s", resp.Status)
+	}
+
+	var doc ec2IdentityDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode the EC2 identity document: %v", err)
+	}
+	attrs := map[string]string{
+		conventions.AttributeCloudProvider: "aws",
+		conventions.AttributeCloudAccount:  doc.AccountID,
+		conventions.AttributeCloudRegion:   doc.Region,
+		conventions.AttributeCloudZone:     doc.AvailabilityZone,
+		conventions.AttributeHostID:        doc.InstanceID,
+		conventions.AttributeHostType:      doc.InstanceType,
+		conventions.AttributeHostImageID:   doc.ImageID,
+	}
+	for k, v := range attrs {
+		if v == "" {
+			delete(attrs, k)
+		}
+	}
+	return attrs, nil
+}
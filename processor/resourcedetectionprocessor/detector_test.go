@@ -0,0 +1,183 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcedetectionprocessor
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+func TestEnvDetector(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "empty", value: "", want: map[string]string{}},
+		{
+			name:  "attributes",
+			value: "service.name=api, deployment.environment = prod,team=a%2Cb",
+			want:  map[string]string{"service.name": "api", "deployment.environment": "prod", "team": "a,b"},
+		},
+		{name: "missing_value", value: "service.name", wantErr: true},
+		{name: "empty_key", value: "=api", wantErr: true},
+		{name: "invalid_encoding", value: "team=a%2", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &envDetector{getenv: func(key string) string {
+				assert.Equal(t, envResourceAttributes, key)
+				return tt.value
+			}}
+			got, err := d.Detect(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSystemDetector(t *testing.T) {
+	dir, err := ioutil.TempDir("", "resourcedetection")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	machineIDPath := filepath.Join(dir, "machine-id")
+	require.NoError(t, ioutil.WriteFile(machineIDPath, []byte("4fd3bd1c9d3e4b2a\n"), 0600))
+
+	hostname := func() (string, error) { return "node-1", nil }
+	d := &systemDetector{hostname: hostname, machineIDPath: machineIDPath}
+	got, err := d.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		conventions.AttributeHostName: "node-1",
+		conventions.AttributeOSType:   runtime.GOOS,
+		conventions.AttributeHostID:   "4fd3bd1c9d3e4b2a",
+	}, got)
+
+	// The machine ID is optional.
+	d.machineIDPath = filepath.Join(dir, "missing")
+	got, err = d.Detect(context.Background())
+	require.NoError(t, err)
+	assert.NotContains(t, got, conventions.AttributeHostID)
+
+	d.hostname = func() (string, error) { return "", errors.New("no host name") }
+	_, err = d.Detect(context.Background())
+	assert.Error(t, err)
+}
+
+func TestContainerDetector(t *testing.T) {
+	const id = "a4d6e5f1c2b3a4d6e5f1c2b3a4d6e5f1c2b3a4d6e5f1c2b3a4d6e5f1c2b3a4d6"
+	tests := []struct {
+		name   string
+		cgroup string
+		want   map[string]string
+	}{
+		{
+			name:   "docker",
+			cgroup: "12:pids:/docker/" + id + "\n11:memory:/docker/" + id + "\n",
+			want:   map[string]string{conventions.AttributeContainerID: id},
+		},
+		{
+			name:   "systemd",
+			cgroup: "1:name=systemd:/system.slice/docker-" + id + ".scope\n",
+			want:   map[string]string{conventions.AttributeContainerID: id},
+		},
+		{
+			name:   "kubernetes",
+			cgroup: "3:cpu,cpuacct:/kubepods/burstable/pod7a6f3b1e/" + id + "\n",
+			want:   map[string]string{conventions.AttributeContainerID: id},
+		},
+		{
+			name:   "host",
+			cgroup: "12:pids:/user.slice/user-1000.slice\n0::/init.scope\n",
+			want:   map[string]string{},
+		},
+	}
+	dir, err := ioutil.TempDir("", "resourcedetection")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cgroupPath := filepath.Join(dir, tt.name)
+			require.NoError(t, ioutil.WriteFile(cgroupPath, []byte(tt.cgroup), 0600))
+			d := &containerDetector{cgroupPath: cgroupPath}
+			got, err := d.Detect(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// Outside of Linux there are no cgroups.
+	d := &containerDetector{cgroupPath: filepath.Join(dir, "missing")}
+	got, err := d.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestEC2Detector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != ec2IdentityDocumentPath {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{
+			"accountId": "123456789012",
+			"region": "eu-west-1",
+			"availabilityZone": "eu-west-1a",
+			"instanceId": "i-0123456789abcdef0",
+			"instanceType": "m5.large",
+			"imageId": ""
+		}`))
+	}))
+	defer server.Close()
+
+	d := &ec2Detector{endpoint: server.URL, client: server.Client()}
+	got, err := d.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		conventions.AttributeCloudProvider: "aws",
+		conventions.AttributeCloudAccount:  "123456789012",
+		conventions.AttributeCloudRegion:   "eu-west-1",
+		conventions.AttributeCloudZone:     "eu-west-1a",
+		conventions.AttributeHostID:        "i-0123456789abcdef0",
+		conventions.AttributeHostType:      "m5.large",
+	}, got)
+
+	d.endpoint = server.URL + "/other"
+	_, err = d.Detect(context.Background())
+	assert.Error(t, err)
+
+	// The detector has a client with the configured timeout.
+	created, err := newDetector(ec2DetectorName, &Config{Timeout: time.Second, EC2: EC2Config{Endpoint: server.URL}})
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, created.(*ec2Detector).client.Timeout)
+}
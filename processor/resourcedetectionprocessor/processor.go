@@ -0,0 +1,178 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcedetectionprocessor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/internal/data"
+)
+
+type resourceDetectionProcessor struct {
+	logger    *zap.Logger
+	detectors []namedDetector
+	override  bool
+	timeout   time.Duration
+
+	traceConsumer   consumer.TraceConsumer
+	metricsConsumer consumer.MetricsConsumer
+	logConsumer     consumer.LogConsumer
+
+	// attributes are the attributes detected at startup.
+	attributes map[string]string
+}
+
+type namedDetector struct {
+	name string
+	detector
+}
+
+var _ component.TraceProcessor = (*resourceDetectionProcessor)(nil)
+var _ component.MetricsProcessor = (*resourceDetectionProcessor)(nil)
+var _ component.LogProcessor = (*resourceDetectionProcessor)(nil)
+
+func newResourceDetectionProcessor(
+	logger *zap.Logger,
+	traceConsumer consumer.TraceConsumer,
+	metricsConsumer consumer.MetricsConsumer,
+	logConsumer consumer.LogConsumer,
+	cfg *Config,
+) (*resourceDetectionProcessor, error) {
+	if len(cfg.Detectors) == 0 {
+		return nil, errors.New("\"detectors\" must be set")
+	}
+	if cfg.Timeout <= 0 {
+		return nil, errors.New("\"timeout\" must be positive")
+	}
+
+	detectors := make([]namedDetector, 0, len(cfg.Detectors))
+	for _, name := range cfg.Detectors {
+		d, err := newDetector(name, cfg)
+		if err != nil {
+			return nil, err
+		}
+		detectors = append(detectors, namedDetector{name: name, detector: d})
+	}
+
+	return &resourceDetectionProcessor{
+		logger:          logger,
+		detectors:       detectors,
+		override:        cfg.Override,
+		timeout:         cfg.Timeout,
+		traceConsumer:   traceConsumer,
+		metricsConsumer: metricsConsumer,
+		logConsumer:     logConsumer,
+	}, nil
+}
+
+// Start detects the resource attributes. The detectors failing, e.g. the ec2
+// detector outside of EC2, are skipped.
+func (p *resourceDetectionProcessor) Start(ctx context.Context, _ component.Host) error {
+	p.attributes = make(map[string]string)
+	for _, d := range p.detectors {
+		attrs, err := p.detect(ctx, d)
+		if err != nil {
+			p.logger.Warn("Failed to detect resource attributes", zap.String("detector", d.name), zap.Error(err))
+			continue
+		}
+		// The attributes of the first detectors take precedence.
+		for k, v := range attrs {
+			if _, ok := p.attributes[k]; !ok {
+				p.attributes[k] = v
+			}
+		}
+	}
+	p.logger.Info("Detected resource attributes", zap.Any("attributes", p.attributes))
+	return nil
+}
+
+// detect runs the detector with its own timeout, so a slow detector doesn't use
+// up the time of the next ones.
+func (p *resourceDetectionProcessor) detect(ctx context.Context, d namedDetector) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	return d.Detect(ctx)
+}
+
+// Shutdown is invoked during service shutdown.
+func (*resourceDetectionProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+// GetCapabilities returns the Capabilities assocciated with the resource detection processor.
+func (*resourceDetectionProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: true}
+}
+
+// ConsumeTraces adds the detected attributes to the resources of the traces.
+func (p *resourceDetectionProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		if rs := rss.At(i); !rs.IsNil() {
+			p.mergeResource(rs.Resource())
+		}
+	}
+	return p.traceConsumer.ConsumeTraces(ctx, td)
+}
+
+// ConsumeMetrics adds the detected attributes to the resources of the metrics.
+func (p *resourceDetectionProcessor) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	imd := pdatautil.MetricsToInternalMetrics(md)
+	rms := imd.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		if rm := rms.At(i); !rm.IsNil() {
+			p.mergeResource(rm.Resource())
+		}
+	}
+	return p.metricsConsumer.ConsumeMetrics(ctx, pdatautil.MetricsFromInternalMetrics(imd))
+}
+
+// ConsumeLogs adds the detected attributes to the resources of the logs.
+func (p *resourceDetectionProcessor) ConsumeLogs(ctx context.Context, ld data.Logs) error {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		if rl := rls.At(i); !rl.IsNil() {
+			p.mergeResource(rl.Resource())
+		}
+	}
+	return p.logConsumer.ConsumeLogs(ctx, ld)
+}
+
+// mergeResource adds the detected attributes to the resource, replacing the
+// existing ones if override is set.
+func (p *resourceDetectionProcessor) mergeResource(resource pdata.Resource) {
+	if len(p.attributes) == 0 {
+		return
+	}
+	if resource.IsNil() {
+		resource.InitEmpty()
+	}
+	attrs := resource.Attributes()
+	for k, v := range p.attributes {
+		if p.override {
+			attrs.UpsertString(k, v)
+		} else {
+			attrs.InsertString(k, v)
+		}
+	}
+}
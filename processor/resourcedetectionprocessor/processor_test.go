@@ -0,0 +1,199 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcedetectionprocessor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/data"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+type fakeDetector struct {
+	attrs map[string]string
+	err   error
+}
+
+func (d *fakeDetector) Detect(context.Context) (map[string]string, error) {
+	return d.attrs, d.err
+}
+
+// blockingDetector never detects anything, it returns once the context is done.
+type blockingDetector struct{}
+
+func (blockingDetector) Detect(ctx context.Context) (map[string]string, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// contextDetector detects its attributes right away, unless the context is
+// already done.
+type contextDetector struct {
+	attrs map[string]string
+}
+
+func (d *contextDetector) Detect(ctx context.Context) (map[string]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return d.attrs, nil
+}
+
+func newTestProcessor(t *testing.T, override bool) (*resourceDetectionProcessor, *exportertest.SinkTraceExporter, *exportertest.SinkMetricsExporter, *exportertest.SinkLogExporter) {
+	traceSink := &exportertest.SinkTraceExporter{}
+	metricsSink := &exportertest.SinkMetricsExporter{}
+	logSink := &exportertest.SinkLogExporter{}
+	p, err := newResourceDetectionProcessor(zap.NewNop(), traceSink, metricsSink, logSink, &Config{
+		Detectors: []string{envDetectorName},
+		Override:  override,
+		Timeout:   time.Second,
+	})
+	require.NoError(t, err)
+
+	// The attributes of the first detectors take precedence, the failing ones are
+	// skipped.
+	p.detectors = []namedDetector{
+		{name: "first", detector: &fakeDetector{attrs: map[string]string{conventions.AttributeHostName: "node-1"}}},
+		{name: "failing", detector: &fakeDetector{err: errors.New("unavailable")}},
+		{name: "second", detector: &fakeDetector{attrs: map[string]string{
+			conventions.AttributeHostName:  "other",
+			conventions.AttributeCloudZone: "eu-west-1a",
+		}}},
+	}
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	return p, traceSink, metricsSink, logSink
+}
+
+func TestNewProcessorInvalidConfig(t *testing.T) {
+	sink := &exportertest.SinkTraceExporter{}
+	invalid := []*Config{
+		{Timeout: time.Second},
+		{Detectors: []string{envDetectorName}},
+		{Detectors: []string{"unknown"}, Timeout: time.Second},
+	}
+	for _, cfg := range invalid {
+		_, err := newResourceDetectionProcessor(zap.NewNop(), sink, nil, nil, cfg)
+		assert.Error(t, err)
+	}
+}
+
+func TestProcessorStart(t *testing.T) {
+	p, _, _, _ := newTestProcessor(t, true)
+	assert.Equal(t, map[string]string{
+		conventions.AttributeHostName:  "node-1",
+		conventions.AttributeCloudZone: "eu-west-1a",
+	}, p.attributes)
+}
+
+func TestProcessorStartTimeout(t *testing.T) {
+	p, err := newResourceDetectionProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, nil, nil, &Config{
+		Detectors: []string{envDetectorName},
+		Timeout:   10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	// Each detector has its own timeout, the ones after a detector that timed out
+	// still get a context that is not done.
+	p.detectors = []namedDetector{
+		{name: "stuck", detector: blockingDetector{}},
+		{name: "first", detector: &contextDetector{attrs: map[string]string{"first": "true"}}},
+		{name: "second", detector: &contextDetector{attrs: map[string]string{"second": "true"}}},
+	}
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Equal(t, map[string]string{"first": "true", "second": "true"}, p.attributes)
+}
+
+func TestProcessorMergeResource(t *testing.T) {
+	tests := []struct {
+		name     string
+		override bool
+		want     map[string]string
+	}{
+		{
+			name:     "override",
+			override: true,
+			want: map[string]string{
+				conventions.AttributeHostName:    "node-1",
+				conventions.AttributeCloudZone:   "eu-west-1a",
+				conventions.AttributeServiceName: "api",
+			},
+		},
+		{
+			name:     "keep_existing",
+			override: false,
+			want: map[string]string{
+				conventions.AttributeHostName:    "host.local",
+				conventions.AttributeCloudZone:   "eu-west-1a",
+				conventions.AttributeServiceName: "api",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, traceSink, metricsSink, logSink := newTestProcessor(t, tt.override)
+
+			td := pdata.NewTraces()
+			td.ResourceSpans().Resize(2)
+			initResource(td.ResourceSpans().At(0).Resource())
+			require.NoError(t, p.ConsumeTraces(context.Background(), td))
+			traces := traceSink.AllTraces()[0]
+			assert.Equal(t, tt.want, attributesToMap(traces.ResourceSpans().At(0).Resource().Attributes()))
+			// The resources are created if missing.
+			assert.Equal(t, map[string]string{
+				conventions.AttributeHostName:  "node-1",
+				conventions.AttributeCloudZone: "eu-west-1a",
+			}, attributesToMap(traces.ResourceSpans().At(1).Resource().Attributes()))
+
+			md := data.NewMetricData()
+			md.ResourceMetrics().Resize(1)
+			initResource(md.ResourceMetrics().At(0).Resource())
+			require.NoError(t, p.ConsumeMetrics(context.Background(), pdatautil.MetricsFromInternalMetrics(md)))
+			metrics := pdatautil.MetricsToInternalMetrics(metricsSink.AllMetrics()[0])
+			assert.Equal(t, tt.want, attributesToMap(metrics.ResourceMetrics().At(0).Resource().Attributes()))
+
+			ld := data.NewLogs()
+			ld.ResourceLogs().Resize(1)
+			initResource(ld.ResourceLogs().At(0).Resource())
+			require.NoError(t, p.ConsumeLogs(context.Background(), ld))
+			logs := logSink.AllLogs()[0]
+			assert.Equal(t, tt.want, attributesToMap(logs.ResourceLogs().At(0).Resource().Attributes()))
+		})
+	}
+}
+
+func initResource(resource pdata.Resource) {
+	resource.InitEmpty()
+	resource.Attributes().InsertString(conventions.AttributeServiceName, "api")
+	resource.Attributes().InsertString(conventions.AttributeHostName, "host.local")
+}
+
+func attributesToMap(attrs pdata.AttributeMap) map[string]string {
+	m := make(map[string]string)
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		m[k] = v.StringVal()
+	})
+	return m
+}
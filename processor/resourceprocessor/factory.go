@@ -89,6 +89,10 @@ func createAttrProcessor(cfg *Config, logger *zap.Logger) (*attraction.AttrProc,
 	if err != nil {
 		return nil, fmt.Errorf("error creating \"%q\" processor: %w", cfg.Name(), err)
 	}
+	if attrProc.HasResourceActions() {
+		return nil, fmt.Errorf("error creating \"%q\" processor: actions %q, %q and %q are not supported on resources",
+			cfg.Name(), attraction.COPY_FROM_RESOURCE, attraction.MOVE_TO_RESOURCE, attraction.MOVE_FROM_RESOURCE)
+	}
 	return attrProc, nil
 }
 
@@ -24,6 +24,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configcheck"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/internal/processor/attraction"
 )
 
@@ -83,6 +84,15 @@ func TestInvalidAttributeActions(t *testing.T) {
 
 	_, err = factory.CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{}, nil, cfg)
 	assert.Error(t, err)
+
+	// The actions between a resource and its spans or records are rejected.
+	for _, action := range []attraction.Action{attraction.COPY_FROM_RESOURCE, attraction.MOVE_TO_RESOURCE, attraction.MOVE_FROM_RESOURCE} {
+		cfg.AttributesActions = []attraction.ActionKeyValue{{Key: "k", Action: action}}
+		_, err = factory.CreateTraceProcessor(context.Background(), component.ProcessorCreateParams{}, exportertest.NewNopTraceExporter(), cfg)
+		assert.Error(t, err)
+		_, err = factory.CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{}, exportertest.NewNopMetricsExporter(), cfg)
+		assert.Error(t, err)
+	}
 }
 
 func TestDeprecatedConfig(t *testing.T) {
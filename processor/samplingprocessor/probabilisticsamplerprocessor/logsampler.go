@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor
+
+import (
+	"context"
+	"encoding/binary"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/data"
+)
+
+type logsamplerprocessor struct {
+	nextConsumer       consumer.LogConsumer
+	scaledSamplingRate uint32
+	hashSeed           uint32
+}
+
+// newLogProcessor returns a component.LogProcessor that will perform head sampling of log
+// records according to the given configuration.
+func newLogProcessor(nextConsumer consumer.LogConsumer, cfg Config) (component.LogProcessor, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+
+	return &logsamplerprocessor{
+		nextConsumer:       nextConsumer,
+		scaledSamplingRate: uint32(cfg.SamplingPercentage * percentageScaleFactor),
+		hashSeed:           cfg.HashSeed,
+	}, nil
+}
+
+func (lsp *logsamplerprocessor) ConsumeLogs(ctx context.Context, ld data.Logs) error {
+	sampledLogs := data.NewLogs()
+	probability := samplingProbability(lsp.scaledSamplingRate)
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		// The destination of the sampled log records of the resource, created on the first sampled record.
+		var sampledRl *pdata.ResourceLogs
+		logs := rl.Logs()
+		for j := 0; j < logs.Len(); j++ {
+			lr := logs.At(j)
+			if lr.IsNil() {
+				continue
+			}
+			sp := parseSamplingPriority(lr.Attributes())
+			if sp == doNotSampleSpan {
+				continue
+			}
+
+			sampled := sp == mustSampleSpan ||
+				hash(logRecordHashKey(lr), lsp.hashSeed)&bitMaskHashBuckets < lsp.scaledSamplingRate
+			if !sampled {
+				continue
+			}
+
+			if sampledRl == nil {
+				newRl := pdata.NewResourceLogs()
+				newRl.InitEmpty()
+				rl.Resource().CopyTo(newRl.Resource())
+				sampledLogs.ResourceLogs().Append(&newRl)
+				sampledRl = &newRl
+			}
+			sampledLr := pdata.NewLogRecord()
+			lr.CopyTo(sampledLr)
+			if sp == deferDecision {
+				recordSamplingProbability(sampledLr.Attributes(), probability)
+			}
+			sampledRl.Logs().Append(&sampledLr)
+		}
+	}
+
+	if sampledLogs.LogRecordCount() == 0 {
+		return nil
+	}
+	return lsp.nextConsumer.ConsumeLogs(ctx, sampledLogs)
+}
+
+func (lsp *logsamplerprocessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: false}
+}
+
+// Start is invoked during service startup.
+func (lsp *logsamplerprocessor) Start(ctx context.Context, host component.Host) error {
+	return nil
+}
+
+// Shutdown is invoked during service shutdown.
+func (lsp *logsamplerprocessor) Shutdown(context.Context) error {
+	return nil
+}
+
+// logRecordHashKey returns the key used to hash the log record: its trace ID, so the
+// log records follow the sampling decision of their trace, or, if the record is not
+// part of a trace, its body and timestamp.
+func logRecordHashKey(lr pdata.LogRecord) []byte {
+	if traceID := lr.TraceID().Bytes(); len(traceID) != 0 {
+		return traceID
+	}
+	body := lr.Body()
+	key := make([]byte, 8, 8+len(body))
+	binary.LittleEndian.PutUint64(key, uint64(lr.Timestamp()))
+	return append(key, body...)
+}
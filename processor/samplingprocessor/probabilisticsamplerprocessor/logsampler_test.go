@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/data"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+func TestNewLogProcessor(t *testing.T) {
+	_, err := newLogProcessor(nil, Config{})
+	assert.Error(t, err)
+
+	cfg := Config{SamplingPercentage: 15.5, HashSeed: 4321}
+	lp, err := newLogProcessor(&exportertest.SinkLogExporter{}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(cfg.SamplingPercentage*percentageScaleFactor), lp.(*logsamplerprocessor).scaledSamplingRate)
+	assert.Equal(t, uint32(4321), lp.(*logsamplerprocessor).hashSeed)
+}
+
+// Test_logsamplerprocessor_FollowsTraceDecision checks that the log records are sampled
+// with the same decision as the spans of their trace.
+func Test_logsamplerprocessor_FollowsTraceDecision(t *testing.T) {
+	cfg := Config{SamplingPercentage: 30, HashSeed: 22}
+	tracesSink := &exportertest.SinkTraceExporter{}
+	tsp, err := newTraceProcessor(tracesSink, cfg)
+	require.NoError(t, err)
+	logsSink := &exportertest.SinkLogExporter{}
+	lsp, err := newLogProcessor(logsSink, cfg)
+	require.NoError(t, err)
+
+	const numTraces = 1000
+	for _, td := range genRandomTestData(1, numTraces, "test-svc") {
+		ld := data.NewLogs()
+		ld.ResourceLogs().Resize(1)
+		logs := ld.ResourceLogs().At(0).Logs()
+		spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+		logs.Resize(spans.Len())
+		for i := 0; i < spans.Len(); i++ {
+			logs.At(i).SetTraceID(spans.At(i).TraceID())
+		}
+
+		require.NoError(t, tsp.ConsumeTraces(context.Background(), td))
+		require.NoError(t, lsp.ConsumeLogs(context.Background(), ld))
+	}
+
+	sampledTraceIDs, sampledSpans := assertSampledData(t, tracesSink.AllTraces(), "test-svc")
+	require.Greater(t, sampledSpans, 0)
+	require.Less(t, sampledSpans, numTraces)
+
+	sampledLogs := 0
+	for _, ld := range logsSink.AllLogs() {
+		logs := ld.ResourceLogs().At(0).Logs()
+		for i := 0; i < logs.Len(); i++ {
+			lr := logs.At(i)
+			assert.True(t, sampledTraceIDs[string(lr.TraceID().Bytes())], "log record of a not sampled trace")
+			prob, ok := lr.Attributes().Get(samplingProbabilityAttribute)
+			require.True(t, ok)
+			assert.InDelta(t, samplingProbability(uint32(cfg.SamplingPercentage*percentageScaleFactor)), prob.DoubleVal(), 1e-9)
+			sampledLogs++
+		}
+	}
+	assert.Equal(t, sampledSpans, sampledLogs)
+}
+
+// Test_logsamplerprocessor_RecordHashing checks that the log records without trace are
+// sampled according to the configured percentage.
+func Test_logsamplerprocessor_RecordHashing(t *testing.T) {
+	sink := &exportertest.SinkLogExporter{}
+	lsp, err := newLogProcessor(sink, Config{SamplingPercentage: 25})
+	require.NoError(t, err)
+
+	const numRecords = 10000
+	ld := data.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	logs := ld.ResourceLogs().At(0).Logs()
+	logs.Resize(numRecords)
+	for i := 0; i < numRecords; i++ {
+		logs.At(i).SetTimestamp(pdata.TimestampUnixNano(1000 + i))
+		logs.At(i).SetBody("log record")
+	}
+	require.NoError(t, lsp.ConsumeLogs(context.Background(), ld))
+
+	sampled := 0
+	for _, sampledLd := range sink.AllLogs() {
+		sampled += sampledLd.LogRecordCount()
+	}
+	assert.InDelta(t, 0.25, float64(sampled)/numRecords, 0.02)
+}
+
+// Test_logsamplerprocessor_SamplingPriority checks if handling of "sampling.priority" is correct.
+func Test_logsamplerprocessor_SamplingPriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		pct      float32
+		priority int64
+		sampled  bool
+	}{
+		{
+			name:     "must_sample",
+			priority: 1,
+			sampled:  true,
+		},
+		{
+			name:     "must_not_sample",
+			pct:      100,
+			priority: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &exportertest.SinkLogExporter{}
+			lsp, err := newLogProcessor(sink, Config{SamplingPercentage: tt.pct})
+			require.NoError(t, err)
+
+			ld := data.NewLogs()
+			ld.ResourceLogs().Resize(1)
+			logs := ld.ResourceLogs().At(0).Logs()
+			logs.Resize(1)
+			logs.At(0).SetTraceID(pdata.NewTraceID(tracetranslator.UInt64ToByteTraceID(1, 2)))
+			logs.At(0).Attributes().InsertInt(samplingPriorityAttribute, tt.priority)
+			require.NoError(t, lsp.ConsumeLogs(context.Background(), ld))
+
+			if !tt.sampled {
+				assert.Len(t, sink.AllLogs(), 0)
+				return
+			}
+			require.Len(t, sink.AllLogs(), 1)
+			lr := sink.AllLogs()[0].ResourceLogs().At(0).Logs().At(0)
+			_, ok := lr.Attributes().Get(samplingProbabilityAttribute)
+			assert.False(t, ok, "forcibly sampled log records must not record the sampling probability")
+		})
+	}
+}
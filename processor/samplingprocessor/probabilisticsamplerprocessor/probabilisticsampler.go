@@ -17,13 +17,12 @@ package probabilisticsamplerprocessor
 import (
 	"context"
 	"strconv"
-
-	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"strings"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/consumer"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
 // samplingPriority has the semantic result of parsing the "sampling.priority"
@@ -48,17 +47,29 @@ const (
 	numHashBuckets        = 0x4000 // Using a power of 2 to avoid division.
 	bitMaskHashBuckets    = numHashBuckets - 1
 	percentageScaleFactor = numHashBuckets / 100.0
+
+	// samplingPriorityAttribute is the OpenTracing semantic convention to force,
+	// or prevent, the sampling of a span.
+	samplingPriorityAttribute = "sampling.priority"
+	// samplingProbabilityAttribute records the probability with which the span or
+	// log record was sampled, so backends can extrapolate the original counts.
+	samplingProbabilityAttribute = "sampling.probability"
+	// traceStateKey is the key of the tracestate entry recording the sampling probability.
+	traceStateKey = "sampling_probability"
+	// maxTraceStateEntries is the maximum number of tracestate entries allowed by
+	// https://www.w3.org/TR/trace-context/#tracestate-header.
+	maxTraceStateEntries = 32
 )
 
 type tracesamplerprocessor struct {
-	nextConsumer       consumer.TraceConsumerOld
+	nextConsumer       consumer.TraceConsumer
 	scaledSamplingRate uint32
 	hashSeed           uint32
 }
 
 // newTraceProcessor returns a processor.TraceProcessor that will perform head sampling according to the given
 // configuration.
-func newTraceProcessor(nextConsumer consumer.TraceConsumerOld, cfg Config) (component.TraceProcessorOld, error) {
+func newTraceProcessor(nextConsumer consumer.TraceConsumer, cfg Config) (component.TraceProcessor, error) {
 	if nextConsumer == nil {
 		return nil, componenterror.ErrNilNextConsumer
 	}
@@ -71,39 +82,79 @@ func newTraceProcessor(nextConsumer consumer.TraceConsumerOld, cfg Config) (comp
 	}, nil
 }
 
-func (tsp *tracesamplerprocessor) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
-	scaledSamplingRate := tsp.scaledSamplingRate
-
-	sampledTraceData := consumerdata.TraceData{
-		Node:         td.Node,
-		Resource:     td.Resource,
-		SourceFormat: td.SourceFormat,
-	}
+func (tsp *tracesamplerprocessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	sampledTraces := pdata.NewTraces()
+	probability := samplingProbability(tsp.scaledSamplingRate)
 
-	sampledSpans := make([]*tracepb.Span, 0, len(td.Spans))
-	for _, span := range td.Spans {
-		sp := parseSpanSamplingPriority(span)
-		if sp == doNotSampleSpan {
-			// The OpenTelemetry mentions this as a "hint" we take a stronger
-			// approach and do not sample the span since some may use it to
-			// remove specific spans from traces.
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
 			continue
 		}
+		// The destination of the sampled spans of the resource, created on the first sampled span.
+		var sampledRs *pdata.ResourceSpans
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
+			}
+			// The destination of the sampled spans, created on the first sampled span.
+			var sampledSpans *pdata.SpanSlice
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
+				}
+				sp := parseSamplingPriority(span.Attributes())
+				if sp == doNotSampleSpan {
+					// The OpenTelemetry mentions this as a "hint" we take a stronger
+					// approach and do not sample the span since some may use it to
+					// remove specific spans from traces.
+					continue
+				}
 
-		// If one assumes random trace ids hashing may seems avoidable, however, traces can be coming from sources
-		// with various different criteria to generate trace id and perhaps were already sampled without hashing.
-		// Hashing here prevents bias due to such systems.
-		sampled := sp == mustSampleSpan ||
-			hash(span.TraceId, tsp.hashSeed)&bitMaskHashBuckets < scaledSamplingRate
+				// If one assumes random trace ids hashing may seems avoidable, however, traces can be coming from sources
+				// with various different criteria to generate trace id and perhaps were already sampled without hashing.
+				// Hashing here prevents bias due to such systems.
+				sampled := sp == mustSampleSpan ||
+					hash(span.TraceID().Bytes(), tsp.hashSeed)&bitMaskHashBuckets < tsp.scaledSamplingRate
+				if !sampled {
+					continue
+				}
 
-		if sampled {
-			sampledSpans = append(sampledSpans, span)
+				if sampledSpans == nil {
+					if sampledRs == nil {
+						newRs := pdata.NewResourceSpans()
+						newRs.InitEmpty()
+						rs.Resource().CopyTo(newRs.Resource())
+						sampledTraces.ResourceSpans().Append(&newRs)
+						sampledRs = &newRs
+					}
+					sampledIls := pdata.NewInstrumentationLibrarySpans()
+					sampledIls.InitEmpty()
+					ils.InstrumentationLibrary().CopyTo(sampledIls.InstrumentationLibrary())
+					sampledRs.InstrumentationLibrarySpans().Append(&sampledIls)
+					ilsSpans := sampledIls.Spans()
+					sampledSpans = &ilsSpans
+				}
+				sampledSpan := pdata.NewSpan()
+				span.CopyTo(sampledSpan)
+				if sp == deferDecision {
+					// Spans forcibly sampled don't carry the probability of this sampler.
+					recordSpanSamplingProbability(sampledSpan, probability)
+				}
+				sampledSpans.Append(&sampledSpan)
+			}
 		}
 	}
 
-	sampledTraceData.Spans = sampledSpans
-
-	return tsp.nextConsumer.ConsumeTraceData(ctx, sampledTraceData)
+	if sampledTraces.SpanCount() == 0 {
+		return nil
+	}
+	return tsp.nextConsumer.ConsumeTraces(ctx, sampledTraces)
 }
 
 func (tsp *tracesamplerprocessor) GetCapabilities() component.ProcessorCapabilities {
@@ -120,18 +171,61 @@ func (tsp *tracesamplerprocessor) Shutdown(context.Context) error {
 	return nil
 }
 
-// parseSpanSamplingPriority checks if the span has the "sampling.priority" tag to
-// decide if the span should be sampled or not. The usage of the tag follows the
-// OpenTracing semantic tags:
-// https://github.com/opentracing/specification/blob/master/semantic_conventions.md#span-tags-table
-func parseSpanSamplingPriority(span *tracepb.Span) samplingPriority {
-	attribMap := span.GetAttributes().GetAttributeMap()
-	if attribMap == nil {
-		return deferDecision
+// samplingProbability returns the actual probability, from 0 to 1, applied for
+// the given scaled sampling rate.
+func samplingProbability(scaledSamplingRate uint32) float64 {
+	if scaledSamplingRate >= numHashBuckets {
+		return 1
 	}
+	return float64(scaledSamplingRate) / numHashBuckets
+}
 
-	samplingPriorityAttrib := attribMap["sampling.priority"]
-	if samplingPriorityAttrib == nil {
+// recordSpanSamplingProbability records the effective sampling probability of the
+// span in its attributes and tracestate. If the span was already sampled by a previous
+// sampler the probabilities are combined, assuming independent samplers, i.e. using
+// different hash seeds.
+func recordSpanSamplingProbability(span pdata.Span, probability float64) {
+	probability = recordSamplingProbability(span.Attributes(), probability)
+	span.SetTraceState(updateTraceState(span.TraceState(),
+		traceStateKey, strconv.FormatFloat(probability, 'g', -1, 64)))
+}
+
+// recordSamplingProbability combines the given probability with the one already
+// recorded, if any, and updates the attribute with the result, which is returned.
+func recordSamplingProbability(attrs pdata.AttributeMap, probability float64) float64 {
+	if prev, ok := attrs.Get(samplingProbabilityAttribute); ok && prev.Type() == pdata.AttributeValueDOUBLE {
+		probability *= prev.DoubleVal()
+	}
+	attrs.UpsertDouble(samplingProbabilityAttribute, probability)
+	return probability
+}
+
+// updateTraceState sets the entry with the given key, moving it to the beginning
+// of the list per https://www.w3.org/TR/trace-context/#mutating-the-tracestate-field.
+func updateTraceState(ts pdata.TraceState, key, value string) pdata.TraceState {
+	entries := []string{key + "=" + value}
+	if ts != pdata.TraceStateEmpty {
+		for _, entry := range strings.Split(string(ts), ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" || strings.HasPrefix(entry, key+"=") {
+				continue
+			}
+			if len(entries) == maxTraceStateEntries {
+				break
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return pdata.TraceState(strings.Join(entries, ","))
+}
+
+// parseSamplingPriority checks if the attributes have the "sampling.priority" tag to
+// decide if the span or log record should be sampled or not. The usage of the tag
+// follows the OpenTracing semantic tags:
+// https://github.com/opentracing/specification/blob/master/semantic_conventions.md#span-tags-table
+func parseSamplingPriority(attrs pdata.AttributeMap) samplingPriority {
+	samplingPriorityAttrib, ok := attrs.Get(samplingPriorityAttribute)
+	if !ok {
 		return deferDecision
 	}
 
@@ -142,23 +236,23 @@ func parseSpanSamplingPriority(span *tracepb.Span) samplingPriority {
 	// using different conventions regarding "sampling.priority". Besides the
 	// client libraries it is also possible that the type was lost in translation
 	// between different formats.
-	switch samplingPriorityAttrib.Value.(type) {
-	case *tracepb.AttributeValue_IntValue:
-		value := samplingPriorityAttrib.GetIntValue()
+	switch samplingPriorityAttrib.Type() {
+	case pdata.AttributeValueINT:
+		value := samplingPriorityAttrib.IntVal()
 		if value == 0 {
 			decision = doNotSampleSpan
 		} else if value > 0 {
 			decision = mustSampleSpan
 		}
-	case *tracepb.AttributeValue_DoubleValue:
-		value := samplingPriorityAttrib.GetDoubleValue()
+	case pdata.AttributeValueDOUBLE:
+		value := samplingPriorityAttrib.DoubleVal()
 		if value == 0.0 {
 			decision = doNotSampleSpan
 		} else if value > 0.0 {
 			decision = mustSampleSpan
 		}
-	case *tracepb.AttributeValue_StringValue:
-		attribVal := samplingPriorityAttrib.GetStringValue().GetValue()
+	case pdata.AttributeValueSTRING:
+		attribVal := samplingPriorityAttrib.StringVal()
 		if value, err := strconv.ParseFloat(attribVal, 64); err == nil {
 			if value == 0.0 {
 				decision = doNotSampleSpan
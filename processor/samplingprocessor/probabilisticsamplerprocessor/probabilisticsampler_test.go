@@ -21,25 +21,23 @@ import (
 	"reflect"
 	"testing"
 
-	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
-	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/exportertest"
-	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/translator/conventions"
 	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 )
 
 func TestNewTraceProcessor(t *testing.T) {
 	tests := []struct {
 		name         string
-		nextConsumer consumer.TraceConsumerOld
+		nextConsumer consumer.TraceConsumer
 		cfg          Config
-		want         component.TraceProcessorOld
+		want         component.TraceProcessor
 		wantErr      bool
 	}{
 		{
@@ -48,23 +46,23 @@ func TestNewTraceProcessor(t *testing.T) {
 		},
 		{
 			name:         "happy_path",
-			nextConsumer: &exportertest.SinkTraceExporterOld{},
+			nextConsumer: &exportertest.SinkTraceExporter{},
 			cfg: Config{
 				SamplingPercentage: 15.5,
 			},
 			want: &tracesamplerprocessor{
-				nextConsumer: &exportertest.SinkTraceExporterOld{},
+				nextConsumer: &exportertest.SinkTraceExporter{},
 			},
 		},
 		{
 			name:         "happy_path_hash_seed",
-			nextConsumer: &exportertest.SinkTraceExporterOld{},
+			nextConsumer: &exportertest.SinkTraceExporter{},
 			cfg: Config{
 				SamplingPercentage: 13.33,
 				HashSeed:           4321,
 			},
 			want: &tracesamplerprocessor{
-				nextConsumer: &exportertest.SinkTraceExporterOld{},
+				nextConsumer: &exportertest.SinkTraceExporter{},
 				hashSeed:     4321,
 			},
 		},
@@ -146,15 +144,15 @@ func Test_tracesamplerprocessor_SamplingPercentageRange(t *testing.T) {
 	const testSvcName = "test-svc"
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			sink := &exportertest.SinkTraceExporterOld{}
+			sink := &exportertest.SinkTraceExporter{}
 			tsp, err := newTraceProcessor(sink, tt.cfg)
 			if err != nil {
 				t.Errorf("error when creating tracesamplerprocessor: %v", err)
 				return
 			}
 			for _, td := range genRandomTestData(tt.numBatches, tt.numTracesPerBatch, testSvcName) {
-				if err := tsp.ConsumeTraceData(context.Background(), td); err != nil {
-					t.Errorf("tracesamplerprocessor.ConsumeTraceData() error = %v", err)
+				if err := tsp.ConsumeTraces(context.Background(), td); err != nil {
+					t.Errorf("tracesamplerprocessor.ConsumeTraces() error = %v", err)
 					return
 				}
 			}
@@ -176,23 +174,20 @@ func Test_tracesamplerprocessor_SamplingPercentageRange(t *testing.T) {
 
 // Test_tracesamplerprocessor_SpanSamplingPriority checks if handling of "sampling.priority" is correct.
 func Test_tracesamplerprocessor_SpanSamplingPriority(t *testing.T) {
-	singleSpanWithAttrib := func(key string, attribValue *tracepb.AttributeValue) consumerdata.TraceData {
-		return consumerdata.TraceData{
-			Spans: []*tracepb.Span{
-				{
-					Attributes: &tracepb.Span_Attributes{
-						AttributeMap: map[string]*tracepb.AttributeValue{
-							key: {Value: attribValue.Value},
-						},
-					},
-				},
-			},
-		}
+	singleSpanWithAttrib := func(key string, attribValue pdata.AttributeValue) pdata.Traces {
+		td := pdata.NewTraces()
+		td.ResourceSpans().Resize(1)
+		rs := td.ResourceSpans().At(0)
+		rs.InstrumentationLibrarySpans().Resize(1)
+		ils := rs.InstrumentationLibrarySpans().At(0)
+		ils.Spans().Resize(1)
+		ils.Spans().At(0).Attributes().InitFromMap(map[string]pdata.AttributeValue{key: attribValue})
+		return td
 	}
 	tests := []struct {
 		name    string
 		cfg     Config
-		td      consumerdata.TraceData
+		td      pdata.Traces
 		sampled bool
 	}{
 		{
@@ -202,7 +197,7 @@ func Test_tracesamplerprocessor_SpanSamplingPriority(t *testing.T) {
 			},
 			td: singleSpanWithAttrib(
 				"sampling.priority",
-				&tracepb.AttributeValue{Value: &tracepb.AttributeValue_IntValue{IntValue: 2}}),
+				pdata.NewAttributeValueInt(2)),
 			sampled: true,
 		},
 		{
@@ -212,7 +207,7 @@ func Test_tracesamplerprocessor_SpanSamplingPriority(t *testing.T) {
 			},
 			td: singleSpanWithAttrib(
 				"sampling.priority",
-				&tracepb.AttributeValue{Value: &tracepb.AttributeValue_DoubleValue{DoubleValue: 1}}),
+				pdata.NewAttributeValueDouble(1)),
 			sampled: true,
 		},
 		{
@@ -222,7 +217,7 @@ func Test_tracesamplerprocessor_SpanSamplingPriority(t *testing.T) {
 			},
 			td: singleSpanWithAttrib(
 				"sampling.priority",
-				&tracepb.AttributeValue{Value: &tracepb.AttributeValue_StringValue{StringValue: &tracepb.TruncatableString{Value: "1"}}}),
+				pdata.NewAttributeValueString("1")),
 			sampled: true,
 		},
 		{
@@ -232,7 +227,7 @@ func Test_tracesamplerprocessor_SpanSamplingPriority(t *testing.T) {
 			},
 			td: singleSpanWithAttrib(
 				"sampling.priority",
-				&tracepb.AttributeValue{Value: &tracepb.AttributeValue_IntValue{IntValue: 0}}),
+				pdata.NewAttributeValueInt(0)),
 		},
 		{
 			name: "must_not_sample_double",
@@ -241,7 +236,7 @@ func Test_tracesamplerprocessor_SpanSamplingPriority(t *testing.T) {
 			},
 			td: singleSpanWithAttrib(
 				"sampling.priority",
-				&tracepb.AttributeValue{Value: &tracepb.AttributeValue_DoubleValue{DoubleValue: 0}}),
+				pdata.NewAttributeValueDouble(0)),
 		},
 		{
 			name: "must_not_sample_string",
@@ -250,7 +245,7 @@ func Test_tracesamplerprocessor_SpanSamplingPriority(t *testing.T) {
 			},
 			td: singleSpanWithAttrib(
 				"sampling.priority",
-				&tracepb.AttributeValue{Value: &tracepb.AttributeValue_StringValue{StringValue: &tracepb.TruncatableString{Value: "0"}}}),
+				pdata.NewAttributeValueString("0")),
 		},
 		{
 			name: "defer_sample_expect_not_sampled",
@@ -259,7 +254,7 @@ func Test_tracesamplerprocessor_SpanSamplingPriority(t *testing.T) {
 			},
 			td: singleSpanWithAttrib(
 				"no.sampling.priority",
-				&tracepb.AttributeValue{Value: &tracepb.AttributeValue_IntValue{IntValue: 2}}),
+				pdata.NewAttributeValueInt(2)),
 		},
 		{
 			name: "defer_sample_expect_sampled",
@@ -268,193 +263,235 @@ func Test_tracesamplerprocessor_SpanSamplingPriority(t *testing.T) {
 			},
 			td: singleSpanWithAttrib(
 				"no.sampling.priority",
-				&tracepb.AttributeValue{Value: &tracepb.AttributeValue_IntValue{IntValue: 2}}),
+				pdata.NewAttributeValueInt(2)),
 			sampled: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			sink := &exportertest.SinkTraceExporterOld{}
+			sink := &exportertest.SinkTraceExporter{}
 			tsp, err := newTraceProcessor(sink, tt.cfg)
 			require.NoError(t, err)
 
-			err = tsp.ConsumeTraceData(context.Background(), tt.td)
+			err = tsp.ConsumeTraces(context.Background(), tt.td)
 			require.NoError(t, err)
 
-			sampledData := sink.AllTraces()
-			require.Equal(t, 1, len(sampledData))
-			assert.Equal(t, tt.sampled, len(sampledData[0].Spans) == 1)
+			assert.Equal(t, tt.sampled, spanCount(sink.AllTraces()) == 1)
 		})
 	}
 }
 
-// Test_parseSpanSamplingPriority ensures that the function parsing the attributes is taking "sampling.priority"
-// attribute correctly.
-func Test_parseSpanSamplingPriority(t *testing.T) {
+// Test_tracesamplerprocessor_SamplingProbability checks that the sampling probability is recorded
+// in the attributes and tracestate of the sampled spans.
+func Test_tracesamplerprocessor_SamplingProbability(t *testing.T) {
+	const halfHashBuckets = numHashBuckets / 2
+	// Find a trace ID sampled with a 50% probability.
+	var sampledTraceID []byte
+	for i := uint64(1); sampledTraceID == nil; i++ {
+		traceID := tracetranslator.UInt64ToByteTraceID(1, i)
+		if hash(traceID, 0)&bitMaskHashBuckets < halfHashBuckets {
+			sampledTraceID = traceID
+		}
+	}
+	newSpan := func(attrs map[string]pdata.AttributeValue, traceState pdata.TraceState) pdata.Traces {
+		td := pdata.NewTraces()
+		td.ResourceSpans().Resize(1)
+		rs := td.ResourceSpans().At(0)
+		rs.InstrumentationLibrarySpans().Resize(1)
+		ils := rs.InstrumentationLibrarySpans().At(0)
+		ils.Spans().Resize(1)
+		span := ils.Spans().At(0)
+		span.SetTraceID(pdata.NewTraceID(sampledTraceID))
+		span.SetTraceState(traceState)
+		span.Attributes().InitFromMap(attrs)
+		return td
+	}
 	tests := []struct {
-		name string
-		span *tracepb.Span
-		want samplingPriority
+		name               string
+		td                 pdata.Traces
+		wantProbability    float64
+		wantTraceState     pdata.TraceState
+		wantNoRecordedProb bool
 	}{
 		{
-			name: "nil_span",
-			span: &tracepb.Span{},
-			want: deferDecision,
+			name:            "new",
+			td:              newSpan(nil, pdata.TraceStateEmpty),
+			wantProbability: 0.5,
+			wantTraceState:  "sampling_probability=0.5",
 		},
 		{
-			name: "nil_attributes",
-			span: &tracepb.Span{},
-			want: deferDecision,
+			name:            "existing_tracestate",
+			td:              newSpan(nil, "vendor1=value1,sampling_probability=0.1,vendor2=value2"),
+			wantProbability: 0.5,
+			wantTraceState:  "sampling_probability=0.5,vendor1=value1,vendor2=value2",
 		},
 		{
-			name: "nil_attribute_map",
-			span: &tracepb.Span{
-				Attributes: &tracepb.Span_Attributes{},
-			},
-			want: deferDecision,
+			name: "previously_sampled",
+			td: newSpan(map[string]pdata.AttributeValue{
+				samplingProbabilityAttribute: pdata.NewAttributeValueDouble(0.1),
+			}, "sampling_probability=0.1"),
+			wantProbability: 0.05,
+			wantTraceState:  "sampling_probability=0.05",
 		},
+		{
+			name: "must_sample",
+			td: newSpan(map[string]pdata.AttributeValue{
+				samplingPriorityAttribute: pdata.NewAttributeValueInt(1),
+			}, pdata.TraceStateEmpty),
+			wantNoRecordedProb: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &exportertest.SinkTraceExporter{}
+			tsp, err := newTraceProcessor(sink, Config{SamplingPercentage: 50})
+			require.NoError(t, err)
+			require.NoError(t, tsp.ConsumeTraces(context.Background(), tt.td))
+			require.Equal(t, 1, spanCount(sink.AllTraces()))
+			span := sink.AllTraces()[0].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+
+			prob, ok := span.Attributes().Get(samplingProbabilityAttribute)
+			if tt.wantNoRecordedProb {
+				assert.False(t, ok)
+				assert.Equal(t, pdata.TraceStateEmpty, span.TraceState())
+				return
+			}
+			require.True(t, ok)
+			assert.InDelta(t, tt.wantProbability, prob.DoubleVal(), 1e-9)
+			assert.Equal(t, tt.wantTraceState, span.TraceState())
+		})
+	}
+}
+
+func Test_samplingProbability(t *testing.T) {
+	assert.Equal(t, 0.0, samplingProbability(0))
+	assert.Equal(t, 0.5, samplingProbability(numHashBuckets/2))
+	assert.Equal(t, 1.0, samplingProbability(numHashBuckets))
+	assert.Equal(t, 1.0, samplingProbability(2*numHashBuckets))
+}
+
+// Test_parseSamplingPriority ensures that the function parsing the attributes is taking "sampling.priority"
+// attribute correctly.
+func Test_parseSamplingPriority(t *testing.T) {
+	tests := []struct {
+		name  string
+		attrs map[string]pdata.AttributeValue
+		want  samplingPriority
+	}{
 		{
 			name: "empty_attribute_map",
-			span: &tracepb.Span{
-				Attributes: &tracepb.Span_Attributes{
-					AttributeMap: map[string]*tracepb.AttributeValue{},
-				},
-			},
 			want: deferDecision,
 		},
 		{
 			name: "no_sampling_priority",
-			span: &tracepb.Span{
-				Attributes: &tracepb.Span_Attributes{
-					AttributeMap: map[string]*tracepb.AttributeValue{
-						"key": {Value: &tracepb.AttributeValue_BoolValue{BoolValue: true}},
-					},
-				},
+			attrs: map[string]pdata.AttributeValue{
+				"key": pdata.NewAttributeValueBool(true),
 			},
 			want: deferDecision,
 		},
 		{
 			name: "sampling_priority_int_zero",
-			span: &tracepb.Span{
-				Attributes: &tracepb.Span_Attributes{
-					AttributeMap: map[string]*tracepb.AttributeValue{
-						"sampling.priority": {Value: &tracepb.AttributeValue_IntValue{IntValue: 0}},
-					},
-				},
+			attrs: map[string]pdata.AttributeValue{
+				"sampling.priority": pdata.NewAttributeValueInt(0),
 			},
 			want: doNotSampleSpan,
 		},
 		{
 			name: "sampling_priority_int_gt_zero",
-			span: &tracepb.Span{
-				Attributes: &tracepb.Span_Attributes{
-					AttributeMap: map[string]*tracepb.AttributeValue{
-						"sampling.priority": {Value: &tracepb.AttributeValue_IntValue{IntValue: 1}},
-					},
-				},
+			attrs: map[string]pdata.AttributeValue{
+				"sampling.priority": pdata.NewAttributeValueInt(1),
 			},
 			want: mustSampleSpan,
 		},
 		{
 			name: "sampling_priority_int_lt_zero",
-			span: &tracepb.Span{
-				Attributes: &tracepb.Span_Attributes{
-					AttributeMap: map[string]*tracepb.AttributeValue{
-						"sampling.priority": {Value: &tracepb.AttributeValue_IntValue{IntValue: -1}},
-					},
-				},
+			attrs: map[string]pdata.AttributeValue{
+				"sampling.priority": pdata.NewAttributeValueInt(-1),
 			},
 			want: deferDecision,
 		},
 		{
 			name: "sampling_priority_double_zero",
-			span: &tracepb.Span{
-				Attributes: &tracepb.Span_Attributes{
-					AttributeMap: map[string]*tracepb.AttributeValue{
-						"sampling.priority": {Value: &tracepb.AttributeValue_DoubleValue{}},
-					},
-				},
+			attrs: map[string]pdata.AttributeValue{
+				"sampling.priority": pdata.NewAttributeValueDouble(0),
 			},
 			want: doNotSampleSpan,
 		},
 		{
 			name: "sampling_priority_double_gt_zero",
-			span: &tracepb.Span{
-				Attributes: &tracepb.Span_Attributes{
-					AttributeMap: map[string]*tracepb.AttributeValue{
-						"sampling.priority": {Value: &tracepb.AttributeValue_DoubleValue{DoubleValue: 1}},
-					},
-				},
+			attrs: map[string]pdata.AttributeValue{
+				"sampling.priority": pdata.NewAttributeValueDouble(1),
 			},
 			want: mustSampleSpan,
 		},
 		{
 			name: "sampling_priority_double_lt_zero",
-			span: &tracepb.Span{
-				Attributes: &tracepb.Span_Attributes{
-					AttributeMap: map[string]*tracepb.AttributeValue{
-						"sampling.priority": {Value: &tracepb.AttributeValue_DoubleValue{DoubleValue: -1}},
-					},
-				},
+			attrs: map[string]pdata.AttributeValue{
+				"sampling.priority": pdata.NewAttributeValueDouble(-1),
 			},
 			want: deferDecision,
 		},
 		{
 			name: "sampling_priority_string_zero",
-			span: &tracepb.Span{
-				Attributes: &tracepb.Span_Attributes{
-					AttributeMap: map[string]*tracepb.AttributeValue{
-						"sampling.priority": {Value: &tracepb.AttributeValue_StringValue{
-							StringValue: &tracepb.TruncatableString{Value: "0.0"},
-						}},
-					},
-				},
+			attrs: map[string]pdata.AttributeValue{
+				"sampling.priority": pdata.NewAttributeValueString("0.0"),
 			},
 			want: doNotSampleSpan,
 		},
 		{
 			name: "sampling_priority_string_gt_zero",
-			span: &tracepb.Span{
-				Attributes: &tracepb.Span_Attributes{
-					AttributeMap: map[string]*tracepb.AttributeValue{
-						"sampling.priority": {Value: &tracepb.AttributeValue_StringValue{
-							StringValue: &tracepb.TruncatableString{Value: "0.5"},
-						}},
-					},
-				},
+			attrs: map[string]pdata.AttributeValue{
+				"sampling.priority": pdata.NewAttributeValueString("0.5"),
 			},
 			want: mustSampleSpan,
 		},
 		{
 			name: "sampling_priority_string_lt_zero",
-			span: &tracepb.Span{
-				Attributes: &tracepb.Span_Attributes{
-					AttributeMap: map[string]*tracepb.AttributeValue{
-						"sampling.priority": {Value: &tracepb.AttributeValue_StringValue{
-							StringValue: &tracepb.TruncatableString{Value: "-0.5"},
-						}},
-					},
-				},
+			attrs: map[string]pdata.AttributeValue{
+				"sampling.priority": pdata.NewAttributeValueString("-0.5"),
 			},
 			want: deferDecision,
 		},
 		{
 			name: "sampling_priority_string_NaN",
-			span: &tracepb.Span{
-				Attributes: &tracepb.Span_Attributes{
-					AttributeMap: map[string]*tracepb.AttributeValue{
-						"sampling.priority": {Value: &tracepb.AttributeValue_StringValue{
-							StringValue: &tracepb.TruncatableString{Value: "NaN"},
-						}},
-					},
-				},
+			attrs: map[string]pdata.AttributeValue{
+				"sampling.priority": pdata.NewAttributeValueString("NaN"),
 			},
 			want: deferDecision,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equal(t, tt.want, parseSpanSamplingPriority(tt.span))
+			attrs := pdata.NewAttributeMap().InitFromMap(tt.attrs)
+			assert.Equal(t, tt.want, parseSamplingPriority(attrs))
+		})
+	}
+}
+
+func Test_updateTraceState(t *testing.T) {
+	tests := []struct {
+		name string
+		ts   pdata.TraceState
+		want pdata.TraceState
+	}{
+		{
+			name: "empty",
+			want: "key=value",
+		},
+		{
+			name: "other_entries",
+			ts:   "a=1, b=2",
+			want: "key=value,a=1,b=2",
+		},
+		{
+			name: "replace_entry",
+			ts:   "a=1,key=old,b=2",
+			want: "key=value,a=1,b=2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, updateTraceState(tt.ts, "key", "value"))
 		})
 	}
 }
@@ -476,39 +513,26 @@ func Test_hash(t *testing.T) {
 	}
 }
 
-// genRandomTestData generates a slice of consumerdata.TraceData with the numBatches elements which one with
+// genRandomTestData generates a slice of pdata.Traces with the numBatches elements which one with
 // numTracesPerBatch spans (ie.: each span has a different trace ID). All spans belong to the specified
 // serviceName.
-func genRandomTestData(numBatches, numTracesPerBatch int, serviceName string) (tdd []consumerdata.TraceData) {
+func genRandomTestData(numBatches, numTracesPerBatch int, serviceName string) (tdd []pdata.Traces) {
 	r := rand.New(rand.NewSource(1))
 
 	for i := 0; i < numBatches; i++ {
-		var spans []*tracepb.Span
+		td := pdata.NewTraces()
+		td.ResourceSpans().Resize(1)
+		rs := td.ResourceSpans().At(0)
+		rs.Resource().InitEmpty()
+		rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, serviceName)
+		rs.InstrumentationLibrarySpans().Resize(1)
+		spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+		spans.Resize(numTracesPerBatch)
 		for j := 0; j < numTracesPerBatch; j++ {
-			span := &tracepb.Span{
-				TraceId: tracetranslator.UInt64ToByteTraceID(r.Uint64(), r.Uint64()),
-				Attributes: &tracepb.Span_Attributes{
-					AttributeMap: map[string]*tracepb.AttributeValue{
-						tracetranslator.TagHTTPStatusCode: {
-							Value: &tracepb.AttributeValue_IntValue{
-								IntValue: 404,
-							},
-						},
-						tracetranslator.TagHTTPStatusMsg: {
-							Value: &tracepb.AttributeValue_StringValue{
-								StringValue: &tracepb.TruncatableString{Value: "NotFound"},
-							},
-						},
-					},
-				},
-			}
-			spans = append(spans, span)
-		}
-		td := consumerdata.TraceData{
-			Node: &commonpb.Node{
-				ServiceInfo: &commonpb.ServiceInfo{Name: serviceName},
-			},
-			Spans: spans,
+			span := spans.At(j)
+			span.SetTraceID(pdata.NewTraceID(tracetranslator.UInt64ToByteTraceID(r.Uint64(), r.Uint64())))
+			span.Attributes().InsertInt(tracetranslator.TagHTTPStatusCode, 404)
+			span.Attributes().InsertString(tracetranslator.TagHTTPStatusMsg, "NotFound")
 		}
 		tdd = append(tdd, td)
 	}
@@ -516,22 +540,39 @@ func genRandomTestData(numBatches, numTracesPerBatch int, serviceName string) (t
 	return tdd
 }
 
+func spanCount(tds []pdata.Traces) int {
+	count := 0
+	for _, td := range tds {
+		count += td.SpanCount()
+	}
+	return count
+}
+
 // assertSampledData checks for no repeated traceIDs and counts the number of spans on the sampled data for
 // the given service.
-func assertSampledData(t *testing.T, sampled []consumerdata.TraceData, serviceName string) (traceIDs map[string]bool, spanCount int) {
+func assertSampledData(t *testing.T, sampled []pdata.Traces, serviceName string) (traceIDs map[string]bool, spanCount int) {
 	traceIDs = make(map[string]bool)
 	for _, td := range sampled {
-		if processor.ServiceNameForNode(td.Node) != serviceName {
-			continue
-		}
-		for _, span := range td.Spans {
-			spanCount++
-			key := string(span.TraceId)
-			if traceIDs[key] {
-				t.Errorf("same traceID used more than once %q", key)
-				return
+		rss := td.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			rs := rss.At(i)
+			svcName, ok := rs.Resource().Attributes().Get(conventions.AttributeServiceName)
+			if !ok || svcName.StringVal() != serviceName {
+				continue
+			}
+			ilss := rs.InstrumentationLibrarySpans()
+			for j := 0; j < ilss.Len(); j++ {
+				spans := ilss.At(j).Spans()
+				for k := 0; k < spans.Len(); k++ {
+					spanCount++
+					key := string(spans.At(k).TraceID().Bytes())
+					if traceIDs[key] {
+						t.Errorf("same traceID used more than once %q", key)
+						return
+					}
+					traceIDs[key] = true
+				}
 			}
-			traceIDs[key] = true
 		}
 	}
 	return
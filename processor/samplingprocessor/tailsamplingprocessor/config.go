@@ -34,6 +34,22 @@ const (
 	StringAttribute PolicyType = "string_attribute"
 	// RateLimiting allows all traces until the specified limits are satisfied.
 	RateLimiting PolicyType = "rate_limiting"
+	// Latency sample traces that take longer than a given threshold, from the
+	// earliest span start to the latest span end.
+	Latency PolicyType = "latency"
+	// StatusCode sample traces that have at least one span with one of the
+	// listed status codes, e.g.: ERROR.
+	StatusCode PolicyType = "status_code"
+	// SpanCount sample traces that have at least a given number of spans.
+	SpanCount PolicyType = "span_count"
+	// Probabilistic samples a given percentage of the traces, deterministically
+	// based on the hash of the trace ID.
+	Probabilistic PolicyType = "probabilistic"
+	// And samples the traces sampled by all of its sub-policies.
+	And PolicyType = "and"
+	// Composite evaluates its sub-policies in order, each one with a share of a
+	// total rate of spans per second.
+	Composite PolicyType = "composite"
 )
 
 // PolicyCfg holds the common configuration to all policies.
@@ -48,6 +64,18 @@ type PolicyCfg struct {
 	StringAttributeCfg StringAttributeCfg `mapstructure:"string_attribute"`
 	// Configs for rate limiting filter sampling policy evaluator.
 	RateLimitingCfg RateLimitingCfg `mapstructure:"rate_limiting"`
+	// Configs for latency filter sampling policy evaluator.
+	LatencyCfg LatencyCfg `mapstructure:"latency"`
+	// Configs for status code filter sampling policy evaluator.
+	StatusCodeCfg StatusCodeCfg `mapstructure:"status_code"`
+	// Configs for span count filter sampling policy evaluator.
+	SpanCountCfg SpanCountCfg `mapstructure:"span_count"`
+	// Configs for probabilistic sampling policy evaluator.
+	ProbabilisticCfg ProbabilisticCfg `mapstructure:"probabilistic"`
+	// Configs for and sampling policy evaluator.
+	AndCfg AndCfg `mapstructure:"and"`
+	// Configs for composite sampling policy evaluator.
+	CompositeCfg CompositeCfg `mapstructure:"composite"`
 }
 
 // NumericAttributeCfg holds the configurable settings to create a numeric attribute filter
@@ -68,6 +96,9 @@ type StringAttributeCfg struct {
 	Key string `mapstructure:"key"`
 	// Values is the set of attribute values that if any is equal to the actual attribute value to be considered a match.
 	Values []string `mapstructure:"values"`
+	// EnabledRegexMatching determines whether Values are regular expressions, in that case
+	// the attribute value is considered a match if it matches any of them.
+	EnabledRegexMatching bool `mapstructure:"enabled_regex_matching"`
 }
 
 // RateLimitingCfg holds the configurable settings to create a rate limiting
@@ -77,6 +108,69 @@ type RateLimitingCfg struct {
 	SpansPerSecond int64 `mapstructure:"spans_per_second"`
 }
 
+// LatencyCfg holds the configurable settings to create a latency filter
+// sampling policy evaluator.
+type LatencyCfg struct {
+	// ThresholdMs is the minimum duration of the trace, in milliseconds, to be sampled.
+	ThresholdMs int64 `mapstructure:"threshold_ms"`
+}
+
+// StatusCodeCfg holds the configurable settings to create a status code filter
+// sampling policy evaluator.
+type StatusCodeCfg struct {
+	// StatusCodes is the set of span status codes, UNSET, OK or ERROR, that if any
+	// span of the trace has one of them the trace is sampled.
+	StatusCodes []string `mapstructure:"status_codes"`
+}
+
+// SpanCountCfg holds the configurable settings to create a span count filter
+// sampling policy evaluator.
+type SpanCountCfg struct {
+	// MinSpans is the minimum number of spans of the trace to be sampled.
+	MinSpans int64 `mapstructure:"min_spans"`
+}
+
+// ProbabilisticCfg holds the configurable settings to create a probabilistic
+// sampling policy evaluator.
+type ProbabilisticCfg struct {
+	// HashSeed allows one to configure the hashing seed, using the same seed and
+	// percentage as a probabilistic_sampler processor samples the same traces.
+	HashSeed uint32 `mapstructure:"hash_seed"`
+	// SamplingPercentage is the percentage, between 0 and 100, of traces to be sampled.
+	SamplingPercentage float32 `mapstructure:"sampling_percentage"`
+}
+
+// AndCfg holds the configurable settings to create an and sampling policy
+// evaluator.
+type AndCfg struct {
+	// SubPolicyCfgs are the policies that must all sample a trace for it to be sampled.
+	SubPolicyCfgs []PolicyCfg `mapstructure:"sub_policies"`
+}
+
+// CompositeCfg holds the configurable settings to create a composite sampling
+// policy evaluator.
+type CompositeCfg struct {
+	// MaxTotalSpansPerSecond is the limit on the number of spans sampled each second
+	// by all the sub-policies together.
+	MaxTotalSpansPerSecond int64 `mapstructure:"max_total_spans_per_second"`
+	// SubPolicyCfgs are the policies evaluated, in order, until one samples the trace
+	// while having budget left.
+	SubPolicyCfgs []PolicyCfg `mapstructure:"sub_policies"`
+	// RateAllocation sets the share of MaxTotalSpansPerSecond of each sub-policy. If
+	// empty, all sub-policies get an equal share. Sub-policies not listed get equal
+	// shares of the rest.
+	RateAllocation []RateAllocationCfg `mapstructure:"rate_allocation"`
+}
+
+// RateAllocationCfg sets the share of the total rate of a composite policy given
+// to one of its sub-policies.
+type RateAllocationCfg struct {
+	// Policy is the name of the sub-policy.
+	Policy string `mapstructure:"policy"`
+	// Percent is the percentage of the total rate given to the sub-policy.
+	Percent int64 `mapstructure:"percent"`
+}
+
 // Config holds the configuration for tail-based sampling.
 type Config struct {
 	configmodels.ProcessorSettings `mapstructure:",squash"`
@@ -86,6 +180,19 @@ type Config struct {
 	// NumTraces is the number of traces kept on memory. Typically most of the data
 	// of a trace is released after a sampling decision is taken.
 	NumTraces uint64 `mapstructure:"num_traces"`
+	// MaxBufferedBytes limits the estimated size of the spans kept on memory waiting
+	// for a sampling decision, when reached the oldest traces are dropped. Zero means
+	// no limit other than NumTraces.
+	MaxBufferedBytes uint64 `mapstructure:"max_buffered_bytes"`
+	// DecisionCacheSize is the number of sampling decisions kept after the traces are
+	// removed from memory, so that the spans arriving later are forwarded or dropped
+	// according to the original decision. Zero disables the cache.
+	DecisionCacheSize int `mapstructure:"decision_cache_size"`
+	// CompletionQuietPeriod enables the early evaluation of complete traces: once the
+	// root span of a trace was received and no new spans arrived for this period the
+	// trace is evaluated without waiting for DecisionWait, which remains the upper bound.
+	// Zero disables the early evaluation.
+	CompletionQuietPeriod time.Duration `mapstructure:"completion_quiet_period"`
 	// ExpectedNewTracesPerSec sets the expected number of new traces sending to the tail sampling processor
 	// per second. This helps with allocating data structures with closer to actual usage size.
 	ExpectedNewTracesPerSec uint64 `mapstructure:"expected_new_traces_per_sec"`
@@ -45,6 +45,9 @@ func TestLoadConfig(t *testing.T) {
 			},
 			DecisionWait:            10 * time.Second,
 			NumTraces:               100,
+			MaxBufferedBytes:        100 * 1024 * 1024,
+			DecisionCacheSize:       1000,
+			CompletionQuietPeriod:   2 * time.Second,
 			ExpectedNewTracesPerSec: 10,
 			PolicyCfgs: []PolicyCfg{
 				{
@@ -66,6 +69,81 @@ func TestLoadConfig(t *testing.T) {
 					Type:            RateLimiting,
 					RateLimitingCfg: RateLimitingCfg{SpansPerSecond: 35},
 				},
+				{
+					Name:       "test-policy-5",
+					Type:       Latency,
+					LatencyCfg: LatencyCfg{ThresholdMs: 5000},
+				},
+				{
+					Name:          "test-policy-6",
+					Type:          StatusCode,
+					StatusCodeCfg: StatusCodeCfg{StatusCodes: []string{"ERROR", "UNSET"}},
+				},
+				{
+					Name:         "test-policy-7",
+					Type:         SpanCount,
+					SpanCountCfg: SpanCountCfg{MinSpans: 2},
+				},
+				{
+					Name:             "test-policy-8",
+					Type:             Probabilistic,
+					ProbabilisticCfg: ProbabilisticCfg{HashSeed: 22, SamplingPercentage: 5},
+				},
+				{
+					Name: "test-policy-9",
+					Type: StringAttribute,
+					StringAttributeCfg: StringAttributeCfg{
+						Key:                  "http.url",
+						Values:               []string{`/api/v\d+/.*`},
+						EnabledRegexMatching: true,
+					},
+				},
+				{
+					Name: "test-policy-10",
+					Type: And,
+					AndCfg: AndCfg{
+						SubPolicyCfgs: []PolicyCfg{
+							{
+								Name:                "test-and-policy-1",
+								Type:                NumericAttribute,
+								NumericAttributeCfg: NumericAttributeCfg{Key: "key1", MinValue: 50, MaxValue: 100},
+							},
+							{
+								Name:               "test-and-policy-2",
+								Type:               StringAttribute,
+								StringAttributeCfg: StringAttributeCfg{Key: "key2", Values: []string{"value1", "value2"}},
+							},
+						},
+					},
+				},
+				{
+					Name: "test-policy-11",
+					Type: Composite,
+					CompositeCfg: CompositeCfg{
+						MaxTotalSpansPerSecond: 1000,
+						SubPolicyCfgs: []PolicyCfg{
+							{
+								Name:          "errors",
+								Type:          StatusCode,
+								StatusCodeCfg: StatusCodeCfg{StatusCodes: []string{"ERROR"}},
+							},
+							{
+								Name:       "slow",
+								Type:       Latency,
+								LatencyCfg: LatencyCfg{ThresholdMs: 2000},
+							},
+							{
+								Name: "everything-else",
+								Type: AlwaysSample,
+							},
+						},
+						RateAllocation: []RateAllocationCfg{
+							{Policy: "errors", Percent: 50},
+							{Policy: "slow", Percent: 30},
+							{Policy: "everything-else", Percent: 20},
+						},
+					},
+				},
 			},
 		})
 }
@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"container/list"
+	"sync"
+)
+
+// decisionCache is a fixed size LRU cache of the final sampling decision of
+// traces, it is safe for concurrent use. It allows handling spans that arrive
+// after their trace was removed from memory according to the original decision.
+type decisionCache struct {
+	mu    sync.Mutex
+	size  int
+	lru   *list.List
+	items map[traceKey]*list.Element
+}
+
+type decisionCacheEntry struct {
+	key     traceKey
+	sampled bool
+}
+
+// newDecisionCache returns a cache for up to size decisions, or nil if size is
+// not positive. All the methods of a nil cache are no-ops.
+func newDecisionCache(size int) *decisionCache {
+	if size <= 0 {
+		return nil
+	}
+	return &decisionCache{
+		size:  size,
+		lru:   list.New(),
+		items: make(map[traceKey]*list.Element, size),
+	}
+}
+
+// Put records the decision of the given trace, evicting the least recently used
+// one if the cache is full.
+func (c *decisionCache) Put(key traceKey, sampled bool) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*decisionCacheEntry).sampled = sampled
+		c.lru.MoveToFront(elem)
+		return
+	}
+	if c.lru.Len() >= c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.items, oldest.Value.(*decisionCacheEntry).key)
+	}
+	c.items[key] = c.lru.PushFront(&decisionCacheEntry{key: key, sampled: sampled})
+}
+
+// Get returns the decision of the given trace and whether it was in the cache.
+func (c *decisionCache) Get(key traceKey) (sampled bool, ok bool) {
+	if c == nil {
+		return false, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return false, false
+	}
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*decisionCacheEntry).sampled, true
+}
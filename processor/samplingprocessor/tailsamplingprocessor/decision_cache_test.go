@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecisionCache(t *testing.T) {
+	c := newDecisionCache(2)
+
+	c.Put("a", true)
+	c.Put("b", false)
+
+	sampled, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.True(t, sampled)
+
+	// "b" is now the least recently used.
+	c.Put("c", true)
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+
+	sampled, ok = c.Get("c")
+	assert.True(t, ok)
+	assert.True(t, sampled)
+
+	// Updating an entry does not evict any other.
+	c.Put("a", false)
+	sampled, ok = c.Get("a")
+	assert.True(t, ok)
+	assert.False(t, sampled)
+	_, ok = c.Get("c")
+	assert.True(t, ok)
+}
+
+func TestDecisionCache_Disabled(t *testing.T) {
+	c := newDecisionCache(0)
+	assert.Nil(t, c)
+
+	c.Put("a", true)
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+}
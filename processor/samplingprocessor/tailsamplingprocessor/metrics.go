@@ -42,6 +42,10 @@ var (
 	statDroppedTooEarlyCount    = stats.Int64("sampling_trace_dropped_too_early", "Count of traces that needed to be dropped the configured wait time", stats.UnitDimensionless)
 	statNewTraceIDReceivedCount = stats.Int64("new_trace_id_received", "Counts the arrival of new traces", stats.UnitDimensionless)
 	statTracesOnMemoryGauge     = stats.Int64("sampling_traces_on_memory", "Tracks the number of traces current on memory", stats.UnitDimensionless)
+	statBytesOnMemoryGauge      = stats.Int64("sampling_bytes_on_memory", "Tracks the estimated size of the spans waiting for a sampling decision", stats.UnitBytes)
+
+	statTracesCompletedEarly    = stats.Int64("sampling_trace_completed_early", "Count of complete traces evaluated before the configured wait time", stats.UnitDimensionless)
+	statLateSpansCachedDecision = stats.Int64("sampling_late_span_cached_decision", "Count of late spans of traces no longer on memory handled with the cached sampling decision", stats.UnitDimensionless)
 )
 
 // SamplingProcessorMetricViews return the metrics views according to given telemetry level.
@@ -116,6 +120,25 @@ func SamplingProcessorMetricViews(level telemetry.Level) []*view.View {
 		Description: statTracesOnMemoryGauge.Description(),
 		Aggregation: view.LastValue(),
 	}
+	trackBytesOnMemoryView := &view.View{
+		Name:        statBytesOnMemoryGauge.Name(),
+		Measure:     statBytesOnMemoryGauge,
+		Description: statBytesOnMemoryGauge.Description(),
+		Aggregation: view.LastValue(),
+	}
+	countTracesCompletedEarlyView := &view.View{
+		Name:        statTracesCompletedEarly.Name(),
+		Measure:     statTracesCompletedEarly,
+		Description: statTracesCompletedEarly.Description(),
+		Aggregation: view.Sum(),
+	}
+	countLateSpansCachedDecisionView := &view.View{
+		Name:        statLateSpansCachedDecision.Name(),
+		Measure:     statLateSpansCachedDecision,
+		Description: statLateSpansCachedDecision.Description(),
+		TagKeys:     []tag.Key{tagSampledKey},
+		Aggregation: view.Sum(),
+	}
 
 	legacyViews := []*view.View{
 		decisionLatencyView,
@@ -131,6 +154,9 @@ func SamplingProcessorMetricViews(level telemetry.Level) []*view.View {
 		countTraceDroppedTooEarlyView,
 		countTraceIDArrivalView,
 		trackTracesOnMemorylView,
+		trackBytesOnMemoryView,
+		countTracesCompletedEarlyView,
+		countLateSpansCachedDecisionView,
 	}
 
 	return obsreport.ProcessorMetricViews(typeStr, legacyViews)
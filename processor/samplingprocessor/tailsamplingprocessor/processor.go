@@ -18,11 +18,11 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 	"go.opencensus.io/stats"
 	"go.opencensus.io/tag"
 	"go.uber.org/zap"
@@ -30,7 +30,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/consumer"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/processor/samplingprocessor/tailsamplingprocessor/idbatcher"
 	"go.opentelemetry.io/collector/processor/samplingprocessor/tailsamplingprocessor/sampling"
 )
@@ -54,16 +54,21 @@ type traceKey string
 // policy to sample traces.
 type tailSamplingSpanProcessor struct {
 	ctx             context.Context
-	nextConsumer    consumer.TraceConsumerOld
+	nextConsumer    consumer.TraceConsumer
 	start           sync.Once
 	maxNumTraces    uint64
+	maxBytes        int64
+	quietPeriod     time.Duration
 	policies        []*Policy
 	logger          *zap.Logger
 	idToTrace       sync.Map
 	policyTicker    tTicker
 	decisionBatcher idbatcher.Batcher
+	decisionCache   *decisionCache
 	deleteChan      chan traceKey
+	completedTraces sync.Map
 	numTracesOnMap  uint64
+	bytesOnMap      int64
 }
 
 const (
@@ -72,7 +77,7 @@ const (
 
 // newTraceProcessor returns a processor.TraceProcessor that will perform tail sampling according to the given
 // configuration.
-func newTraceProcessor(logger *zap.Logger, nextConsumer consumer.TraceConsumerOld, cfg Config) (component.TraceProcessorOld, error) {
+func newTraceProcessor(logger *zap.Logger, nextConsumer consumer.TraceConsumer, cfg Config) (component.TraceProcessor, error) {
 	if nextConsumer == nil {
 		return nil, componenterror.ErrNilNextConsumer
 	}
@@ -107,8 +112,11 @@ func newTraceProcessor(logger *zap.Logger, nextConsumer consumer.TraceConsumerOl
 		ctx:             ctx,
 		nextConsumer:    nextConsumer,
 		maxNumTraces:    cfg.NumTraces,
+		maxBytes:        int64(cfg.MaxBufferedBytes),
+		quietPeriod:     cfg.CompletionQuietPeriod,
 		logger:          logger,
 		decisionBatcher: inBatcher,
+		decisionCache:   newDecisionCache(cfg.DecisionCacheSize),
 		policies:        policies,
 	}
 
@@ -127,113 +135,288 @@ func getPolicyEvaluator(logger *zap.Logger, cfg *PolicyCfg) (sampling.PolicyEval
 		return sampling.NewNumericAttributeFilter(logger, nafCfg.Key, nafCfg.MinValue, nafCfg.MaxValue), nil
 	case StringAttribute:
 		safCfg := cfg.StringAttributeCfg
+		if safCfg.EnabledRegexMatching {
+			return sampling.NewStringAttributeRegexFilter(logger, safCfg.Key, safCfg.Values)
+		}
 		return sampling.NewStringAttributeFilter(logger, safCfg.Key, safCfg.Values), nil
 	case RateLimiting:
 		rlfCfg := cfg.RateLimitingCfg
 		return sampling.NewRateLimiting(logger, rlfCfg.SpansPerSecond), nil
+	case Latency:
+		lfCfg := cfg.LatencyCfg
+		return sampling.NewLatency(logger, time.Duration(lfCfg.ThresholdMs)*time.Millisecond), nil
+	case StatusCode:
+		scfCfg := cfg.StatusCodeCfg
+		return sampling.NewStatusCodeFilter(logger, scfCfg.StatusCodes)
+	case SpanCount:
+		scCfg := cfg.SpanCountCfg
+		return sampling.NewSpanCount(logger, scCfg.MinSpans), nil
+	case Probabilistic:
+		pCfg := cfg.ProbabilisticCfg
+		return sampling.NewProbabilistic(logger, pCfg.HashSeed, pCfg.SamplingPercentage)
+	case And:
+		return getAndEvaluator(logger, cfg)
+	case Composite:
+		return getCompositeEvaluator(logger, cfg)
 	default:
 		return nil, fmt.Errorf("unknown sampling policy type %s", cfg.Type)
 	}
 }
 
+func getSubPolicyEvaluators(logger *zap.Logger, cfg *PolicyCfg, subPolicyCfgs []PolicyCfg) ([]sampling.PolicyEvaluator, error) {
+	if len(subPolicyCfgs) == 0 {
+		return nil, fmt.Errorf("policy %q of type %s has no sub-policies", cfg.Name, cfg.Type)
+	}
+	evaluators := make([]sampling.PolicyEvaluator, 0, len(subPolicyCfgs))
+	for i := range subPolicyCfgs {
+		eval, err := getPolicyEvaluator(logger, &subPolicyCfgs[i])
+		if err != nil {
+			return nil, fmt.Errorf("sub-policy %q of policy %q: %w", subPolicyCfgs[i].Name, cfg.Name, err)
+		}
+		evaluators = append(evaluators, eval)
+	}
+	return evaluators, nil
+}
+
+func getAndEvaluator(logger *zap.Logger, cfg *PolicyCfg) (sampling.PolicyEvaluator, error) {
+	evaluators, err := getSubPolicyEvaluators(logger, cfg, cfg.AndCfg.SubPolicyCfgs)
+	if err != nil {
+		return nil, err
+	}
+	return sampling.NewAnd(logger, evaluators), nil
+}
+
+func getCompositeEvaluator(logger *zap.Logger, cfg *PolicyCfg) (sampling.PolicyEvaluator, error) {
+	cCfg := cfg.CompositeCfg
+	if cCfg.MaxTotalSpansPerSecond <= 0 {
+		return nil, fmt.Errorf("policy %q: max_total_spans_per_second must be positive", cfg.Name)
+	}
+	evaluators, err := getSubPolicyEvaluators(logger, cfg, cCfg.SubPolicyCfgs)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := getCompositeRates(cfg)
+	if err != nil {
+		return nil, err
+	}
+	params := make([]sampling.SubPolicyEvalParams, 0, len(evaluators))
+	for i, eval := range evaluators {
+		params = append(params, sampling.SubPolicyEvalParams{
+			Evaluator:         eval,
+			MaxSpansPerSecond: rates[i],
+		})
+	}
+	return sampling.NewComposite(logger, params), nil
+}
+
+// getCompositeRates returns the spans per second allocated to each of the
+// sub-policies of a composite policy.
+func getCompositeRates(cfg *PolicyCfg) ([]int64, error) {
+	cCfg := cfg.CompositeCfg
+	total := cCfg.MaxTotalSpansPerSecond
+	numSubPolicies := int64(len(cCfg.SubPolicyCfgs))
+	rates := make([]int64, numSubPolicies)
+
+	if len(cCfg.RateAllocation) == 0 {
+		// Equal shares, computed from the cumulative amounts so that they add
+		// up to the total.
+		for i := int64(0); i < numSubPolicies; i++ {
+			rates[i] = total*(i+1)/numSubPolicies - total*i/numSubPolicies
+		}
+		return rates, nil
+	}
+
+	indexes := make(map[string]int, numSubPolicies)
+	for i, subCfg := range cCfg.SubPolicyCfgs {
+		if _, ok := indexes[subCfg.Name]; ok {
+			return nil, fmt.Errorf("policy %q: duplicated sub-policy name %q", cfg.Name, subCfg.Name)
+		}
+		indexes[subCfg.Name] = i
+	}
+	var totalPercent, allocated int64
+	hasAllocation := make([]bool, numSubPolicies)
+	for _, allocation := range cCfg.RateAllocation {
+		i, ok := indexes[allocation.Policy]
+		if !ok {
+			return nil, fmt.Errorf("policy %q: rate_allocation for unknown sub-policy %q", cfg.Name, allocation.Policy)
+		}
+		if allocation.Percent < 0 {
+			return nil, fmt.Errorf("policy %q: rate_allocation percent of sub-policy %q must not be negative", cfg.Name, allocation.Policy)
+		}
+		totalPercent += allocation.Percent
+		rates[i] += total * allocation.Percent / 100
+		allocated += total * allocation.Percent / 100
+		hasAllocation[i] = true
+	}
+	if totalPercent > 100 {
+		return nil, fmt.Errorf("policy %q: rate_allocation percents add up to %d, more than 100", cfg.Name, totalPercent)
+	}
+
+	// The sub-policies without an allocation get equal shares of the rest.
+	var unallocated []int
+	for i, ok := range hasAllocation {
+		if !ok {
+			unallocated = append(unallocated, i)
+		}
+	}
+	rest, n := total-allocated, int64(len(unallocated))
+	for j, i := range unallocated {
+		k := int64(j)
+		rates[i] = rest*(k+1)/n - rest*k/n
+	}
+	return rates, nil
+}
+
 func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
-	var idNotFoundOnMapCount, evaluateErrorCount, decisionSampled, decisionNotSampled int64
+	var metrics policyMetrics
 	startTime := time.Now()
+	tsp.evaluateCompletedTraces(startTime, &metrics)
 	batch, _ := tsp.decisionBatcher.CloseCurrentAndTakeFirstBatch()
 	batchLen := len(batch)
 	tsp.logger.Debug("Sampling Policy Evaluation ticked")
 	for _, id := range batch {
 		d, ok := tsp.idToTrace.Load(traceKey(id))
 		if !ok {
-			idNotFoundOnMapCount++
+			metrics.idNotFoundOnMap++
 			continue
 		}
 		trace := d.(*sampling.TraceData)
-		trace.DecisionTime = time.Now()
-		for i, policy := range tsp.policies {
-			policyEvaluateStartTime := time.Now()
-			decision, err := policy.Evaluator.Evaluate(id, trace)
-			stats.Record(
-				policy.ctx,
-				statDecisionLatencyMicroSec.M(int64(time.Since(policyEvaluateStartTime)/time.Microsecond)))
-			if err != nil {
-				trace.Decisions[i] = sampling.NotSampled
-				evaluateErrorCount++
-				tsp.logger.Error("Sampling policy error", zap.Error(err))
-				continue
-			}
-
-			trace.Decisions[i] = decision
-
-			switch decision {
-			case sampling.Sampled:
-				stats.RecordWithTags(
-					policy.ctx,
-					[]tag.Mutator{tag.Insert(tagSampledKey, "true")},
-					statCountTracesSampled.M(int64(1)),
-				)
-				decisionSampled++
-
-				trace.Lock()
-				traceBatches := trace.ReceivedBatches
-				trace.Unlock()
-
-				for j := 0; j < len(traceBatches); j++ {
-					tsp.nextConsumer.ConsumeTraceData(policy.ctx, traceBatches[j])
-				}
-			case sampling.NotSampled:
-				stats.RecordWithTags(
-					policy.ctx,
-					[]tag.Mutator{tag.Insert(tagSampledKey, "false")},
-					statCountTracesSampled.M(int64(1)),
-				)
-				decisionNotSampled++
-			}
+		if !trace.DecisionTime.IsZero() {
+			// Already evaluated as a complete trace.
+			continue
 		}
-
-		// Sampled or not, remove the batches
-		trace.Lock()
-		trace.ReceivedBatches = nil
-		trace.Unlock()
+		tsp.completedTraces.Delete(traceKey(id))
+		tsp.makeDecision(id, trace, &metrics)
 	}
 
 	stats.Record(tsp.ctx,
 		statOverallDecisionLatencyµs.M(int64(time.Since(startTime)/time.Microsecond)),
-		statDroppedTooEarlyCount.M(idNotFoundOnMapCount),
-		statPolicyEvaluationErrorCount.M(evaluateErrorCount),
-		statTracesOnMemoryGauge.M(int64(atomic.LoadUint64(&tsp.numTracesOnMap))))
+		statDroppedTooEarlyCount.M(metrics.idNotFoundOnMap),
+		statPolicyEvaluationErrorCount.M(metrics.evaluateErrorCount),
+		statTracesCompletedEarly.M(metrics.completedEarly),
+		statTracesOnMemoryGauge.M(int64(atomic.LoadUint64(&tsp.numTracesOnMap))),
+		statBytesOnMemoryGauge.M(atomic.LoadInt64(&tsp.bytesOnMap)))
 
 	tsp.logger.Debug("Sampling policy evaluation completed",
 		zap.Int("batch.len", batchLen),
-		zap.Int64("sampled", decisionSampled),
-		zap.Int64("notSampled", decisionNotSampled),
-		zap.Int64("droppedPriorToEvaluation", idNotFoundOnMapCount),
-		zap.Int64("policyEvaluationErrors", evaluateErrorCount),
+		zap.Int64("sampled", metrics.decisionSampled),
+		zap.Int64("notSampled", metrics.decisionNotSampled),
+		zap.Int64("completedEarly", metrics.completedEarly),
+		zap.Int64("droppedPriorToEvaluation", metrics.idNotFoundOnMap),
+		zap.Int64("policyEvaluationErrors", metrics.evaluateErrorCount),
 	)
 }
 
-// ConsumeTraceData is required by the SpanProcessor interface.
-func (tsp *tailSamplingSpanProcessor) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+// policyMetrics accumulates the results of the evaluations of a tick.
+type policyMetrics struct {
+	idNotFoundOnMap, evaluateErrorCount, decisionSampled, decisionNotSampled, completedEarly int64
+}
+
+// evaluateCompletedTraces makes the sampling decision of the traces whose root span was
+// received and that didn't receive new spans during the completion quiet period.
+func (tsp *tailSamplingSpanProcessor) evaluateCompletedTraces(now time.Time, metrics *policyMetrics) {
+	if tsp.quietPeriod <= 0 {
+		return
+	}
+	tsp.completedTraces.Range(func(key, _ interface{}) bool {
+		id := key.(traceKey)
+		d, ok := tsp.idToTrace.Load(id)
+		if !ok {
+			tsp.completedTraces.Delete(id)
+			return true
+		}
+		trace := d.(*sampling.TraceData)
+		trace.Lock()
+		quiet := now.Sub(trace.LastSpanArrivalTime) >= tsp.quietPeriod
+		trace.Unlock()
+		if quiet {
+			tsp.completedTraces.Delete(id)
+			metrics.completedEarly++
+			tsp.makeDecision([]byte(id), trace, metrics)
+		}
+		return true
+	})
+}
+
+// makeDecision evaluates the policies for the given trace and forwards it, only once,
+// if any of the policies samples it.
+func (tsp *tailSamplingSpanProcessor) makeDecision(id []byte, trace *sampling.TraceData, metrics *policyMetrics) {
+	trace.DecisionTime = time.Now()
+	// The trace is sampled if any of the policies samples it, but it is only
+	// forwarded once.
+	traceSampled := false
+	for i, policy := range tsp.policies {
+		policyEvaluateStartTime := time.Now()
+		decision, err := policy.Evaluator.Evaluate(id, trace)
+		stats.Record(
+			policy.ctx,
+			statDecisionLatencyMicroSec.M(int64(time.Since(policyEvaluateStartTime)/time.Microsecond)))
+		if err != nil {
+			trace.Decisions[i] = sampling.NotSampled
+			metrics.evaluateErrorCount++
+			tsp.logger.Error("Sampling policy error", zap.Error(err))
+			continue
+		}
+
+		trace.Decisions[i] = decision
+
+		switch decision {
+		case sampling.Sampled:
+			stats.RecordWithTags(
+				policy.ctx,
+				[]tag.Mutator{tag.Insert(tagSampledKey, "true")},
+				statCountTracesSampled.M(int64(1)),
+			)
+			metrics.decisionSampled++
+			traceSampled = true
+		case sampling.NotSampled:
+			stats.RecordWithTags(
+				policy.ctx,
+				[]tag.Mutator{tag.Insert(tagSampledKey, "false")},
+				statCountTracesSampled.M(int64(1)),
+			)
+			metrics.decisionNotSampled++
+		}
+	}
+
+	// Sampled or not, remove the batches
+	trace.Lock()
+	traceBatches := trace.ReceivedBatches
+	trace.ReceivedBatches = nil
+	atomic.AddInt64(&tsp.bytesOnMap, -trace.ReceivedBytes)
+	trace.ReceivedBytes = 0
+	trace.Unlock()
+
+	// Keep the decision for the spans arriving after the trace is removed from memory.
+	tsp.decisionCache.Put(traceKey(id), traceSampled)
+
+	if traceSampled {
+		for j := 0; j < len(traceBatches); j++ {
+			if err := tsp.nextConsumer.ConsumeTraces(tsp.ctx, traceBatches[j]); err != nil {
+				tsp.logger.Warn("Error sending sampled trace to destination", zap.Error(err))
+			}
+		}
+	}
+}
+
+// ConsumeTraces is required by the component.TraceProcessor interface.
+func (tsp *tailSamplingSpanProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
 	tsp.start.Do(func() {
 		tsp.logger.Info("First trace data arrived, starting tail_sampling timers")
 		tsp.policyTicker.Start(1 * time.Second)
 	})
 
-	// Groupd spans per their traceId to minimize contention on idToTrace
-	idToSpans := make(map[traceKey][]*tracepb.Span)
-	for _, span := range td.Spans {
-		if len(span.TraceId) != 16 {
-			tsp.logger.Warn("Span without valid TraceId", zap.String("SourceFormat", td.SourceFormat))
+	// Group spans per their traceId to minimize contention on idToTrace
+	idToBatch := tsp.groupSpansByTraceKey(td)
+
+	var newTraceIDs int64
+	for id, batch := range idToBatch {
+		if tsp.handleCachedDecision(id, batch) {
 			continue
 		}
-		tk := traceKey(span.TraceId)
-		idToSpans[tk] = append(idToSpans[tk], span)
-	}
 
-	var newTraceIDs int64
-	singleTrace := len(idToSpans) == 1
-	for id, spans := range idToSpans {
-		lenSpans := int64(len(spans))
+		lenSpans := int64(len(batch.spans))
 		lenPolicies := len(tsp.policies)
 		initialDecisions := make([]sampling.Decision, lenPolicies)
 		for i := 0; i < lenPolicies; i++ {
@@ -266,6 +449,7 @@ func (tsp *tailSamplingSpanProcessor) ConsumeTraceData(ctx context.Context, td c
 			}
 		}
 
+		forwarded := false
 		for i, policy := range tsp.policies {
 			actualData.Lock()
 			actualDecision := actualData.Decisions[i]
@@ -274,8 +458,19 @@ func (tsp *tailSamplingSpanProcessor) ConsumeTraceData(ctx context.Context, td c
 			if actualDecision == sampling.Pending {
 				// Add the spans to the trace, but only once for all policy, otherwise same spans will
 				// be duplicated in the final trace.
-				traceTd := prepareTraceBatch(spans, singleTrace, td)
-				actualData.ReceivedBatches = append(actualData.ReceivedBatches, traceTd)
+				actualData.ReceivedBatches = append(actualData.ReceivedBatches, batch.traces)
+				if tsp.maxBytes > 0 {
+					size := tracesSize(batch.traces)
+					actualData.ReceivedBytes += size
+					atomic.AddInt64(&tsp.bytesOnMap, size)
+				}
+				if tsp.quietPeriod > 0 {
+					actualData.LastSpanArrivalTime = time.Now()
+					if !actualData.RootSpanEnded && hasEndedRootSpan(batch.spans) {
+						actualData.RootSpanEnded = true
+						tsp.completedTraces.Store(id, struct{}{})
+					}
+				}
 				actualData.Unlock()
 				break
 			}
@@ -285,16 +480,18 @@ func (tsp *tailSamplingSpanProcessor) ConsumeTraceData(ctx context.Context, td c
 			case sampling.Pending:
 				// All process for pending done above, keep the case so it doesn't go to default.
 			case sampling.Sampled:
-				// Forward the spans to the policy destinations
-				traceTd := prepareTraceBatch(spans, singleTrace, td)
-				if err := tsp.nextConsumer.ConsumeTraceData(policy.ctx, traceTd); err != nil {
-					tsp.logger.Warn("Error sending late arrived spans to destination",
-						zap.String("policy", policy.Name),
-						zap.Error(err))
+				// Forward the spans to the destination, only once even if sampled by multiple policies.
+				if !forwarded {
+					forwarded = true
+					if err := tsp.nextConsumer.ConsumeTraces(tsp.ctx, batch.traces); err != nil {
+						tsp.logger.Warn("Error sending late arrived spans to destination",
+							zap.String("policy", policy.Name),
+							zap.Error(err))
+					}
 				}
 				fallthrough // so OnLateArrivingSpans is also called for decision Sampled.
 			case sampling.NotSampled:
-				policy.Evaluator.OnLateArrivingSpans(actualDecision, spans)
+				policy.Evaluator.OnLateArrivingSpans(actualDecision, batch.spans)
 				stats.Record(tsp.ctx, statLateSpanArrivalAfterDecision.M(int64(time.Since(actualData.DecisionTime)/time.Second)))
 
 			default:
@@ -305,10 +502,54 @@ func (tsp *tailSamplingSpanProcessor) ConsumeTraceData(ctx context.Context, td c
 		}
 	}
 
+	tsp.enforceMemoryLimit()
+
 	stats.Record(tsp.ctx, statNewTraceIDReceivedCount.M(newTraceIDs))
 	return nil
 }
 
+// handleCachedDecision forwards or drops, according to the cached sampling
+// decision, the spans of a trace that is no longer on memory. It returns false
+// if the trace is still on memory or its decision is not cached.
+func (tsp *tailSamplingSpanProcessor) handleCachedDecision(id traceKey, batch *traceBatch) bool {
+	if _, ok := tsp.idToTrace.Load(id); ok {
+		return false
+	}
+	sampled, ok := tsp.decisionCache.Get(id)
+	if !ok {
+		return false
+	}
+
+	stats.RecordWithTags(
+		tsp.ctx,
+		[]tag.Mutator{tag.Insert(tagSampledKey, strconv.FormatBool(sampled))},
+		statLateSpansCachedDecision.M(int64(len(batch.spans))),
+	)
+	if sampled {
+		if err := tsp.nextConsumer.ConsumeTraces(tsp.ctx, batch.traces); err != nil {
+			tsp.logger.Warn("Error sending late arrived spans to destination", zap.Error(err))
+		}
+	}
+	return true
+}
+
+// enforceMemoryLimit drops the oldest traces on memory while the estimated size of
+// the spans waiting for a sampling decision is over the configured limit.
+func (tsp *tailSamplingSpanProcessor) enforceMemoryLimit() {
+	if tsp.maxBytes <= 0 {
+		return
+	}
+	currTime := time.Now()
+	for atomic.LoadInt64(&tsp.bytesOnMap) > tsp.maxBytes {
+		select {
+		case traceKeyToDrop := <-tsp.deleteChan:
+			tsp.dropTrace(traceKeyToDrop, currTime)
+		default:
+			return
+		}
+	}
+}
+
 func (tsp *tailSamplingSpanProcessor) GetCapabilities() component.ProcessorCapabilities {
 	return component.ProcessorCapabilities{MutatesConsumedData: false}
 }
@@ -335,6 +576,7 @@ func (tsp *tailSamplingSpanProcessor) dropTrace(traceID traceKey, deletionTime t
 		tsp.logger.Error("Attempt to delete traceID not on table")
 		return
 	}
+	tsp.completedTraces.Delete(traceID)
 	policiesLen := len(tsp.policies)
 	stats.Record(tsp.ctx, statTraceRemovalAgeSec.M(int64(deletionTime.Sub(trace.ArrivalTime)/time.Second)))
 	for j := 0; j < policiesLen; j++ {
@@ -348,21 +590,103 @@ func (tsp *tailSamplingSpanProcessor) dropTrace(traceID traceKey, deletionTime t
 			}
 		}
 	}
+
+	trace.Lock()
+	trace.ReceivedBatches = nil
+	atomic.AddInt64(&tsp.bytesOnMap, -trace.ReceivedBytes)
+	trace.ReceivedBytes = 0
+	trace.Unlock()
+}
+
+// traceBatch holds the spans of a single trace received in a batch.
+type traceBatch struct {
+	traces pdata.Traces
+	spans  []pdata.Span
 }
 
-func prepareTraceBatch(spans []*tracepb.Span, singleTrace bool, td consumerdata.TraceData) consumerdata.TraceData {
-	var traceTd consumerdata.TraceData
-	if singleTrace {
-		// Special case no need to prepare a batch
-		traceTd = td
-	} else {
-		traceTd = consumerdata.TraceData{
-			Node:     td.Node,
-			Resource: td.Resource,
-			Spans:    spans,
+// groupSpansByTraceKey splits the given batch in one batch per trace, keeping the
+// resource and instrumentation library of the spans.
+func (tsp *tailSamplingSpanProcessor) groupSpansByTraceKey(td pdata.Traces) map[traceKey]*traceBatch {
+	idToBatch := make(map[traceKey]*traceBatch)
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
 		}
+		// The destination resource spans of each trace for the current resource.
+		idToRs := make(map[traceKey]pdata.ResourceSpans)
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
+			}
+			// The destination spans of each trace for the current instrumentation library.
+			idToSpans := make(map[traceKey]pdata.SpanSlice)
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
+				}
+				if len(span.TraceID().Bytes()) != 16 {
+					tsp.logger.Warn("Span without valid TraceId")
+					continue
+				}
+				tk := traceKey(span.TraceID().Bytes())
+
+				batch, ok := idToBatch[tk]
+				if !ok {
+					batch = &traceBatch{traces: pdata.NewTraces()}
+					idToBatch[tk] = batch
+				}
+				destSpans, ok := idToSpans[tk]
+				if !ok {
+					destRs, ok := idToRs[tk]
+					if !ok {
+						destRs = pdata.NewResourceSpans()
+						destRs.InitEmpty()
+						rs.Resource().CopyTo(destRs.Resource())
+						batch.traces.ResourceSpans().Append(&destRs)
+						idToRs[tk] = destRs
+					}
+					destIls := pdata.NewInstrumentationLibrarySpans()
+					destIls.InitEmpty()
+					ils.InstrumentationLibrary().CopyTo(destIls.InstrumentationLibrary())
+					destRs.InstrumentationLibrarySpans().Append(&destIls)
+					destSpans = destIls.Spans()
+					idToSpans[tk] = destSpans
+				}
+
+				destSpan := pdata.NewSpan()
+				span.CopyTo(destSpan)
+				destSpans.Append(&destSpan)
+				batch.spans = append(batch.spans, destSpan)
+			}
+		}
+	}
+	return idToBatch
+}
+
+// hasEndedRootSpan returns true if any of the given spans is a root span, i.e. without
+// parent, that has ended.
+func hasEndedRootSpan(spans []pdata.Span) bool {
+	for _, span := range spans {
+		if len(span.ParentSpanID().Bytes()) == 0 && span.EndTime() != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// tracesSize returns the estimated size, in bytes, of the given traces.
+func tracesSize(td pdata.Traces) int64 {
+	var size int
+	for _, rs := range pdata.TracesToOtlp(td) {
+		size += rs.Size()
 	}
-	return traceTd
+	return int64(size)
 }
 
 // tTicker interface allows easier testing of ticker related functionality used by tailSamplingProcessor
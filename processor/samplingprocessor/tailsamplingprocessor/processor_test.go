@@ -17,15 +17,15 @@ package tailsamplingprocessor
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
-	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/processor/samplingprocessor/tailsamplingprocessor/idbatcher"
 	"go.opentelemetry.io/collector/processor/samplingprocessor/tailsamplingprocessor/sampling"
@@ -46,10 +46,10 @@ func TestSequentialTraceArrival(t *testing.T) {
 		ExpectedNewTracesPerSec: 64,
 		PolicyCfgs:              testPolicy,
 	}
-	sp, _ := newTraceProcessor(zap.NewNop(), &exportertest.SinkTraceExporterOld{}, cfg)
+	sp, _ := newTraceProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, cfg)
 	tsp := sp.(*tailSamplingSpanProcessor)
 	for _, batch := range batches {
-		tsp.ConsumeTraceData(context.Background(), batch)
+		tsp.ConsumeTraces(context.Background(), batch)
 	}
 
 	for i := range traceIds {
@@ -70,19 +70,17 @@ func TestConcurrentTraceArrival(t *testing.T) {
 		ExpectedNewTracesPerSec: 64,
 		PolicyCfgs:              testPolicy,
 	}
-	sp, _ := newTraceProcessor(zap.NewNop(), &exportertest.SinkTraceExporterOld{}, cfg)
+	sp, _ := newTraceProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, cfg)
 	tsp := sp.(*tailSamplingSpanProcessor)
 	for _, batch := range batches {
 		// Add the same traceId twice.
 		wg.Add(2)
-		go func(td consumerdata.TraceData) {
-			td.SourceFormat = "test-0"
-			tsp.ConsumeTraceData(context.Background(), td)
+		go func(td pdata.Traces) {
+			tsp.ConsumeTraces(context.Background(), td)
 			wg.Done()
 		}(batch)
-		go func(td consumerdata.TraceData) {
-			td.SourceFormat = "test-1"
-			tsp.ConsumeTraceData(context.Background(), td)
+		go func(td pdata.Traces) {
+			tsp.ConsumeTraces(context.Background(), td)
 			wg.Done()
 		}(batch)
 	}
@@ -106,10 +104,10 @@ func TestSequentialTraceMapSize(t *testing.T) {
 		ExpectedNewTracesPerSec: 64,
 		PolicyCfgs:              testPolicy,
 	}
-	sp, _ := newTraceProcessor(zap.NewNop(), &exportertest.SinkTraceExporterOld{}, cfg)
+	sp, _ := newTraceProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, cfg)
 	tsp := sp.(*tailSamplingSpanProcessor)
 	for _, batch := range batches {
-		tsp.ConsumeTraceData(context.Background(), batch)
+		tsp.ConsumeTraces(context.Background(), batch)
 	}
 
 	// On sequential insertion it is possible to know exactly which traces should be still on the map.
@@ -129,12 +127,12 @@ func TestConcurrentTraceMapSize(t *testing.T) {
 		ExpectedNewTracesPerSec: 64,
 		PolicyCfgs:              testPolicy,
 	}
-	sp, _ := newTraceProcessor(zap.NewNop(), &exportertest.SinkTraceExporterOld{}, cfg)
+	sp, _ := newTraceProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, cfg)
 	tsp := sp.(*tailSamplingSpanProcessor)
 	for _, batch := range batches {
 		wg.Add(1)
-		go func(td consumerdata.TraceData) {
-			tsp.ConsumeTraceData(context.Background(), td)
+		go func(td pdata.Traces) {
+			tsp.ConsumeTraces(context.Background(), td)
 			wg.Done()
 		}(batch)
 	}
@@ -176,7 +174,7 @@ func TestSamplingPolicyTypicalPath(t *testing.T) {
 	// First evaluations shouldn't have anything to evaluate, until decision wait time passed.
 	for evalNum := 0; evalNum < decisionWaitSeconds; evalNum++ {
 		for ; currItem < numSpansPerBatchWindow*(evalNum+1); currItem++ {
-			tsp.ConsumeTraceData(context.Background(), batches[currItem])
+			tsp.ConsumeTraces(context.Background(), batches[currItem])
 			require.True(t, mtt.Started, "Time ticker was expected to have started")
 		}
 		tsp.samplingPolicyOnTick()
@@ -201,34 +199,453 @@ func TestSamplingPolicyTypicalPath(t *testing.T) {
 	require.Equal(t, numSpansPerBatchWindow, msp.TotalSpans, "not all spans of first window were accounted for")
 
 	// Late span of a sampled trace should be sent directly down the pipeline exporter
-	tsp.ConsumeTraceData(context.Background(), batches[0])
+	tsp.ConsumeTraces(context.Background(), batches[0])
 	expectedNumWithLateSpan := numSpansPerBatchWindow + 1
 	require.Equal(t, expectedNumWithLateSpan, msp.TotalSpans, "late span was not accounted for")
 	require.Equal(t, 1, mpe.LateArrivingSpansCount, "policy was not notified of the late span")
 }
 
-func generateIdsAndBatches(numIds int) ([][]byte, []consumerdata.TraceData) {
+func TestSamplingMultiplePolicies(t *testing.T) {
+	const maxSize = 100
+	const decisionWaitSeconds = 5
+	msp := &mockSpanProcessor{}
+	mpe1 := &mockPolicyEvaluator{}
+	mpe2 := &mockPolicyEvaluator{}
+	mtt := &manualTTicker{}
+	tsp := &tailSamplingSpanProcessor{
+		ctx:             context.Background(),
+		nextConsumer:    msp,
+		maxNumTraces:    maxSize,
+		logger:          zap.NewNop(),
+		decisionBatcher: newSyncIDBatcher(decisionWaitSeconds),
+		policies: []*Policy{
+			{Name: "policy-1", Evaluator: mpe1, ctx: context.TODO()},
+			{Name: "policy-2", Evaluator: mpe2, ctx: context.TODO()},
+		},
+		deleteChan:   make(chan traceKey, maxSize),
+		policyTicker: mtt,
+	}
+
+	_, batches := generateIdsAndBatches(210)
+	currItem := 0
+	numSpansPerBatchWindow := 10
+	for evalNum := 0; evalNum < decisionWaitSeconds; evalNum++ {
+		for ; currItem < numSpansPerBatchWindow*(evalNum+1); currItem++ {
+			tsp.ConsumeTraces(context.Background(), batches[currItem])
+		}
+		tsp.samplingPolicyOnTick()
+	}
+
+	// Both policies sample the traces, the spans must be forwarded only once.
+	mpe1.NextDecision = sampling.Sampled
+	mpe2.NextDecision = sampling.Sampled
+	tsp.samplingPolicyOnTick()
+	// The first window has the 10 spans of the traces with 1, 2, 3 and 4 spans.
+	require.Equal(t, 4, mpe1.EvaluationCount)
+	require.Equal(t, 4, mpe2.EvaluationCount)
+	require.Equal(t, numSpansPerBatchWindow, msp.TotalSpans, "spans sampled by multiple policies must be forwarded once")
+
+	// Same for late spans.
+	tsp.ConsumeTraces(context.Background(), batches[0])
+	require.Equal(t, numSpansPerBatchWindow+1, msp.TotalSpans, "late span was not accounted for")
+	require.Equal(t, 1, mpe1.LateArrivingSpansCount)
+	require.Equal(t, 1, mpe2.LateArrivingSpansCount)
+}
+
+func TestSequentialTraceMemoryLimit(t *testing.T) {
+	traceIds, batches := generateIdsAndBatches(128)
+	// Enough to keep roughly the spans of the last 10 batches.
+	maxBytes := 10 * tracesSize(batches[0])
+	cfg := Config{
+		DecisionWait:            defaultTestDecisionWait,
+		NumTraces:               uint64(2 * len(traceIds)),
+		MaxBufferedBytes:        uint64(maxBytes),
+		ExpectedNewTracesPerSec: 64,
+		PolicyCfgs:              testPolicy,
+	}
+	sp, _ := newTraceProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, cfg)
+	tsp := sp.(*tailSamplingSpanProcessor)
+	for _, batch := range batches {
+		tsp.ConsumeTraces(context.Background(), batch)
+		require.LessOrEqual(t, atomic.LoadInt64(&tsp.bytesOnMap), maxBytes)
+	}
+
+	// The oldest traces are dropped first.
+	_, ok := tsp.idToTrace.Load(traceKey(traceIds[0]))
+	require.False(t, ok, "Found unexpected oldest traceId still on map")
+	_, ok = tsp.idToTrace.Load(traceKey(traceIds[len(traceIds)-1]))
+	require.True(t, ok, "Missing expected newest traceId")
+}
+
+func TestLateSpansUseCachedDecision(t *testing.T) {
+	const maxSize = 100
+	const decisionWaitSeconds = 5
+	msp := &mockSpanProcessor{}
+	mpe := &mockPolicyEvaluator{}
+	mtt := &manualTTicker{}
+	tsp := &tailSamplingSpanProcessor{
+		ctx:             context.Background(),
+		nextConsumer:    msp,
+		maxNumTraces:    maxSize,
+		logger:          zap.NewNop(),
+		decisionBatcher: newSyncIDBatcher(decisionWaitSeconds),
+		decisionCache:   newDecisionCache(maxSize),
+		policies:        []*Policy{{Name: "mock-policy", Evaluator: mpe, ctx: context.TODO()}},
+		deleteChan:      make(chan traceKey, maxSize),
+		policyTicker:    mtt,
+	}
+
+	traceIds, batches := generateIdsAndBatches(210)
+	currItem := 0
+	numSpansPerBatchWindow := 10
+	for evalNum := 0; evalNum < decisionWaitSeconds; evalNum++ {
+		for ; currItem < numSpansPerBatchWindow*(evalNum+1); currItem++ {
+			tsp.ConsumeTraces(context.Background(), batches[currItem])
+		}
+		tsp.samplingPolicyOnTick()
+	}
+
+	// The first window, traces 0 to 3, is sampled.
+	mpe.NextDecision = sampling.Sampled
+	tsp.samplingPolicyOnTick()
+	require.Equal(t, numSpansPerBatchWindow, msp.TotalSpans)
+
+	// The second window, traces 4 and 5, is not sampled.
+	mpe.NextDecision = sampling.NotSampled
+	tsp.samplingPolicyOnTick()
+	require.Equal(t, numSpansPerBatchWindow, msp.TotalSpans)
+
+	// Remove the traces from memory, the late spans must follow the cached decisions
+	// without notifying the policies.
+	tsp.dropTrace(traceKey(traceIds[0]), time.Now())
+	tsp.dropTrace(traceKey(traceIds[4]), time.Now())
+	lateArrivingSpansCount := mpe.LateArrivingSpansCount
+
+	tsp.ConsumeTraces(context.Background(), batches[0])
+	require.Equal(t, numSpansPerBatchWindow+1, msp.TotalSpans, "late span of sampled trace was not forwarded")
+
+	tsp.ConsumeTraces(context.Background(), batches[numSpansPerBatchWindow])
+	require.Equal(t, numSpansPerBatchWindow+1, msp.TotalSpans, "late span of not sampled trace was forwarded")
+
+	require.Equal(t, lateArrivingSpansCount, mpe.LateArrivingSpansCount)
+	_, ok := tsp.idToTrace.Load(traceKey(traceIds[0]))
+	require.False(t, ok, "late span of trace with cached decision must not be kept on memory")
+}
+
+func TestCompleteTraceEarlyEvaluation(t *testing.T) {
+	const maxSize = 100
+	const decisionWaitSeconds = 5
+	tests := []struct {
+		name          string
+		quietPeriod   time.Duration
+		rootSpanEnded bool
+		wantEarly     bool
+	}{
+		{
+			name:          "complete_trace",
+			quietPeriod:   time.Nanosecond,
+			rootSpanEnded: true,
+			wantEarly:     true,
+		},
+		{
+			name:          "disabled",
+			rootSpanEnded: true,
+		},
+		{
+			name:        "without_root_span",
+			quietPeriod: time.Nanosecond,
+		},
+		{
+			name:          "within_quiet_period",
+			quietPeriod:   time.Hour,
+			rootSpanEnded: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msp := &mockSpanProcessor{}
+			mpe := &mockPolicyEvaluator{NextDecision: sampling.Sampled}
+			tsp := &tailSamplingSpanProcessor{
+				ctx:             context.Background(),
+				nextConsumer:    msp,
+				maxNumTraces:    maxSize,
+				quietPeriod:     tt.quietPeriod,
+				logger:          zap.NewNop(),
+				decisionBatcher: newSyncIDBatcher(decisionWaitSeconds),
+				policies:        []*Policy{{Name: "mock-policy", Evaluator: mpe, ctx: context.TODO()}},
+				deleteChan:      make(chan traceKey, maxSize),
+				policyTicker:    &manualTTicker{},
+			}
+
+			traceID := tracetranslator.UInt64ToByteTraceID(1, 1)
+			tsp.ConsumeTraces(context.Background(), newTraceWithSpan(traceID, 2, 1))
+			tsp.ConsumeTraces(context.Background(), newTraceWithSpan(traceID, 3, 1))
+			if tt.rootSpanEnded {
+				tsp.ConsumeTraces(context.Background(), newTraceWithSpan(traceID, 1, 0))
+			}
+
+			tsp.samplingPolicyOnTick()
+			if !tt.wantEarly {
+				require.Equal(t, 0, mpe.EvaluationCount)
+				require.Equal(t, 0, msp.TotalSpans)
+				return
+			}
+			require.Equal(t, 1, mpe.EvaluationCount)
+			require.Equal(t, 3, msp.TotalSpans)
+
+			// The trace is not evaluated again after the decision wait.
+			for i := 0; i < decisionWaitSeconds; i++ {
+				tsp.samplingPolicyOnTick()
+			}
+			require.Equal(t, 1, mpe.EvaluationCount)
+			require.Equal(t, 3, msp.TotalSpans)
+		})
+	}
+}
+
+func TestGetPolicyEvaluator(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     PolicyCfg
+		wantErr bool
+	}{
+		{
+			name: "latency",
+			cfg:  PolicyCfg{Type: Latency, LatencyCfg: LatencyCfg{ThresholdMs: 100}},
+		},
+		{
+			name: "status_code",
+			cfg:  PolicyCfg{Type: StatusCode, StatusCodeCfg: StatusCodeCfg{StatusCodes: []string{"ERROR"}}},
+		},
+		{
+			name:    "status_code_invalid",
+			cfg:     PolicyCfg{Type: StatusCode, StatusCodeCfg: StatusCodeCfg{StatusCodes: []string{"BAD"}}},
+			wantErr: true,
+		},
+		{
+			name: "span_count",
+			cfg:  PolicyCfg{Type: SpanCount, SpanCountCfg: SpanCountCfg{MinSpans: 2}},
+		},
+		{
+			name: "probabilistic",
+			cfg:  PolicyCfg{Type: Probabilistic, ProbabilisticCfg: ProbabilisticCfg{SamplingPercentage: 5}},
+		},
+		{
+			name:    "probabilistic_invalid",
+			cfg:     PolicyCfg{Type: Probabilistic, ProbabilisticCfg: ProbabilisticCfg{SamplingPercentage: 200}},
+			wantErr: true,
+		},
+		{
+			name: "string_attribute_regex",
+			cfg: PolicyCfg{Type: StringAttribute, StringAttributeCfg: StringAttributeCfg{
+				Key: "key", Values: []string{"^v.*"}, EnabledRegexMatching: true}},
+		},
+		{
+			name: "string_attribute_regex_invalid",
+			cfg: PolicyCfg{Type: StringAttribute, StringAttributeCfg: StringAttributeCfg{
+				Key: "key", Values: []string{"("}, EnabledRegexMatching: true}},
+			wantErr: true,
+		},
+		{
+			name: "and",
+			cfg: PolicyCfg{Type: And, AndCfg: AndCfg{SubPolicyCfgs: []PolicyCfg{
+				{Name: "sub-1", Type: AlwaysSample},
+				{Name: "sub-2", Type: SpanCount, SpanCountCfg: SpanCountCfg{MinSpans: 2}},
+			}}},
+		},
+		{
+			name:    "and_without_sub_policies",
+			cfg:     PolicyCfg{Type: And},
+			wantErr: true,
+		},
+		{
+			name: "and_invalid_sub_policy",
+			cfg: PolicyCfg{Type: And, AndCfg: AndCfg{SubPolicyCfgs: []PolicyCfg{
+				{Name: "sub-1", Type: "unknown"},
+			}}},
+			wantErr: true,
+		},
+		{
+			name: "composite",
+			cfg: PolicyCfg{Type: Composite, CompositeCfg: CompositeCfg{
+				MaxTotalSpansPerSecond: 100,
+				SubPolicyCfgs:          []PolicyCfg{{Name: "sub-1", Type: AlwaysSample}},
+			}},
+		},
+		{
+			name: "composite_without_rate",
+			cfg: PolicyCfg{Type: Composite, CompositeCfg: CompositeCfg{
+				SubPolicyCfgs: []PolicyCfg{{Name: "sub-1", Type: AlwaysSample}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "composite_without_sub_policies",
+			cfg: PolicyCfg{Type: Composite, CompositeCfg: CompositeCfg{
+				MaxTotalSpansPerSecond: 100,
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown",
+			cfg:     PolicyCfg{Type: "unknown"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eval, err := getPolicyEvaluator(zap.NewNop(), &tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, eval)
+		})
+	}
+}
+
+func TestGetCompositeRates(t *testing.T) {
+	subPolicies := []PolicyCfg{
+		{Name: "sub-1", Type: AlwaysSample},
+		{Name: "sub-2", Type: AlwaysSample},
+		{Name: "sub-3", Type: AlwaysSample},
+	}
+	tests := []struct {
+		name           string
+		subPolicies    []PolicyCfg
+		rateAllocation []RateAllocationCfg
+		want           []int64
+		wantErr        bool
+	}{
+		{
+			name:        "equal_shares",
+			subPolicies: subPolicies,
+			want:        []int64{33, 33, 34},
+		},
+		{
+			name:        "allocation",
+			subPolicies: subPolicies,
+			rateAllocation: []RateAllocationCfg{
+				{Policy: "sub-1", Percent: 50},
+				{Policy: "sub-2", Percent: 30},
+				{Policy: "sub-3", Percent: 20},
+			},
+			want: []int64{50, 30, 20},
+		},
+		{
+			name:        "partial_allocation",
+			subPolicies: subPolicies,
+			rateAllocation: []RateAllocationCfg{
+				{Policy: "sub-1", Percent: 50},
+			},
+			want: []int64{50, 25, 25},
+		},
+		{
+			name:        "partial_allocation_uneven_rest",
+			subPolicies: subPolicies,
+			rateAllocation: []RateAllocationCfg{
+				{Policy: "sub-2", Percent: 25},
+			},
+			want: []int64{37, 25, 38},
+		},
+		{
+			name:        "full_allocation_of_some",
+			subPolicies: subPolicies,
+			rateAllocation: []RateAllocationCfg{
+				{Policy: "sub-1", Percent: 60},
+				{Policy: "sub-3", Percent: 40},
+			},
+			want: []int64{60, 0, 40},
+		},
+		{
+			name:        "allocation_under_100_percent",
+			subPolicies: subPolicies,
+			rateAllocation: []RateAllocationCfg{
+				{Policy: "sub-1", Percent: 30},
+				{Policy: "sub-2", Percent: 30},
+				{Policy: "sub-3", Percent: 30},
+			},
+			want: []int64{30, 30, 30},
+		},
+		{
+			name:        "unknown_sub_policy",
+			subPolicies: subPolicies,
+			rateAllocation: []RateAllocationCfg{
+				{Policy: "sub-4", Percent: 50},
+			},
+			wantErr: true,
+		},
+		{
+			name:        "negative_percent",
+			subPolicies: subPolicies,
+			rateAllocation: []RateAllocationCfg{
+				{Policy: "sub-1", Percent: -10},
+			},
+			wantErr: true,
+		},
+		{
+			name:        "more_than_100_percent",
+			subPolicies: subPolicies,
+			rateAllocation: []RateAllocationCfg{
+				{Policy: "sub-1", Percent: 60},
+				{Policy: "sub-2", Percent: 60},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicated_sub_policy_name",
+			subPolicies: []PolicyCfg{
+				{Name: "sub-1", Type: AlwaysSample},
+				{Name: "sub-1", Type: AlwaysSample},
+			},
+			rateAllocation: []RateAllocationCfg{
+				{Policy: "sub-1", Percent: 50},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &PolicyCfg{
+				Name: "composite",
+				Type: Composite,
+				CompositeCfg: CompositeCfg{
+					MaxTotalSpansPerSecond: 100,
+					SubPolicyCfgs:          tt.subPolicies,
+					RateAllocation:         tt.rateAllocation,
+				},
+			}
+			rates, err := getCompositeRates(cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, rates)
+		})
+	}
+}
+
+func generateIdsAndBatches(numIds int) ([][]byte, []pdata.Traces) {
 	traceIds := make([][]byte, numIds)
 	for i := 0; i < numIds; i++ {
 		traceIds[i] = tracetranslator.UInt64ToByteTraceID(1, uint64(i+1))
 	}
 
-	tds := []consumerdata.TraceData{}
+	tds := []pdata.Traces{}
 	for i := range traceIds {
-		spans := make([]*tracepb.Span, i+1)
-		for j := range spans {
-			spans[j] = &tracepb.Span{
-				TraceId: traceIds[i],
-				SpanId:  tracetranslator.UInt64ToByteSpanID(uint64(i + 1)),
-			}
-		}
-
 		// Send each span in a separate batch
-		for _, span := range spans {
-			td := consumerdata.TraceData{
-				Spans:        []*tracepb.Span{span},
-				SourceFormat: "test",
-			}
+		for j := 0; j <= i; j++ {
+			td := pdata.NewTraces()
+			td.ResourceSpans().Resize(1)
+			rs := td.ResourceSpans().At(0)
+			rs.InstrumentationLibrarySpans().Resize(1)
+			ils := rs.InstrumentationLibrarySpans().At(0)
+			ils.Spans().Resize(1)
+			span := ils.Spans().At(0)
+			span.SetTraceID(pdata.NewTraceID(traceIds[i]))
+			span.SetSpanID(pdata.NewSpanID(tracetranslator.UInt64ToByteSpanID(uint64(i + 1))))
 			tds = append(tds, td)
 		}
 	}
@@ -236,6 +653,26 @@ func generateIdsAndBatches(numIds int) ([][]byte, []consumerdata.TraceData) {
 	return traceIds, tds
 }
 
+// newTraceWithSpan returns a batch with a single ended span, parentSpanID zero
+// means the span is the root span of the trace.
+func newTraceWithSpan(traceID []byte, spanID, parentSpanID uint64) pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(1)
+	span := ils.Spans().At(0)
+	span.SetTraceID(pdata.NewTraceID(traceID))
+	span.SetSpanID(pdata.NewSpanID(tracetranslator.UInt64ToByteSpanID(spanID)))
+	if parentSpanID != 0 {
+		span.SetParentSpanID(pdata.NewSpanID(tracetranslator.UInt64ToByteSpanID(parentSpanID)))
+	}
+	span.SetStartTime(pdata.TimestampUnixNano(1))
+	span.SetEndTime(pdata.TimestampUnixNano(2))
+	return td
+}
+
 type mockPolicyEvaluator struct {
 	NextDecision           sampling.Decision
 	NextError              error
@@ -246,7 +683,7 @@ type mockPolicyEvaluator struct {
 
 var _ (sampling.PolicyEvaluator) = (*mockPolicyEvaluator)(nil)
 
-func (m *mockPolicyEvaluator) OnLateArrivingSpans(earlyDecision sampling.Decision, spans []*tracepb.Span) error {
+func (m *mockPolicyEvaluator) OnLateArrivingSpans(earlyDecision sampling.Decision, spans []pdata.Span) error {
 	m.LateArrivingSpansCount++
 	return m.NextError
 }
@@ -310,9 +747,8 @@ type mockSpanProcessor struct {
 	TotalSpans int
 }
 
-func (p *mockSpanProcessor) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
-	batchSize := len(td.Spans)
-	p.TotalSpans += batchSize
+func (p *mockSpanProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	p.TotalSpans += td.SpanCount()
 	return nil
 }
 
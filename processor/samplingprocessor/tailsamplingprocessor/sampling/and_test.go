@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestAndFilter(t *testing.T) {
+	errorFilter, err := NewStatusCodeFilter(zap.NewNop(), []string{StatusCodeError})
+	require.NoError(t, err)
+	filter := NewAnd(zap.NewNop(), []PolicyEvaluator{
+		errorFilter,
+		NewSpanCount(zap.NewNop(), 2),
+	})
+
+	cases := []struct {
+		Desc     string
+		Spans    []func(span pdata.Span)
+		Decision Decision
+	}{
+		{
+			Desc:     "only the first sub-policy matches",
+			Spans:    []func(span pdata.Span){newStatusSpan(2)},
+			Decision: NotSampled,
+		},
+		{
+			Desc:     "only the second sub-policy matches",
+			Spans:    []func(span pdata.Span){newUnsetStatusSpan, newUnsetStatusSpan},
+			Decision: NotSampled,
+		},
+		{
+			Desc:     "all sub-policies match",
+			Spans:    []func(span pdata.Span){newUnsetStatusSpan, newStatusSpan(2)},
+			Decision: Sampled,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			decision, err := filter.Evaluate([]byte{1, 2, 3, 4}, newTraceWithSpans(nil, c.Spans...))
+			assert.NoError(t, err)
+			assert.Equal(t, c.Decision, decision)
+		})
+	}
+}
+
+func TestAndFilter_Error(t *testing.T) {
+	errSub := errors.New("sub-policy error")
+	filter := NewAnd(zap.NewNop(), []PolicyEvaluator{
+		NewAlwaysSample(zap.NewNop()),
+		&errorEvaluator{err: errSub},
+	})
+
+	_, err := filter.Evaluate([]byte{1, 2, 3, 4}, &TraceData{})
+	assert.Equal(t, errSub, err)
+}
+
+type errorEvaluator struct {
+	err error
+}
+
+func (e *errorEvaluator) OnLateArrivingSpans(Decision, []pdata.Span) error {
+	return e.err
+}
+
+func (e *errorEvaluator) Evaluate([]byte, *TraceData) (Decision, error) {
+	return Unspecified, e.err
+}
+
+func (e *errorEvaluator) OnDroppedSpans([]byte, *TraceData) (Decision, error) {
+	return Unspecified, e.err
+}
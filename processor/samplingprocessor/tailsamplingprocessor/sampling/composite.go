@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// SubPolicyEvalParams defines a sub-policy of a composite policy and the number
+// of spans per second it is allowed to sample on its own.
+type SubPolicyEvalParams struct {
+	Evaluator         PolicyEvaluator
+	MaxSpansPerSecond int64
+}
+
+type subpolicy struct {
+	evaluator PolicyEvaluator
+	// budget is the cumulative number of spans per second that the sub-policies
+	// up to this one, included, are allowed to sample.
+	budget int64
+	// used is the cumulative number of spans sampled in the current second by
+	// the sub-policies up to this one, included.
+	used int64
+}
+
+type composite struct {
+	subpolicies   []*subpolicy
+	currentSecond int64
+	// now is the clock, replaceable in tests.
+	now    func() time.Time
+	logger *zap.Logger
+}
+
+var _ PolicyEvaluator = (*composite)(nil)
+
+// NewComposite creates a policy evaluator that evaluates the sub-policies in
+// order, the trace is sampled by the first one that samples it while having
+// budget left in the current second. The budget a sub-policy does not use in a
+// second is available to the sub-policies after it, never to the ones before,
+// so a flood of traces of one sub-policy does not crowd out the others.
+func NewComposite(logger *zap.Logger, subPolicyParams []SubPolicyEvalParams) PolicyEvaluator {
+	var budget int64
+	subpolicies := make([]*subpolicy, 0, len(subPolicyParams))
+	for _, params := range subPolicyParams {
+		budget += params.MaxSpansPerSecond
+		subpolicies = append(subpolicies, &subpolicy{
+			evaluator: params.Evaluator,
+			budget:    budget,
+		})
+	}
+	return &composite{
+		subpolicies: subpolicies,
+		now:         time.Now,
+		logger:      logger,
+	}
+}
+
+// OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
+// after the sampling decision was already taken for the trace.
+// This gives the evaluator a chance to log any message/metrics and/or update any
+// related internal state.
+func (c *composite) OnLateArrivingSpans(earlyDecision Decision, spans []pdata.Span) error {
+	c.logger.Debug("Triggering action for late arriving spans in composite filter")
+	for _, sub := range c.subpolicies {
+		if err := sub.evaluator.OnLateArrivingSpans(earlyDecision, spans); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (c *composite) Evaluate(traceID []byte, trace *TraceData) (Decision, error) {
+	c.logger.Debug("Evaluating spans in composite filter")
+	currSecond := c.now().Unix()
+	if c.currentSecond != currSecond {
+		c.currentSecond = currSecond
+		for _, sub := range c.subpolicies {
+			sub.used = 0
+		}
+	}
+
+	spanCount := atomic.LoadInt64(&trace.SpanCount)
+	for i, sub := range c.subpolicies {
+		decision, err := sub.evaluator.Evaluate(traceID, trace)
+		if err != nil {
+			return Unspecified, err
+		}
+		if decision != Sampled || !c.hasBudget(i, spanCount) {
+			continue
+		}
+		// The spans count against the budget of this sub-policy and, through
+		// the cumulative counts, against the one left for the following ones.
+		for _, next := range c.subpolicies[i:] {
+			next.used += spanCount
+		}
+		return Sampled, nil
+	}
+
+	return NotSampled, nil
+}
+
+// hasBudget returns whether the sub-policy at index i can sample the given
+// number of spans without exceeding its budget, nor the budget of the ones after
+// it, that may already be using part of the budget it left unused.
+func (c *composite) hasBudget(i int, spanCount int64) bool {
+	for _, sub := range c.subpolicies[i:] {
+		if sub.used+spanCount > sub.budget {
+			return false
+		}
+	}
+	return true
+}
+
+// OnDroppedSpans is called when the trace needs to be dropped, due to memory
+// pressure, before the decision_wait time has been reached.
+func (c *composite) OnDroppedSpans(traceID []byte, trace *TraceData) (Decision, error) {
+	c.logger.Debug("Triggering action for dropped spans in composite filter")
+	return NotSampled, nil
+}
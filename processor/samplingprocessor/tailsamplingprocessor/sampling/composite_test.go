@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCompositeFilter(t *testing.T) {
+	errorFilter, err := NewStatusCodeFilter(zap.NewNop(), []string{StatusCodeError})
+	require.NoError(t, err)
+	okFilter, err := NewStatusCodeFilter(zap.NewNop(), []string{StatusCodeOk})
+	require.NoError(t, err)
+
+	filter := NewComposite(zap.NewNop(), []SubPolicyEvalParams{
+		{Evaluator: errorFilter, MaxSpansPerSecond: 5},
+		{Evaluator: okFilter, MaxSpansPerSecond: 5},
+	})
+	now := time.Unix(1000, 0)
+	filter.(*composite).now = func() time.Time { return now }
+
+	errorTrace := newTraceWithSpans(nil, newStatusSpan(2))
+	okTrace := newTraceWithSpans(nil, newStatusSpan(0))
+	otherTrace := newTraceWithSpans(nil, newUnsetStatusSpan)
+
+	evaluate := func(trace *TraceData, times int) int {
+		sampled := 0
+		for i := 0; i < times; i++ {
+			decision, err := filter.Evaluate([]byte{1, 2, 3, 4}, trace)
+			require.NoError(t, err)
+			if decision == Sampled {
+				sampled++
+			}
+		}
+		return sampled
+	}
+
+	// A flood of errors does not crowd out the other sub-policies.
+	assert.Equal(t, 5, evaluate(errorTrace, 20))
+	assert.Equal(t, 5, evaluate(okTrace, 20))
+	assert.Equal(t, 0, evaluate(otherTrace, 1))
+
+	// The budget unused by the first sub-policy flows to the second one.
+	now = now.Add(time.Second)
+	assert.Equal(t, 10, evaluate(okTrace, 20))
+
+	// But not the other way around, the total is never exceeded.
+	assert.Equal(t, 0, evaluate(errorTrace, 1))
+
+	// Budgets are renewed every second.
+	now = now.Add(time.Second)
+	assert.Equal(t, 2, evaluate(errorTrace, 2))
+	assert.Equal(t, 8, evaluate(okTrace, 20))
+}
+
+func TestCompositeFilter_TraceLargerThanBudget(t *testing.T) {
+	filter := NewComposite(zap.NewNop(), []SubPolicyEvalParams{
+		{Evaluator: NewAlwaysSample(zap.NewNop()), MaxSpansPerSecond: 5},
+	})
+
+	trace := &TraceData{SpanCount: 6}
+	decision, err := filter.Evaluate([]byte{1, 2, 3, 4}, trace)
+	assert.NoError(t, err)
+	assert.Equal(t, NotSampled, decision)
+}
@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type latency struct {
+	threshold time.Duration
+	logger    *zap.Logger
+}
+
+var _ PolicyEvaluator = (*latency)(nil)
+
+// NewLatency creates a policy evaluator that samples traces whose duration, from
+// the earliest span start to the latest span end, is at least the given threshold.
+func NewLatency(logger *zap.Logger, threshold time.Duration) PolicyEvaluator {
+	return &latency{
+		threshold: threshold,
+		logger:    logger,
+	}
+}
+
+// OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
+// after the sampling decision was already taken for the trace.
+// This gives the evaluator a chance to log any message/metrics and/or update any
+// related internal state.
+func (l *latency) OnLateArrivingSpans(earlyDecision Decision, spans []pdata.Span) error {
+	l.logger.Debug("Triggering action for late arriving spans in latency filter")
+	return nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (l *latency) Evaluate(traceID []byte, trace *TraceData) (Decision, error) {
+	l.logger.Debug("Evaluating spans in latency filter")
+	var minStart, maxEnd pdata.TimestampUnixNano
+	if hasSpanWithCondition(trace, func(_ pdata.Resource, span pdata.Span) bool {
+		start, end := span.StartTime(), span.EndTime()
+		if start == 0 || end == 0 {
+			return false
+		}
+		if minStart == 0 || start < minStart {
+			minStart = start
+		}
+		if end > maxEnd {
+			maxEnd = end
+		}
+		return maxEnd >= minStart && time.Duration(maxEnd-minStart) >= l.threshold
+	}) {
+		return Sampled, nil
+	}
+	return NotSampled, nil
+}
+
+// OnDroppedSpans is called when the trace needs to be dropped, due to memory
+// pressure, before the decision_wait time has been reached.
+func (l *latency) OnDroppedSpans(traceID []byte, trace *TraceData) (Decision, error) {
+	l.logger.Debug("Triggering action for dropped spans in latency filter")
+	return NotSampled, nil
+}
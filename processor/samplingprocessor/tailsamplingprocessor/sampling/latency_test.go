@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestLatencyFilter(t *testing.T) {
+	filter := NewLatency(zap.NewNop(), 5*time.Second)
+
+	now := time.Now()
+	cases := []struct {
+		Desc     string
+		Spans    []func(span pdata.Span)
+		Decision Decision
+	}{
+		{
+			Desc:     "short single span",
+			Spans:    []func(span pdata.Span){newLatencySpan(now, time.Second)},
+			Decision: NotSampled,
+		},
+		{
+			Desc:     "long single span",
+			Spans:    []func(span pdata.Span){newLatencySpan(now, 5*time.Second)},
+			Decision: Sampled,
+		},
+		{
+			Desc: "short spans spread over a long trace",
+			Spans: []func(span pdata.Span){
+				newLatencySpan(now, time.Second),
+				newLatencySpan(now.Add(4*time.Second), 2*time.Second),
+			},
+			Decision: Sampled,
+		},
+		{
+			Desc:     "spans without timestamps",
+			Spans:    []func(span pdata.Span){func(span pdata.Span) { span.InitEmpty() }},
+			Decision: NotSampled,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			decision, err := filter.Evaluate([]byte{1, 2, 3, 4}, newTraceWithSpans(nil, c.Spans...))
+			assert.NoError(t, err)
+			assert.Equal(t, c.Decision, decision)
+		})
+	}
+}
+
+func newLatencySpan(start time.Time, duration time.Duration) func(span pdata.Span) {
+	return func(span pdata.Span) {
+		span.InitEmpty()
+		span.SetStartTime(pdata.TimestampUnixNano(start.UnixNano()))
+		span.SetEndTime(pdata.TimestampUnixNano(start.Add(duration).UnixNano()))
+	}
+}
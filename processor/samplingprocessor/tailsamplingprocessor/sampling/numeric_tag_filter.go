@@ -15,8 +15,9 @@
 package sampling
 
 import (
-	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
 type numericAttributeFilter struct {
@@ -42,7 +43,7 @@ func NewNumericAttributeFilter(logger *zap.Logger, key string, minValue, maxValu
 // after the sampling decision was already taken for the trace.
 // This gives the evaluator a chance to log any message/metrics and/or update any
 // related internal state.
-func (naf *numericAttributeFilter) OnLateArrivingSpans(earlyDecision Decision, spans []*tracepb.Span) error {
+func (naf *numericAttributeFilter) OnLateArrivingSpans(earlyDecision Decision, spans []pdata.Span) error {
 	naf.logger.Debug("Triggering action for late arriving spans in numeric-attribute filter")
 	return nil
 }
@@ -50,23 +51,15 @@ func (naf *numericAttributeFilter) OnLateArrivingSpans(earlyDecision Decision, s
 // Evaluate looks at the trace data and returns a corresponding SamplingDecision.
 func (naf *numericAttributeFilter) Evaluate(traceID []byte, trace *TraceData) (Decision, error) {
 	naf.logger.Debug("Evaluating spans in numeric-attribute filter")
-	trace.Lock()
-	batches := trace.ReceivedBatches
-	trace.Unlock()
-	for _, batch := range batches {
-		for _, span := range batch.Spans {
-			if span == nil || span.Attributes == nil {
-				continue
-			}
-			if v, ok := span.Attributes.AttributeMap[naf.key]; ok {
-				value := v.GetIntValue()
-				if value >= naf.minValue && value <= naf.maxValue {
-					return Sampled, nil
-				}
-			}
+	if hasSpanWithCondition(trace, func(_ pdata.Resource, span pdata.Span) bool {
+		if v, ok := span.Attributes().Get(naf.key); ok && v.Type() == pdata.AttributeValueINT {
+			value := v.IntVal()
+			return value >= naf.minValue && value <= naf.maxValue
 		}
+		return false
+	}) {
+		return Sampled, nil
 	}
-
 	return NotSampled, nil
 }
 
@@ -18,9 +18,7 @@ import (
 	"sync"
 	"time"
 
-	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
-
-	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
 // TraceData stores the sampling related trace data.
@@ -35,7 +33,13 @@ type TraceData struct {
 	// SpanCount track the number of spans on the trace.
 	SpanCount int64
 	// ReceivedBatches stores all the batches received for the trace.
-	ReceivedBatches []consumerdata.TraceData
+	ReceivedBatches []pdata.Traces
+	// ReceivedBytes is the estimated size, in bytes, of ReceivedBatches.
+	ReceivedBytes int64
+	// LastSpanArrivalTime is the arrival time of the latest span received for the trace.
+	LastSpanArrivalTime time.Time
+	// RootSpanEnded tracks if the root span of the trace was already received.
+	RootSpanEnded bool
 }
 
 // Decision gives the status of sampling decision.
@@ -64,7 +68,7 @@ type PolicyEvaluator interface {
 	// after the sampling decision was already taken for the trace.
 	// This gives the evaluator a chance to log any message/metrics and/or update any
 	// related internal state.
-	OnLateArrivingSpans(earlyDecision Decision, spans []*tracepb.Span) error
+	OnLateArrivingSpans(earlyDecision Decision, spans []pdata.Span) error
 
 	// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
 	Evaluate(traceID []byte, trace *TraceData) (Decision, error)
@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// The constants help translate user friendly percentages to numbers direct used in sampling,
+// they match the ones of the probabilistic sampler processor so that, given the same seed and
+// percentage, both sample the same traces.
+const (
+	numHashBuckets        = 0x4000 // Using a power of 2 to avoid division.
+	bitMaskHashBuckets    = numHashBuckets - 1
+	percentageScaleFactor = numHashBuckets / 100.0
+)
+
+type probabilistic struct {
+	scaledSamplingRate uint32
+	hashSeed           uint32
+	logger             *zap.Logger
+}
+
+var _ PolicyEvaluator = (*probabilistic)(nil)
+
+// NewProbabilistic creates a policy evaluator that samples the given percentage of
+// traces, the decision is deterministic on the trace ID and the hash seed.
+func NewProbabilistic(logger *zap.Logger, hashSeed uint32, samplingPercentage float32) (PolicyEvaluator, error) {
+	if samplingPercentage < 0 || samplingPercentage > 100 {
+		return nil, fmt.Errorf("sampling_percentage must be between 0 and 100, got %v", samplingPercentage)
+	}
+	return &probabilistic{
+		scaledSamplingRate: uint32(samplingPercentage * percentageScaleFactor),
+		hashSeed:           hashSeed,
+		logger:             logger,
+	}, nil
+}
+
+// OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
+// after the sampling decision was already taken for the trace.
+// This gives the evaluator a chance to log any message/metrics and/or update any
+// related internal state.
+func (p *probabilistic) OnLateArrivingSpans(earlyDecision Decision, spans []pdata.Span) error {
+	p.logger.Debug("Triggering action for late arriving spans in probabilistic filter")
+	return nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (p *probabilistic) Evaluate(traceID []byte, trace *TraceData) (Decision, error) {
+	p.logger.Debug("Evaluating spans in probabilistic filter")
+	if hash(traceID, p.hashSeed)&bitMaskHashBuckets < p.scaledSamplingRate {
+		return Sampled, nil
+	}
+	return NotSampled, nil
+}
+
+// OnDroppedSpans is called when the trace needs to be dropped, due to memory
+// pressure, before the decision_wait time has been reached.
+func (p *probabilistic) OnDroppedSpans(traceID []byte, trace *TraceData) (Decision, error) {
+	p.logger.Debug("Triggering action for dropped spans in probabilistic filter")
+	return NotSampled, nil
+}
+
+// hash is a murmur3 hash function, see http://en.wikipedia.org/wiki/MurmurHash.
+func hash(key []byte, seed uint32) (hash uint32) {
+	const (
+		c1 = 0xcc9e2d51
+		c2 = 0x1b873593
+		c3 = 0x85ebca6b
+		c4 = 0xc2b2ae35
+		r1 = 15
+		r2 = 13
+		m  = 5
+		n  = 0xe6546b64
+	)
+
+	hash = seed
+	iByte := 0
+	for ; iByte+4 <= len(key); iByte += 4 {
+		k := uint32(key[iByte]) | uint32(key[iByte+1])<<8 | uint32(key[iByte+2])<<16 | uint32(key[iByte+3])<<24
+		k *= c1
+		k = (k << r1) | (k >> (32 - r1))
+		k *= c2
+		hash ^= k
+		hash = (hash << r2) | (hash >> (32 - r2))
+		hash = hash*m + n
+	}
+
+	// TraceId and SpanId have lengths that are multiple of 4 so the code below is never expected to
+	// be hit when sampling traces. However, it is preserved here to keep it as a correct murmur3 implementation.
+	var remainingBytes uint32
+	switch len(key) - iByte {
+	case 3:
+		remainingBytes += uint32(key[iByte+2]) << 16
+		fallthrough
+	case 2:
+		remainingBytes += uint32(key[iByte+1]) << 8
+		fallthrough
+	case 1:
+		remainingBytes += uint32(key[iByte])
+		remainingBytes *= c1
+		remainingBytes = (remainingBytes << r1) | (remainingBytes >> (32 - r1))
+		remainingBytes = remainingBytes * c2
+		hash ^= remainingBytes
+	}
+
+	hash ^= uint32(len(key))
+	hash ^= hash >> 16
+	hash *= c3
+	hash ^= hash >> 13
+	hash *= c4
+	hash ^= hash >> 16
+
+	return
+}
@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNewProbabilistic_InvalidPercentage(t *testing.T) {
+	_, err := NewProbabilistic(zap.NewNop(), 0, -1)
+	assert.Error(t, err)
+
+	_, err = NewProbabilistic(zap.NewNop(), 0, 101)
+	assert.Error(t, err)
+}
+
+func TestProbabilistic(t *testing.T) {
+	tests := []struct {
+		name               string
+		samplingPercentage float32
+		hashSeed           uint32
+	}{
+		{
+			name:               "0%",
+			samplingPercentage: 0,
+		},
+		{
+			name:               "5%",
+			samplingPercentage: 5,
+		},
+		{
+			name:               "33% with seed",
+			samplingPercentage: 33,
+			hashSeed:           22,
+		},
+		{
+			name:               "100%",
+			samplingPercentage: 100,
+		},
+	}
+
+	const numTraces = 10000
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := NewProbabilistic(zap.NewNop(), tt.hashSeed, tt.samplingPercentage)
+			require.NoError(t, err)
+
+			r := rand.New(rand.NewSource(1))
+			sampled := 0
+			for i := 0; i < numTraces; i++ {
+				traceID := make([]byte, 16)
+				r.Read(traceID)
+
+				decision, err := filter.Evaluate(traceID, &TraceData{})
+				require.NoError(t, err)
+				if decision == Sampled {
+					sampled++
+				}
+
+				// The decision is deterministic.
+				again, err := filter.Evaluate(traceID, &TraceData{})
+				require.NoError(t, err)
+				require.Equal(t, decision, again)
+			}
+
+			assert.InDelta(t, tt.samplingPercentage, float32(sampled)*100/numTraces, 1)
+		})
+	}
+}
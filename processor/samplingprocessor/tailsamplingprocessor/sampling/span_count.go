@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type spanCount struct {
+	minSpans int64
+	logger   *zap.Logger
+}
+
+var _ PolicyEvaluator = (*spanCount)(nil)
+
+// NewSpanCount creates a policy evaluator that samples all traces with at least
+// the given number of spans.
+func NewSpanCount(logger *zap.Logger, minSpans int64) PolicyEvaluator {
+	return &spanCount{
+		minSpans: minSpans,
+		logger:   logger,
+	}
+}
+
+// OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
+// after the sampling decision was already taken for the trace.
+// This gives the evaluator a chance to log any message/metrics and/or update any
+// related internal state.
+func (sc *spanCount) OnLateArrivingSpans(earlyDecision Decision, spans []pdata.Span) error {
+	sc.logger.Debug("Triggering action for late arriving spans in span count filter")
+	return nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (sc *spanCount) Evaluate(traceID []byte, trace *TraceData) (Decision, error) {
+	sc.logger.Debug("Evaluating spans in span count filter")
+	if atomic.LoadInt64(&trace.SpanCount) >= sc.minSpans {
+		return Sampled, nil
+	}
+	return NotSampled, nil
+}
+
+// OnDroppedSpans is called when the trace needs to be dropped, due to memory
+// pressure, before the decision_wait time has been reached.
+func (sc *spanCount) OnDroppedSpans(traceID []byte, trace *TraceData) (Decision, error) {
+	sc.logger.Debug("Triggering action for dropped spans in span count filter")
+	return NotSampled, nil
+}
@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestSpanCountFilter(t *testing.T) {
+	filter := NewSpanCount(zap.NewNop(), 3)
+
+	cases := []struct {
+		Desc      string
+		SpanCount int64
+		Decision  Decision
+	}{
+		{
+			Desc:      "fewer spans",
+			SpanCount: 2,
+			Decision:  NotSampled,
+		},
+		{
+			Desc:      "exact number of spans",
+			SpanCount: 3,
+			Decision:  Sampled,
+		},
+		{
+			Desc:      "more spans",
+			SpanCount: 10,
+			Decision:  Sampled,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			decision, err := filter.Evaluate([]byte{1, 2, 3, 4}, &TraceData{SpanCount: c.SpanCount})
+			assert.NoError(t, err)
+			assert.Equal(t, c.Decision, decision)
+		})
+	}
+}
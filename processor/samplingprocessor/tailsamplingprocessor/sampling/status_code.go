@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// Status codes accepted by the status code filter.
+const (
+	// StatusCodeUnset matches spans without a status.
+	StatusCodeUnset = "UNSET"
+	// StatusCodeOk matches spans with the OK status.
+	StatusCodeOk = "OK"
+	// StatusCodeError matches spans with any status other than OK.
+	StatusCodeError = "ERROR"
+)
+
+type statusCodeFilter struct {
+	statusCodes map[string]struct{}
+	logger      *zap.Logger
+}
+
+var _ PolicyEvaluator = (*statusCodeFilter)(nil)
+
+// NewStatusCodeFilter creates a policy evaluator that samples all traces with
+// at least one span with one of the given status codes.
+func NewStatusCodeFilter(logger *zap.Logger, statusCodes []string) (PolicyEvaluator, error) {
+	if len(statusCodes) == 0 {
+		return nil, fmt.Errorf("expected at least one status code to filter on")
+	}
+
+	codes := make(map[string]struct{}, len(statusCodes))
+	for _, code := range statusCodes {
+		switch code {
+		case StatusCodeUnset, StatusCodeOk, StatusCodeError:
+			codes[code] = struct{}{}
+		default:
+			return nil, fmt.Errorf("unknown status code %q, supported: %s, %s, %s",
+				code, StatusCodeUnset, StatusCodeOk, StatusCodeError)
+		}
+	}
+
+	return &statusCodeFilter{
+		statusCodes: codes,
+		logger:      logger,
+	}, nil
+}
+
+// OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
+// after the sampling decision was already taken for the trace.
+// This gives the evaluator a chance to log any message/metrics and/or update any
+// related internal state.
+func (scf *statusCodeFilter) OnLateArrivingSpans(earlyDecision Decision, spans []pdata.Span) error {
+	scf.logger.Debug("Triggering action for late arriving spans in status code filter")
+	return nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (scf *statusCodeFilter) Evaluate(traceID []byte, trace *TraceData) (Decision, error) {
+	scf.logger.Debug("Evaluating spans in status code filter")
+	if hasSpanWithCondition(trace, func(_ pdata.Resource, span pdata.Span) bool {
+		_, ok := scf.statusCodes[statusCode(span.Status())]
+		return ok
+	}) {
+		return Sampled, nil
+	}
+	return NotSampled, nil
+}
+
+// OnDroppedSpans is called when the trace needs to be dropped, due to memory
+// pressure, before the decision_wait time has been reached.
+func (scf *statusCodeFilter) OnDroppedSpans(traceID []byte, trace *TraceData) (Decision, error) {
+	scf.logger.Debug("Triggering action for dropped spans in status code filter")
+	return NotSampled, nil
+}
+
+func statusCode(status pdata.SpanStatus) string {
+	switch {
+	case status.IsNil():
+		return StatusCodeUnset
+	case status.Code() == 0:
+		return StatusCodeOk
+	default:
+		return StatusCodeError
+	}
+}
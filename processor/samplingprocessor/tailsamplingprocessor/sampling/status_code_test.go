@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestNewStatusCodeFilter_Errors(t *testing.T) {
+	_, err := NewStatusCodeFilter(zap.NewNop(), nil)
+	assert.Error(t, err)
+
+	_, err = NewStatusCodeFilter(zap.NewNop(), []string{StatusCodeError, "FAILED"})
+	assert.Error(t, err)
+}
+
+func TestStatusCodeFilter(t *testing.T) {
+	cases := []struct {
+		Desc        string
+		StatusCodes []string
+		Spans       []func(span pdata.Span)
+		Decision    Decision
+	}{
+		{
+			Desc:        "no error spans",
+			StatusCodes: []string{StatusCodeError},
+			Spans:       []func(span pdata.Span){newStatusSpan(0), newUnsetStatusSpan},
+			Decision:    NotSampled,
+		},
+		{
+			Desc:        "one error span",
+			StatusCodes: []string{StatusCodeError},
+			Spans:       []func(span pdata.Span){newStatusSpan(0), newStatusSpan(2)},
+			Decision:    Sampled,
+		},
+		{
+			Desc:        "unset status",
+			StatusCodes: []string{StatusCodeUnset},
+			Spans:       []func(span pdata.Span){newStatusSpan(0), newUnsetStatusSpan},
+			Decision:    Sampled,
+		},
+		{
+			Desc:        "ok status",
+			StatusCodes: []string{StatusCodeOk, StatusCodeError},
+			Spans:       []func(span pdata.Span){newStatusSpan(0)},
+			Decision:    Sampled,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			filter, err := NewStatusCodeFilter(zap.NewNop(), c.StatusCodes)
+			require.NoError(t, err)
+
+			decision, err := filter.Evaluate([]byte{1, 2, 3, 4}, newTraceWithSpans(nil, c.Spans...))
+			assert.NoError(t, err)
+			assert.Equal(t, c.Decision, decision)
+		})
+	}
+}
+
+func newStatusSpan(code pdata.StatusCode) func(span pdata.Span) {
+	return func(span pdata.Span) {
+		span.InitEmpty()
+		span.Status().InitEmpty()
+		span.Status().SetCode(code)
+	}
+}
+
+func newUnsetStatusSpan(span pdata.Span) {
+	span.InitEmpty()
+}
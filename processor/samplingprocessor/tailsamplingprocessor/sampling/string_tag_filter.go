@@ -15,20 +15,24 @@
 package sampling
 
 import (
-	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"fmt"
+	"regexp"
+
 	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
 type stringAttributeFilter struct {
-	key    string
-	values map[string]struct{}
-	logger *zap.Logger
+	key     string
+	matcher func(string) bool
+	logger  *zap.Logger
 }
 
 var _ PolicyEvaluator = (*stringAttributeFilter)(nil)
 
 // NewStringAttributeFilter creates a policy evaluator that samples all traces with
-// the given attribute in the given numeric range.
+// the given attribute equal to one of the given values.
 func NewStringAttributeFilter(logger *zap.Logger, key string, values []string) PolicyEvaluator {
 	valuesMap := make(map[string]struct{})
 	for _, value := range values {
@@ -37,17 +41,45 @@ func NewStringAttributeFilter(logger *zap.Logger, key string, values []string) P
 		}
 	}
 	return &stringAttributeFilter{
-		key:    key,
-		values: valuesMap,
+		key: key,
+		matcher: func(v string) bool {
+			_, ok := valuesMap[v]
+			return ok
+		},
 		logger: logger,
 	}
 }
 
+// NewStringAttributeRegexFilter creates a policy evaluator that samples all traces
+// with the given attribute matching one of the given regular expressions.
+func NewStringAttributeRegexFilter(logger *zap.Logger, key string, regexValues []string) (PolicyEvaluator, error) {
+	regexes := make([]*regexp.Regexp, 0, len(regexValues))
+	for _, value := range regexValues {
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q for attribute %q: %w", value, key, err)
+		}
+		regexes = append(regexes, re)
+	}
+	return &stringAttributeFilter{
+		key: key,
+		matcher: func(v string) bool {
+			for _, re := range regexes {
+				if re.MatchString(v) {
+					return true
+				}
+			}
+			return false
+		},
+		logger: logger,
+	}, nil
+}
+
 // OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
 // after the sampling decision was already taken for the trace.
 // This gives the evaluator a chance to log any message/metrics and/or update any
 // related internal state.
-func (saf *stringAttributeFilter) OnLateArrivingSpans(earlyDecision Decision, spans []*tracepb.Span) error {
+func (saf *stringAttributeFilter) OnLateArrivingSpans(earlyDecision Decision, spans []pdata.Span) error {
 	saf.logger.Debug("Triggering action for late arriving spans in string-tag filter")
 	return nil
 }
@@ -55,36 +87,25 @@ func (saf *stringAttributeFilter) OnLateArrivingSpans(earlyDecision Decision, sp
 // Evaluate looks at the trace data and returns a corresponding SamplingDecision.
 func (saf *stringAttributeFilter) Evaluate(traceID []byte, trace *TraceData) (Decision, error) {
 	saf.logger.Debug("Evaluting spans in string-tag filter")
-	trace.Lock()
-	batches := trace.ReceivedBatches
-	trace.Unlock()
-	for _, batch := range batches {
-		node := batch.Node
-		if node != nil && node.Attributes != nil {
-			if v, ok := node.Attributes[saf.key]; ok {
-				if _, ok := saf.values[v]; ok {
-					return Sampled, nil
-				}
-			}
-		}
-		for _, span := range batch.Spans {
-			if span == nil || span.Attributes == nil {
-				continue
-			}
-			if v, ok := span.Attributes.AttributeMap[saf.key]; ok {
-				truncableStr := v.GetStringValue()
-				if truncableStr != nil {
-					if _, ok := saf.values[truncableStr.Value]; ok {
-						return Sampled, nil
-					}
-				}
-			}
+	if hasSpanWithCondition(trace, func(resource pdata.Resource, span pdata.Span) bool {
+		if !resource.IsNil() && saf.matches(resource.Attributes()) {
+			return true
 		}
+		return saf.matches(span.Attributes())
+	}) {
+		return Sampled, nil
 	}
-
 	return NotSampled, nil
 }
 
+// matches returns whether the filtered attribute is in attrs, with a matching value.
+func (saf *stringAttributeFilter) matches(attrs pdata.AttributeMap) bool {
+	if v, ok := attrs.Get(saf.key); ok && v.Type() == pdata.AttributeValueSTRING {
+		return saf.matcher(v.StringVal())
+	}
+	return false
+}
+
 // OnDroppedSpans is called when the trace needs to be dropped, due to memory
 // pressure, before the decision_wait time has been reached.
 func (saf *stringAttributeFilter) OnDroppedSpans(traceID []byte, trace *TraceData) (Decision, error) {
@@ -17,13 +17,11 @@ package sampling
 import (
 	"testing"
 
-	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
-	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 
-	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
 func TestStringTagFilter(t *testing.T) {
@@ -78,33 +76,65 @@ func TestStringTagFilter(t *testing.T) {
 	}
 }
 
-func newSpan(attrKey string, attrValue string) *tracepb.Span {
-	return &tracepb.Span{
-		Attributes: &tracepb.Span_Attributes{
-			AttributeMap: map[string]*tracepb.AttributeValue{
-				attrKey: {
-					Value: &tracepb.AttributeValue_StringValue{
-						StringValue: &tracepb.TruncatableString{
-							Value: attrValue,
-						},
-					},
-				},
-			},
+func TestStringTagRegexFilter(t *testing.T) {
+
+	var empty = map[string]string{}
+	filter, err := NewStringAttributeRegexFilter(zap.NewNop(), "example", []string{"^val.*", "other"})
+	assert.NoError(t, err)
+
+	cases := []struct {
+		Desc     string
+		Trace    *TraceData
+		Decision Decision
+	}{
+		{
+			Desc:     "matching node attribute",
+			Trace:    newTraceStringAttrs(map[string]string{"example": "value"}, nil),
+			Decision: Sampled,
+		},
+		{
+			Desc:     "nonmatching span attribute value",
+			Trace:    newTraceStringAttrs(empty, newSpan("example", "invalid")),
+			Decision: NotSampled,
+		},
+		{
+			Desc:     "span attribute matching first regex",
+			Trace:    newTraceStringAttrs(empty, newSpan("example", "valid")),
+			Decision: Sampled,
 		},
+		{
+			Desc:     "span attribute matching second regex",
+			Trace:    newTraceStringAttrs(empty, newSpan("example", "another")),
+			Decision: Sampled,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			u, _ := uuid.NewRandom()
+			decision, err := filter.Evaluate(u[:], c.Trace)
+			assert.NoError(t, err)
+			assert.Equal(t, decision, c.Decision)
+		})
 	}
 }
 
-func newTraceStringAttrs(nodeAttrs map[string]string, span *tracepb.Span) *TraceData {
-	return &TraceData{
-		ReceivedBatches: []consumerdata.TraceData{
-			{
-				Node: &commonpb.Node{
-					Attributes: nodeAttrs,
-				},
-				Spans: []*tracepb.Span{
-					span,
-				},
-			},
-		},
+func TestStringTagRegexFilter_InvalidRegex(t *testing.T) {
+	_, err := NewStringAttributeRegexFilter(zap.NewNop(), "example", []string{"("})
+	assert.Error(t, err)
+}
+
+func newSpan(attrKey string, attrValue string) func(span pdata.Span) {
+	return func(span pdata.Span) {
+		span.InitEmpty()
+		span.Attributes().InsertString(attrKey, attrValue)
+	}
+}
+
+func newTraceStringAttrs(nodeAttrs map[string]string, span func(span pdata.Span)) *TraceData {
+	if span == nil {
+		// A span without attributes.
+		span = func(span pdata.Span) { span.InitEmpty() }
 	}
+	return newTraceWithSpans(nodeAttrs, span)
 }
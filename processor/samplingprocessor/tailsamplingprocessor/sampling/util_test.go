@@ -0,0 +1,43 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// newTraceWithSpans returns the data of a trace with a single batch, with the
+// given resource attributes and one span initialized by each of spanInits.
+func newTraceWithSpans(resourceAttrs map[string]string, spanInits ...func(span pdata.Span)) *TraceData {
+	traces := pdata.NewTraces()
+	traces.ResourceSpans().Resize(1)
+	rs := traces.ResourceSpans().At(0)
+	if resourceAttrs != nil {
+		rs.Resource().InitEmpty()
+		for k, v := range resourceAttrs {
+			rs.Resource().Attributes().InsertString(k, v)
+		}
+	}
+	rs.InstrumentationLibrarySpans().Resize(1)
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(len(spanInits))
+	for i, init := range spanInits {
+		init(spans.At(i))
+	}
+	return &TraceData{
+		ReceivedBatches: []pdata.Traces{traces},
+		SpanCount:       int64(len(spanInits)),
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicegraphprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines the configuration for the service graph processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// MetricsExporter is the name of the exporter, configured in a metrics pipeline,
+	// that receives the generated metrics.
+	MetricsExporter string `mapstructure:"metrics_exporter"`
+
+	// LatencyHistogramBuckets are the upper bounds of the buckets of the latency histogram,
+	// if empty a default set of buckets from 2ms to 15s is used.
+	LatencyHistogramBuckets []time.Duration `mapstructure:"latency_histogram_buckets"`
+
+	// Store configures the in-memory store of the requests waiting for the span of the
+	// other side.
+	Store StoreConfig `mapstructure:"store"`
+
+	// SeriesExpiry is the duration after which the metrics of a pair of services
+	// without any request are forgotten, if zero a default of 5m is used.
+	SeriesExpiry time.Duration `mapstructure:"series_expiry"`
+}
+
+// StoreConfig defines the bounds of the store of the requests waiting to be paired.
+type StoreConfig struct {
+	// TTL is how long a request waits for the span of the other side before it is
+	// discarded.
+	TTL time.Duration `mapstructure:"ttl"`
+	// MaxItems is the maximum number of requests waiting to be paired, the spans of new
+	// requests are discarded while the store is full.
+	MaxItems int `mapstructure:"max_items"`
+}
@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicegraphprocessor
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := config.ExampleComponents()
+	require.NoError(t, err)
+
+	factories.Processors[typeStr] = &Factory{}
+
+	cfg, err := config.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, cfg.Processors["servicegraph"], &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: "servicegraph",
+			NameVal: "servicegraph",
+		},
+		MetricsExporter: "exampleexporter",
+		Store: StoreConfig{
+			TTL:      2 * time.Second,
+			MaxItems: 1000,
+		},
+	})
+
+	assert.Equal(t, cfg.Processors["servicegraph/custom"], &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: "servicegraph",
+			NameVal: "servicegraph/custom",
+		},
+		MetricsExporter:         "exampleexporter",
+		LatencyHistogramBuckets: []time.Duration{time.Millisecond, 10 * time.Millisecond, 100 * time.Millisecond, time.Second},
+		Store: StoreConfig{
+			TTL:      5 * time.Second,
+			MaxItems: 500,
+		},
+		SeriesExpiry: time.Hour,
+	})
+}
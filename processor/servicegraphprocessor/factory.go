@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicegraphprocessor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configerror"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "servicegraph"
+)
+
+// defaultSeriesExpiry is the series expiry used if none is configured.
+const defaultSeriesExpiry = 5 * time.Minute
+
+var errMissingMetricsExporter = errors.New("\"metrics_exporter\" must be set")
+
+// defaultLatencyHistogramBuckets are the latency histogram bucket bounds used if none
+// is configured. They are not set in the default config since the decoding of a shorter
+// list on top of them would keep the trailing defaults.
+var defaultLatencyHistogramBuckets = []time.Duration{
+	2 * time.Millisecond, 4 * time.Millisecond, 6 * time.Millisecond, 8 * time.Millisecond,
+	10 * time.Millisecond, 50 * time.Millisecond, 100 * time.Millisecond, 200 * time.Millisecond,
+	400 * time.Millisecond, 800 * time.Millisecond, time.Second, 1400 * time.Millisecond,
+	2 * time.Second, 5 * time.Second, 10 * time.Second, 15 * time.Second,
+}
+
+// Factory is the factory for the service graph processor.
+type Factory struct {
+}
+
+// Type gets the type of the config created by this factory.
+func (*Factory) Type() configmodels.Type {
+	return typeStr
+}
+
+// CreateDefaultConfig creates the default configuration for processor.
+// Note: This isn't a valid configuration because "metrics_exporter" is required.
+func (*Factory) CreateDefaultConfig() configmodels.Processor {
+	return &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
+		Store: StoreConfig{
+			TTL:      2 * time.Second,
+			MaxItems: 1000,
+		},
+	}
+}
+
+// CreateTraceProcessor creates a trace processor based on this config.
+func (*Factory) CreateTraceProcessor(
+	ctx context.Context,
+	params component.ProcessorCreateParams,
+	nextConsumer consumer.TraceConsumer,
+	cfg configmodels.Processor,
+) (component.TraceProcessor, error) {
+	pCfg := cfg.(*Config)
+	if pCfg.MetricsExporter == "" {
+		return nil, errMissingMetricsExporter
+	}
+	return newProcessor(params.Logger, nextConsumer, *pCfg)
+}
+
+// CreateMetricsProcessor creates a metrics processor based on this config.
+func (*Factory) CreateMetricsProcessor(
+	ctx context.Context,
+	params component.ProcessorCreateParams,
+	nextConsumer consumer.MetricsConsumer,
+	cfg configmodels.Processor,
+) (component.MetricsProcessor, error) {
+	return nil, configerror.ErrDataTypeIsNotSupported
+}
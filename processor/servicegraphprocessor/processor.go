@@ -0,0 +1,389 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicegraphprocessor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/internal/data"
+	"go.opentelemetry.io/collector/internal/processor/timeseries"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+const (
+	requestsMetricName       = "service_graph_request_total"
+	failedRequestsMetricName = "service_graph_request_failed_total"
+	latencyMetricName        = "service_graph_request_duration"
+
+	clientLabel = "client"
+	serverLabel = "server"
+
+	// sweepsPerExpiry is how many times per series expiry the idle edges are removed.
+	sweepsPerExpiry = 10
+)
+
+// side identifies the role of a span in a request between services.
+type side int
+
+const (
+	sideNone side = iota
+	sideClient
+	sideServer
+)
+
+// edgeMetrics holds the cumulative metric values of the requests between two services.
+type edgeMetrics struct {
+	clientService string
+	serverService string
+	// start is the start time of the cumulative values.
+	start time.Time
+	// lastSeen is the time of the last request of the edge, used to expire it.
+	lastSeen time.Time
+	// updated is true if a request was completed since the metrics were last sent.
+	updated bool
+
+	requests     int64
+	failed       int64
+	latencySum   float64
+	bucketCounts []uint64
+}
+
+type processorImp struct {
+	lock   sync.Mutex
+	logger *zap.Logger
+	config Config
+
+	nextConsumer    consumer.TraceConsumer
+	metricsExporter consumer.MetricsConsumer
+
+	store *store
+	// latencyBounds are the latency histogram bucket bounds in milliseconds.
+	latencyBounds []float64
+	seriesExpiry  time.Duration
+	edges         map[string]*edgeMetrics
+	lastSweep     time.Time
+}
+
+func newProcessor(logger *zap.Logger, nextConsumer consumer.TraceConsumer, cfg Config) (*processorImp, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	if cfg.Store.TTL <= 0 || cfg.Store.MaxItems <= 0 {
+		return nil, fmt.Errorf("\"store\" \"ttl\" and \"max_items\" must be greater than zero")
+	}
+
+	buckets := cfg.LatencyHistogramBuckets
+	if len(buckets) == 0 {
+		buckets = defaultLatencyHistogramBuckets
+	}
+	latencyBounds := make([]float64, len(buckets))
+	for i, bucket := range buckets {
+		latencyBounds[i] = float64(bucket) / float64(time.Millisecond)
+	}
+	if !sort.Float64sAreSorted(latencyBounds) {
+		return nil, fmt.Errorf("\"latency_histogram_buckets\" must be sorted in increasing order")
+	}
+
+	seriesExpiry := cfg.SeriesExpiry
+	if seriesExpiry < 0 {
+		return nil, fmt.Errorf("\"series_expiry\" must not be negative")
+	}
+	if seriesExpiry == 0 {
+		seriesExpiry = defaultSeriesExpiry
+	}
+
+	return &processorImp{
+		logger:        logger,
+		config:        cfg,
+		nextConsumer:  nextConsumer,
+		store:         newStore(cfg.Store.TTL, cfg.Store.MaxItems),
+		latencyBounds: latencyBounds,
+		seriesExpiry:  seriesExpiry,
+		edges:         make(map[string]*edgeMetrics),
+	}, nil
+}
+
+// Start looks for the metrics exporter that receives the generated metrics.
+func (p *processorImp) Start(ctx context.Context, host component.Host) error {
+	exporters := host.GetExporters()[configmodels.MetricsDataType]
+	for cfg, exp := range exporters {
+		if cfg.Name() != p.config.MetricsExporter {
+			continue
+		}
+		metricsExporter, ok := exp.(consumer.MetricsConsumer)
+		if !ok {
+			return fmt.Errorf("the exporter %q isn't a metrics exporter", p.config.MetricsExporter)
+		}
+		p.metricsExporter = metricsExporter
+		return nil
+	}
+	return fmt.Errorf("failed to find metrics exporter %q, it must be configured in a metrics pipeline", p.config.MetricsExporter)
+}
+
+// Shutdown is invoked during service shutdown.
+func (p *processorImp) Shutdown(context.Context) error {
+	return nil
+}
+
+func (p *processorImp) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: false}
+}
+
+// ConsumeTraces pairs the client and server spans, sends the metrics of the edges
+// updated by the completed requests to the metrics exporter and forwards the traces
+// to the next consumer.
+func (p *processorImp) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	if md, ok := p.aggregateEdges(td, time.Now()); ok {
+		if err := p.metricsExporter.ConsumeMetrics(ctx, md); err != nil {
+			p.logger.Warn("Failed to export service graph metrics", zap.Error(err))
+		}
+	}
+	return p.nextConsumer.ConsumeTraces(ctx, td)
+}
+
+// aggregateEdges pairs the spans of the given traces and returns the metrics of the
+// edges updated by the completed requests, it returns false if no request was
+// completed.
+func (p *processorImp) aggregateEdges(td pdata.Traces, now time.Time) (pdata.Metrics, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.sweep(now)
+	if expired := p.store.expire(now); expired > 0 {
+		p.logger.Debug("Discarded requests without the span of the other side", zap.Int("count", expired))
+	}
+
+	completed := false
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		serviceName := ""
+		if !rs.Resource().IsNil() {
+			if attr, ok := rs.Resource().Attributes().Get(conventions.AttributeServiceName); ok {
+				serviceName = attr.StringVal()
+			}
+		}
+
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
+			}
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
+				}
+				e, err := p.upsertEdge(serviceName, span, now)
+				if err != nil {
+					p.logger.Debug("Discarded span of request between services", zap.Error(err))
+					continue
+				}
+				if e != nil {
+					p.aggregateEdge(e, now)
+					completed = true
+				}
+			}
+		}
+	}
+	if !completed {
+		return pdata.Metrics{}, false
+	}
+	return p.buildMetrics(now), true
+}
+
+// upsertEdge adds the span to its request, if it is the client or the server side of one,
+// and returns the request if it is complete.
+func (p *processorImp) upsertEdge(serviceName string, span pdata.Span, now time.Time) (*edge, error) {
+	failed := !span.Status().IsNil() && span.Status().Code() != pdata.StatusCode(0)
+	traceID := string(span.TraceID().Bytes())
+
+	switch spanSide(span) {
+	case sideClient:
+		// The server span is a child of the client span.
+		key := traceID + string(span.SpanID().Bytes())
+		return p.store.upsert(key, now, func(e *edge) {
+			e.hasClient = true
+			e.clientService = serviceName
+			e.clientLatency = float64(span.EndTime()-span.StartTime()) / float64(time.Millisecond)
+			e.failed = e.failed || failed
+		})
+	case sideServer:
+		if len(span.ParentSpanID().Bytes()) == 0 {
+			// Without parent, no client to pair with.
+			return nil, nil
+		}
+		key := traceID + string(span.ParentSpanID().Bytes())
+		return p.store.upsert(key, now, func(e *edge) {
+			e.hasServer = true
+			e.serverService = serviceName
+			e.failed = e.failed || failed
+		})
+	}
+	return nil, nil
+}
+
+func (p *processorImp) aggregateEdge(e *edge, now time.Time) {
+	key := e.clientService + timeseries.KeySeparator + e.serverService
+	em, ok := p.edges[key]
+	if !ok {
+		em = &edgeMetrics{
+			clientService: e.clientService,
+			serverService: e.serverService,
+			start:         now,
+			bucketCounts:  make([]uint64, len(p.latencyBounds)+1),
+		}
+		p.edges[key] = em
+	}
+	em.lastSeen = now
+	em.updated = true
+	em.requests++
+	if e.failed {
+		em.failed++
+	}
+	em.latencySum += e.clientLatency
+	// The bounds are the inclusive upper bounds of the buckets.
+	em.bucketCounts[sort.SearchFloat64s(p.latencyBounds, e.clientLatency)]++
+}
+
+// buildMetrics returns the metrics of the edges updated since the last call,
+// cumulative since the start of each edge.
+func (p *processorImp) buildMetrics(now time.Time) pdata.Metrics {
+	var updated []*edgeMetrics
+	for _, em := range p.edges {
+		if em.updated {
+			updated = append(updated, em)
+			em.updated = false
+		}
+	}
+	timestamp := pdata.TimestampUnixNano(uint64(now.UnixNano()))
+
+	md := data.NewMetricData()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	ilm := rm.InstrumentationLibraryMetrics().At(0)
+	ilm.InstrumentationLibrary().InitEmpty()
+	ilm.InstrumentationLibrary().SetName(typeStr)
+	ilm.Metrics().Resize(3)
+
+	requests := ilm.Metrics().At(0)
+	initDescriptor(requests, requestsMetricName, "Count of requests between services", "1", pdata.MetricTypeMonotonicInt64)
+	failed := ilm.Metrics().At(1)
+	initDescriptor(failed, failedRequestsMetricName, "Count of failed requests between services", "1", pdata.MetricTypeMonotonicInt64)
+	latency := ilm.Metrics().At(2)
+	initDescriptor(latency, latencyMetricName, "Duration of the requests between services as seen by the client", "ms", pdata.MetricTypeHistogram)
+
+	requests.Int64DataPoints().Resize(len(updated))
+	failed.Int64DataPoints().Resize(len(updated))
+	latency.HistogramDataPoints().Resize(len(updated))
+	for i, em := range updated {
+		start := pdata.TimestampUnixNano(uint64(em.start.UnixNano()))
+		labels := map[string]string{
+			clientLabel: em.clientService,
+			serverLabel: em.serverService,
+		}
+
+		requestsDp := requests.Int64DataPoints().At(i)
+		requestsDp.SetStartTime(start)
+		requestsDp.SetTimestamp(timestamp)
+		requestsDp.SetValue(em.requests)
+		requestsDp.LabelsMap().InitFromMap(labels)
+
+		failedDp := failed.Int64DataPoints().At(i)
+		failedDp.SetStartTime(start)
+		failedDp.SetTimestamp(timestamp)
+		failedDp.SetValue(em.failed)
+		failedDp.LabelsMap().InitFromMap(labels)
+
+		latencyDp := latency.HistogramDataPoints().At(i)
+		latencyDp.SetStartTime(start)
+		latencyDp.SetTimestamp(timestamp)
+		latencyDp.SetCount(uint64(em.requests))
+		latencyDp.SetSum(em.latencySum)
+		latencyDp.SetExplicitBounds(p.latencyBounds)
+		latencyDp.Buckets().Resize(len(em.bucketCounts))
+		for j, count := range em.bucketCounts {
+			latencyDp.Buckets().At(j).SetCount(count)
+		}
+		latencyDp.LabelsMap().InitFromMap(labels)
+	}
+
+	return pdatautil.MetricsFromInternalMetrics(md)
+}
+
+// sweep removes the edges without any request over the series expiry, a few times
+// per expiry.
+func (p *processorImp) sweep(now time.Time) {
+	if now.Sub(p.lastSweep) < p.seriesExpiry/sweepsPerExpiry {
+		return
+	}
+	p.lastSweep = now
+	for key, em := range p.edges {
+		if now.Sub(em.lastSeen) > p.seriesExpiry {
+			delete(p.edges, key)
+		}
+	}
+}
+
+func initDescriptor(metric pdata.Metric, name, description, unit string, metricType pdata.MetricType) {
+	metric.MetricDescriptor().InitEmpty()
+	metric.MetricDescriptor().SetName(name)
+	metric.MetricDescriptor().SetDescription(description)
+	metric.MetricDescriptor().SetUnit(unit)
+	metric.MetricDescriptor().SetType(metricType)
+}
+
+// spanSide returns the side of the request between services of the span, based on its
+// kind or, if unspecified, its "span.kind" attribute. Producer and consumer spans are
+// considered client and server respectively.
+func spanSide(span pdata.Span) side {
+	switch span.Kind() {
+	case pdata.SpanKindCLIENT, pdata.SpanKindPRODUCER:
+		return sideClient
+	case pdata.SpanKindSERVER, pdata.SpanKindCONSUMER:
+		return sideServer
+	case pdata.SpanKindUNSPECIFIED:
+		attr, ok := span.Attributes().Get(tracetranslator.TagSpanKind)
+		if !ok || attr.Type() != pdata.AttributeValueSTRING {
+			return sideNone
+		}
+		switch tracetranslator.OpenTracingSpanKind(attr.StringVal()) {
+		case tracetranslator.OpenTracingSpanKindClient, tracetranslator.OpenTracingSpanKindProducer:
+			return sideClient
+		case tracetranslator.OpenTracingSpanKindServer, tracetranslator.OpenTracingSpanKindConsumer:
+			return sideServer
+		}
+	}
+	return sideNone
+}
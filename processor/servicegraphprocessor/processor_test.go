@@ -0,0 +1,300 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicegraphprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+type mockHost struct {
+	componenttest.NopHost
+	exporters map[configmodels.DataType]map[configmodels.Exporter]component.Exporter
+}
+
+func (h *mockHost) GetExporters() map[configmodels.DataType]map[configmodels.Exporter]component.Exporter {
+	return h.exporters
+}
+
+func newMockHost(dataType configmodels.DataType, name string, exp component.Exporter) *mockHost {
+	return &mockHost{
+		exporters: map[configmodels.DataType]map[configmodels.Exporter]component.Exporter{
+			dataType: {
+				&configmodels.ExporterSettings{TypeVal: "mock", NameVal: name}: exp,
+			},
+		},
+	}
+}
+
+func testConfig() Config {
+	return Config{
+		MetricsExporter:         "mock",
+		LatencyHistogramBuckets: []time.Duration{10 * time.Millisecond, 100 * time.Millisecond},
+		Store: StoreConfig{
+			TTL:      time.Minute,
+			MaxItems: 10,
+		},
+	}
+}
+
+func TestProcessorStart(t *testing.T) {
+	tests := []struct {
+		name    string
+		host    component.Host
+		wantErr bool
+	}{
+		{
+			name: "metrics_exporter",
+			host: newMockHost(configmodels.MetricsDataType, "mock", &exportertest.SinkMetricsExporter{}),
+		},
+		{
+			name:    "missing_exporter",
+			host:    newMockHost(configmodels.MetricsDataType, "other", &exportertest.SinkMetricsExporter{}),
+			wantErr: true,
+		},
+		{
+			name:    "not_a_metrics_exporter",
+			host:    newMockHost(configmodels.MetricsDataType, "mock", &exportertest.SinkTraceExporter{}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := newProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, testConfig())
+			require.NoError(t, err)
+			err = p.Start(context.Background(), tt.host)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNewProcessorInvalidConfig(t *testing.T) {
+	_, err := newProcessor(zap.NewNop(), nil, testConfig())
+	assert.Error(t, err)
+
+	cfg := testConfig()
+	cfg.LatencyHistogramBuckets = []time.Duration{time.Second, time.Millisecond}
+	_, err = newProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, cfg)
+	assert.Error(t, err)
+
+	cfg = testConfig()
+	cfg.Store.MaxItems = 0
+	_, err = newProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, cfg)
+	assert.Error(t, err)
+
+	cfg = testConfig()
+	cfg.SeriesExpiry = -time.Minute
+	_, err = newProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, cfg)
+	assert.Error(t, err)
+}
+
+func TestProcessorConsumeTraces(t *testing.T) {
+	tracesSink := &exportertest.SinkTraceExporter{}
+	metricsSink := &exportertest.SinkMetricsExporter{}
+	p, err := newProcessor(zap.NewNop(), tracesSink, testConfig())
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), newMockHost(configmodels.MetricsDataType, "mock", metricsSink)))
+
+	traceID := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+
+	// The client spans arrive first, without metrics since no request is complete.
+	clientTd := newTraces("frontend",
+		newTestSpan(traceID, []byte{1}, nil, pdata.SpanKindCLIENT, pdata.StatusCode(0), 50*time.Millisecond),
+		newTestSpan(traceID, []byte{2}, nil, pdata.SpanKindCLIENT, pdata.StatusCode(0), 5*time.Millisecond),
+		// Internal spans are ignored.
+		newTestSpan(traceID, []byte{3}, nil, pdata.SpanKindINTERNAL, pdata.StatusCode(0), time.Millisecond),
+	)
+	require.NoError(t, p.ConsumeTraces(context.Background(), clientTd))
+	assert.Len(t, metricsSink.AllMetrics(), 0)
+	assert.Equal(t, 2, p.store.len())
+
+	// The server span of the second request uses the "span.kind" attribute.
+	serverSpan := newTestSpan(traceID, []byte{5}, []byte{2}, pdata.SpanKindUNSPECIFIED, pdata.StatusCode(2), time.Millisecond)
+	serverSpan.Attributes().InsertString(tracetranslator.TagSpanKind, string(tracetranslator.OpenTracingSpanKindServer))
+	serverTd := newTraces("backend",
+		newTestSpan(traceID, []byte{4}, []byte{1}, pdata.SpanKindSERVER, pdata.StatusCode(0), 40*time.Millisecond),
+		serverSpan,
+	)
+	require.NoError(t, p.ConsumeTraces(context.Background(), serverTd))
+	assert.Equal(t, 0, p.store.len())
+
+	// The traces are forwarded as is.
+	require.Len(t, tracesSink.AllTraces(), 2)
+	assert.Equal(t, 3, tracesSink.AllTraces()[0].SpanCount())
+
+	allMetrics := metricsSink.AllMetrics()
+	require.Len(t, allMetrics, 1)
+	md := pdatautil.MetricsToInternalMetrics(allMetrics[0])
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 3, metrics.Len())
+
+	requests := metrics.At(0)
+	assert.Equal(t, requestsMetricName, requests.MetricDescriptor().Name())
+	require.Equal(t, 1, requests.Int64DataPoints().Len())
+	requestsDp := requests.Int64DataPoints().At(0)
+	assert.Equal(t, map[string]string{clientLabel: "frontend", serverLabel: "backend"}, labelsToMap(requestsDp.LabelsMap()))
+	assert.Equal(t, int64(2), requestsDp.Value())
+
+	failed := metrics.At(1)
+	assert.Equal(t, failedRequestsMetricName, failed.MetricDescriptor().Name())
+	require.Equal(t, 1, failed.Int64DataPoints().Len())
+	assert.Equal(t, int64(1), failed.Int64DataPoints().At(0).Value())
+
+	latency := metrics.At(2)
+	assert.Equal(t, latencyMetricName, latency.MetricDescriptor().Name())
+	require.Equal(t, 1, latency.HistogramDataPoints().Len())
+	latencyDp := latency.HistogramDataPoints().At(0)
+	assert.Equal(t, uint64(2), latencyDp.Count())
+	// The latency is the one seen by the client.
+	assert.InDelta(t, 55, latencyDp.Sum(), 1e-9)
+	assert.Equal(t, []float64{10, 100}, latencyDp.ExplicitBounds())
+	assert.Equal(t, []uint64{1, 1, 0}, bucketCounts(latencyDp))
+}
+
+func TestProcessorExpireEdges(t *testing.T) {
+	cfg := testConfig()
+	cfg.SeriesExpiry = time.Minute
+	p, err := newProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, cfg)
+	require.NoError(t, err)
+
+	traceID := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	// request returns the traces of a complete request from the client to the server.
+	request := func(client, server string, spanID byte) pdata.Traces {
+		td := newTraces(client, newTestSpan(traceID, []byte{spanID}, nil, pdata.SpanKindCLIENT, pdata.StatusCode(0), time.Millisecond))
+		newTraces(server, newTestSpan(traceID, []byte{spanID + 1}, []byte{spanID}, pdata.SpanKindSERVER, pdata.StatusCode(0), time.Millisecond)).
+			ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+		return td
+	}
+	edgeRequests := func(md pdata.Metrics) map[string]int64 {
+		requests := pdatautil.MetricsToInternalMetrics(md).ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+		got := make(map[string]int64)
+		for i := 0; i < requests.Int64DataPoints().Len(); i++ {
+			dp := requests.Int64DataPoints().At(i)
+			labels := labelsToMap(dp.LabelsMap())
+			got[labels[clientLabel]+"->"+labels[serverLabel]] = dp.Value()
+		}
+		return got
+	}
+
+	now := time.Unix(1000, 0)
+	_, ok := p.aggregateEdges(request("frontend", "backend", 1), now)
+	require.True(t, ok)
+	_, ok = p.aggregateEdges(request("frontend", "auth", 3), now)
+	require.True(t, ok)
+
+	// Only the edges updated by the requests are sent.
+	md, ok := p.aggregateEdges(request("frontend", "auth", 5), now.Add(50*time.Second))
+	require.True(t, ok)
+	assert.Equal(t, map[string]int64{"frontend->auth": 2}, edgeRequests(md))
+
+	// The edge idle for the whole expiry starts again from zero.
+	md, ok = p.aggregateEdges(request("frontend", "backend", 7), now.Add(70*time.Second))
+	require.True(t, ok)
+	assert.Len(t, p.edges, 2)
+	assert.Equal(t, map[string]int64{"frontend->backend": 1}, edgeRequests(md))
+
+	_, ok = p.aggregateEdges(pdata.NewTraces(), now.Add(3*time.Minute))
+	assert.False(t, ok)
+	assert.Len(t, p.edges, 0)
+}
+
+func TestStore(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := newStore(time.Second, 2)
+
+	e, err := s.upsert("a", now, func(e *edge) { e.hasClient = true })
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+	e, err = s.upsert("b", now.Add(500*time.Millisecond), func(e *edge) { e.hasServer = true })
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+
+	// New edges are refused while the store is full.
+	_, err = s.upsert("c", now, func(e *edge) { e.hasClient = true })
+	assert.Equal(t, errStoreFull, err)
+
+	// Completed edges are returned and removed.
+	e, err = s.upsert("b", now, func(e *edge) { e.hasClient = true })
+	assert.NoError(t, err)
+	require.NotNil(t, e)
+	assert.Equal(t, 1, s.len())
+
+	assert.Equal(t, 0, s.expire(now.Add(999*time.Millisecond)))
+	assert.Equal(t, 1, s.expire(now.Add(time.Second)))
+	assert.Equal(t, 0, s.len())
+}
+
+func newTraces(serviceName string, spans ...pdata.Span) pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().InitEmpty()
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, serviceName)
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	for i := range spans {
+		ils.Spans().Append(&spans[i])
+	}
+	return td
+}
+
+func newTestSpan(traceID, spanID, parentSpanID []byte, kind pdata.SpanKind, code pdata.StatusCode, duration time.Duration) pdata.Span {
+	span := pdata.NewSpan()
+	span.InitEmpty()
+	span.SetTraceID(pdata.NewTraceID(traceID))
+	span.SetSpanID(pdata.NewSpanID(spanID))
+	span.SetParentSpanID(pdata.NewSpanID(parentSpanID))
+	span.SetKind(kind)
+	span.Status().InitEmpty()
+	span.Status().SetCode(code)
+	start := time.Unix(1000, 0)
+	span.SetStartTime(pdata.TimestampUnixNano(uint64(start.UnixNano())))
+	span.SetEndTime(pdata.TimestampUnixNano(uint64(start.Add(duration).UnixNano())))
+	return span
+}
+
+func labelsToMap(labels pdata.StringMap) map[string]string {
+	m := make(map[string]string)
+	labels.ForEach(func(k string, v pdata.StringValue) {
+		m[k] = v.Value()
+	})
+	return m
+}
+
+func bucketCounts(dp pdata.HistogramDataPoint) []uint64 {
+	counts := make([]uint64, dp.Buckets().Len())
+	for i := range counts {
+		counts[i] = dp.Buckets().At(i).Count()
+	}
+	return counts
+}
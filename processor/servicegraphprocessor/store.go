@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicegraphprocessor
+
+import (
+	"container/list"
+	"errors"
+	"time"
+)
+
+var errStoreFull = errors.New("the store is full")
+
+// edge is a request between two services, completed once both the client and
+// the server spans were received.
+type edge struct {
+	key string
+
+	clientService string
+	serverService string
+	// clientLatency is the duration, in milliseconds, of the request as seen by the client.
+	clientLatency float64
+	failed        bool
+
+	hasClient bool
+	hasServer bool
+
+	expiration time.Time
+}
+
+func (e *edge) isComplete() bool {
+	return e.hasClient && e.hasServer
+}
+
+// store keeps the edges waiting for the span of the other side, bounded in size
+// and in time. It is not safe for concurrent use.
+type store struct {
+	l        *list.List
+	m        map[string]*list.Element
+	ttl      time.Duration
+	maxItems int
+}
+
+func newStore(ttl time.Duration, maxItems int) *store {
+	return &store{
+		l:        list.New(),
+		m:        make(map[string]*list.Element),
+		ttl:      ttl,
+		maxItems: maxItems,
+	}
+}
+
+// upsert updates the edge with the given key, creating it if needed, and returns it
+// if it is complete, in which case the edge is removed from the store.
+func (s *store) upsert(key string, now time.Time, update func(e *edge)) (*edge, error) {
+	if elem, ok := s.m[key]; ok {
+		e := elem.Value.(*edge)
+		update(e)
+		if !e.isComplete() {
+			return nil, nil
+		}
+		s.l.Remove(elem)
+		delete(s.m, key)
+		return e, nil
+	}
+
+	e := &edge{key: key}
+	update(e)
+	if e.isComplete() {
+		return e, nil
+	}
+	if s.l.Len() >= s.maxItems {
+		return nil, errStoreFull
+	}
+	e.expiration = now.Add(s.ttl)
+	s.m[key] = s.l.PushBack(e)
+	return nil, nil
+}
+
+// expire removes the edges that expired at the given time, and returns how many.
+func (s *store) expire(now time.Time) int {
+	expired := 0
+	// The edges are sorted by expiration since all have the same TTL.
+	for elem := s.l.Front(); elem != nil; elem = s.l.Front() {
+		e := elem.Value.(*edge)
+		if now.Before(e.expiration) {
+			break
+		}
+		s.l.Remove(elem)
+		delete(s.m, e.key)
+		expired++
+	}
+	return expired
+}
+
+func (s *store) len() int {
+	return s.l.Len()
+}
@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Dimension defines a span attribute added as a label to the generated metrics.
+type Dimension struct {
+	// Name of the span, or resource, attribute.
+	Name string `mapstructure:"name"`
+	// Default is the label value used when the attribute is missing. If nil the
+	// label is omitted for such spans.
+	Default *string `mapstructure:"default"`
+}
+
+// Config defines the configuration for the span metrics processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// MetricsExporter is the name of the exporter, configured in a metrics pipeline,
+	// that receives the generated metrics.
+	MetricsExporter string `mapstructure:"metrics_exporter"`
+
+	// LatencyHistogramBuckets are the upper bounds of the buckets of the latency histogram,
+	// if empty a default set of buckets from 2ms to 15s is used.
+	LatencyHistogramBuckets []time.Duration `mapstructure:"latency_histogram_buckets"`
+
+	// Dimensions are the additional labels of the metrics, taken from the span attributes,
+	// or from the resource attributes if the span doesn't have it.
+	Dimensions []Dimension `mapstructure:"dimensions"`
+
+	// SeriesExpiry is the duration after which the metrics of a combination of labels
+	// without any span are forgotten, if zero a default of 5m is used.
+	SeriesExpiry time.Duration `mapstructure:"series_expiry"`
+}
@@ -0,0 +1,355 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsprocessor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/internal/data"
+	"go.opentelemetry.io/collector/internal/processor/timeseries"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+const (
+	callsMetricName   = "calls_total"
+	latencyMetricName = "latency"
+
+	serviceNameLabel = "service.name"
+	operationLabel   = "operation"
+	spanKindLabel    = "span.kind"
+	statusCodeLabel  = "status.code"
+
+	// sweepsPerExpiry is how many times per series expiry the idle aggregates are
+	// removed.
+	sweepsPerExpiry = 10
+)
+
+// metricKey identifies the aggregated metrics of a unique combination of labels.
+type metricKey string
+
+// aggregate holds the cumulative metric values of a unique combination of labels.
+type aggregate struct {
+	labels map[string]string
+	// start is the start time of the cumulative values.
+	start time.Time
+	// lastSeen is the time of the last span of the aggregate, used to expire it.
+	lastSeen time.Time
+	// updated is true if a span was aggregated since the metrics were last sent.
+	updated bool
+
+	calls        int64
+	latencySum   float64
+	bucketCounts []uint64
+}
+
+type processorImp struct {
+	lock   sync.Mutex
+	logger *zap.Logger
+	config Config
+
+	nextConsumer    consumer.TraceConsumer
+	metricsExporter consumer.MetricsConsumer
+
+	// latencyBounds are the latency histogram bucket bounds in milliseconds.
+	latencyBounds []float64
+	seriesExpiry  time.Duration
+	aggregates    map[metricKey]*aggregate
+	lastSweep     time.Time
+}
+
+func newProcessor(logger *zap.Logger, nextConsumer consumer.TraceConsumer, cfg Config) (*processorImp, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+
+	buckets := cfg.LatencyHistogramBuckets
+	if len(buckets) == 0 {
+		buckets = defaultLatencyHistogramBuckets
+	}
+	latencyBounds := make([]float64, len(buckets))
+	for i, bucket := range buckets {
+		latencyBounds[i] = durationToMillis(bucket)
+	}
+	if !sort.Float64sAreSorted(latencyBounds) {
+		return nil, fmt.Errorf("\"latency_histogram_buckets\" must be sorted in increasing order")
+	}
+
+	seriesExpiry := cfg.SeriesExpiry
+	if seriesExpiry < 0 {
+		return nil, fmt.Errorf("\"series_expiry\" must not be negative")
+	}
+	if seriesExpiry == 0 {
+		seriesExpiry = defaultSeriesExpiry
+	}
+
+	seen := make(map[string]bool)
+	for _, label := range []string{serviceNameLabel, operationLabel, spanKindLabel, statusCodeLabel} {
+		seen[label] = true
+	}
+	for _, dim := range cfg.Dimensions {
+		if seen[dim.Name] {
+			return nil, fmt.Errorf("duplicated dimension %q", dim.Name)
+		}
+		seen[dim.Name] = true
+	}
+
+	return &processorImp{
+		logger:        logger,
+		config:        cfg,
+		nextConsumer:  nextConsumer,
+		latencyBounds: latencyBounds,
+		seriesExpiry:  seriesExpiry,
+		aggregates:    make(map[metricKey]*aggregate),
+	}, nil
+}
+
+// Start looks for the metrics exporter that receives the generated metrics.
+func (p *processorImp) Start(ctx context.Context, host component.Host) error {
+	exporters := host.GetExporters()[configmodels.MetricsDataType]
+	for cfg, exp := range exporters {
+		if cfg.Name() != p.config.MetricsExporter {
+			continue
+		}
+		metricsExporter, ok := exp.(consumer.MetricsConsumer)
+		if !ok {
+			return fmt.Errorf("the exporter %q isn't a metrics exporter", p.config.MetricsExporter)
+		}
+		p.metricsExporter = metricsExporter
+		return nil
+	}
+	return fmt.Errorf("failed to find metrics exporter %q, it must be configured in a metrics pipeline", p.config.MetricsExporter)
+}
+
+// Shutdown is invoked during service shutdown.
+func (p *processorImp) Shutdown(context.Context) error {
+	return nil
+}
+
+func (p *processorImp) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: false}
+}
+
+// ConsumeTraces aggregates the spans into the metrics, sends the metrics updated by the
+// spans to the metrics exporter and forwards the traces to the next consumer.
+func (p *processorImp) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	if md, ok := p.aggregateMetrics(td, time.Now()); ok {
+		if err := p.metricsExporter.ConsumeMetrics(ctx, md); err != nil {
+			p.logger.Warn("Failed to export span metrics", zap.Error(err))
+		}
+	}
+	return p.nextConsumer.ConsumeTraces(ctx, td)
+}
+
+// aggregateMetrics aggregates the spans into the metrics and returns the metrics they
+// updated, it returns false if there is no span.
+func (p *processorImp) aggregateMetrics(td pdata.Traces, now time.Time) (pdata.Metrics, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.sweep(now)
+	updated := false
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		resourceAttrs := pdata.NewAttributeMap()
+		if !rs.Resource().IsNil() {
+			resourceAttrs = rs.Resource().Attributes()
+		}
+		serviceName := ""
+		if attr, ok := resourceAttrs.Get(conventions.AttributeServiceName); ok {
+			serviceName = attr.StringVal()
+		}
+
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
+			}
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
+				}
+				p.aggregateSpan(serviceName, resourceAttrs, span, now)
+				updated = true
+			}
+		}
+	}
+	if !updated {
+		return pdata.Metrics{}, false
+	}
+	return p.buildMetrics(now), true
+}
+
+func (p *processorImp) aggregateSpan(serviceName string, resourceAttrs pdata.AttributeMap, span pdata.Span, now time.Time) {
+	statusCode := pdata.StatusCode(0)
+	if !span.Status().IsNil() {
+		statusCode = span.Status().Code()
+	}
+
+	labels := map[string]string{
+		serviceNameLabel: serviceName,
+		operationLabel:   span.Name(),
+		spanKindLabel:    span.Kind().String(),
+		statusCodeLabel:  statusCode.String(),
+	}
+	var sb strings.Builder
+	for _, v := range []string{serviceName, span.Name(), labels[spanKindLabel], labels[statusCodeLabel]} {
+		sb.WriteString(v)
+		sb.WriteString(timeseries.KeySeparator)
+	}
+	for _, dim := range p.config.Dimensions {
+		value, ok := dimensionValue(dim, span.Attributes(), resourceAttrs)
+		if ok {
+			labels[dim.Name] = value
+			// Distinguish missing dimensions from empty values.
+			sb.WriteString("+")
+			sb.WriteString(value)
+		}
+		sb.WriteString(timeseries.KeySeparator)
+	}
+	key := metricKey(sb.String())
+
+	agg, ok := p.aggregates[key]
+	if !ok {
+		agg = &aggregate{
+			labels:       labels,
+			start:        now,
+			bucketCounts: make([]uint64, len(p.latencyBounds)+1),
+		}
+		p.aggregates[key] = agg
+	}
+	agg.lastSeen = now
+	agg.updated = true
+
+	latency := float64(span.EndTime()-span.StartTime()) / float64(time.Millisecond)
+	agg.calls++
+	agg.latencySum += latency
+	// The bounds are the inclusive upper bounds of the buckets.
+	agg.bucketCounts[sort.SearchFloat64s(p.latencyBounds, latency)]++
+}
+
+// buildMetrics returns the metrics of the aggregates updated since the last call,
+// cumulative since the start of each aggregate.
+func (p *processorImp) buildMetrics(now time.Time) pdata.Metrics {
+	var updated []*aggregate
+	for _, agg := range p.aggregates {
+		if agg.updated {
+			updated = append(updated, agg)
+			agg.updated = false
+		}
+	}
+	timestamp := pdata.TimestampUnixNano(uint64(now.UnixNano()))
+
+	md := data.NewMetricData()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	ilm := rm.InstrumentationLibraryMetrics().At(0)
+	ilm.InstrumentationLibrary().InitEmpty()
+	ilm.InstrumentationLibrary().SetName(typeStr)
+	ilm.Metrics().Resize(2)
+
+	calls := ilm.Metrics().At(0)
+	calls.MetricDescriptor().InitEmpty()
+	calls.MetricDescriptor().SetName(callsMetricName)
+	calls.MetricDescriptor().SetDescription("Count of spans")
+	calls.MetricDescriptor().SetUnit("1")
+	calls.MetricDescriptor().SetType(pdata.MetricTypeMonotonicInt64)
+
+	latency := ilm.Metrics().At(1)
+	latency.MetricDescriptor().InitEmpty()
+	latency.MetricDescriptor().SetName(latencyMetricName)
+	latency.MetricDescriptor().SetDescription("Duration of the spans")
+	latency.MetricDescriptor().SetUnit("ms")
+	latency.MetricDescriptor().SetType(pdata.MetricTypeHistogram)
+
+	calls.Int64DataPoints().Resize(len(updated))
+	latency.HistogramDataPoints().Resize(len(updated))
+	for i, agg := range updated {
+		start := pdata.TimestampUnixNano(uint64(agg.start.UnixNano()))
+		callsDp := calls.Int64DataPoints().At(i)
+		callsDp.SetStartTime(start)
+		callsDp.SetTimestamp(timestamp)
+		callsDp.SetValue(agg.calls)
+		callsDp.LabelsMap().InitFromMap(agg.labels)
+
+		latencyDp := latency.HistogramDataPoints().At(i)
+		latencyDp.SetStartTime(start)
+		latencyDp.SetTimestamp(timestamp)
+		latencyDp.SetCount(uint64(agg.calls))
+		latencyDp.SetSum(agg.latencySum)
+		latencyDp.SetExplicitBounds(p.latencyBounds)
+		latencyDp.Buckets().Resize(len(agg.bucketCounts))
+		for j, count := range agg.bucketCounts {
+			latencyDp.Buckets().At(j).SetCount(count)
+		}
+		latencyDp.LabelsMap().InitFromMap(agg.labels)
+	}
+
+	return pdatautil.MetricsFromInternalMetrics(md)
+}
+
+// sweep removes the aggregates without any span over the series expiry, a few times
+// per expiry.
+func (p *processorImp) sweep(now time.Time) {
+	if now.Sub(p.lastSweep) < p.seriesExpiry/sweepsPerExpiry {
+		return
+	}
+	p.lastSweep = now
+	for key, agg := range p.aggregates {
+		if now.Sub(agg.lastSeen) > p.seriesExpiry {
+			delete(p.aggregates, key)
+		}
+	}
+}
+
+// dimensionValue returns the value of the dimension from the span attributes, the
+// resource attributes or its default, in that order.
+func dimensionValue(dim Dimension, spanAttrs, resourceAttrs pdata.AttributeMap) (string, bool) {
+	if attr, ok := spanAttrs.Get(dim.Name); ok {
+		return timeseries.AttributeValueString(attr), true
+	}
+	if attr, ok := resourceAttrs.Get(dim.Name); ok {
+		return timeseries.AttributeValueString(attr), true
+	}
+	if dim.Default != nil {
+		return *dim.Default, true
+	}
+	return "", false
+}
+
+func durationToMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
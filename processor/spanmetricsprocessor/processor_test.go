@@ -0,0 +1,276 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+type mockHost struct {
+	componenttest.NopHost
+	exporters map[configmodels.DataType]map[configmodels.Exporter]component.Exporter
+}
+
+func (h *mockHost) GetExporters() map[configmodels.DataType]map[configmodels.Exporter]component.Exporter {
+	return h.exporters
+}
+
+func newMockHost(dataType configmodels.DataType, name string, exp component.Exporter) *mockHost {
+	return &mockHost{
+		exporters: map[configmodels.DataType]map[configmodels.Exporter]component.Exporter{
+			dataType: {
+				&configmodels.ExporterSettings{TypeVal: "mock", NameVal: name}: exp,
+			},
+		},
+	}
+}
+
+func TestProcessorStart(t *testing.T) {
+	tests := []struct {
+		name    string
+		host    component.Host
+		wantErr bool
+	}{
+		{
+			name: "metrics_exporter",
+			host: newMockHost(configmodels.MetricsDataType, "mock", &exportertest.SinkMetricsExporter{}),
+		},
+		{
+			name:    "missing_exporter",
+			host:    newMockHost(configmodels.MetricsDataType, "other", &exportertest.SinkMetricsExporter{}),
+			wantErr: true,
+		},
+		{
+			name:    "exporter_in_traces_pipeline",
+			host:    newMockHost(configmodels.TracesDataType, "mock", &exportertest.SinkTraceExporter{}),
+			wantErr: true,
+		},
+		{
+			name:    "not_a_metrics_exporter",
+			host:    newMockHost(configmodels.MetricsDataType, "mock", &exportertest.SinkTraceExporter{}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := newProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, Config{MetricsExporter: "mock"})
+			require.NoError(t, err)
+			err = p.Start(context.Background(), tt.host)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNewProcessorInvalidConfig(t *testing.T) {
+	_, err := newProcessor(zap.NewNop(), nil, Config{})
+	assert.Error(t, err)
+
+	_, err = newProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, Config{
+		LatencyHistogramBuckets: []time.Duration{time.Second, time.Millisecond},
+	})
+	assert.Error(t, err)
+
+	_, err = newProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, Config{
+		Dimensions: []Dimension{{Name: "a"}, {Name: "a"}},
+	})
+	assert.Error(t, err)
+
+	_, err = newProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, Config{
+		Dimensions: []Dimension{{Name: operationLabel}},
+	})
+	assert.Error(t, err)
+
+	_, err = newProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, Config{
+		SeriesExpiry: -time.Minute,
+	})
+	assert.Error(t, err)
+}
+
+func TestProcessorConsumeTraces(t *testing.T) {
+	defaultMethod := "GET"
+	cfg := Config{
+		MetricsExporter:         "mock",
+		LatencyHistogramBuckets: []time.Duration{10 * time.Millisecond, 100 * time.Millisecond},
+		Dimensions: []Dimension{
+			{Name: "http.method", Default: &defaultMethod},
+			{Name: "http.status_code"},
+		},
+	}
+	tracesSink := &exportertest.SinkTraceExporter{}
+	metricsSink := &exportertest.SinkMetricsExporter{}
+	p, err := newProcessor(zap.NewNop(), tracesSink, cfg)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), newMockHost(configmodels.MetricsDataType, "mock", metricsSink)))
+
+	td := newTraces("svc-a",
+		newTestSpan("GET /users", pdata.SpanKindSERVER, pdata.StatusCode(0), 5*time.Millisecond, map[string]pdata.AttributeValue{
+			"http.status_code": pdata.NewAttributeValueInt(200),
+		}),
+		newTestSpan("GET /users", pdata.SpanKindSERVER, pdata.StatusCode(0), 50*time.Millisecond, map[string]pdata.AttributeValue{
+			"http.status_code": pdata.NewAttributeValueInt(200),
+		}),
+		newTestSpan("GET /users", pdata.SpanKindSERVER, pdata.StatusCode(0), 500*time.Millisecond, map[string]pdata.AttributeValue{
+			"http.method": pdata.NewAttributeValueString("POST"),
+		}),
+	)
+	require.NoError(t, p.ConsumeTraces(context.Background(), td))
+	require.NoError(t, p.ConsumeTraces(context.Background(), td))
+
+	// The traces are forwarded as is.
+	require.Len(t, tracesSink.AllTraces(), 2)
+	assert.Equal(t, 3, tracesSink.AllTraces()[0].SpanCount())
+
+	// The metrics are cumulative, the last one has the data of both batches.
+	allMetrics := metricsSink.AllMetrics()
+	require.Len(t, allMetrics, 2)
+	md := pdatautil.MetricsToInternalMetrics(allMetrics[1])
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+
+	calls := metrics.At(0)
+	assert.Equal(t, callsMetricName, calls.MetricDescriptor().Name())
+	assert.Equal(t, pdata.MetricTypeMonotonicInt64, calls.MetricDescriptor().Type())
+	latency := metrics.At(1)
+	assert.Equal(t, latencyMetricName, latency.MetricDescriptor().Name())
+	assert.Equal(t, pdata.MetricTypeHistogram, latency.MetricDescriptor().Type())
+
+	require.Equal(t, 2, calls.Int64DataPoints().Len())
+	require.Equal(t, 2, latency.HistogramDataPoints().Len())
+	for i := 0; i < calls.Int64DataPoints().Len(); i++ {
+		callsDp := calls.Int64DataPoints().At(i)
+		labels := labelsToMap(callsDp.LabelsMap())
+		assert.Equal(t, "svc-a", labels[serviceNameLabel])
+		assert.Equal(t, "GET /users", labels[operationLabel])
+		assert.Equal(t, pdata.SpanKindSERVER.String(), labels[spanKindLabel])
+		assert.Equal(t, pdata.StatusCode(0).String(), labels[statusCodeLabel])
+
+		latencyDp := latency.HistogramDataPoints().At(i)
+		assert.Equal(t, labels, labelsToMap(latencyDp.LabelsMap()))
+		assert.Equal(t, []float64{10, 100}, latencyDp.ExplicitBounds())
+		assert.Equal(t, uint64(callsDp.Value()), latencyDp.Count())
+
+		switch labels["http.method"] {
+		case "GET":
+			// The default value is used when the attribute is missing.
+			assert.Equal(t, "200", labels["http.status_code"])
+			assert.Equal(t, int64(4), callsDp.Value())
+			assert.InDelta(t, 110, latencyDp.Sum(), 1e-9)
+			assert.Equal(t, []uint64{2, 2, 0}, bucketCounts(latencyDp))
+		case "POST":
+			// Dimensions without default are omitted if missing.
+			assert.NotContains(t, labels, "http.status_code")
+			assert.Equal(t, int64(2), callsDp.Value())
+			assert.InDelta(t, 1000, latencyDp.Sum(), 1e-9)
+			assert.Equal(t, []uint64{0, 0, 2}, bucketCounts(latencyDp))
+		default:
+			t.Errorf("unexpected http.method %q", labels["http.method"])
+		}
+	}
+}
+
+func TestProcessorExpireAggregates(t *testing.T) {
+	p, err := newProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, Config{MetricsExporter: "mock", SeriesExpiry: time.Minute})
+	require.NoError(t, err)
+
+	now := time.Unix(1000, 0)
+	getUsers := newTestSpan("GET /users", pdata.SpanKindSERVER, pdata.StatusCode(0), time.Millisecond, nil)
+	getOrders := newTestSpan("GET /orders", pdata.SpanKindSERVER, pdata.StatusCode(0), time.Millisecond, nil)
+	_, ok := p.aggregateMetrics(newTraces("svc-a", getUsers, getOrders), now)
+	require.True(t, ok)
+
+	// Only the aggregates updated by the spans are sent.
+	md, ok := p.aggregateMetrics(newTraces("svc-a", getOrders), now.Add(50*time.Second))
+	require.True(t, ok)
+	calls := pdatautil.MetricsToInternalMetrics(md).ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	require.Equal(t, 1, calls.Int64DataPoints().Len())
+	assert.Equal(t, "GET /orders", labelsToMap(calls.Int64DataPoints().At(0).LabelsMap())[operationLabel])
+	assert.Equal(t, int64(2), calls.Int64DataPoints().At(0).Value())
+
+	// The aggregate idle for the whole expiry starts again from zero.
+	md, ok = p.aggregateMetrics(newTraces("svc-a", getUsers), now.Add(70*time.Second))
+	require.True(t, ok)
+	assert.Len(t, p.aggregates, 2)
+	calls = pdatautil.MetricsToInternalMetrics(md).ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	require.Equal(t, 1, calls.Int64DataPoints().Len())
+	dp := calls.Int64DataPoints().At(0)
+	assert.Equal(t, int64(1), dp.Value())
+	assert.Equal(t, pdata.TimestampUnixNano(uint64(now.Add(70*time.Second).UnixNano())), dp.StartTime())
+
+	_, ok = p.aggregateMetrics(pdata.NewTraces(), now.Add(3*time.Minute))
+	assert.False(t, ok)
+	assert.Len(t, p.aggregates, 0)
+}
+
+func newTraces(serviceName string, spans ...pdata.Span) pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().InitEmpty()
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, serviceName)
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	for i := range spans {
+		ils.Spans().Append(&spans[i])
+	}
+	return td
+}
+
+func newTestSpan(name string, kind pdata.SpanKind, code pdata.StatusCode, duration time.Duration, attrs map[string]pdata.AttributeValue) pdata.Span {
+	span := pdata.NewSpan()
+	span.InitEmpty()
+	span.SetName(name)
+	span.SetKind(kind)
+	span.Status().InitEmpty()
+	span.Status().SetCode(code)
+	start := time.Unix(1000, 0)
+	span.SetStartTime(pdata.TimestampUnixNano(uint64(start.UnixNano())))
+	span.SetEndTime(pdata.TimestampUnixNano(uint64(start.Add(duration).UnixNano())))
+	span.Attributes().InitFromMap(attrs)
+	return span
+}
+
+func labelsToMap(labels pdata.StringMap) map[string]string {
+	m := make(map[string]string)
+	labels.ForEach(func(k string, v pdata.StringValue) {
+		m[k] = v.Value()
+	})
+	return m
+}
+
+func bucketCounts(dp pdata.HistogramDataPoint) []uint64 {
+	counts := make([]uint64, dp.Buckets().Len())
+	for i := range counts {
+		counts[i] = dp.Buckets().At(i).Count()
+	}
+	return counts
+}
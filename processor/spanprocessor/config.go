@@ -34,6 +34,10 @@ type Config struct {
 	// Note: The field name is `Rename` to avoid collision with the Name() method
 	// from configmodels.ProcessorSettings.NamedEntity
 	Rename Name `mapstructure:"name"`
+
+	// Normalize specifies the replacement of high cardinality segments, e.g. IDs,
+	// of the span name and attributes by placeholders. It is applied after Rename.
+	Normalize *Normalize `mapstructure:"normalize"`
 }
 
 // Name specifies the attributes to use to re-name a span.
@@ -77,3 +81,27 @@ type ToAttributes struct {
 	// modified span name.
 	BreakAfterMatch bool `mapstructure:"break_after_match"`
 }
+
+// Normalize specifies how to normalize the span name and attributes.
+type Normalize struct {
+	// Attributes are the keys of the string attributes, e.g. "http.url" or
+	// "http.target", normalized along with the span name.
+	Attributes []string `mapstructure:"attributes"`
+
+	// Rules is a list of regex replacements applied, in the order they are
+	// specified, after the default rules. The default rules replace the segments
+	// of the value, delimited by "/", "?", "&", "=", "#" or whitespace, that are
+	// numbers by "{id}", UUIDs by "{uuid}" and hexadecimal strings of at least 16
+	// characters by "{hex}".
+	Rules []ReplaceRule `mapstructure:"rules"`
+}
+
+// ReplaceRule replaces all the matches of a regex pattern.
+type ReplaceRule struct {
+	// Pattern is the regex pattern to match.
+	Pattern string `mapstructure:"pattern"`
+
+	// Replacement is the replacement of the matches, it can reference the
+	// submatches of the pattern, e.g. "$1" or "${name}".
+	Replacement string `mapstructure:"replacement"`
+}
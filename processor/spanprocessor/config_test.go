@@ -98,6 +98,20 @@ func TestLoadConfig(t *testing.T) {
 			},
 		},
 	})
+
+	p4 := config.Processors["span/normalize"]
+	assert.Equal(t, p4, &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: typeStr,
+			NameVal: "span/normalize",
+		},
+		Normalize: &Normalize{
+			Attributes: []string{"http.url", "http.target"},
+			Rules: []ReplaceRule{
+				{Pattern: "[^/@]+@[^/]+", Replacement: "{email}"},
+			},
+		},
+	})
 }
 
 func createMatchConfig(matchType filterset.MatchType) *filterset.Config {
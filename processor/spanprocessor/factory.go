@@ -33,7 +33,7 @@ const (
 // is not specified.
 // TODO https://go.opentelemetry.io/collector/issues/215
 //	Move this to the error package that allows for span name and field to be specified.
-var errMissingRequiredField = errors.New("error creating \"span\" processor: either \"from_attributes\" or \"to_attributes\" must be specified in \"name:\"")
+var errMissingRequiredField = errors.New("error creating \"span\" processor: either \"from_attributes\" or \"to_attributes\" must be specified in \"name:\", or \"normalize:\" must be specified")
 
 // Factory is the factory for the Span processor.
 type Factory struct {
@@ -63,11 +63,13 @@ func (f *Factory) CreateTraceProcessor(
 	nextConsumer consumer.TraceConsumer,
 	cfg configmodels.Processor) (component.TraceProcessor, error) {
 
-	// 'from_attributes' or 'to_attributes' under 'name' has to be set for the span
-	// processor to be valid. If not set and not enforced, the processor would do no work.
+	// 'from_attributes' or 'to_attributes' under 'name', or 'normalize' has to be set
+	// for the span processor to be valid. If not set and not enforced, the processor
+	// would do no work.
 	oCfg := cfg.(*Config)
 	if len(oCfg.Rename.FromAttributes) == 0 &&
-		(oCfg.Rename.ToAttributes == nil || len(oCfg.Rename.ToAttributes.Rules) == 0) {
+		(oCfg.Rename.ToAttributes == nil || len(oCfg.Rename.ToAttributes.Rules) == 0) &&
+		oCfg.Normalize == nil {
 		return nil, errMissingRequiredField
 	}
 
@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanprocessor
+
+import (
+	"regexp"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const (
+	idPlaceholder   = "{id}"
+	uuidPlaceholder = "{uuid}"
+	hexPlaceholder  = "{hex}"
+)
+
+var (
+	// segmentRegexp matches the segments of a span name or URL, delimited by the
+	// path, query and fragment separators or whitespace.
+	segmentRegexp = regexp.MustCompile(`[^/?&=#\s]+`)
+
+	numberRegexp = regexp.MustCompile(`^[0-9]+$`)
+	uuidRegexp   = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hexRegexp    = regexp.MustCompile(`^[0-9a-fA-F]{16,}$`)
+)
+
+// replaceRule is the compiled equivalent of config.ReplaceRule.
+type replaceRule struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+// processNormalize normalizes the span name and the configured attributes.
+func (sp *spanProcessor) processNormalize(span pdata.Span) {
+	if sp.config.Normalize == nil {
+		return
+	}
+
+	if name := span.Name(); name != "" {
+		span.SetName(sp.normalize(name))
+	}
+
+	attrs := span.Attributes()
+	for _, key := range sp.config.Normalize.Attributes {
+		attr, found := attrs.Get(key)
+		if !found || attr.Type() != pdata.AttributeValueSTRING {
+			continue
+		}
+		attr.SetStringVal(sp.normalize(attr.StringVal()))
+	}
+}
+
+// normalize applies the default rules and then the configured ones to the value.
+func (sp *spanProcessor) normalize(value string) string {
+	value = segmentRegexp.ReplaceAllStringFunc(value, normalizeSegment)
+	for _, rule := range sp.normalizeRules {
+		value = rule.re.ReplaceAllString(value, rule.replacement)
+	}
+	return value
+}
+
+// normalizeSegment returns the placeholder of the segment if it is an ID,
+// otherwise the segment itself.
+func normalizeSegment(segment string) string {
+	switch {
+	case numberRegexp.MatchString(segment):
+		return idPlaceholder
+	case uuidRegexp.MatchString(segment):
+		return uuidPlaceholder
+	case hexRegexp.MatchString(segment):
+		return hexPlaceholder
+	}
+	return segment
+}
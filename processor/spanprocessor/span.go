@@ -33,6 +33,7 @@ type spanProcessor struct {
 	nextConsumer     consumer.TraceConsumer
 	config           Config
 	toAttributeRules []toAttributeRule
+	normalizeRules   []replaceRule
 	include          filterspan.Matcher
 	exclude          filterspan.Matcher
 }
@@ -88,6 +89,17 @@ func newSpanProcessor(nextConsumer consumer.TraceConsumer, config Config) (*span
 		}
 	}
 
+	// Compile the Normalize regexps.
+	if config.Normalize != nil {
+		for _, rule := range config.Normalize.Rules {
+			re, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regexp pattern %s", rule.Pattern)
+			}
+			sp.normalizeRules = append(sp.normalizeRules, replaceRule{re: re, replacement: rule.Replacement})
+		}
+	}
+
 	return sp, nil
 }
 
@@ -117,6 +129,7 @@ func (sp *spanProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) err
 				}
 				sp.processFromAttributes(s)
 				sp.processToAttributes(s)
+				sp.processNormalize(s)
 			}
 		}
 	}
@@ -16,6 +16,7 @@ package spanprocessor
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -628,3 +629,66 @@ func TestSpanProcessor_skipSpan(t *testing.T) {
 		runIndividualTestCase(t, tc, tp)
 	}
 }
+
+func TestSpanProcessor_Normalize(t *testing.T) {
+	testCases := []testCase{
+		{
+			inputName:  "GET /users/12345/orders/9",
+			outputName: "GET /users/{id}/orders/{id}",
+		},
+		{
+			inputName: "/documents/3f2504e0-4f89-11d3-9a0c-0305e82c3301",
+			inputAttributes: map[string]pdata.AttributeValue{
+				"http.url":    pdata.NewAttributeValueString("http://example.com:8080/documents/3f2504e0-4f89-11d3-9a0c-0305e82c3301?page=2"),
+				"http.target": pdata.NewAttributeValueString("/blobs/4bf92f3577b34da6a3ce929d0e0e4736"),
+				"other":       pdata.NewAttributeValueString("/documents/1"),
+			},
+			outputName: "/documents/{uuid}",
+			outputAttributes: map[string]pdata.AttributeValue{
+				"http.url":    pdata.NewAttributeValueString("http://example.com:8080/documents/{uuid}?page={id}"),
+				"http.target": pdata.NewAttributeValueString("/blobs/{hex}"),
+				"other":       pdata.NewAttributeValueString("/documents/1"),
+			},
+		},
+		{
+			// The custom rules are applied, short hexadecimal strings like "cafe" are left as is.
+			inputName:  "/accounts/jane@example.com/cafe",
+			outputName: "/accounts/{email}/cafe",
+		},
+		{
+			// Non string attributes are left as is.
+			inputName: "/health",
+			inputAttributes: map[string]pdata.AttributeValue{
+				"http.target": pdata.NewAttributeValueInt(1),
+			},
+			outputName: "/health",
+			outputAttributes: map[string]pdata.AttributeValue{
+				"http.target": pdata.NewAttributeValueInt(1),
+			},
+		},
+	}
+
+	factory := Factory{}
+	cfg := factory.CreateDefaultConfig()
+	oCfg := cfg.(*Config)
+	oCfg.Normalize = &Normalize{
+		Attributes: []string{"http.url", "http.target"},
+		Rules: []ReplaceRule{
+			{Pattern: "[^/@]+@[^/]+", Replacement: "{email}"},
+		},
+	}
+	tp, err := factory.CreateTraceProcessor(
+		context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, exportertest.NewNopTraceExporter(), oCfg)
+	require.Nil(t, err)
+	require.NotNil(t, tp)
+
+	for _, tc := range testCases {
+		runIndividualTestCase(t, tc, tp)
+	}
+
+	oCfg.Normalize.Rules = []ReplaceRule{{Pattern: "\\"}}
+	tp, err = factory.CreateTraceProcessor(
+		context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, exportertest.NewNopTraceExporter(), oCfg)
+	require.Nil(t, tp)
+	assert.EqualValues(t, fmt.Errorf("invalid regexp pattern \\"), err)
+}
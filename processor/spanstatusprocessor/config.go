@@ -0,0 +1,38 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstatusprocessor
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines the configuration for the span status processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Client configures the derivation of the status of the client spans, and of
+	// all the spans that are neither server nor consumer spans.
+	Client SpanKindConfig `mapstructure:"client"`
+
+	// Server configures the derivation of the status of the server and consumer spans.
+	Server SpanKindConfig `mapstructure:"server"`
+}
+
+// SpanKindConfig defines how the status of the spans of a kind is derived.
+type SpanKindConfig struct {
+	// HTTP4xxAsError specifies if the 4xx HTTP status codes are errors, 5xx HTTP
+	// status codes are always errors.
+	HTTP4xxAsError bool `mapstructure:"http_4xx_as_error"`
+}
@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstatusprocessor
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := config.ExampleComponents()
+	require.NoError(t, err)
+
+	factories.Processors[typeStr] = &Factory{}
+
+	cfg, err := config.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, cfg.Processors["span_status"], &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: "span_status",
+			NameVal: "span_status",
+		},
+		Client: SpanKindConfig{HTTP4xxAsError: true},
+		Server: SpanKindConfig{HTTP4xxAsError: false},
+	})
+
+	assert.Equal(t, cfg.Processors["span_status/4xx"], &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: "span_status",
+			NameVal: "span_status/4xx",
+		},
+		Client: SpanKindConfig{HTTP4xxAsError: true},
+		Server: SpanKindConfig{HTTP4xxAsError: true},
+	})
+}
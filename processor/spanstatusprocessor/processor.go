@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstatusprocessor
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+type spanStatusProcessor struct {
+	nextConsumer consumer.TraceConsumer
+	config       Config
+}
+
+var _ component.TraceProcessor = (*spanStatusProcessor)(nil)
+
+func newSpanStatusProcessor(nextConsumer consumer.TraceConsumer, config Config) (*spanStatusProcessor, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	return &spanStatusProcessor{
+		nextConsumer: nextConsumer,
+		config:       config,
+	}, nil
+}
+
+func (sp *spanStatusProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
+			}
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				s := spans.At(k)
+				if s.IsNil() {
+					continue
+				}
+				sp.processStatus(s)
+			}
+		}
+	}
+	return sp.nextConsumer.ConsumeTraces(ctx, td)
+}
+
+func (sp *spanStatusProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: true}
+}
+
+// Start is invoked during service startup.
+func (sp *spanStatusProcessor) Start(_ context.Context, _ component.Host) error {
+	return nil
+}
+
+// Shutdown is invoked during service shutdown.
+func (sp *spanStatusProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+// processStatus sets the status code of the span if it can be derived from its
+// attributes, otherwise the status is left unchanged.
+func (sp *spanStatusProcessor) processStatus(span pdata.Span) {
+	code, ok := sp.deriveStatusCode(span)
+	if !ok {
+		return
+	}
+	status := span.Status()
+	if status.IsNil() {
+		status.InitEmpty()
+	}
+	status.SetCode(pdata.StatusCode(code))
+}
+
+// deriveStatusCode returns the status code derived from, in order of precedence,
+// the gRPC status code, the "error" tag set by the Jaeger and Zipkin clients and
+// the HTTP status code attributes of the span.
+func (sp *spanStatusProcessor) deriveStatusCode(span pdata.Span) (int32, bool) {
+	attrs := span.Attributes()
+
+	if attr, found := attrs.Get(conventions.AttributeRPCGRPCStatusCode); found {
+		if code, ok := attributeToInt32(attr); ok {
+			return code, true
+		}
+	}
+
+	if attr, found := attrs.Get(tracetranslator.TagError); found {
+		if code, ok := statusCodeFromErrorTag(attr); ok {
+			return code, true
+		}
+	}
+
+	if attr, found := attrs.Get(conventions.AttributeHTTPStatusCode); found {
+		if httpCode, ok := attributeToInt32(attr); ok {
+			if httpCode >= 400 && httpCode < 500 && !sp.spanKindConfig(span).HTTP4xxAsError {
+				return tracetranslator.OCOK, true
+			}
+			return tracetranslator.OCStatusCodeFromHTTP(httpCode), true
+		}
+	}
+
+	return 0, false
+}
+
+// spanKindConfig returns the configuration of the kind of the span, based on its
+// kind or, if unspecified, its "span.kind" attribute.
+func (sp *spanStatusProcessor) spanKindConfig(span pdata.Span) SpanKindConfig {
+	switch span.Kind() {
+	case pdata.SpanKindSERVER, pdata.SpanKindCONSUMER:
+		return sp.config.Server
+	case pdata.SpanKindUNSPECIFIED:
+		attr, ok := span.Attributes().Get(tracetranslator.TagSpanKind)
+		if !ok || attr.Type() != pdata.AttributeValueSTRING {
+			break
+		}
+		switch tracetranslator.OpenTracingSpanKind(attr.StringVal()) {
+		case tracetranslator.OpenTracingSpanKindServer, tracetranslator.OpenTracingSpanKindConsumer:
+			return sp.config.Server
+		}
+	}
+	return sp.config.Client
+}
+
+// statusCodeFromErrorTag maps the "error" tag to a status code. Jaeger clients set
+// it to true on errors, while Zipkin clients set it to the canonical name of the
+// status code or to an error message. The function returns false if the tag doesn't
+// indicate an error.
+func statusCodeFromErrorTag(attr pdata.AttributeValue) (int32, bool) {
+	switch attr.Type() {
+	case pdata.AttributeValueBOOL:
+		if attr.BoolVal() {
+			return tracetranslator.OCUnknown, true
+		}
+	case pdata.AttributeValueSTRING:
+		val := attr.StringVal()
+		if val == "" || val == "false" {
+			return 0, false
+		}
+		if code, ok := tracetranslator.OCStatusCodeFromName(val); ok {
+			return code, true
+		}
+		return tracetranslator.OCUnknown, true
+	}
+	return 0, false
+}
+
+// attributeToInt32 maps an integer or string attribute value to an int32. The function
+// returns false if the value is of another type or cannot be converted to an int32 value.
+func attributeToInt32(attr pdata.AttributeValue) (int32, bool) {
+	var i int64
+	switch attr.Type() {
+	case pdata.AttributeValueINT:
+		i = attr.IntVal()
+	case pdata.AttributeValueSTRING:
+		var err error
+		if i, err = strconv.ParseInt(attr.StringVal(), 10, 64); err != nil {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+	if i > math.MaxInt32 || i < math.MinInt32 {
+		return 0, false
+	}
+	return int32(i), true
+}
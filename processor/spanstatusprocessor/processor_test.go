@@ -0,0 +1,177 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstatusprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+func TestNewProcessor(t *testing.T) {
+	_, err := newSpanStatusProcessor(nil, Config{})
+	assert.Error(t, err)
+}
+
+func TestProcessorConsumeTraces(t *testing.T) {
+	tests := []struct {
+		name       string
+		kind       pdata.SpanKind
+		attrs      map[string]pdata.AttributeValue
+		statusCode *pdata.StatusCode
+		want       *pdata.StatusCode
+	}{
+		{
+			name: "no_attributes",
+			kind: pdata.SpanKindCLIENT,
+		},
+		{
+			name:       "no_attributes_keeps_status",
+			kind:       pdata.SpanKindCLIENT,
+			statusCode: statusCode(tracetranslator.OCInternal),
+			want:       statusCode(tracetranslator.OCInternal),
+		},
+		{
+			name: "grpc_status_code",
+			kind: pdata.SpanKindSERVER,
+			attrs: map[string]pdata.AttributeValue{
+				"rpc.grpc.status_code": pdata.NewAttributeValueInt(tracetranslator.OCUnavailable),
+				"http.status_code":     pdata.NewAttributeValueInt(200),
+			},
+			want: statusCode(tracetranslator.OCUnavailable),
+		},
+		{
+			name: "http_4xx_client",
+			kind: pdata.SpanKindCLIENT,
+			attrs: map[string]pdata.AttributeValue{
+				"http.status_code": pdata.NewAttributeValueInt(404),
+			},
+			want: statusCode(tracetranslator.OCNotFound),
+		},
+		{
+			name: "http_4xx_server",
+			kind: pdata.SpanKindSERVER,
+			attrs: map[string]pdata.AttributeValue{
+				"http.status_code": pdata.NewAttributeValueString("404"),
+			},
+			statusCode: statusCode(tracetranslator.OCNotFound),
+			want:       statusCode(tracetranslator.OCOK),
+		},
+		{
+			name: "http_4xx_server_span_kind_attribute",
+			kind: pdata.SpanKindUNSPECIFIED,
+			attrs: map[string]pdata.AttributeValue{
+				"http.status_code": pdata.NewAttributeValueInt(404),
+				"span.kind":        pdata.NewAttributeValueString("server"),
+			},
+			want: statusCode(tracetranslator.OCOK),
+		},
+		{
+			name: "http_5xx_server",
+			kind: pdata.SpanKindSERVER,
+			attrs: map[string]pdata.AttributeValue{
+				"http.status_code": pdata.NewAttributeValueInt(503),
+			},
+			want: statusCode(tracetranslator.OCUnavailable),
+		},
+		{
+			name: "jaeger_error_tag",
+			kind: pdata.SpanKindSERVER,
+			attrs: map[string]pdata.AttributeValue{
+				"error":            pdata.NewAttributeValueBool(true),
+				"http.status_code": pdata.NewAttributeValueInt(200),
+			},
+			want: statusCode(tracetranslator.OCUnknown),
+		},
+		{
+			name: "jaeger_error_tag_false",
+			kind: pdata.SpanKindSERVER,
+			attrs: map[string]pdata.AttributeValue{
+				"error":            pdata.NewAttributeValueBool(false),
+				"http.status_code": pdata.NewAttributeValueInt(200),
+			},
+			want: statusCode(tracetranslator.OCOK),
+		},
+		{
+			name: "zipkin_error_tag_code",
+			kind: pdata.SpanKindCLIENT,
+			attrs: map[string]pdata.AttributeValue{
+				"error": pdata.NewAttributeValueString("DEADLINE_EXCEEDED"),
+			},
+			want: statusCode(tracetranslator.OCDeadlineExceeded),
+		},
+		{
+			name: "zipkin_error_tag_message",
+			kind: pdata.SpanKindCLIENT,
+			attrs: map[string]pdata.AttributeValue{
+				"error": pdata.NewAttributeValueString("connection refused"),
+			},
+			want: statusCode(tracetranslator.OCUnknown),
+		},
+		{
+			name: "invalid_attributes",
+			kind: pdata.SpanKindCLIENT,
+			attrs: map[string]pdata.AttributeValue{
+				"rpc.grpc.status_code": pdata.NewAttributeValueString("UNAVAILABLE"),
+				"http.status_code":     pdata.NewAttributeValueDouble(404),
+				"error":                pdata.NewAttributeValueInt(1),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &exportertest.SinkTraceExporter{}
+			factory := &Factory{}
+			sp, err := newSpanStatusProcessor(sink, *factory.CreateDefaultConfig().(*Config))
+			require.NoError(t, err)
+
+			td := pdata.NewTraces()
+			td.ResourceSpans().Resize(1)
+			rs := td.ResourceSpans().At(0)
+			rs.InstrumentationLibrarySpans().Resize(1)
+			spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+			spans.Resize(1)
+			span := spans.At(0)
+			span.SetKind(tt.kind)
+			span.Attributes().InitFromMap(tt.attrs)
+			if tt.statusCode != nil {
+				span.Status().InitEmpty()
+				span.Status().SetCode(*tt.statusCode)
+			}
+
+			require.NoError(t, sp.ConsumeTraces(context.Background(), td))
+			require.Len(t, sink.AllTraces(), 1)
+
+			status := sink.AllTraces()[0].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Status()
+			if tt.want == nil {
+				assert.True(t, status.IsNil())
+				return
+			}
+			require.False(t, status.IsNil())
+			assert.Equal(t, *tt.want, status.Code())
+		})
+	}
+}
+
+func statusCode(code int32) *pdata.StatusCode {
+	sc := pdata.StatusCode(code)
+	return &sc
+}
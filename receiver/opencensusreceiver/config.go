@@ -17,6 +17,7 @@ package opencensusreceiver
 import (
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
 )
 
 // Config defines configuration for OpenCensus receiver.
@@ -31,6 +32,9 @@ type Config struct {
 	// An empty list means that CORS is not enabled at all. A wildcard (*) can be
 	// used to match any origin or one or more characters of an origin.
 	CorsOrigins []string `mapstructure:"cors_allowed_origins"`
+
+	// RateLimit is the optional per client rate limit applied to the received data.
+	RateLimit *receiverhelper.RateLimitSettings `mapstructure:"rate_limit"`
 }
 
 func (rOpts *Config) buildOptions() ([]Option, error) {
@@ -39,6 +43,14 @@ func (rOpts *Config) buildOptions() ([]Option, error) {
 		opts = append(opts, WithCorsOrigins(rOpts.CorsOrigins))
 	}
 
+	if rOpts.RateLimit != nil {
+		rl, err := receiverhelper.NewRateLimiter(*rOpts.RateLimit)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithRateLimiter(rl))
+	}
+
 	grpcServerOptions, err := rOpts.GRPCServerSettings.ToServerOption()
 	if err != nil {
 		return nil, err
@@ -27,6 +27,7 @@ import (
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -40,7 +41,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 7)
+	assert.Equal(t, len(cfg.Receivers), 8)
 
 	r0 := cfg.Receivers["opencensus"]
 	assert.Equal(t, r0, factory.CreateDefaultConfig())
@@ -170,6 +171,25 @@ func TestLoadConfig(t *testing.T) {
 				ReadBufferSize: 512 * 1024,
 			},
 		})
+
+	r7 := cfg.Receivers["opencensus/ratelimit"].(*Config)
+	assert.Equal(t, r7,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal: typeStr,
+				NameVal: "opencensus/ratelimit",
+			},
+			GRPCServerSettings: configgrpc.GRPCServerSettings{
+				NetAddr: confignet.NetAddr{
+					Endpoint:  "0.0.0.0:55678",
+					Transport: "tcp",
+				},
+				ReadBufferSize: 512 * 1024,
+			},
+			RateLimit: &receiverhelper.RateLimitSettings{
+				Rate: 500,
+			},
+		})
 }
 
 func TestBuildOptions_TLSCredentials(t *testing.T) {
@@ -193,3 +213,22 @@ func TestBuildOptions_TLSCredentials(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, opt)
 }
+
+func TestBuildOptions_RateLimit(t *testing.T) {
+	cfg := Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			NameVal: "RateLimit",
+		},
+		RateLimit: &receiverhelper.RateLimitSettings{
+			Rate: 10,
+			Key:  "unknown",
+		},
+	}
+	_, err := cfg.buildOptions()
+	assert.EqualError(t, err, `rate_limit: unsupported key "unknown", must be "client_ip" or "tenant"`)
+
+	cfg.RateLimit.Key = receiverhelper.RateLimitKeyClientIP
+	opts, err := cfg.buildOptions()
+	assert.NoError(t, err)
+	assert.Len(t, opts, 1)
+}
@@ -35,6 +35,7 @@ import (
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/receiver/opencensusreceiver/ocmetrics"
 	"go.opentelemetry.io/collector/receiver/opencensusreceiver/octrace"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
 )
 
 // Receiver is the type that exposes Trace and Metrics reception.
@@ -46,6 +47,7 @@ type Receiver struct {
 	gatewayMux        *gatewayruntime.ServeMux
 	corsOrigins       []string
 	grpcServerOptions []grpc.ServerOption
+	rateLimiter       *receiverhelper.RateLimiter
 
 	traceReceiverOpts []octrace.Option
 
@@ -83,13 +85,22 @@ func New(
 	ocr := &Receiver{
 		ln:          ln,
 		corsOrigins: []string{}, // Disable CORS by default.
-		gatewayMux:  gatewayruntime.NewServeMux(),
 	}
 
 	for _, opt := range opts {
 		opt.withReceiver(ocr)
 	}
 
+	muxOpts := []gatewayruntime.ServeMuxOption{
+		gatewayruntime.WithProtoErrorHandler(receiverhelper.GatewayErrorHandler),
+	}
+	if ocr.rateLimiter != nil {
+		muxOpts = append(muxOpts,
+			gatewayruntime.WithIncomingHeaderMatcher(ocr.rateLimiter.GatewayHeaderMatcher()),
+			gatewayruntime.WithMetadata(ocr.rateLimiter.GatewayMetadata()))
+	}
+	ocr.gatewayMux = gatewayruntime.NewServeMux(muxOpts...)
+
 	ocr.instanceName = instanceName
 	ocr.traceConsumer = tc
 	ocr.metricsConsumer = mc
@@ -107,8 +118,12 @@ func (ocr *Receiver) registerTraceConsumer() error {
 	var err = componenterror.ErrAlreadyStarted
 
 	ocr.startTraceReceiverOnce.Do(func() {
+		tc := ocr.traceConsumer
+		if ocr.rateLimiter != nil {
+			tc = receiverhelper.NewRateLimitedTraceConsumerOld(ocr.rateLimiter, tc)
+		}
 		ocr.traceReceiver, err = octrace.New(
-			ocr.instanceName, ocr.traceConsumer, ocr.traceReceiverOpts...)
+			ocr.instanceName, tc, ocr.traceReceiverOpts...)
 		if err == nil {
 			srv := ocr.grpcServer()
 			agenttracepb.RegisterTraceServiceServer(srv, ocr.traceReceiver)
@@ -122,8 +137,12 @@ func (ocr *Receiver) registerMetricsConsumer() error {
 	var err = componenterror.ErrAlreadyStarted
 
 	ocr.startMetricsReceiverOnce.Do(func() {
+		mc := ocr.metricsConsumer
+		if ocr.rateLimiter != nil {
+			mc = receiverhelper.NewRateLimitedMetricsConsumerOld(ocr.rateLimiter, mc)
+		}
 		ocr.metricsReceiver, err = ocmetrics.New(
-			ocr.instanceName, ocr.metricsConsumer)
+			ocr.instanceName, mc)
 		if err == nil {
 			srv := ocr.grpcServer()
 			agentmetricspb.RegisterMetricsServiceServer(srv, ocr.metricsReceiver)
@@ -137,7 +156,12 @@ func (ocr *Receiver) grpcServer() *grpc.Server {
 	defer ocr.mu.Unlock()
 
 	if ocr.serverGRPC == nil {
-		ocr.serverGRPC = obsreport.GRPCServerWithObservabilityEnabled(ocr.grpcServerOptions...)
+		opts := append([]grpc.ServerOption{}, ocr.grpcServerOptions...)
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(receiverhelper.UnaryServerInterceptor()),
+			grpc.ChainStreamInterceptor(receiverhelper.StreamServerInterceptor()),
+		)
+		ocr.serverGRPC = obsreport.GRPCServerWithObservabilityEnabled(opts...)
 	}
 
 	return ocr.serverGRPC
@@ -16,6 +16,8 @@ package opencensusreceiver
 
 import (
 	"google.golang.org/grpc"
+
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
 )
 
 // Option interface defines for configuration settings to be applied to receivers.
@@ -54,3 +56,19 @@ func WithGRPCServerOptions(gsOpts ...grpc.ServerOption) Option {
 	gsvOpts := grpcServerOptions(gsOpts)
 	return gsvOpts
 }
+
+type rateLimiter struct {
+	rl *receiverhelper.RateLimiter
+}
+
+var _ Option = (*rateLimiter)(nil)
+
+func (rlo *rateLimiter) withReceiver(ocr *Receiver) {
+	ocr.rateLimiter = rlo.rl
+}
+
+// WithRateLimiter is an option to limit the rate of spans and metric points
+// accepted from each client.
+func WithRateLimiter(rl *receiverhelper.RateLimiter) Option {
+	return &rateLimiter{rl: rl}
+}
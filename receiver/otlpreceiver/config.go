@@ -18,6 +18,7 @@ import (
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
 )
 
 type Protocols struct {
@@ -31,4 +32,8 @@ type Config struct {
 
 	// Protocols is the configuration for the supported protocols, currently gRPC and HTTP (Proto and JSON).
 	Protocols `mapstructure:"protocols"`
+
+	// RateLimit is the optional per client rate limit applied to the data
+	// received by all protocols.
+	RateLimit *receiverhelper.RateLimitSettings `mapstructure:"rate_limit"`
 }
@@ -28,6 +28,7 @@ import (
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -41,7 +42,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 9)
+	assert.Equal(t, len(cfg.Receivers), 10)
 
 	assert.Equal(t, cfg.Receivers["otlp"], factory.CreateDefaultConfig())
 
@@ -195,6 +196,29 @@ func TestLoadConfig(t *testing.T) {
 				},
 			},
 		})
+
+	assert.Equal(t, cfg.Receivers["otlp/ratelimit"],
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal: typeStr,
+				NameVal: "otlp/ratelimit",
+			},
+			Protocols: Protocols{
+				GRPC: &configgrpc.GRPCServerSettings{
+					NetAddr: confignet.NetAddr{
+						Endpoint:  "0.0.0.0:55680",
+						Transport: "tcp",
+					},
+					ReadBufferSize: 512 * 1024,
+				},
+			},
+			RateLimit: &receiverhelper.RateLimitSettings{
+				Rate:         1000,
+				Burst:        2000,
+				Key:          "tenant",
+				TenantHeader: "x-tenant-id",
+			},
+		})
 }
 
 func TestFailedLoadConfig(t *testing.T) {
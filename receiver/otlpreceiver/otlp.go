@@ -31,6 +31,7 @@ import (
 	collectortrace "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/collector/trace/v1"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/metrics"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/trace"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
 )
 
 // Receiver is the type that exposes Trace and Metrics reception.
@@ -40,6 +41,8 @@ type Receiver struct {
 	gatewayMux *gatewayruntime.ServeMux
 	serverHTTP *http.Server
 
+	rateLimiter *receiverhelper.RateLimiter
+
 	traceReceiver   *trace.Receiver
 	metricsReceiver *metrics.Receiver
 
@@ -54,17 +57,35 @@ func New(cfg *Config) (*Receiver, error) {
 	r := &Receiver{
 		cfg: cfg,
 	}
+	if cfg.RateLimit != nil {
+		rl, err := receiverhelper.NewRateLimiter(*cfg.RateLimit)
+		if err != nil {
+			return nil, err
+		}
+		r.rateLimiter = rl
+	}
 	if cfg.GRPC != nil {
 		opts, err := cfg.GRPC.ToServerOption()
 		if err != nil {
 			return nil, err
 		}
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(receiverhelper.UnaryServerInterceptor()),
+			grpc.ChainStreamInterceptor(receiverhelper.StreamServerInterceptor()),
+		)
 		r.serverGRPC = grpc.NewServer(opts...)
 	}
 	if cfg.HTTP != nil {
-		r.gatewayMux = gatewayruntime.NewServeMux(
+		muxOpts := []gatewayruntime.ServeMuxOption{
 			gatewayruntime.WithMarshalerOption("application/x-protobuf", &xProtobufMarshaler{}),
-		)
+			gatewayruntime.WithProtoErrorHandler(receiverhelper.GatewayErrorHandler),
+		}
+		if r.rateLimiter != nil {
+			muxOpts = append(muxOpts,
+				gatewayruntime.WithIncomingHeaderMatcher(r.rateLimiter.GatewayHeaderMatcher()),
+				gatewayruntime.WithMetadata(r.rateLimiter.GatewayMetadata()))
+		}
+		r.gatewayMux = gatewayruntime.NewServeMux(muxOpts...)
 	}
 
 	return r, nil
@@ -129,6 +150,9 @@ func (r *Receiver) registerTraceConsumer(ctx context.Context, tc consumer.TraceC
 	if tc == nil {
 		return componenterror.ErrNilNextConsumer
 	}
+	if r.rateLimiter != nil {
+		tc = receiverhelper.NewRateLimitedTraceConsumer(r.rateLimiter, tc)
+	}
 	r.traceReceiver = trace.New(r.cfg.Name(), tc)
 	if r.serverGRPC != nil {
 		collectortrace.RegisterTraceServiceServer(r.serverGRPC, r.traceReceiver)
@@ -143,6 +167,9 @@ func (r *Receiver) registerMetricsConsumer(ctx context.Context, mc consumer.Metr
 	if mc == nil {
 		return componenterror.ErrNilNextConsumer
 	}
+	if r.rateLimiter != nil {
+		mc = receiverhelper.NewRateLimitedMetricsConsumer(r.rateLimiter, mc)
+	}
 	r.metricsReceiver = metrics.New(r.cfg.Name(), mc)
 	if r.serverGRPC != nil {
 		collectormetrics.RegisterMetricsServiceServer(r.serverGRPC, r.metricsReceiver)
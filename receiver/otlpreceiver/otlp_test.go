@@ -19,6 +19,7 @@ package otlpreceiver
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -29,6 +30,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -41,6 +43,7 @@ import (
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	collectortrace "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/collector/trace/v1"
@@ -49,6 +52,7 @@ import (
 	otlptrace "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/trace/v1"
 	"go.opentelemetry.io/collector/internal/data/testdata"
 	"go.opentelemetry.io/collector/obsreport/obsreporttest"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
 	"go.opentelemetry.io/collector/testutil"
 	"go.opentelemetry.io/collector/translator/conventions"
 )
@@ -388,6 +392,138 @@ func TestOTLPReceiverTrace_HandleNextConsumerResponse(t *testing.T) {
 	}
 }
 
+func TestOTLPReceiverTrace_Throttled(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	sink := new(exportertest.SinkTraceExporter)
+	sink.SetConsumeTraceError(consumererror.Throttled(errors.New("memory limit"), 3*time.Second))
+
+	ocr := newGRPCReceiver(t, otlpReceiver, addr, sink, nil)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	defer ocr.Shutdown(context.Background())
+
+	cc, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer cc.Close()
+
+	req := &collectortrace.ExportTraceServiceRequest{
+		ResourceSpans: pdata.TracesToOtlp(testdata.GenerateTraceDataOneSpan()),
+	}
+	_, err = collectortrace.NewTraceServiceClient(cc).Export(context.Background(), req)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 1)
+	retryInfo, ok := st.Details()[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	assert.EqualValues(t, 3, retryInfo.RetryDelay.Seconds)
+}
+
+func TestOTLPReceiverTrace_RateLimitHTTP(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.SetName(otlpReceiver)
+	cfg.HTTP.Endpoint = addr
+	cfg.GRPC = nil
+	cfg.RateLimit = &receiverhelper.RateLimitSettings{
+		Rate:         1,
+		Burst:        1,
+		Key:          receiverhelper.RateLimitKeyTenant,
+		TenantHeader: "X-Tenant-Id",
+	}
+	sink := new(exportertest.SinkTraceExporter)
+	ocr := newReceiver(t, factory, cfg, sink, nil)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	defer ocr.Shutdown(context.Background())
+
+	// TODO(nilebox): make starting server deterministic
+	// Wait for the servers to start
+	<-time.After(10 * time.Millisecond)
+
+	traceBytes, err := proto.Marshal(&collectortrace.ExportTraceServiceRequest{
+		ResourceSpans: pdata.TracesToOtlp(testdata.GenerateTraceDataOneSpan()),
+	})
+	require.NoError(t, err)
+
+	post := func(tenant string) *http.Response {
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://%s/v1/trace", addr), bytes.NewReader(traceBytes))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-protobuf")
+		req.Header.Set("X-Tenant-Id", tenant)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_, err = ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp
+	}
+
+	assert.Equal(t, http.StatusOK, post("tenant-a").StatusCode)
+
+	resp := post("tenant-a")
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+
+	// Other tenants are not affected.
+	assert.Equal(t, http.StatusOK, post("tenant-b").StatusCode)
+	assert.Len(t, sink.AllTraces(), 2)
+}
+
+func TestOTLPReceiverTrace_RateLimitHTTPClientIP(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.SetName(otlpReceiver)
+	cfg.HTTP.Endpoint = addr
+	cfg.GRPC = nil
+	cfg.RateLimit = &receiverhelper.RateLimitSettings{
+		Rate:  1,
+		Burst: 1,
+		Key:   receiverhelper.RateLimitKeyClientIP,
+	}
+	sink := new(exportertest.SinkTraceExporter)
+	ocr := newReceiver(t, factory, cfg, sink, nil)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	defer ocr.Shutdown(context.Background())
+
+	// TODO(nilebox): make starting server deterministic
+	// Wait for the servers to start
+	<-time.After(10 * time.Millisecond)
+
+	traceBytes, err := proto.Marshal(&collectortrace.ExportTraceServiceRequest{
+		ResourceSpans: pdata.TracesToOtlp(testdata.GenerateTraceDataOneSpan()),
+	})
+	require.NoError(t, err)
+
+	// post sends the traces from the local IP, with the given X-Forwarded-For
+	// header if not empty.
+	post := func(localIP, forwardedFor string) *http.Response {
+		dialer := &net.Dialer{LocalAddr: &net.TCPAddr{IP: net.ParseIP(localIP)}}
+		httpClient := &http.Client{Transport: &http.Transport{DialContext: dialer.DialContext}}
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://%s/v1/trace", addr), bytes.NewReader(traceBytes))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-protobuf")
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		resp, err := httpClient.Do(req)
+		require.NoError(t, err)
+		_, err = ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp
+	}
+
+	assert.Equal(t, http.StatusOK, post("127.0.0.1", "").StatusCode)
+	assert.Equal(t, http.StatusTooManyRequests, post("127.0.0.1", "").StatusCode)
+	// A spoofed X-Forwarded-For header doesn't get a new bucket.
+	assert.Equal(t, http.StatusTooManyRequests, post("127.0.0.1", "10.0.0.9").StatusCode)
+
+	// Other clients are not affected.
+	assert.Equal(t, http.StatusOK, post("127.0.0.2", "").StatusCode)
+	assert.Len(t, sink.AllTraces(), 2)
+}
+
 func TestGRPCInvalidTLSCredentials(t *testing.T) {
 	cfg := &Config{
 		ReceiverSettings: configmodels.ReceiverSettings{
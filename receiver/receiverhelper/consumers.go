@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receiverhelper
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+)
+
+// NewRateLimitedTraceConsumer returns a TraceConsumer that checks the rate
+// limit of the client before passing the spans to next.
+func NewRateLimitedTraceConsumer(rl *RateLimiter, next consumer.TraceConsumer) consumer.TraceConsumer {
+	return &rateLimitedTraceConsumer{rl: rl, next: next}
+}
+
+type rateLimitedTraceConsumer struct {
+	rl   *RateLimiter
+	next consumer.TraceConsumer
+}
+
+func (c *rateLimitedTraceConsumer) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	if err := c.rl.Acquire(ctx, td.SpanCount()); err != nil {
+		return err
+	}
+	return c.next.ConsumeTraces(ctx, td)
+}
+
+// NewRateLimitedMetricsConsumer returns a MetricsConsumer that checks the
+// rate limit of the client before passing the data points to next.
+func NewRateLimitedMetricsConsumer(rl *RateLimiter, next consumer.MetricsConsumer) consumer.MetricsConsumer {
+	return &rateLimitedMetricsConsumer{rl: rl, next: next}
+}
+
+type rateLimitedMetricsConsumer struct {
+	rl   *RateLimiter
+	next consumer.MetricsConsumer
+}
+
+func (c *rateLimitedMetricsConsumer) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	_, numPoints := pdatautil.MetricAndDataPointCount(md)
+	if err := c.rl.Acquire(ctx, numPoints); err != nil {
+		return err
+	}
+	return c.next.ConsumeMetrics(ctx, md)
+}
+
+// NewRateLimitedTraceConsumerOld returns a TraceConsumerOld that checks the
+// rate limit of the client before passing the spans to next.
+func NewRateLimitedTraceConsumerOld(rl *RateLimiter, next consumer.TraceConsumerOld) consumer.TraceConsumerOld {
+	return &rateLimitedTraceConsumerOld{rl: rl, next: next}
+}
+
+type rateLimitedTraceConsumerOld struct {
+	rl   *RateLimiter
+	next consumer.TraceConsumerOld
+}
+
+func (c *rateLimitedTraceConsumerOld) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	if err := c.rl.Acquire(ctx, len(td.Spans)); err != nil {
+		return err
+	}
+	return c.next.ConsumeTraceData(ctx, td)
+}
+
+// NewRateLimitedMetricsConsumerOld returns a MetricsConsumerOld that checks
+// the rate limit of the client before passing the data points to next.
+func NewRateLimitedMetricsConsumerOld(rl *RateLimiter, next consumer.MetricsConsumerOld) consumer.MetricsConsumerOld {
+	return &rateLimitedMetricsConsumerOld{rl: rl, next: next}
+}
+
+type rateLimitedMetricsConsumerOld struct {
+	rl   *RateLimiter
+	next consumer.MetricsConsumerOld
+}
+
+func (c *rateLimitedMetricsConsumerOld) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	_, numPoints := pdatautil.TimeseriesAndPointCount(md)
+	if err := c.rl.Acquire(ctx, numPoints); err != nil {
+		return err
+	}
+	return c.next.ConsumeMetricsData(ctx, md)
+}
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package receiverhelper provides helpers shared by receivers to apply
+// backpressure: per client rate limiting and the mapping of the errors
+// returned by the pipeline to the status codes understood by senders.
+package receiverhelper
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/golang/protobuf/ptypes"
+	gatewayruntime "github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+// ToGRPCError converts an error returned by the pipeline into a gRPC status
+// error so that senders can tell how to react to it:
+//   - throttled errors become RESOURCE_EXHAUSTED, carrying the suggested
+//     retry delay as a RetryInfo detail;
+//   - permanent errors become INVALID_ARGUMENT, the data must not be retried;
+//   - gRPC status errors and any other errors are returned unchanged.
+func ToGRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	if retryAfter, ok := consumererror.RetryAfter(err); ok {
+		st := status.New(codes.ResourceExhausted, err.Error())
+		if retryAfter > 0 {
+			if withDetails, detailsErr := st.WithDetails(&errdetails.RetryInfo{
+				RetryDelay: ptypes.DurationProto(retryAfter),
+			}); detailsErr == nil {
+				st = withDetails
+			}
+		}
+		return st.Err()
+	}
+
+	if consumererror.IsPermanent(err) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return err
+}
+
+// UnaryServerInterceptor returns a gRPC interceptor converting the errors
+// returned by unary handlers with ToGRPCError.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, ToGRPCError(err)
+	}
+}
+
+// StreamServerInterceptor returns a gRPC interceptor converting the errors
+// returned by stream handlers with ToGRPCError.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return ToGRPCError(handler(srv, ss))
+	}
+}
+
+// GatewayErrorHandler is a grpc-gateway error handler that converts the
+// errors with ToGRPCError, which maps RESOURCE_EXHAUSTED to HTTP 429, and
+// sets the Retry-After header from the RetryInfo detail, if any.
+func GatewayErrorHandler(
+	ctx context.Context,
+	mux *gatewayruntime.ServeMux,
+	marshaler gatewayruntime.Marshaler,
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+) {
+	err = ToGRPCError(err)
+	if st, ok := status.FromError(err); ok && st.Code() == codes.ResourceExhausted {
+		for _, detail := range st.Details() {
+			retryInfo, ok := detail.(*errdetails.RetryInfo)
+			if !ok {
+				continue
+			}
+			if delay, durErr := ptypes.Duration(retryInfo.RetryDelay); durErr == nil {
+				// Retry-After is expressed in whole seconds, round up to not
+				// encourage retrying too early.
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			}
+		}
+	}
+	gatewayruntime.DefaultHTTPError(ctx, mux, marshaler, w, r, err)
+}
@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receiverhelper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	gatewayruntime "github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+func TestToGRPCError(t *testing.T) {
+	assert.NoError(t, ToGRPCError(nil))
+
+	plain := errors.New("my error")
+	assert.Equal(t, plain, ToGRPCError(plain))
+
+	grpcErr := status.Error(codes.Unavailable, "unavailable")
+	assert.Equal(t, grpcErr, ToGRPCError(grpcErr))
+
+	st, ok := status.FromError(ToGRPCError(consumererror.Permanent(plain)))
+	require.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+
+	st, ok = status.FromError(ToGRPCError(consumererror.Throttled(plain, 1500*time.Millisecond)))
+	require.True(t, ok)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 1)
+	retryInfo, ok := st.Details()[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	assert.EqualValues(t, 1, retryInfo.RetryDelay.Seconds)
+	assert.EqualValues(t, 500*time.Millisecond, retryInfo.RetryDelay.Nanos)
+
+	st, ok = status.FromError(ToGRPCError(consumererror.Throttled(plain, 0)))
+	require.True(t, ok)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	assert.Len(t, st.Details(), 0)
+}
+
+func TestServerInterceptors(t *testing.T) {
+	throttled := consumererror.Throttled(errors.New("my error"), time.Second)
+
+	_, err := UnaryServerInterceptor()(context.Background(), nil, nil,
+		func(context.Context, interface{}) (interface{}, error) {
+			return nil, throttled
+		})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	err = StreamServerInterceptor()(nil, nil, nil,
+		func(interface{}, grpc.ServerStream) error {
+			return throttled
+		})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestGatewayErrorHandler(t *testing.T) {
+	mux := gatewayruntime.NewServeMux()
+	marshaler := &gatewayruntime.JSONPb{}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/v1/trace", nil)
+	GatewayErrorHandler(context.Background(), mux, marshaler, w, r,
+		consumererror.Throttled(errors.New("my error"), 1500*time.Millisecond))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+
+	w = httptest.NewRecorder()
+	GatewayErrorHandler(context.Background(), mux, marshaler, w, r,
+		consumererror.Permanent(errors.New("my error")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, w.Header().Get("Retry-After"))
+}
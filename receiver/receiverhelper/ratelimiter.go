@@ -0,0 +1,247 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receiverhelper
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	gatewayruntime "github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+const (
+	// RateLimitKeyClientIP limits each client IP address separately.
+	RateLimitKeyClientIP = "client_ip"
+	// RateLimitKeyTenant limits each tenant separately, the tenant is read
+	// from the request header configured with TenantHeader.
+	RateLimitKeyTenant = "tenant"
+
+	// bucketIdleTimeout is the time after which the bucket of a client that
+	// stopped sending is forgotten.
+	bucketIdleTimeout = 5 * time.Minute
+
+	// gatewayTokenKey is the metadata key carrying the token that marks
+	// requests forwarded by the grpc-gateway of the receiver.
+	gatewayTokenKey = "otel-gateway-token"
+)
+
+var errRateLimited = errors.New("rate limit exceeded")
+
+// RateLimitSettings defines a token bucket limit applied to every client of
+// a receiver separately.
+type RateLimitSettings struct {
+	// Rate is the number of items (spans, metric data points) per second
+	// allowed for each client.
+	Rate float64 `mapstructure:"rate"`
+
+	// Burst is the maximum number of items accepted at once from a client.
+	// Defaults to the rate, rounded up.
+	Burst int `mapstructure:"burst"`
+
+	// Key selects how clients are told apart, either "client_ip" (default) or
+	// "tenant".
+	Key string `mapstructure:"key"`
+
+	// TenantHeader is the request header (gRPC metadata) carrying the tenant,
+	// required when Key is "tenant".
+	TenantHeader string `mapstructure:"tenant_header"`
+}
+
+// RateLimiter limits the rate of items accepted from each client.
+type RateLimiter struct {
+	limit        rate.Limit
+	burst        int
+	tenantHeader string
+	// gatewayToken is a random secret added by the grpc-gateway to the
+	// requests it forwards, only those are trusted to carry the client
+	// address in x-forwarded-for.
+	gatewayToken string
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewRateLimiter creates a RateLimiter from the given settings.
+func NewRateLimiter(settings RateLimitSettings) (*RateLimiter, error) {
+	if settings.Rate <= 0 {
+		return nil, fmt.Errorf("rate_limit: rate must be positive, got %v", settings.Rate)
+	}
+	if settings.Burst < 0 {
+		return nil, fmt.Errorf("rate_limit: burst must not be negative, got %d", settings.Burst)
+	}
+
+	rl := &RateLimiter{
+		limit:   rate.Limit(settings.Rate),
+		burst:   settings.Burst,
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+	if rl.burst == 0 {
+		rl.burst = int(math.Ceil(settings.Rate))
+	}
+
+	switch settings.Key {
+	case "", RateLimitKeyClientIP:
+	case RateLimitKeyTenant:
+		if settings.TenantHeader == "" {
+			return nil, errors.New("rate_limit: tenant_header is required when key is \"tenant\"")
+		}
+		rl.tenantHeader = strings.ToLower(settings.TenantHeader)
+	default:
+		return nil, fmt.Errorf("rate_limit: unsupported key %q, must be %q or %q",
+			settings.Key, RateLimitKeyClientIP, RateLimitKeyTenant)
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("rate_limit: failed to generate gateway token: %v", err)
+	}
+	rl.gatewayToken = hex.EncodeToString(token)
+
+	rl.lastSweep = rl.now()
+	return rl, nil
+}
+
+// Acquire takes n items from the bucket of the client the context belongs
+// to. When the client is over its limit a throttled error is returned, see
+// consumererror.Throttled, suggesting when to retry. Requests bigger than
+// the burst are accepted once the bucket is full, they can't be split.
+func (rl *RateLimiter) Acquire(ctx context.Context, n int) error {
+	if n <= 0 {
+		return nil
+	}
+	if n > rl.burst {
+		n = rl.burst
+	}
+
+	now := rl.now()
+	limiter := rl.limiterFor(rl.keyFromContext(ctx), now)
+
+	reservation := limiter.ReserveN(now, n)
+	if !reservation.OK() {
+		return consumererror.Throttled(errRateLimited, 0)
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return consumererror.Throttled(errRateLimited, delay)
+	}
+	return nil
+}
+
+// GatewayHeaderMatcher returns a grpc-gateway header matcher that forwards,
+// in addition to the default ones, the tenant header as gRPC metadata so
+// it is available to the rate limiter.
+func (rl *RateLimiter) GatewayHeaderMatcher() gatewayruntime.HeaderMatcherFunc {
+	return func(key string) (string, bool) {
+		if rl.tenantHeader != "" && strings.ToLower(key) == rl.tenantHeader {
+			return rl.tenantHeader, true
+		}
+		return gatewayruntime.DefaultHeaderMatcher(key)
+	}
+}
+
+// GatewayMetadata returns a grpc-gateway metadata annotator marking the
+// requests forwarded by the gateway, so that the client address the gateway
+// puts in x-forwarded-for is trusted for them and only for them.
+func (rl *RateLimiter) GatewayMetadata() func(context.Context, *http.Request) metadata.MD {
+	return func(context.Context, *http.Request) metadata.MD {
+		return metadata.Pairs(gatewayTokenKey, rl.gatewayToken)
+	}
+}
+
+func (rl *RateLimiter) limiterFor(key string, now time.Time) *rate.Limiter {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if now.Sub(rl.lastSweep) >= bucketIdleTimeout {
+		for k, b := range rl.buckets {
+			if now.Sub(b.lastSeen) >= bucketIdleTimeout {
+				delete(rl.buckets, k)
+			}
+		}
+		rl.lastSweep = now
+	}
+
+	b, ok := rl.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rl.limit, rl.burst)}
+		rl.buckets[key] = b
+	}
+	b.lastSeen = now
+	return b.limiter
+}
+
+// keyFromContext returns the key identifying the client, data from clients
+// that can't be identified share the same bucket.
+func (rl *RateLimiter) keyFromContext(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if rl.tenantHeader != "" {
+		if values := md.Get(rl.tenantHeader); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	// Requests received via the grpc-gateway reach the gRPC server from the
+	// gateway itself, the address of the client is only available as
+	// forwarded metadata. Any gRPC client can set x-forwarded-for, so it is
+	// only used when the request carries the token of the gateway.
+	if rl.fromGateway(md) {
+		if values := md.Get("x-forwarded-for"); len(values) > 0 {
+			forwarded := strings.Split(values[len(values)-1], ",")
+			return strings.TrimSpace(forwarded[len(forwarded)-1])
+		}
+	}
+	if c, ok := client.FromContext(ctx); ok {
+		return c.IP
+	}
+	if c, ok := client.FromGRPC(ctx); ok {
+		return c.IP
+	}
+	return ""
+}
+
+// fromGateway reports whether the request was forwarded by the grpc-gateway
+// of the receiver.
+func (rl *RateLimiter) fromGateway(md metadata.MD) bool {
+	for _, v := range md.Get(gatewayTokenKey) {
+		if subtle.ConstantTimeCompare([]byte(v), []byte(rl.gatewayToken)) == 1 {
+			return true
+		}
+	}
+	return false
+}
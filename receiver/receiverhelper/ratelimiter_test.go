@@ -0,0 +1,226 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receiverhelper
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/data/testdata"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings RateLimitSettings
+		err      string
+	}{
+		{
+			name:     "default key",
+			settings: RateLimitSettings{Rate: 10},
+		},
+		{
+			name:     "tenant",
+			settings: RateLimitSettings{Rate: 10, Key: RateLimitKeyTenant, TenantHeader: "X-Tenant"},
+		},
+		{
+			name:     "no rate",
+			settings: RateLimitSettings{},
+			err:      "rate_limit: rate must be positive, got 0",
+		},
+		{
+			name:     "negative burst",
+			settings: RateLimitSettings{Rate: 10, Burst: -1},
+			err:      "rate_limit: burst must not be negative, got -1",
+		},
+		{
+			name:     "tenant without header",
+			settings: RateLimitSettings{Rate: 10, Key: RateLimitKeyTenant},
+			err:      `rate_limit: tenant_header is required when key is "tenant"`,
+		},
+		{
+			name:     "unknown key",
+			settings: RateLimitSettings{Rate: 10, Key: "service"},
+			err:      `rate_limit: unsupported key "service", must be "client_ip" or "tenant"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rl, err := NewRateLimiter(tt.settings)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, rl)
+		})
+	}
+
+	rl, err := NewRateLimiter(RateLimitSettings{Rate: 2.5})
+	require.NoError(t, err)
+	assert.Equal(t, 3, rl.burst)
+}
+
+func TestRateLimiterAcquire(t *testing.T) {
+	rl, err := NewRateLimiter(RateLimitSettings{Rate: 10, Burst: 20})
+	require.NoError(t, err)
+	now := time.Now()
+	rl.now = func() time.Time { return now }
+
+	ctxA := client.NewContext(context.Background(), &client.Client{IP: "10.0.0.1"})
+	ctxB := client.NewContext(context.Background(), &client.Client{IP: "10.0.0.2"})
+
+	assert.NoError(t, rl.Acquire(ctxA, 0))
+	assert.NoError(t, rl.Acquire(ctxA, 15))
+
+	err = rl.Acquire(ctxA, 10)
+	require.Error(t, err)
+	retryAfter, ok := consumererror.RetryAfter(err)
+	require.True(t, ok)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	// The refused request does not consume tokens.
+	assert.NoError(t, rl.Acquire(ctxA, 5))
+
+	// Clients have separate buckets.
+	assert.NoError(t, rl.Acquire(ctxB, 20))
+
+	// Requests bigger than the burst are accepted when the bucket is full.
+	now = now.Add(2 * time.Second)
+	assert.NoError(t, rl.Acquire(ctxA, 100))
+	assert.True(t, consumererror.IsThrottled(rl.Acquire(ctxA, 1)))
+}
+
+func TestRateLimiterEvictsIdleBuckets(t *testing.T) {
+	rl, err := NewRateLimiter(RateLimitSettings{Rate: 1})
+	require.NoError(t, err)
+	now := time.Now()
+	rl.now = func() time.Time { return now }
+
+	assert.NoError(t, rl.Acquire(client.NewContext(context.Background(), &client.Client{IP: "10.0.0.1"}), 1))
+	assert.Len(t, rl.buckets, 1)
+
+	now = now.Add(bucketIdleTimeout)
+	assert.NoError(t, rl.Acquire(client.NewContext(context.Background(), &client.Client{IP: "10.0.0.2"}), 1))
+	assert.Len(t, rl.buckets, 1)
+	assert.Contains(t, rl.buckets, "10.0.0.2")
+}
+
+func TestRateLimiterIgnoresSpoofedForwardedFor(t *testing.T) {
+	rl, err := NewRateLimiter(RateLimitSettings{Rate: 1, Burst: 1})
+	require.NoError(t, err)
+	now := time.Now()
+	rl.now = func() time.Time { return now }
+
+	peerCtx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234},
+	})
+	assert.NoError(t, rl.Acquire(metadata.NewIncomingContext(peerCtx, metadata.Pairs("x-forwarded-for", "192.168.0.1")), 1))
+	err = rl.Acquire(metadata.NewIncomingContext(peerCtx, metadata.Pairs("x-forwarded-for", "192.168.0.2")), 1)
+	assert.True(t, consumererror.IsThrottled(err))
+	assert.Len(t, rl.buckets, 1)
+	assert.Contains(t, rl.buckets, "10.0.0.1")
+}
+
+func TestRateLimiterKeyFromContext(t *testing.T) {
+	ipLimiter, err := NewRateLimiter(RateLimitSettings{Rate: 1})
+	require.NoError(t, err)
+
+	assert.Equal(t, "", ipLimiter.keyFromContext(context.Background()))
+
+	ctx := client.NewContext(context.Background(), &client.Client{IP: "10.0.0.1"})
+	assert.Equal(t, "10.0.0.1", ipLimiter.keyFromContext(ctx))
+
+	ctx = peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 1234},
+	})
+	assert.Equal(t, "10.0.0.2", ipLimiter.keyFromContext(ctx))
+
+	// x-forwarded-for is only trusted on requests forwarded by the gateway.
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-forwarded-for", "10.0.0.3, 10.0.0.4"))
+	assert.Equal(t, "10.0.0.2", ipLimiter.keyFromContext(ctx))
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(
+		"x-forwarded-for", "10.0.0.3, 10.0.0.4",
+		gatewayTokenKey, "guessed"))
+	assert.Equal(t, "10.0.0.2", ipLimiter.keyFromContext(ctx))
+	gatewayMD := ipLimiter.GatewayMetadata()(context.Background(), nil)
+	ctx = metadata.NewIncomingContext(ctx, metadata.Join(metadata.Pairs("x-forwarded-for", "10.0.0.3, 10.0.0.4"), gatewayMD))
+	assert.Equal(t, "10.0.0.4", ipLimiter.keyFromContext(ctx))
+
+	tenantLimiter, err := NewRateLimiter(RateLimitSettings{Rate: 1, Key: RateLimitKeyTenant, TenantHeader: "X-Tenant"})
+	require.NoError(t, err)
+	assert.Equal(t, "", tenantLimiter.keyFromContext(ctx))
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-tenant", "acme"))
+	assert.Equal(t, "acme", tenantLimiter.keyFromContext(ctx))
+
+	matcher := tenantLimiter.GatewayHeaderMatcher()
+	key, ok := matcher("X-Tenant")
+	assert.True(t, ok)
+	assert.Equal(t, "x-tenant", key)
+	_, ok = matcher("X-Other")
+	assert.False(t, ok)
+}
+
+func TestRateLimitedConsumers(t *testing.T) {
+	rl, err := NewRateLimiter(RateLimitSettings{Rate: 1, Burst: 1})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	traceSink := new(exportertest.SinkTraceExporter)
+	tc := NewRateLimitedTraceConsumer(rl, traceSink)
+	assert.NoError(t, tc.ConsumeTraces(ctx, testdata.GenerateTraceDataOneSpan()))
+	assert.True(t, consumererror.IsThrottled(tc.ConsumeTraces(ctx, testdata.GenerateTraceDataOneSpan())))
+	assert.Len(t, traceSink.AllTraces(), 1)
+
+	rl, err = NewRateLimiter(RateLimitSettings{Rate: 1, Burst: 1})
+	require.NoError(t, err)
+	metricsSink := new(exportertest.SinkMetricsExporter)
+	mc := NewRateLimitedMetricsConsumer(rl, metricsSink)
+	md := pdatautil.MetricsFromInternalMetrics(testdata.GenerateMetricDataOneMetric())
+	assert.NoError(t, mc.ConsumeMetrics(ctx, md))
+	assert.True(t, consumererror.IsThrottled(mc.ConsumeMetrics(ctx, md)))
+	assert.Len(t, metricsSink.AllMetrics(), 1)
+
+	rl, err = NewRateLimiter(RateLimitSettings{Rate: 1, Burst: 1})
+	require.NoError(t, err)
+	traceSinkOld := new(exportertest.SinkTraceExporterOld)
+	tcOld := NewRateLimitedTraceConsumerOld(rl, traceSinkOld)
+	td := consumerdata.TraceData{Spans: make([]*tracepb.Span, 1)}
+	assert.NoError(t, tcOld.ConsumeTraceData(ctx, td))
+	assert.True(t, consumererror.IsThrottled(tcOld.ConsumeTraceData(ctx, td)))
+	assert.Len(t, traceSinkOld.AllTraces(), 1)
+
+	rl, err = NewRateLimiter(RateLimitSettings{Rate: 1, Burst: 1})
+	require.NoError(t, err)
+	metricsSinkOld := new(exportertest.SinkMetricsExporterOld)
+	mcOld := NewRateLimitedMetricsConsumerOld(rl, metricsSinkOld)
+	mdOld := pdatautil.MetricsToMetricsData(md)[0]
+	assert.NoError(t, mcOld.ConsumeMetricsData(ctx, mdOld))
+	assert.True(t, consumererror.IsThrottled(mcOld.ConsumeMetricsData(ctx, mdOld)))
+	assert.Len(t, metricsSinkOld.AllMetrics(), 1)
+}
@@ -21,6 +21,7 @@ import (
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/exporter/fileexporter"
 	"go.opentelemetry.io/collector/exporter/jaegerexporter"
+	"go.opentelemetry.io/collector/exporter/loadbalancingexporter"
 	"go.opentelemetry.io/collector/exporter/loggingexporter"
 	"go.opentelemetry.io/collector/exporter/opencensusexporter"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
@@ -32,13 +33,26 @@ import (
 	"go.opentelemetry.io/collector/extension/zpagesextension"
 	"go.opentelemetry.io/collector/processor/attributesprocessor"
 	"go.opentelemetry.io/collector/processor/batchprocessor"
+	"go.opentelemetry.io/collector/processor/cardinalitylimiter"
+	"go.opentelemetry.io/collector/processor/clockskewprocessor"
+	"go.opentelemetry.io/collector/processor/cumulativetodeltaprocessor"
+	"go.opentelemetry.io/collector/processor/deltatocumulativeprocessor"
 	"go.opentelemetry.io/collector/processor/filterprocessor"
+	"go.opentelemetry.io/collector/processor/groupbyattrsprocessor"
+	"go.opentelemetry.io/collector/processor/histogramprocessor"
+	"go.opentelemetry.io/collector/processor/intervalprocessor"
+	"go.opentelemetry.io/collector/processor/logmetricsprocessor"
 	"go.opentelemetry.io/collector/processor/memorylimiter"
+	"go.opentelemetry.io/collector/processor/metricstransformprocessor"
 	"go.opentelemetry.io/collector/processor/queuedprocessor"
+	"go.opentelemetry.io/collector/processor/resourcedetectionprocessor"
 	"go.opentelemetry.io/collector/processor/resourceprocessor"
 	"go.opentelemetry.io/collector/processor/samplingprocessor/probabilisticsamplerprocessor"
 	"go.opentelemetry.io/collector/processor/samplingprocessor/tailsamplingprocessor"
+	"go.opentelemetry.io/collector/processor/servicegraphprocessor"
+	"go.opentelemetry.io/collector/processor/spanmetricsprocessor"
 	"go.opentelemetry.io/collector/processor/spanprocessor"
+	"go.opentelemetry.io/collector/processor/spanstatusprocessor"
 	"go.opentelemetry.io/collector/receiver/hostmetricsreceiver"
 	"go.opentelemetry.io/collector/receiver/jaegerreceiver"
 	"go.opentelemetry.io/collector/receiver/opencensusreceiver"
@@ -85,6 +99,7 @@ func Components() (
 		&jaegerexporter.Factory{},
 		&fileexporter.Factory{},
 		&otlpexporter.Factory{},
+		&loadbalancingexporter.Factory{},
 	)
 	if err != nil {
 		errs = append(errs, err)
@@ -100,6 +115,19 @@ func Components() (
 		&probabilisticsamplerprocessor.Factory{},
 		&spanprocessor.Factory{},
 		&filterprocessor.Factory{},
+		&spanmetricsprocessor.Factory{},
+		&servicegraphprocessor.Factory{},
+		&spanstatusprocessor.Factory{},
+		&clockskewprocessor.Factory{},
+		&metricstransformprocessor.Factory{},
+		&cumulativetodeltaprocessor.Factory{},
+		&deltatocumulativeprocessor.Factory{},
+		&cardinalitylimiter.Factory{},
+		&intervalprocessor.Factory{},
+		&histogramprocessor.Factory{},
+		&logmetricsprocessor.Factory{},
+		&resourcedetectionprocessor.Factory{},
+		&groupbyattrsprocessor.Factory{},
 	)
 	if err != nil {
 		errs = append(errs, err)
@@ -25,6 +25,7 @@ import (
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/exporter/fileexporter"
 	"go.opentelemetry.io/collector/exporter/jaegerexporter"
+	"go.opentelemetry.io/collector/exporter/loadbalancingexporter"
 	"go.opentelemetry.io/collector/exporter/loggingexporter"
 	"go.opentelemetry.io/collector/exporter/opencensusexporter"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
@@ -36,13 +37,26 @@ import (
 	"go.opentelemetry.io/collector/extension/zpagesextension"
 	"go.opentelemetry.io/collector/processor/attributesprocessor"
 	"go.opentelemetry.io/collector/processor/batchprocessor"
+	"go.opentelemetry.io/collector/processor/cardinalitylimiter"
+	"go.opentelemetry.io/collector/processor/clockskewprocessor"
+	"go.opentelemetry.io/collector/processor/cumulativetodeltaprocessor"
+	"go.opentelemetry.io/collector/processor/deltatocumulativeprocessor"
 	"go.opentelemetry.io/collector/processor/filterprocessor"
+	"go.opentelemetry.io/collector/processor/groupbyattrsprocessor"
+	"go.opentelemetry.io/collector/processor/histogramprocessor"
+	"go.opentelemetry.io/collector/processor/intervalprocessor"
+	"go.opentelemetry.io/collector/processor/logmetricsprocessor"
 	"go.opentelemetry.io/collector/processor/memorylimiter"
+	"go.opentelemetry.io/collector/processor/metricstransformprocessor"
 	"go.opentelemetry.io/collector/processor/queuedprocessor"
+	"go.opentelemetry.io/collector/processor/resourcedetectionprocessor"
 	"go.opentelemetry.io/collector/processor/resourceprocessor"
 	"go.opentelemetry.io/collector/processor/samplingprocessor/probabilisticsamplerprocessor"
 	"go.opentelemetry.io/collector/processor/samplingprocessor/tailsamplingprocessor"
+	"go.opentelemetry.io/collector/processor/servicegraphprocessor"
+	"go.opentelemetry.io/collector/processor/spanmetricsprocessor"
 	"go.opentelemetry.io/collector/processor/spanprocessor"
+	"go.opentelemetry.io/collector/processor/spanstatusprocessor"
 	"go.opentelemetry.io/collector/receiver/hostmetricsreceiver"
 	"go.opentelemetry.io/collector/receiver/jaegerreceiver"
 	"go.opentelemetry.io/collector/receiver/opencensusreceiver"
@@ -76,15 +90,29 @@ func TestDefaultComponents(t *testing.T) {
 		"probabilistic_sampler": &probabilisticsamplerprocessor.Factory{},
 		"span":                  &spanprocessor.Factory{},
 		"filter":                &filterprocessor.Factory{},
+		"spanmetrics":           &spanmetricsprocessor.Factory{},
+		"servicegraph":          &servicegraphprocessor.Factory{},
+		"span_status":           &spanstatusprocessor.Factory{},
+		"clock_skew":            &clockskewprocessor.Factory{},
+		"metrics_transform":     &metricstransformprocessor.Factory{},
+		"cumulative_to_delta":   &cumulativetodeltaprocessor.Factory{},
+		"delta_to_cumulative":   &deltatocumulativeprocessor.Factory{},
+		"cardinality_limiter":   &cardinalitylimiter.Factory{},
+		"histogram":             &histogramprocessor.Factory{},
+		"log_metrics":           &logmetricsprocessor.Factory{},
+		"resourcedetection":     &resourcedetectionprocessor.Factory{},
+		"groupbyattrs":          &groupbyattrsprocessor.Factory{},
+		"interval":              &intervalprocessor.Factory{},
 	}
 	expectedExporters := map[configmodels.Type]component.ExporterFactoryBase{
-		"opencensus": &opencensusexporter.Factory{},
-		"prometheus": &prometheusexporter.Factory{},
-		"logging":    &loggingexporter.Factory{},
-		"zipkin":     &zipkinexporter.Factory{},
-		"jaeger":     &jaegerexporter.Factory{},
-		"file":       &fileexporter.Factory{},
-		"otlp":       &otlpexporter.Factory{},
+		"opencensus":    &opencensusexporter.Factory{},
+		"prometheus":    &prometheusexporter.Factory{},
+		"logging":       &loggingexporter.Factory{},
+		"zipkin":        &zipkinexporter.Factory{},
+		"jaeger":        &jaegerexporter.Factory{},
+		"file":          &fileexporter.Factory{},
+		"otlp":          &otlpexporter.Factory{},
+		"loadbalancing": &loadbalancingexporter.Factory{},
 	}
 
 	factories, err := Components()
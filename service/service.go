@@ -39,6 +39,7 @@ import (
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configcheck"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/internal/collector/telemetry"
 	"go.opentelemetry.io/collector/internal/version"
 	"go.opentelemetry.io/collector/service/builder"
@@ -531,11 +532,37 @@ func (app *Application) handlePipelinezRequest(w http.ResponseWriter, r *http.Re
 		internal.WriteHTMLComponentHeader(w, internal.ComponentHeaderData{
 			Name: componentKind + ": " + fullName,
 		})
+		if componentKind == "exporter" {
+			app.writeExporterStatus(w, componentName)
+		}
 		// TODO: Add config + status info.
 	}
 	internal.WriteHTMLFooter(w)
 }
 
+// writeExporterStatus writes the circuit breaker state of the exporter with
+// the given name, if it has one enabled.
+func (app *Application) writeExporterStatus(w http.ResponseWriter, exporterName string) {
+	for _, exporters := range app.GetExporters() {
+		for cfg, exp := range exporters {
+			if cfg.Name() != exporterName {
+				continue
+			}
+			reporter, ok := exp.(exporterhelper.CircuitBreakerStateReporter)
+			if !ok {
+				continue
+			}
+			if state, enabled := reporter.CircuitBreakerState(); enabled {
+				internal.WriteHTMLPropertiesTable(w, internal.PropertiesTableData{
+					Name:       "Status",
+					Properties: [][2]string{{"Circuit breaker", state.String()}},
+				})
+				return
+			}
+		}
+	}
+}
+
 func (app *Application) handleExtensionzRequest(w http.ResponseWriter, r *http.Request) {
 	r.ParseForm()
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
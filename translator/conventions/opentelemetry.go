@@ -25,6 +25,7 @@ const (
 	AttributeTelemetrySDKLanguage  = "telemetry.sdk.language"
 	AttributeTelemetrySDKVersion   = "telemetry.sdk.version"
 	AttributeContainerName         = "container.name"
+	AttributeContainerID           = "container.id"
 	AttributeContainerImage        = "container.image.name"
 	AttributeContainerTag          = "container.image.tag"
 	AttributeFaasName              = "faas.name"
@@ -42,6 +43,7 @@ const (
 	AttributeHostImageName         = "host.image.name"
 	AttributeHostImageID           = "host.image.id"
 	AttributeHostImageVersion      = "host.image.version"
+	AttributeOSType                = "os.type"
 	AttributeProcessID             = "process.id"
 	AttributeProcessExecutableName = "process.executable.name"
 	AttributeProcessExecutablePath = "process.executable.path"
@@ -117,6 +119,7 @@ const (
 	AttributeRPCSystem               = "rpc.system"
 	AttributeRPCService              = "rpc.service"
 	AttributeRPCMethod               = "rpc.method"
+	AttributeRPCGRPCStatusCode       = "rpc.grpc.status_code"
 	EventTypeMessage                 = "message"
 	AttributeMessageType             = "message.type"
 	MessageTypeReceived              = "RECEIVED"
@@ -39,6 +39,33 @@ const (
 	OCUnauthenticated    = 16
 )
 
+var ocCodeNames = map[string]int32{
+	"OK":                  OCOK,
+	"CANCELLED":           OCCancelled,
+	"UNKNOWN":             OCUnknown,
+	"INVALID_ARGUMENT":    OCInvalidArgument,
+	"DEADLINE_EXCEEDED":   OCDeadlineExceeded,
+	"NOT_FOUND":           OCNotFound,
+	"ALREADY_EXISTS":      OCAlreadyExists,
+	"PERMISSION_DENIED":   OCPermissionDenied,
+	"RESOURCE_EXHAUSTED":  OCResourceExhausted,
+	"FAILED_PRECONDITION": OCFailedPrecondition,
+	"ABORTED":             OCAborted,
+	"OUT_OF_RANGE":        OCOutOfRange,
+	"UNIMPLEMENTED":       OCUnimplemented,
+	"INTERNAL":            OCInternal,
+	"UNAVAILABLE":         OCUnavailable,
+	"DATA_LOSS":           OCDataLoss,
+	"UNAUTHENTICATED":     OCUnauthenticated,
+}
+
+// OCStatusCodeFromName takes the canonical name of a status code, e.g. "NOT_FOUND", and returns
+// the status code, or false if the name is unknown.
+func OCStatusCodeFromName(name string) (int32, bool) {
+	code, ok := ocCodeNames[name]
+	return code, ok
+}
+
 var httpToOCCodeMap = map[int32]int32{
 	401: OCUnauthenticated,
 	403: OCPermissionDenied,
@@ -27,6 +27,15 @@ func TestHTTPStatusFromOTStatus(t *testing.T) {
 	}
 }
 
+func TestOTStatusFromName(t *testing.T) {
+	code, ok := OCStatusCodeFromName("NOT_FOUND")
+	assert.True(t, ok)
+	assert.Equal(t, int32(OCNotFound), code)
+
+	_, ok = OCStatusCodeFromName("not_found")
+	assert.False(t, ok)
+}
+
 func TestOTStatusFromHTTPStatus(t *testing.T) {
 	for httpStatus := int32(100); httpStatus <= 604; httpStatus++ {
 		otelStatus := OCStatusCodeFromHTTP(httpStatus)
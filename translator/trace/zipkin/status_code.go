@@ -164,7 +164,7 @@ func extractStatusFromError(attrib *tracepb.AttributeValue) (*int32, bool) {
 		if canonicalCodeStr == "" {
 			return nil, true
 		}
-		code, set := canonicalCodesMap[canonicalCodeStr]
+		code, set := tracetranslator.OCStatusCodeFromName(canonicalCodeStr)
 		if set {
 			return &code, true
 		}
@@ -174,24 +174,3 @@ func extractStatusFromError(attrib *tracepb.AttributeValue) (*int32, bool) {
 
 	return &unknown, false
 }
-
-var canonicalCodesMap = map[string]int32{
-	// https://github.com/googleapis/googleapis/blob/bee79fbe03254a35db125dc6d2f1e9b752b390fe/google/rpc/code.proto#L33-L186
-	"OK":                  0,
-	"CANCELLED":           1,
-	"UNKNOWN":             2,
-	"INVALID_ARGUMENT":    3,
-	"DEADLINE_EXCEEDED":   4,
-	"NOT_FOUND":           5,
-	"ALREADY_EXISTS":      6,
-	"PERMISSION_DENIED":   7,
-	"RESOURCE_EXHAUSTED":  8,
-	"FAILED_PRECONDITION": 9,
-	"ABORTED":             10,
-	"OUT_OF_RANGE":        11,
-	"UNIMPLEMENTED":       12,
-	"INTERNAL":            13,
-	"UNAVAILABLE":         14,
-	"DATA_LOSS":           15,
-	"UNAUTHENTICATED":     16,
-}